@@ -0,0 +1,5 @@
+// Package schedsimpb holds the protocol buffer and gRPC definitions of the
+// schedsim API, generated from schedsim.proto.
+package schedsimpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative schedsim.proto
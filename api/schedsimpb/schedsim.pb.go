@@ -0,0 +1,975 @@
+// The schedsim gRPC API, for driving the simulator from other languages.
+//
+// Times are in ticks. Fields of Options left unset keep the defaults of the
+// schedsim command.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: schedsim.proto
+
+package schedsimpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Event_Kind int32
+
+const (
+	Event_KIND_UNSPECIFIED Event_Kind = 0
+	Event_KIND_ARRIVE      Event_Kind = 1
+	Event_KIND_DISPATCH    Event_Kind = 2
+	Event_KIND_PREEMPT     Event_Kind = 3
+	Event_KIND_COMPLETE    Event_Kind = 4
+	Event_KIND_IDLE        Event_Kind = 5
+)
+
+// Enum value maps for Event_Kind.
+var (
+	Event_Kind_name = map[int32]string{
+		0: "KIND_UNSPECIFIED",
+		1: "KIND_ARRIVE",
+		2: "KIND_DISPATCH",
+		3: "KIND_PREEMPT",
+		4: "KIND_COMPLETE",
+		5: "KIND_IDLE",
+	}
+	Event_Kind_value = map[string]int32{
+		"KIND_UNSPECIFIED": 0,
+		"KIND_ARRIVE":      1,
+		"KIND_DISPATCH":    2,
+		"KIND_PREEMPT":     3,
+		"KIND_COMPLETE":    4,
+		"KIND_IDLE":        5,
+	}
+)
+
+func (x Event_Kind) Enum() *Event_Kind {
+	p := new(Event_Kind)
+	*p = x
+	return p
+}
+
+func (x Event_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Event_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_schedsim_proto_enumTypes[0].Descriptor()
+}
+
+func (Event_Kind) Type() protoreflect.EnumType {
+	return &file_schedsim_proto_enumTypes[0]
+}
+
+func (x Event_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Event_Kind.Descriptor instead.
+func (Event_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_schedsim_proto_rawDescGZIP(), []int{9, 0}
+}
+
+type Process struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Arrival int64                  `protobuf:"varint,2,opt,name=arrival,proto3" json:"arrival,omitempty"`
+	Burst   int64                  `protobuf:"varint,3,opt,name=burst,proto3" json:"burst,omitempty"`
+	// A lower value means a higher priority.
+	Priority      int64 `protobuf:"varint,4,opt,name=priority,proto3" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Process) Reset() {
+	*x = Process{}
+	mi := &file_schedsim_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Process) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Process) ProtoMessage() {}
+
+func (x *Process) ProtoReflect() protoreflect.Message {
+	mi := &file_schedsim_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Process.ProtoReflect.Descriptor instead.
+func (*Process) Descriptor() ([]byte, []int) {
+	return file_schedsim_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Process) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Process) GetArrival() int64 {
+	if x != nil {
+		return x.Arrival
+	}
+	return 0
+}
+
+func (x *Process) GetBurst() int64 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+func (x *Process) GetPriority() int64 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+type Options struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	RrQuantum *int64                 `protobuf:"varint,1,opt,name=rr_quantum,json=rrQuantum,proto3,oneof" json:"rr_quantum,omitempty"`
+	// One quantum per MLFQ level, from the highest level down.
+	MlfqQuanta    []int64  `protobuf:"varint,2,rep,packed,name=mlfq_quanta,json=mlfqQuanta,proto3" json:"mlfq_quanta,omitempty"`
+	AgingRate     *float64 `protobuf:"fixed64,3,opt,name=aging_rate,json=agingRate,proto3,oneof" json:"aging_rate,omitempty"`
+	Cpus          *int32   `protobuf:"varint,4,opt,name=cpus,proto3,oneof" json:"cpus,omitempty"`
+	SwitchCost    *int64   `protobuf:"varint,5,opt,name=switch_cost,json=switchCost,proto3,oneof" json:"switch_cost,omitempty"`
+	Seed          *int64   `protobuf:"varint,6,opt,name=seed,proto3,oneof" json:"seed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Options) Reset() {
+	*x = Options{}
+	mi := &file_schedsim_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Options) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Options) ProtoMessage() {}
+
+func (x *Options) ProtoReflect() protoreflect.Message {
+	mi := &file_schedsim_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Options.ProtoReflect.Descriptor instead.
+func (*Options) Descriptor() ([]byte, []int) {
+	return file_schedsim_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Options) GetRrQuantum() int64 {
+	if x != nil && x.RrQuantum != nil {
+		return *x.RrQuantum
+	}
+	return 0
+}
+
+func (x *Options) GetMlfqQuanta() []int64 {
+	if x != nil {
+		return x.MlfqQuanta
+	}
+	return nil
+}
+
+func (x *Options) GetAgingRate() float64 {
+	if x != nil && x.AgingRate != nil {
+		return *x.AgingRate
+	}
+	return 0
+}
+
+func (x *Options) GetCpus() int32 {
+	if x != nil && x.Cpus != nil {
+		return *x.Cpus
+	}
+	return 0
+}
+
+func (x *Options) GetSwitchCost() int64 {
+	if x != nil && x.SwitchCost != nil {
+		return *x.SwitchCost
+	}
+	return 0
+}
+
+func (x *Options) GetSeed() int64 {
+	if x != nil && x.Seed != nil {
+		return *x.Seed
+	}
+	return 0
+}
+
+type RunSimulationRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Processes []*Process             `protobuf:"bytes,1,rep,name=processes,proto3" json:"processes,omitempty"`
+	// Names of the algorithms to run, such as "fcfs" or "rr". Defaults to
+	// fcfs, sjf, priority and rr.
+	Algorithms    []string `protobuf:"bytes,2,rep,name=algorithms,proto3" json:"algorithms,omitempty"`
+	Options       *Options `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunSimulationRequest) Reset() {
+	*x = RunSimulationRequest{}
+	mi := &file_schedsim_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunSimulationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunSimulationRequest) ProtoMessage() {}
+
+func (x *RunSimulationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schedsim_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunSimulationRequest.ProtoReflect.Descriptor instead.
+func (*RunSimulationRequest) Descriptor() ([]byte, []int) {
+	return file_schedsim_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RunSimulationRequest) GetProcesses() []*Process {
+	if x != nil {
+		return x.Processes
+	}
+	return nil
+}
+
+func (x *RunSimulationRequest) GetAlgorithms() []string {
+	if x != nil {
+		return x.Algorithms
+	}
+	return nil
+}
+
+func (x *RunSimulationRequest) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type RunSimulationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*Result              `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunSimulationResponse) Reset() {
+	*x = RunSimulationResponse{}
+	mi := &file_schedsim_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunSimulationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunSimulationResponse) ProtoMessage() {}
+
+func (x *RunSimulationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_schedsim_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunSimulationResponse.ProtoReflect.Descriptor instead.
+func (*RunSimulationResponse) Descriptor() ([]byte, []int) {
+	return file_schedsim_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RunSimulationResponse) GetResults() []*Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type StreamSimulationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Processes     []*Process             `protobuf:"bytes,1,rep,name=processes,proto3" json:"processes,omitempty"`
+	Algorithm     string                 `protobuf:"bytes,2,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	Options       *Options               `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamSimulationRequest) Reset() {
+	*x = StreamSimulationRequest{}
+	mi := &file_schedsim_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamSimulationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamSimulationRequest) ProtoMessage() {}
+
+func (x *StreamSimulationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_schedsim_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamSimulationRequest.ProtoReflect.Descriptor instead.
+func (*StreamSimulationRequest) Descriptor() ([]byte, []int) {
+	return file_schedsim_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StreamSimulationRequest) GetProcesses() []*Process {
+	if x != nil {
+		return x.Processes
+	}
+	return nil
+}
+
+func (x *StreamSimulationRequest) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *StreamSimulationRequest) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type StreamSimulationResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Item:
+	//
+	//	*StreamSimulationResponse_Event
+	//	*StreamSimulationResponse_Slice
+	//	*StreamSimulationResponse_Result
+	Item          isStreamSimulationResponse_Item `protobuf_oneof:"item"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamSimulationResponse) Reset() {
+	*x = StreamSimulationResponse{}
+	mi := &file_schedsim_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamSimulationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamSimulationResponse) ProtoMessage() {}
+
+func (x *StreamSimulationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_schedsim_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamSimulationResponse.ProtoReflect.Descriptor instead.
+func (*StreamSimulationResponse) Descriptor() ([]byte, []int) {
+	return file_schedsim_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StreamSimulationResponse) GetItem() isStreamSimulationResponse_Item {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *StreamSimulationResponse) GetEvent() *Event {
+	if x != nil {
+		if x, ok := x.Item.(*StreamSimulationResponse_Event); ok {
+			return x.Event
+		}
+	}
+	return nil
+}
+
+func (x *StreamSimulationResponse) GetSlice() *TimeSlice {
+	if x != nil {
+		if x, ok := x.Item.(*StreamSimulationResponse_Slice); ok {
+			return x.Slice
+		}
+	}
+	return nil
+}
+
+func (x *StreamSimulationResponse) GetResult() *Result {
+	if x != nil {
+		if x, ok := x.Item.(*StreamSimulationResponse_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+type isStreamSimulationResponse_Item interface {
+	isStreamSimulationResponse_Item()
+}
+
+type StreamSimulationResponse_Event struct {
+	Event *Event `protobuf:"bytes,1,opt,name=event,proto3,oneof"`
+}
+
+type StreamSimulationResponse_Slice struct {
+	Slice *TimeSlice `protobuf:"bytes,2,opt,name=slice,proto3,oneof"`
+}
+
+type StreamSimulationResponse_Result struct {
+	Result *Result `protobuf:"bytes,3,opt,name=result,proto3,oneof"`
+}
+
+func (*StreamSimulationResponse_Event) isStreamSimulationResponse_Item() {}
+
+func (*StreamSimulationResponse_Slice) isStreamSimulationResponse_Item() {}
+
+func (*StreamSimulationResponse_Result) isStreamSimulationResponse_Item() {}
+
+type TimeSlice struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pid           int64                  `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	Start         int64                  `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	Stop          int64                  `protobuf:"varint,3,opt,name=stop,proto3" json:"stop,omitempty"`
+	Cpu           int32                  `protobuf:"varint,4,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeSlice) Reset() {
+	*x = TimeSlice{}
+	mi := &file_schedsim_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeSlice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeSlice) ProtoMessage() {}
+
+func (x *TimeSlice) ProtoReflect() protoreflect.Message {
+	mi := &file_schedsim_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeSlice.ProtoReflect.Descriptor instead.
+func (*TimeSlice) Descriptor() ([]byte, []int) {
+	return file_schedsim_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TimeSlice) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *TimeSlice) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *TimeSlice) GetStop() int64 {
+	if x != nil {
+		return x.Stop
+	}
+	return 0
+}
+
+func (x *TimeSlice) GetCpu() int32 {
+	if x != nil {
+		return x.Cpu
+	}
+	return 0
+}
+
+type Stat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Process       *Process               `protobuf:"bytes,1,opt,name=process,proto3" json:"process,omitempty"`
+	Wait          int64                  `protobuf:"varint,2,opt,name=wait,proto3" json:"wait,omitempty"`
+	Turnaround    int64                  `protobuf:"varint,3,opt,name=turnaround,proto3" json:"turnaround,omitempty"`
+	Completion    int64                  `protobuf:"varint,4,opt,name=completion,proto3" json:"completion,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stat) Reset() {
+	*x = Stat{}
+	mi := &file_schedsim_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stat) ProtoMessage() {}
+
+func (x *Stat) ProtoReflect() protoreflect.Message {
+	mi := &file_schedsim_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stat.ProtoReflect.Descriptor instead.
+func (*Stat) Descriptor() ([]byte, []int) {
+	return file_schedsim_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Stat) GetProcess() *Process {
+	if x != nil {
+		return x.Process
+	}
+	return nil
+}
+
+func (x *Stat) GetWait() int64 {
+	if x != nil {
+		return x.Wait
+	}
+	return 0
+}
+
+func (x *Stat) GetTurnaround() int64 {
+	if x != nil {
+		return x.Turnaround
+	}
+	return 0
+}
+
+func (x *Stat) GetCompletion() int64 {
+	if x != nil {
+		return x.Completion
+	}
+	return 0
+}
+
+type Result struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Algorithm         string                 `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	Title             string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Gantt             []*TimeSlice           `protobuf:"bytes,3,rep,name=gantt,proto3" json:"gantt,omitempty"`
+	Stats             []*Stat                `protobuf:"bytes,4,rep,name=stats,proto3" json:"stats,omitempty"`
+	AverageWait       float64                `protobuf:"fixed64,5,opt,name=average_wait,json=averageWait,proto3" json:"average_wait,omitempty"`
+	AverageTurnaround float64                `protobuf:"fixed64,6,opt,name=average_turnaround,json=averageTurnaround,proto3" json:"average_turnaround,omitempty"`
+	Throughput        float64                `protobuf:"fixed64,7,opt,name=throughput,proto3" json:"throughput,omitempty"`
+	ContextSwitches   int32                  `protobuf:"varint,8,opt,name=context_switches,json=contextSwitches,proto3" json:"context_switches,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	mi := &file_schedsim_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_schedsim_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_schedsim_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Result) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *Result) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Result) GetGantt() []*TimeSlice {
+	if x != nil {
+		return x.Gantt
+	}
+	return nil
+}
+
+func (x *Result) GetStats() []*Stat {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *Result) GetAverageWait() float64 {
+	if x != nil {
+		return x.AverageWait
+	}
+	return 0
+}
+
+func (x *Result) GetAverageTurnaround() float64 {
+	if x != nil {
+		return x.AverageTurnaround
+	}
+	return 0
+}
+
+func (x *Result) GetThroughput() float64 {
+	if x != nil {
+		return x.Throughput
+	}
+	return 0
+}
+
+func (x *Result) GetContextSwitches() int32 {
+	if x != nil {
+		return x.ContextSwitches
+	}
+	return 0
+}
+
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Kind  Event_Kind             `protobuf:"varint,1,opt,name=kind,proto3,enum=schedsim.v1.Event_Kind" json:"kind,omitempty"`
+	Time  int64                  `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	// Unset for idle events.
+	Pid           int64 `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
+	Cpu           int32 `protobuf:"varint,4,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_schedsim_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_schedsim_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_schedsim_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Event) GetKind() Event_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return Event_KIND_UNSPECIFIED
+}
+
+func (x *Event) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *Event) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *Event) GetCpu() int32 {
+	if x != nil {
+		return x.Cpu
+	}
+	return 0
+}
+
+var File_schedsim_proto protoreflect.FileDescriptor
+
+const file_schedsim_proto_rawDesc = "" +
+	"\n" +
+	"\x0eschedsim.proto\x12\vschedsim.v1\"e\n" +
+	"\aProcess\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aarrival\x18\x02 \x01(\x03R\aarrival\x12\x14\n" +
+	"\x05burst\x18\x03 \x01(\x03R\x05burst\x12\x1a\n" +
+	"\bpriority\x18\x04 \x01(\x03R\bpriority\"\x8a\x02\n" +
+	"\aOptions\x12\"\n" +
+	"\n" +
+	"rr_quantum\x18\x01 \x01(\x03H\x00R\trrQuantum\x88\x01\x01\x12\x1f\n" +
+	"\vmlfq_quanta\x18\x02 \x03(\x03R\n" +
+	"mlfqQuanta\x12\"\n" +
+	"\n" +
+	"aging_rate\x18\x03 \x01(\x01H\x01R\tagingRate\x88\x01\x01\x12\x17\n" +
+	"\x04cpus\x18\x04 \x01(\x05H\x02R\x04cpus\x88\x01\x01\x12$\n" +
+	"\vswitch_cost\x18\x05 \x01(\x03H\x03R\n" +
+	"switchCost\x88\x01\x01\x12\x17\n" +
+	"\x04seed\x18\x06 \x01(\x03H\x04R\x04seed\x88\x01\x01B\r\n" +
+	"\v_rr_quantumB\r\n" +
+	"\v_aging_rateB\a\n" +
+	"\x05_cpusB\x0e\n" +
+	"\f_switch_costB\a\n" +
+	"\x05_seed\"\x9a\x01\n" +
+	"\x14RunSimulationRequest\x122\n" +
+	"\tprocesses\x18\x01 \x03(\v2\x14.schedsim.v1.ProcessR\tprocesses\x12\x1e\n" +
+	"\n" +
+	"algorithms\x18\x02 \x03(\tR\n" +
+	"algorithms\x12.\n" +
+	"\aoptions\x18\x03 \x01(\v2\x14.schedsim.v1.OptionsR\aoptions\"F\n" +
+	"\x15RunSimulationResponse\x12-\n" +
+	"\aresults\x18\x01 \x03(\v2\x13.schedsim.v1.ResultR\aresults\"\x9b\x01\n" +
+	"\x17StreamSimulationRequest\x122\n" +
+	"\tprocesses\x18\x01 \x03(\v2\x14.schedsim.v1.ProcessR\tprocesses\x12\x1c\n" +
+	"\talgorithm\x18\x02 \x01(\tR\talgorithm\x12.\n" +
+	"\aoptions\x18\x03 \x01(\v2\x14.schedsim.v1.OptionsR\aoptions\"\xad\x01\n" +
+	"\x18StreamSimulationResponse\x12*\n" +
+	"\x05event\x18\x01 \x01(\v2\x12.schedsim.v1.EventH\x00R\x05event\x12.\n" +
+	"\x05slice\x18\x02 \x01(\v2\x16.schedsim.v1.TimeSliceH\x00R\x05slice\x12-\n" +
+	"\x06result\x18\x03 \x01(\v2\x13.schedsim.v1.ResultH\x00R\x06resultB\x06\n" +
+	"\x04item\"Y\n" +
+	"\tTimeSlice\x12\x10\n" +
+	"\x03pid\x18\x01 \x01(\x03R\x03pid\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\x03R\x05start\x12\x12\n" +
+	"\x04stop\x18\x03 \x01(\x03R\x04stop\x12\x10\n" +
+	"\x03cpu\x18\x04 \x01(\x05R\x03cpu\"\x8a\x01\n" +
+	"\x04Stat\x12.\n" +
+	"\aprocess\x18\x01 \x01(\v2\x14.schedsim.v1.ProcessR\aprocess\x12\x12\n" +
+	"\x04wait\x18\x02 \x01(\x03R\x04wait\x12\x1e\n" +
+	"\n" +
+	"turnaround\x18\x03 \x01(\x03R\n" +
+	"turnaround\x12\x1e\n" +
+	"\n" +
+	"completion\x18\x04 \x01(\x03R\n" +
+	"completion\"\xb0\x02\n" +
+	"\x06Result\x12\x1c\n" +
+	"\talgorithm\x18\x01 \x01(\tR\talgorithm\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12,\n" +
+	"\x05gantt\x18\x03 \x03(\v2\x16.schedsim.v1.TimeSliceR\x05gantt\x12'\n" +
+	"\x05stats\x18\x04 \x03(\v2\x11.schedsim.v1.StatR\x05stats\x12!\n" +
+	"\faverage_wait\x18\x05 \x01(\x01R\vaverageWait\x12-\n" +
+	"\x12average_turnaround\x18\x06 \x01(\x01R\x11averageTurnaround\x12\x1e\n" +
+	"\n" +
+	"throughput\x18\a \x01(\x01R\n" +
+	"throughput\x12)\n" +
+	"\x10context_switches\x18\b \x01(\x05R\x0fcontextSwitches\"\xe2\x01\n" +
+	"\x05Event\x12+\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x17.schedsim.v1.Event.KindR\x04kind\x12\x12\n" +
+	"\x04time\x18\x02 \x01(\x03R\x04time\x12\x10\n" +
+	"\x03pid\x18\x03 \x01(\x03R\x03pid\x12\x10\n" +
+	"\x03cpu\x18\x04 \x01(\x05R\x03cpu\"t\n" +
+	"\x04Kind\x12\x14\n" +
+	"\x10KIND_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vKIND_ARRIVE\x10\x01\x12\x11\n" +
+	"\rKIND_DISPATCH\x10\x02\x12\x10\n" +
+	"\fKIND_PREEMPT\x10\x03\x12\x11\n" +
+	"\rKIND_COMPLETE\x10\x04\x12\r\n" +
+	"\tKIND_IDLE\x10\x052\xc6\x01\n" +
+	"\tSimulator\x12V\n" +
+	"\rRunSimulation\x12!.schedsim.v1.RunSimulationRequest\x1a\".schedsim.v1.RunSimulationResponse\x12a\n" +
+	"\x10StreamSimulation\x12$.schedsim.v1.StreamSimulationRequest\x1a%.schedsim.v1.StreamSimulationResponse0\x01B\"\n" +
+	"\vschedsim.v1P\x01Z\x11p1/api/schedsimpbb\x06proto3"
+
+var (
+	file_schedsim_proto_rawDescOnce sync.Once
+	file_schedsim_proto_rawDescData []byte
+)
+
+func file_schedsim_proto_rawDescGZIP() []byte {
+	file_schedsim_proto_rawDescOnce.Do(func() {
+		file_schedsim_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_schedsim_proto_rawDesc), len(file_schedsim_proto_rawDesc)))
+	})
+	return file_schedsim_proto_rawDescData
+}
+
+var file_schedsim_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_schedsim_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_schedsim_proto_goTypes = []any{
+	(Event_Kind)(0),                  // 0: schedsim.v1.Event.Kind
+	(*Process)(nil),                  // 1: schedsim.v1.Process
+	(*Options)(nil),                  // 2: schedsim.v1.Options
+	(*RunSimulationRequest)(nil),     // 3: schedsim.v1.RunSimulationRequest
+	(*RunSimulationResponse)(nil),    // 4: schedsim.v1.RunSimulationResponse
+	(*StreamSimulationRequest)(nil),  // 5: schedsim.v1.StreamSimulationRequest
+	(*StreamSimulationResponse)(nil), // 6: schedsim.v1.StreamSimulationResponse
+	(*TimeSlice)(nil),                // 7: schedsim.v1.TimeSlice
+	(*Stat)(nil),                     // 8: schedsim.v1.Stat
+	(*Result)(nil),                   // 9: schedsim.v1.Result
+	(*Event)(nil),                    // 10: schedsim.v1.Event
+}
+var file_schedsim_proto_depIdxs = []int32{
+	1,  // 0: schedsim.v1.RunSimulationRequest.processes:type_name -> schedsim.v1.Process
+	2,  // 1: schedsim.v1.RunSimulationRequest.options:type_name -> schedsim.v1.Options
+	9,  // 2: schedsim.v1.RunSimulationResponse.results:type_name -> schedsim.v1.Result
+	1,  // 3: schedsim.v1.StreamSimulationRequest.processes:type_name -> schedsim.v1.Process
+	2,  // 4: schedsim.v1.StreamSimulationRequest.options:type_name -> schedsim.v1.Options
+	10, // 5: schedsim.v1.StreamSimulationResponse.event:type_name -> schedsim.v1.Event
+	7,  // 6: schedsim.v1.StreamSimulationResponse.slice:type_name -> schedsim.v1.TimeSlice
+	9,  // 7: schedsim.v1.StreamSimulationResponse.result:type_name -> schedsim.v1.Result
+	1,  // 8: schedsim.v1.Stat.process:type_name -> schedsim.v1.Process
+	7,  // 9: schedsim.v1.Result.gantt:type_name -> schedsim.v1.TimeSlice
+	8,  // 10: schedsim.v1.Result.stats:type_name -> schedsim.v1.Stat
+	0,  // 11: schedsim.v1.Event.kind:type_name -> schedsim.v1.Event.Kind
+	3,  // 12: schedsim.v1.Simulator.RunSimulation:input_type -> schedsim.v1.RunSimulationRequest
+	5,  // 13: schedsim.v1.Simulator.StreamSimulation:input_type -> schedsim.v1.StreamSimulationRequest
+	4,  // 14: schedsim.v1.Simulator.RunSimulation:output_type -> schedsim.v1.RunSimulationResponse
+	6,  // 15: schedsim.v1.Simulator.StreamSimulation:output_type -> schedsim.v1.StreamSimulationResponse
+	14, // [14:16] is the sub-list for method output_type
+	12, // [12:14] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_schedsim_proto_init() }
+func file_schedsim_proto_init() {
+	if File_schedsim_proto != nil {
+		return
+	}
+	file_schedsim_proto_msgTypes[1].OneofWrappers = []any{}
+	file_schedsim_proto_msgTypes[5].OneofWrappers = []any{
+		(*StreamSimulationResponse_Event)(nil),
+		(*StreamSimulationResponse_Slice)(nil),
+		(*StreamSimulationResponse_Result)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_schedsim_proto_rawDesc), len(file_schedsim_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_schedsim_proto_goTypes,
+		DependencyIndexes: file_schedsim_proto_depIdxs,
+		EnumInfos:         file_schedsim_proto_enumTypes,
+		MessageInfos:      file_schedsim_proto_msgTypes,
+	}.Build()
+	File_schedsim_proto = out.File
+	file_schedsim_proto_goTypes = nil
+	file_schedsim_proto_depIdxs = nil
+}
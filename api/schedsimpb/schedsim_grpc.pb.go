@@ -0,0 +1,176 @@
+// The schedsim gRPC API, for driving the simulator from other languages.
+//
+// Times are in ticks. Fields of Options left unset keep the defaults of the
+// schedsim command.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: schedsim.proto
+
+package schedsimpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Simulator_RunSimulation_FullMethodName    = "/schedsim.v1.Simulator/RunSimulation"
+	Simulator_StreamSimulation_FullMethodName = "/schedsim.v1.Simulator/StreamSimulation"
+)
+
+// SimulatorClient is the client API for Simulator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SimulatorClient interface {
+	// RunSimulation runs each requested algorithm over a workload and returns
+	// all of their results at once.
+	RunSimulation(ctx context.Context, in *RunSimulationRequest, opts ...grpc.CallOption) (*RunSimulationResponse, error)
+	// StreamSimulation runs one algorithm, streaming every event as it
+	// happens, then each slice of the Gantt chart, then the result.
+	StreamSimulation(ctx context.Context, in *StreamSimulationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamSimulationResponse], error)
+}
+
+type simulatorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSimulatorClient(cc grpc.ClientConnInterface) SimulatorClient {
+	return &simulatorClient{cc}
+}
+
+func (c *simulatorClient) RunSimulation(ctx context.Context, in *RunSimulationRequest, opts ...grpc.CallOption) (*RunSimulationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RunSimulationResponse)
+	err := c.cc.Invoke(ctx, Simulator_RunSimulation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulatorClient) StreamSimulation(ctx context.Context, in *StreamSimulationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamSimulationResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Simulator_ServiceDesc.Streams[0], Simulator_StreamSimulation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamSimulationRequest, StreamSimulationResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Simulator_StreamSimulationClient = grpc.ServerStreamingClient[StreamSimulationResponse]
+
+// SimulatorServer is the server API for Simulator service.
+// All implementations must embed UnimplementedSimulatorServer
+// for forward compatibility.
+type SimulatorServer interface {
+	// RunSimulation runs each requested algorithm over a workload and returns
+	// all of their results at once.
+	RunSimulation(context.Context, *RunSimulationRequest) (*RunSimulationResponse, error)
+	// StreamSimulation runs one algorithm, streaming every event as it
+	// happens, then each slice of the Gantt chart, then the result.
+	StreamSimulation(*StreamSimulationRequest, grpc.ServerStreamingServer[StreamSimulationResponse]) error
+	mustEmbedUnimplementedSimulatorServer()
+}
+
+// UnimplementedSimulatorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSimulatorServer struct{}
+
+func (UnimplementedSimulatorServer) RunSimulation(context.Context, *RunSimulationRequest) (*RunSimulationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RunSimulation not implemented")
+}
+func (UnimplementedSimulatorServer) StreamSimulation(*StreamSimulationRequest, grpc.ServerStreamingServer[StreamSimulationResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamSimulation not implemented")
+}
+func (UnimplementedSimulatorServer) mustEmbedUnimplementedSimulatorServer() {}
+func (UnimplementedSimulatorServer) testEmbeddedByValue()                   {}
+
+// UnsafeSimulatorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SimulatorServer will
+// result in compilation errors.
+type UnsafeSimulatorServer interface {
+	mustEmbedUnimplementedSimulatorServer()
+}
+
+func RegisterSimulatorServer(s grpc.ServiceRegistrar, srv SimulatorServer) {
+	// If the following call panics, it indicates UnimplementedSimulatorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Simulator_ServiceDesc, srv)
+}
+
+func _Simulator_RunSimulation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunSimulationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulatorServer).RunSimulation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Simulator_RunSimulation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulatorServer).RunSimulation(ctx, req.(*RunSimulationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulator_StreamSimulation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamSimulationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SimulatorServer).StreamSimulation(m, &grpc.GenericServerStream[StreamSimulationRequest, StreamSimulationResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Simulator_StreamSimulationServer = grpc.ServerStreamingServer[StreamSimulationResponse]
+
+// Simulator_ServiceDesc is the grpc.ServiceDesc for Simulator service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Simulator_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "schedsim.v1.Simulator",
+	HandlerType: (*SimulatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RunSimulation",
+			Handler:    _Simulator_RunSimulation_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamSimulation",
+			Handler:       _Simulator_StreamSimulation_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "schedsim.proto",
+}
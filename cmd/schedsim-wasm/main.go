@@ -0,0 +1,52 @@
+//go:build js && wasm
+
+// Command schedsim-wasm exposes the schedulers to JavaScript. Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o schedsim.wasm ./cmd/schedsim-wasm
+//
+// and load it with the wasm_exec.js shipped in $(go env GOROOT)/lib/wasm. It
+// defines a global simulate(workload, options) function, where workload is a
+// CSV string or an array of {id, burst, arrival, priority} objects and options
+// is an optional object in the format of the config file, such as
+// {algorithms: ["rr"], rr: {quantum: 4}}. It returns the results as a JSON
+// string, or on failure a JSON object whose error field says why.
+package main
+
+import (
+	"encoding/json"
+	"syscall/js"
+
+	"p1/internal/jsapi"
+)
+
+func main() {
+	js.Global().Set("simulate", js.FuncOf(simulate))
+	select {}
+}
+
+func simulate(_ js.Value, args []js.Value) any {
+	var workload, options []byte
+	if len(args) > 0 {
+		workload = jsonBytes(args[0])
+	}
+	if len(args) > 1 {
+		options = jsonBytes(args[1])
+	}
+
+	out, err := jsapi.Simulate(workload, options)
+	if err != nil {
+		out, _ = json.Marshal(map[string]string{"error": err.Error()})
+	}
+	return string(out)
+}
+
+// jsonBytes passes strings through as-is and encodes anything else as JSON.
+func jsonBytes(v js.Value) []byte {
+	switch v.Type() {
+	case js.TypeString:
+		return []byte(v.String())
+	case js.TypeUndefined, js.TypeNull:
+		return nil
+	}
+	return []byte(js.Global().Get("JSON").Call("stringify", v).String())
+}
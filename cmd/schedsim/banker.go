@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"p1/internal/banker"
+	"p1/internal/render"
+)
+
+// runBanker checks a resource state with the Banker's algorithm and decides
+// the requests pending in it: "schedsim banker [flags] [state.csv]",
+// reading the state from standard input if no file is given. See
+// banker.Load for the format.
+func runBanker(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim banker [flags] [state.csv]\nRows are kind,process,counts... where kind is allocation, max, available or request.\n")
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", formatText, "output `format`: text or json")
+	var opts render.Options
+	fs.TextVar(&opts.Style, "table-style", render.StyleBox, "table `style` of text output: box, plain or borderless")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if *format != formatText && *format != formatJSON {
+		return fmt.Errorf("%w: unknown output format %q", ErrInvalidArgs, *format)
+	}
+	r, err := openInput(fs, stdin, "state")
+	if err != nil {
+		return err
+	}
+	s, requests, err := banker.Load(r)
+	_ = r.Close()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	decisions, err := s.EvaluateAll(requests)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	if *format == formatJSON {
+		sequence, safe := s.Safe()
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			banker.State
+			Need      [][]int64         `json:"need"`
+			Safe      bool              `json:"safe"`
+			Sequence  []int             `json:"sequence"`
+			Decisions []banker.Decision `json:"decisions"`
+		}{s, s.Need(), safe, sequence, decisions})
+	}
+	render.Title(stdout, "Banker's algorithm")
+	render.Banker(stdout, s, opts)
+	if len(decisions) > 0 {
+		_, _ = fmt.Fprintln(stdout, "Requests, in turn")
+		render.Decisions(stdout, s, decisions, opts)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"p1/internal/bench"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// runBench times every algorithm on synthetic workloads of increasing size.
+func runBench(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim bench [flags]\n")
+		fs.PrintDefaults()
+	}
+	var algorithms stringList
+	for _, a := range scheduler.Algorithms() {
+		algorithms = append(algorithms, a.Name)
+	}
+	sizes := int64List{1000, 10000}
+	fs.Var(&algorithms, "algorithms", "comma-separated `names` of the algorithms to time")
+	fs.Var(&sizes, "sizes", "comma-separated `numbers` of processes of the workloads")
+	minTime := fs.Duration("min-time", 200*time.Millisecond, "least `duration` to spend timing each algorithm on each workload")
+	cpus := fs.Int("cpus", 1, "`number` of CPUs to schedule onto")
+	seed := fs.Int64("seed", 1, "`seed` of the synthetic workloads")
+	var style render.TableStyle
+	fs.TextVar(&style, "table-style", render.StyleBox, "table `style`: box, plain or borderless")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	sim, err := scheduler.NewSimulator(scheduler.WithCPUs(*cpus))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+	var as []scheduler.Algorithm
+	for _, name := range algorithms {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		as = append(as, a)
+	}
+
+	table := render.Table{
+		Columns: []render.Column{{Header: "ALGORITHM"}, {Header: "PROCESSES"}, {Header: "RUNS"}, {Header: "TIME/RUN"}, {Header: "DECISIONS"}, {Header: "DECISIONS/S"}},
+		Style:   style,
+	}
+	for _, n := range sizes {
+		if n < 1 {
+			return fmt.Errorf("%w: workload sizes must be positive, got %d", ErrInvalidArgs, n)
+		}
+		processes := bench.Workload(int(n), *cpus, *seed)
+		for _, a := range as {
+			m := bench.Measure(sim, a, processes, *minTime)
+			table.Rows = append(table.Rows, []string{
+				m.Algorithm,
+				fmt.Sprint(m.Processes),
+				fmt.Sprint(m.Runs),
+				m.Elapsed.Round(time.Microsecond).String(),
+				fmt.Sprint(m.Decisions),
+				fmt.Sprintf("%.0f", m.PerSecond()),
+			})
+		}
+	}
+	return table.Render(stdout)
+}
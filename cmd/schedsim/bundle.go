@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"p1/internal/bundle"
+	"p1/internal/render"
+	"p1/internal/version"
+)
+
+// runExportBundle schedules a workload like schedsim itself and packs the
+// run into a bundle: "schedsim export-bundle [flags] workload bundle.zip".
+func runExportBundle(args []string, _ io.Reader, _, stderr io.Writer) error {
+	s, err := parseFlags(args, stderr)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: s.level})))
+	if len(s.args) != 3 {
+		return fmt.Errorf("%w: usage: schedsim export-bundle [flags] workload bundle.zip", ErrInvalidArgs)
+	}
+	workload, out := s.args[1], s.args[2]
+
+	data, err := os.ReadFile(workload)
+	if err != nil {
+		return fmt.Errorf("%v: error opening scheduling file", err)
+	}
+	b, err := bundle.Create(workload, data, s.cfg, s.tick)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("%v: error creating bundle", err)
+	}
+	if err := b.Write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	slog.Info("exported bundle", "file", out, "algorithms", len(b.Results))
+	return nil
+}
+
+// runImportBundle writes the results held in a bundle again, after rerunning
+// them to check they are reproduced if -verify is given.
+func runImportBundle(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim import-bundle [flags] bundle.zip\n")
+		fs.PrintDefaults()
+	}
+	verify := fs.Bool("verify", false, "rerun the bundled workload and fail unless it reproduces the bundled results")
+	format := fs.String("format", formatText, "output `format`: text or json")
+	var style render.TableStyle
+	fs.TextVar(&style, "table-style", render.StyleBox, "table `style` of text output: box, plain or borderless")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if *format != formatText && *format != formatJSON {
+		return fmt.Errorf("%w: unknown output format %q", ErrInvalidArgs, *format)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("%w: must give a bundle to import", ErrInvalidArgs)
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	b, err := readBundle(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *verify {
+		if tool := version.String(); tool != b.Manifest.Tool {
+			slog.Warn("bundle was made by a different build", "bundle", b.Manifest.Tool, "this", tool)
+		}
+		if err := b.Verify(); err != nil {
+			return err
+		}
+		slog.Info("verified bundle", "results", len(b.Results))
+	}
+
+	opts := render.Options{Tick: b.Manifest.Tick, Style: style, GanttLimit: render.DefaultGanttLimit, NoGantt: b.Config.NoGantt, CPUs: b.Config.CPUs}
+	if *format == formatJSON {
+		return render.JSON(stdout, b.Results, opts)
+	}
+	for _, n := range b.Results {
+		render.Report(stdout, n.Title, n.Result, opts)
+	}
+	return nil
+}
+
+func readBundle(path string) (*bundle.Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%v: error opening bundle", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	b, err := bundle.Read(f, info.Size())
+	if errors.Is(err, bundle.ErrInvalid) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return b, err
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"p1/internal/grpcapi"
+	"p1/internal/metrics"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+	"p1/internal/server"
+	"p1/internal/tui"
+)
+
+// commands are the subcommands run as "schedsim <command> [args]" instead of
+// scheduling a workload file.
+var commands = map[string]func(args []string, stdin io.Reader, stdout, stderr io.Writer) error{
+	"banker":        runBanker,
+	"bench":         runBench,
+	"compare-trace": runCompareTrace,
+	"convert":       runConvert,
+	"deadlock":      runDeadlock,
+	"disk":          runDisk,
+	"examples":      runExamples,
+	"explain":       runExplain,
+	"export-bundle": runExportBundle,
+	"fault-curve":   runFaultCurve,
+	"gap":           runGap,
+	"generate":      runGenerate,
+	"import-bundle": runImportBundle,
+	"matrix":        runMatrix,
+	"paging":        runPaging,
+	"grade":         runGrade,
+	"pareto":        runPareto,
+	"philosophers":  runPhilosophers,
+	"prodcons":      runProdcons,
+	"quiz":          runQuiz,
+	"rank":          runRank,
+	"recommend":     runRecommend,
+	"serve":         runServe,
+	"history":       runHistory,
+	"repl":          runREPL,
+	"replay":        runReplay,
+	"snapshot":      runSnapshot,
+	"tlb":           runTLB,
+	"sweep":         runSweep,
+	"tui":           runTUI,
+	"working-set":   runWorkingSet,
+	"what-if":       runWhatIf,
+}
+
+// runConvert rewrites a JSON results document written by any earlier version
+// of schedsim in the current schema, reading the file named in args or
+// standard input.
+func runConvert(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim convert [results.json]\n")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	r := stdin
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return fmt.Errorf("%v: error opening results file", err)
+		}
+		defer f.Close()
+		r = f
+	default:
+		return fmt.Errorf("%w: convert takes at most one results file", ErrInvalidArgs)
+	}
+	return render.Convert(stdout, r)
+}
+
+// runServe serves the HTTP API of package server, and the gRPC API if
+// -grpc-addr is given, until interrupted.
+func runServe(args []string, _ io.Reader, _, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", "localhost:8080", "`address` to listen on")
+	grpcAddr := fs.String("grpc-addr", "", "`address` to serve the gRPC API on, if any")
+	level := fs.String("log-level", "info", "log `level`: debug, info, warn or error")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(*level)); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: l})))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	m := metrics.New()
+	api := server.New(m)
+	defer api.Close()
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           api,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	slog.Info("serving", "addr", *addr)
+
+	grpcErrc := make(chan error, 1)
+	if *grpcAddr != "" {
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			_ = srv.Close()
+			return err
+		}
+		gs := grpc.NewServer()
+		grpcapi.Register(gs, m)
+		go func() { grpcErrc <- gs.Serve(lis) }()
+		defer gs.GracefulStop()
+		slog.Info("serving gRPC", "addr", *grpcAddr)
+	}
+
+	select {
+	case err := <-errc:
+		return err
+	case err := <-grpcErrc:
+		_ = srv.Close()
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// runTUI animates the first algorithm named by -algorithms in the terminal.
+// It takes the same flags and workload file as schedsim itself.
+func runTUI(args []string, _ io.Reader, _, stderr io.Writer) error {
+	s, err := parseFlags(args, stderr)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: s.level})))
+
+	sim, processes, err := setup(s)
+	if err != nil {
+		return err
+	}
+	if len(s.cfg.Algorithms) == 0 {
+		return fmt.Errorf("%w: tui needs an algorithm to run", ErrInvalidArgs)
+	}
+	a, err := scheduler.LookupAlgorithm(s.cfg.Algorithms[0])
+	if err != nil {
+		return err
+	}
+	return tui.Run(tui.New(sim, a, processes))
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"p1/internal/deadlock"
+	"p1/internal/render"
+)
+
+// runDeadlock follows the wait-for graph through a trace of resource events
+// and reports the deadlocks in it: "schedsim deadlock [flags] [trace.csv]",
+// reading the trace from standard input if no file is given. See
+// deadlock.Load for the format.
+func runDeadlock(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim deadlock [flags] [trace.csv]\nRows are time,process,kind,resource where kind is request, acquire or release.\n")
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", formatText, "output `format`: text or json")
+	var opts render.Options
+	fs.TextVar(&opts.Style, "table-style", render.StyleBox, "table `style` of text output: box, plain or borderless")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if *format != formatText && *format != formatJSON {
+		return fmt.Errorf("%w: unknown output format %q", ErrInvalidArgs, *format)
+	}
+	r, err := openInput(fs, stdin, "trace")
+	if err != nil {
+		return err
+	}
+	events, err := deadlock.Load(r)
+	_ = r.Close()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	res, err := deadlock.Detect(events)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	if *format == formatJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	render.Title(stdout, "Deadlock detection")
+	render.WaitFor(stdout, res, opts)
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"p1/internal/disk"
+	"p1/internal/render"
+)
+
+// runDisk serves a list of cylinder requests with disk scheduling
+// algorithms and reports each one's seeks and head movement: "schedsim disk
+// [flags] [requests]", reading the requests from standard input if no file
+// is given.
+func runDisk(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim disk [flags] [requests]\nRequests are cylinders separated by commas or spaces.\n")
+		fs.PrintDefaults()
+	}
+	var algorithms stringList
+	for _, a := range disk.Algorithms() {
+		algorithms = append(algorithms, a.Name)
+	}
+	p := disk.DefaultParams()
+	fs.Var(&algorithms, "algorithms", "comma-separated `names` of the algorithms to run")
+	fs.Int64Var(&p.Head, "head", p.Head, "`cylinder` the head starts at")
+	fs.Int64Var(&p.Cylinders, "cylinders", p.Cylinders, "`number` of cylinders on the disk")
+	direction := fs.String("direction", "up", "`direction` the head starts moving in: up, away from cylinder 0, or down")
+	format := fs.String("format", formatText, "output `format`: text or json")
+	var style render.TableStyle
+	fs.TextVar(&style, "table-style", render.StyleBox, "table `style` of the summary: box, plain or borderless")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	switch *direction {
+	case "up":
+	case "down":
+		p.Down = true
+	default:
+		return fmt.Errorf("%w: unknown direction %q, want up or down", ErrInvalidArgs, *direction)
+	}
+	if *format != formatText && *format != formatJSON {
+		return fmt.Errorf("%w: unknown output format %q", ErrInvalidArgs, *format)
+	}
+
+	requests, err := loadNumbers(fs, stdin, "requests")
+	if err != nil {
+		return err
+	}
+
+	type result struct {
+		Algorithm string `json:"algorithm"`
+		Title     string `json:"title"`
+		disk.Result
+	}
+	var results []result
+	for _, name := range algorithms {
+		a, err := disk.LookupAlgorithm(name)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		res, err := disk.Schedule(a, requests, p)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		results = append(results, result{a.Name, a.Title, res})
+	}
+
+	if *format == formatJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			disk.Params
+			Requests []int64  `json:"requests"`
+			Results  []result `json:"results"`
+		}{p, requests, results})
+	}
+	table := render.Table{
+		Columns: []render.Column{{Header: "ALGORITHM"}, {Header: "ORDER", MaxWidth: 60}, {Header: "HEAD MOVEMENT", Align: render.AlignRight}},
+		Style:   style,
+	}
+	for _, res := range results {
+		render.DiskReport(stdout, res.Title, res.Result, p)
+		order := make([]string, 0, len(requests))
+		for _, c := range res.Order() {
+			order = append(order, strconv.FormatInt(c, 10))
+		}
+		table.Rows = append(table.Rows, []string{res.Title, strings.Join(order, ", "), strconv.FormatInt(res.Movement, 10)})
+	}
+	return table.Render(stdout)
+}
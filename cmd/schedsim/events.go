@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// eventLog writes the events of simulations as JSON lines, for -events.
+type eventLog struct {
+	enc *json.Encoder
+	// window, unless zero, is the span of time outside which events are
+	// left out.
+	window render.Window
+}
+
+// eventLine is one line of an event log.
+type eventLine struct {
+	Algorithm string `json:"algorithm"`
+	scheduler.Event
+}
+
+func newEventLog(w io.Writer) *eventLog {
+	return &eventLog{enc: json.NewEncoder(w)}
+}
+
+func (l *eventLog) write(algorithm string, ev scheduler.Event) error {
+	if !l.window.Contains(ev.Time) {
+		return nil
+	}
+	return l.enc.Encode(eventLine{Algorithm: algorithm, Event: ev})
+}
+
+// schedule runs a over processes until ctx is cancelled, logging every
+// event.
+func (l *eventLog) schedule(ctx context.Context, sim *scheduler.Simulator, a scheduler.Algorithm, processes []scheduler.Process) (scheduler.Result, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	run := sim.Simulate(ctx, a, processes)
+	var err error
+	for ev := range run.Events {
+		if err != nil {
+			continue
+		}
+		if err = l.write(a.Name, ev); err != nil {
+			cancel()
+		}
+	}
+	return run.Result(), err
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"p1/internal/examples"
+	"p1/internal/input"
+	"p1/internal/render"
+)
+
+const examplesUsage = `usage: schedsim examples [-table-style style] [command]
+commands:
+  list                 list the built-in workloads (the default)
+  show NAME            write a workload as CSV
+  run [flags] NAME     schedule a workload, taking the flags of schedsim itself
+`
+
+// runExamples lists, shows and schedules the built-in example workloads.
+func runExamples(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { _, _ = fmt.Fprint(stderr, examplesUsage) }
+	var style render.TableStyle
+	fs.TextVar(&style, "table-style", render.StyleBox, "table `style` of the list: box, plain or borderless")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	cmd, rest := fs.Arg(0), fs.Args()[min(1, fs.NArg()):]
+	switch {
+	case cmd == "" || cmd == "list" && len(rest) == 0:
+		table := render.Table{
+			Columns: []render.Column{{Header: "NAME"}, {Header: "PROCESSES"}, {Header: "DESCRIPTION"}},
+			Style:   style,
+		}
+		for _, e := range examples.List() {
+			processes, err := examples.Load(e.Name)
+			if err != nil {
+				return err
+			}
+			table.Rows = append(table.Rows, []string{e.Name, fmt.Sprint(len(processes)), e.Description})
+		}
+		return table.Render(stdout)
+	case cmd == "show" && len(rest) == 1:
+		processes, err := examples.Load(rest[0])
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		return input.WriteCSV(stdout, processes)
+	case cmd == "run":
+		s, err := parseFlags(append([]string{args[0] + " run"}, rest...), stderr)
+		if err != nil {
+			return err
+		}
+		if len(s.args) != 2 {
+			return fmt.Errorf("%w: must give the name of one example to run", ErrInvalidArgs)
+		}
+		s.example = s.args[1]
+		slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: s.level})))
+		return schedule(s, stdin, stdout)
+	}
+	fs.Usage()
+	return fmt.Errorf("%w: unknown examples command %q", ErrInvalidArgs, strings.Join(fs.Args(), " "))
+}
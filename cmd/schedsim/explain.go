@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// runExplain runs two algorithms over a workload and narrates how their
+// schedules differ: "schedsim explain [flags] A B workload". It takes the
+// flags of schedsim itself.
+func runExplain(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	s, err := parseFlags(args, stderr)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: s.level})))
+	if len(s.args) != 4 {
+		return fmt.Errorf("%w: usage: schedsim explain [flags] algorithm algorithm workload", ErrInvalidArgs)
+	}
+	names := s.args[1:3]
+	s.args = []string{s.args[0], s.args[3]}
+
+	sim, processes, err := setup(s)
+	if err != nil {
+		return err
+	}
+	var results [2]render.Named
+	for i, name := range names {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		results[i] = render.Named{Algorithm: a.Name, Title: a.Title, Result: sim.Schedule(a, processes)}
+	}
+	render.Explain(stdout, results[0], results[1], render.Options{Tick: s.tick})
+	return nil
+}
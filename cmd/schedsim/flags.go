@@ -0,0 +1,360 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"p1/internal/config"
+	"p1/internal/history"
+	"p1/internal/input"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// settings are the parsed command line.
+type settings struct {
+	level slog.Level
+	cfg   config.Config
+	// format is the output format, formatText, formatJSON, formatJSONL,
+	// formatCSV, formatSVG, formatHTML or formatPlantUML.
+	format string
+	// stream writes text output a row at a time as processes complete.
+	stream bool
+	// style is how text output draws its tables.
+	style render.TableStyle
+	// chart is the chart text output draws, chartGantt or chartTimeline.
+	chart string
+	// ganttLimit is the most slices a text Gantt chart draws, or 0 for all.
+	ganttLimit int
+	// history names the history database to record the run in, or is
+	// empty to keep no history.
+	history string
+	// noCache forces every result to be computed afresh.
+	noCache bool
+	// events names the file to log events to as JSON lines, if any.
+	events string
+	// step pauses after every event of a run to show its state.
+	step bool
+	// playback replays every run in wall-clock time at this speed, unless
+	// it is 0.
+	playback speed
+	// tick is the real length of a tick, or 0 if times are bare ticks.
+	tick time.Duration
+	// window restricts charts and events to a span of time, unless zero.
+	window render.Window
+	// saturate runs workloads whose times could overflow, holding them at
+	// the largest int64.
+	saturate bool
+	// crossCheck reschedules each workload with the oracle and fails if
+	// the results differ.
+	crossCheck bool
+	// verify recomputes the timings of every result from its Gantt chart
+	// and fails if they differ.
+	verify bool
+	// example names the built-in workload to schedule instead of a file,
+	// if any.
+	example string
+	// args are the arguments left after the flags, prefixed by the program
+	// name.
+	args []string
+}
+
+// Output formats selected by -format.
+const (
+	formatText     = "text"
+	formatJSON     = "json"
+	formatJSONL    = "jsonl"
+	formatCSV      = "csv"
+	formatSVG      = "svg"
+	formatHTML     = "html"
+	formatPlantUML = "plantuml"
+)
+
+// Charts of text output selected by -chart.
+const (
+	chartGantt    = "gantt"
+	chartTimeline = "timeline"
+)
+
+// parseFlags parses args on top of the config file named by -config, if any,
+// so that flags given explicitly win over the file.
+func parseFlags(args []string, stderr io.Writer) (settings, error) {
+	return parseFlagsWith(args, stderr, config.Default(), nil)
+}
+
+// parseFlagsWith is parseFlags for subcommands that use defaults of their
+// own and, if extra is not nil, define flags of their own on the flag set.
+func parseFlagsWith(args []string, stderr io.Writer, defaults config.Config, extra func(*flag.FlagSet)) (settings, error) {
+	var (
+		s             settings
+		configPath    string
+		interventions string
+		from, to      string
+		algorithms    stringList
+		fcfsOrder     string
+		quantum       int64
+		mlfqQuanta    int64List
+		shares        shareList
+		agingRate     float64
+		cpus          int
+		speeds        int64List
+		cpusGiven     bool
+		switchCost    int64
+		seed          int64
+		noGantt       bool
+		resumeBoost   bool
+		assert        bool
+	)
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.TextVar(&s.level, "log-level", slog.LevelWarn, "log `level`: debug, info, warn or error")
+	fs.StringVar(&s.format, "format", formatText, "output `format`: text, json, jsonl to write results as they are produced, csv for the Gantt slices, svg for the Gantt charts, html for a page of charts and tables or plantuml for timing diagrams")
+	fs.BoolVar(&s.stream, "stream", false, "write text output a row at a time as processes complete")
+	fs.TextVar(&s.style, "table-style", render.StyleBox, "table `style` of text output: box, plain or borderless")
+	fs.StringVar(&s.chart, "chart", chartGantt, "`chart` of text output: gantt, or timeline for a row per process showing when it ran, was ready and was blocked")
+	fs.IntVar(&s.ganttLimit, "gantt-limit", render.DefaultGanttLimit, "most `slices` to draw in a Gantt chart, or 0 for all")
+	fs.StringVar(&s.history, "history", history.DefaultPath(), "history database `file` to record runs in, or empty for none")
+	fs.BoolVar(&s.noCache, "no-cache", false, "recompute results instead of reusing cached ones")
+	fs.StringVar(&s.events, "events", "", "write every event to `file` as a JSON line")
+	fs.BoolVar(&s.step, "step", false, "pause after every event to show the state and wait for a command")
+	fs.Var(&s.playback, "playback", "replay each run's events as they happen in wall-clock time sped up by `factor`, such as 10x, a tick lasting -tick or else a second")
+	fs.BoolVar(&s.saturate, "saturate", false, "run workloads whose times could overflow, completing processes at the largest time rather than failing")
+	fs.BoolVar(&s.crossCheck, "cross-check", false, "also schedule with the slow reference simulator and fail if its results differ")
+	fs.BoolVar(&s.verify, "verify", false, "recompute every timing and average from the Gantt chart and fail if they disagree")
+	fs.DurationVar(&s.tick, "tick", 0, "read and report times as durations, with one tick lasting `duration`")
+	fs.StringVar(&from, "from", "", "restrict Gantt charts and events to times from `time` on, and report the timings within them")
+	fs.StringVar(&to, "to", "", "restrict Gantt charts and events to times up to `time`, and report the timings within them")
+	fs.StringVar(&configPath, "config", "", "JSON config `file` to read settings from")
+	fs.StringVar(&interventions, "interventions", "", "`file` of operator interventions, lines such as \"t=30 suspend pid=2\" and \"t=45 resume pid=2\"")
+	fs.Var(&algorithms, "algorithms", "comma-separated `names` of the algorithms to run (default "+strings.Join(defaults.Algorithms, ",")+")")
+	fs.StringVar(&fcfsOrder, "fcfs-order", string(scheduler.OrderArrival), "`order` of first-come, first-serve: arrival, given, or strict to refuse workloads not sorted by arrival")
+	fs.Int64Var(&quantum, "quantum", defaults.RR.Quantum, "round-robin, lottery and fair-share time `quantum`")
+	fs.Var(&mlfqQuanta, "mlfq-quanta", "comma-separated `quanta` of the MLFQ levels, one per level")
+	fs.Var(&shares, "shares", "comma-separated CPU `shares` of the fair-share groups named by the workload's group column, such as web=2048, each group otherwise holding "+strconv.Itoa(scheduler.DefaultShare))
+	fs.Float64Var(&agingRate, "aging-rate", defaults.Aging.Rate, "priority `boost` per unit of waiting; given, it ages sjf and priority as well as priority with aging")
+	fs.IntVar(&cpus, "cpus", defaults.CPUs, "`number` of CPUs to schedule onto")
+	fs.Var(&speeds, "speeds", "comma-separated `speeds` of the CPUs as whole multiples of the slowest, such as 2,2,1,1 for two cores twice as fast as the other two; jobs go to the fastest CPU free, and -cpus defaults to the number of speeds")
+	fs.Int64Var(&switchCost, "switch-cost", defaults.SwitchCost, "`time` taken by a context switch")
+	fs.Int64Var(&seed, "seed", defaults.Seed, "`seed` for randomized algorithms")
+	fs.BoolVar(&noGantt, "no-gantt", defaults.NoGantt, "leave out the Gantt chart, computing and writing only the timings")
+	fs.BoolVar(&resumeBoost, "resume-boost", defaults.ResumeBoost, "favour processes resuming from -interventions, as interactive schedulers favour those back from I/O: sjf and priority run them first until they next leave a CPU, and mlfq puts them back on its top level")
+	fs.BoolVar(&assert, "assert", defaults.Assert, "check the simulator's state after every step and abort with a dump of it at the first inconsistency")
+	if extra != nil {
+		extra(fs)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return settings{}, fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	switch s.format {
+	case formatText, formatJSON, formatJSONL, formatCSV, formatSVG, formatHTML, formatPlantUML:
+	default:
+		return settings{}, fmt.Errorf("%w: unknown output format %q", ErrInvalidArgs, s.format)
+	}
+	switch s.chart {
+	case chartGantt, chartTimeline:
+	default:
+		return settings{}, fmt.Errorf("%w: unknown chart %q", ErrInvalidArgs, s.chart)
+	}
+	if s.stream && s.format != formatText {
+		return settings{}, fmt.Errorf("%w: -stream is for text output; -format jsonl streams JSON", ErrInvalidArgs)
+	}
+	if (s.stream || s.format == formatJSONL) && (s.step || s.events != "") {
+		return settings{}, fmt.Errorf("%w: streamed output cannot be combined with -step or -events", ErrInvalidArgs)
+	}
+
+	if s.playback > 0 && (s.format != formatText || s.stream || s.step || s.events != "") {
+		return settings{}, fmt.Errorf("%w: -playback writes text output and cannot be combined with -format, -stream, -step or -events", ErrInvalidArgs)
+	}
+
+	if from != "" || to != "" {
+		var err error
+		if s.window, err = parseWindow(from, to, s.tick); err != nil {
+			return settings{}, fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+	}
+
+	s.cfg = defaults
+	if configPath != "" {
+		var err error
+		if s.cfg, err = config.LoadFile(configPath); err != nil {
+			return settings{}, err
+		}
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "algorithms":
+			s.cfg.Algorithms = algorithms
+		case "fcfs-order":
+			s.cfg.FCFS.Order = scheduler.FCFSOrder(fcfsOrder)
+		case "quantum":
+			s.cfg.RR.Quantum = quantum
+		case "mlfq-quanta":
+			s.cfg.MLFQ.Levels = len(mlfqQuanta)
+			s.cfg.MLFQ.Quanta = mlfqQuanta
+		case "shares":
+			s.cfg.FairShare.Shares = shares
+		case "aging-rate":
+			s.cfg.Aging.Rate, s.cfg.Aging.All = agingRate, true
+		case "cpus":
+			s.cfg.CPUs, cpusGiven = cpus, true
+		case "speeds":
+			s.cfg.Speeds = speeds
+		case "switch-cost":
+			s.cfg.SwitchCost = switchCost
+		case "seed":
+			s.cfg.Seed = seed
+		case "no-gantt":
+			s.cfg.NoGantt = noGantt
+		case "resume-boost":
+			s.cfg.ResumeBoost = resumeBoost
+		case "assert":
+			s.cfg.Assert = assert
+		}
+	})
+	if len(speeds) > 0 && !cpusGiven {
+		s.cfg.CPUs = len(speeds)
+	}
+	if interventions != "" {
+		var err error
+		if s.cfg.Suspensions, err = loadInterventions(interventions, s.tick); err != nil {
+			return settings{}, err
+		}
+	}
+	if err := s.cfg.Validate(); err != nil {
+		return settings{}, fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+	if s.verify && s.cfg.NoGantt {
+		return settings{}, fmt.Errorf("%w: -verify checks results against the Gantt chart, which -no-gantt leaves out", ErrInvalidArgs)
+	}
+	if s.format == formatCSV && s.cfg.NoGantt {
+		return settings{}, fmt.Errorf("%w: -format csv writes the Gantt chart, which -no-gantt leaves out", ErrInvalidArgs)
+	}
+
+	s.args = append([]string{args[0]}, fs.Args()...)
+	return s, nil
+}
+
+// parseWindow parses the -from and -to times, either of which may be
+// empty for the start or end of the run.
+func parseWindow(from, to string, tick time.Duration) (render.Window, error) {
+	w := render.Window{To: math.MaxInt64}
+	var err error
+	if from != "" {
+		if w.From, err = parseTime(from, tick); err != nil {
+			return w, fmt.Errorf("-from: %w", err)
+		}
+	}
+	if to != "" {
+		if w.To, err = parseTime(to, tick); err != nil {
+			return w, fmt.Errorf("-to: %w", err)
+		}
+	}
+	switch {
+	case w.From < 0:
+		return w, fmt.Errorf("-from must not be negative, got %s", from)
+	case w.To <= w.From:
+		return w, fmt.Errorf("-to %s must come after -from %s", to, cmp.Or(from, "0"))
+	}
+	return w, nil
+}
+
+// parseTime parses a time in ticks, or a duration such as 50ms when a tick
+// length is set.
+func parseTime(s string, tick time.Duration) (int64, error) {
+	if t, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return t, nil
+	}
+	if tick > 0 {
+		if d, err := time.ParseDuration(s); err == nil {
+			return int64(d / tick), nil
+		}
+	}
+	return 0, fmt.Errorf("bad time %q", s)
+}
+
+// loadInterventions reads the suspensions made by the interventions file at
+// path.
+func loadInterventions(path string, tick time.Duration) ([]scheduler.Suspension, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%v: error opening interventions file", err)
+	}
+	defer f.Close()
+	suspensions, err := input.LoadInterventions(f, tick)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	return suspensions, nil
+}
+
+// stringList is a flag holding comma-separated strings.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(s string) error {
+	*l = strings.Split(s, ",")
+	return nil
+}
+
+// shareList is a flag holding comma-separated group shares such as
+// web=2048.
+type shareList []scheduler.GroupShare
+
+func (l *shareList) String() string {
+	s := make([]string, len(*l))
+	for i, g := range *l {
+		s[i] = g.Group + "=" + strconv.FormatInt(g.Share, 10)
+	}
+	return strings.Join(s, ",")
+}
+
+func (l *shareList) Set(s string) error {
+	*l = (*l)[:0]
+	for _, f := range strings.Split(s, ",") {
+		group, share, ok := strings.Cut(f, "=")
+		if !ok {
+			return fmt.Errorf("share %q is not group=share", f)
+		}
+		v, err := strconv.ParseInt(share, 10, 64)
+		if err != nil {
+			return err
+		}
+		*l = append(*l, scheduler.GroupShare{Group: group, Share: v})
+	}
+	return nil
+}
+
+// int64List is a flag holding comma-separated integers.
+type int64List []int64
+
+func (l *int64List) String() string {
+	s := make([]string, len(*l))
+	for i, v := range *l {
+		s[i] = strconv.FormatInt(v, 10)
+	}
+	return strings.Join(s, ",")
+}
+
+func (l *int64List) Set(s string) error {
+	*l = (*l)[:0]
+	for _, f := range strings.Split(s, ",") {
+		v, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return err
+		}
+		*l = append(*l, v)
+	}
+	return nil
+}
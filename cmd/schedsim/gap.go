@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+
+	"p1/internal/config"
+	"p1/internal/experiment"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// runGap runs every algorithm over a workload and reports how much longer
+// each waits on average than the best schedule of it: "schedsim gap [flags]
+// workload". It takes the flags of schedsim itself, defaulting to every
+// algorithm. Workloads too large to search the schedules of are measured
+// against a lower bound on the best.
+func runGap(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	defaults := config.Default()
+	defaults.Algorithms = nil
+	for _, a := range scheduler.Algorithms() {
+		defaults.Algorithms = append(defaults.Algorithms, a.Name)
+	}
+	s, err := parseFlagsWith(args, stderr, defaults, nil)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: s.level})))
+
+	sim, processes, err := setup(s)
+	if err != nil {
+		return err
+	}
+	optimum := experiment.Optimal(processes, sim.Options())
+	table := render.Table{
+		Columns: []render.Column{{Header: "ALGORITHM"}, {Header: "AVERAGE WAIT", Align: render.AlignRight}, {Header: "GAP", Align: render.AlignRight}},
+		Style:   s.style,
+	}
+	for _, name := range s.cfg.Algorithms {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return err
+		}
+		wait := sim.Schedule(a, processes).AveWait
+		gap := "-"
+		if g := experiment.Gap(wait, optimum); !math.IsInf(g, 1) {
+			gap = fmt.Sprintf("%.2f%%", g)
+		}
+		table.Rows = append(table.Rows, []string{a.Name, metricValue(wait), gap})
+	}
+	if optimum.Exact {
+		_, err = fmt.Fprintf(stdout, "The best schedule waits %s on average.\n", metricValue(optimum.Wait))
+	} else {
+		_, err = fmt.Fprintf(stdout, "No schedule waits less than %s on average; the gaps from it overstate those from the best.\n", metricValue(optimum.Wait))
+	}
+	if err != nil {
+		return err
+	}
+	return table.Render(stdout)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"p1/internal/input"
+	"p1/internal/workload"
+)
+
+// runGenerate writes a synthetic workload as CSV that schedsim reads back:
+// "schedsim generate [flags]".
+func runGenerate(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim generate [flags]\n")
+		fs.PrintDefaults()
+	}
+	spec := workload.Spec{Bursts: workload.Uniform}
+	fs.IntVar(&spec.Processes, "processes", 20, "`number` of processes")
+	fs.IntVar(&spec.CPUs, "cpus", 1, "`number` of CPUs the processes arrive about as fast as can run them")
+	fs.Int64Var(&spec.MeanBurst, "mean-burst", 10, "mean `burst` of CPU-bound processes")
+	fs.TextVar(&spec.Bursts, "bursts", workload.Uniform, "`distribution` of CPU-bound bursts: uniform, exponential, or pareto for heavy-tailed ones")
+	fs.Float64Var(&spec.IOBound, "io-bound", 0, "`fraction` of processes that are I/O-bound, with short bursts, interactive and of the more urgent priorities")
+	fs.IntVar(&spec.Phase, "phase", 0, "mean `number` of processes arriving in each of the busy and quiet phases arrivals alternate between, or 0 for a steady stream")
+	seed := fs.Int64("seed", 1, "`seed` of the workload")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return fmt.Errorf("%w: unexpected arguments %q", ErrInvalidArgs, fs.Args())
+	}
+	processes, err := workload.Generate(spec, *seed)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	return input.WriteCSV(stdout, processes)
+}
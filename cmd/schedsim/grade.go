@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+
+	"p1/internal/grade"
+	"p1/internal/pool"
+	"p1/internal/render"
+)
+
+const gradeUsage = `usage: schedsim grade [flags] key.json submission...
+Grades submissions against an answer key and fails unless every algorithm
+of every submission passes. Each submission is either a JSON results
+document, as written by schedsim -format json, or a workload to schedule as
+the key is configured. Several submissions are graded at once.
+`
+
+// runGrade checks submissions against an answer key, writing a pass or fail
+// report per algorithm of each.
+func runGrade(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(stderr, gradeUsage)
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", formatText, "output `format`: text or json")
+	average := fs.Float64("average-tolerance", 0, "allowed difference in averages and throughput, overriding the key")
+	ticks := fs.Int64("time-tolerance", 0, "allowed difference in per-process and Gantt times in ticks, overriding the key")
+	skipGantt := fs.Bool("skip-gantt", false, "leave the Gantt chart unchecked, overriding the key")
+	workers := fs.Int("workers", runtime.GOMAXPROCS(0), "`number` of submissions to grade at once")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if *format != formatText && *format != formatJSON {
+		return fmt.Errorf("%w: unknown output format %q", ErrInvalidArgs, *format)
+	}
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return fmt.Errorf("%w: must give an answer key and a submission", ErrInvalidArgs)
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("%v: error opening answer key", err)
+	}
+	key, err := grade.LoadKey(f)
+	_ = f.Close()
+	if err != nil {
+		return err
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "average-tolerance":
+			key.Tolerance.Average = *average
+		case "time-tolerance":
+			key.Tolerance.Time = *ticks
+		case "skip-gantt":
+			key.Tolerance.SkipGantt = *skipGantt
+		}
+	})
+	if err := key.Tolerance.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	submissions := fs.Args()[1:]
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	graded := pool.Run(ctx, *workers, submissions, func(_ context.Context, path string) ([]grade.Report, error) {
+		return gradeFile(key, path)
+	})
+	if len(graded) == 1 {
+		if graded[0].Err != nil {
+			return graded[0].Err
+		}
+		return writeReports(stdout, *format, graded[0].Value)
+	}
+
+	if *format == formatJSON {
+		type submission struct {
+			Submission string         `json:"submission"`
+			Reports    []grade.Report `json:"reports,omitempty"`
+			Error      string         `json:"error,omitempty"`
+		}
+		out := make([]submission, len(graded))
+		for i, g := range graded {
+			out[i] = submission{Submission: submissions[i], Reports: g.Value}
+			if g.Err != nil {
+				out[i].Error = g.Err.Error()
+			}
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	}
+	passed := 0
+	for i, g := range graded {
+		if g.Err == nil && grade.Failed(g.Value) == nil {
+			passed++
+		}
+		if *format == formatJSON {
+			continue
+		}
+		_, _ = fmt.Fprintf(stdout, "== %s\n", submissions[i])
+		if g.Err != nil {
+			_, _ = fmt.Fprintf(stdout, "ERROR  %v\n", g.Err)
+			continue
+		}
+		_ = writeReports(stdout, *format, g.Value)
+	}
+	if *format == formatText {
+		_, _ = fmt.Fprintf(stdout, "%d/%d submissions passed\n", passed, len(graded))
+	}
+	if passed < len(graded) {
+		return fmt.Errorf("%w: %d of %d submissions", grade.ErrFailed, len(graded)-passed, len(graded))
+	}
+	return nil
+}
+
+// gradeFile grades the submission in the file at path against key.
+func gradeFile(key grade.Key, path string) ([]grade.Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%v: error opening submission", err)
+	}
+	// Workloads in JSON are arrays, while results documents are objects.
+	var submitted []render.Named
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		doc, err := render.ReadJSON(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		submitted = doc.Results
+	} else if submitted, err = key.Schedule(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return key.Grade(submitted), nil
+}
+
+// writeReports writes the reports on one submission in format, returning
+// grade.ErrFailed if any algorithm failed.
+func writeReports(w io.Writer, format string, reports []grade.Report) error {
+	if format == formatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			return err
+		}
+		return grade.Failed(reports)
+	}
+	passed := 0
+	for _, r := range reports {
+		result := "FAIL"
+		if r.Pass {
+			result = "PASS"
+			passed++
+		}
+		_, _ = fmt.Fprintf(w, "%s  %s\n", result, r.Algorithm)
+		for _, f := range r.Failures {
+			_, _ = fmt.Fprintf(w, "      %s\n", f)
+		}
+	}
+	_, _ = fmt.Fprintf(w, "%d/%d algorithms passed\n", passed, len(reports))
+	return grade.Failed(reports)
+}
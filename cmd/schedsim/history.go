@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"p1/internal/history"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// record adds a run to the history database named by s. History is a
+// convenience, so failing to record only logs a warning.
+func record(s settings, processes []scheduler.Process, results []render.Named) {
+	store, err := history.Open(s.history)
+	if err != nil {
+		slog.Warn("not recording run in history", "err", err)
+		return
+	}
+	defer store.Close()
+
+	workload := "example:" + s.example
+	if s.example == "" {
+		workload = s.args[1]
+		if abs, err := filepath.Abs(workload); err == nil {
+			workload = abs
+		}
+	}
+	run := &history.Run{
+		Time:      time.Now().UTC(),
+		Workload:  workload,
+		Config:    s.cfg,
+		Tick:      s.tick,
+		Processes: processes,
+		Results:   results,
+	}
+	if err := store.Add(run); err != nil {
+		slog.Warn("not recording run in history", "err", err)
+		return
+	}
+	slog.Debug("recorded run", "id", run.ID, "history", s.history)
+}
+
+const historyUsage = `usage: schedsim history [-history file] [-table-style style] command
+commands:
+  list       list past runs
+  show ID    write the parameters and results of a run as JSON
+  render ID  write the report of a run again
+`
+
+// runHistory lists and shows runs recorded in the history database.
+func runHistory(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { _, _ = fmt.Fprint(stderr, historyUsage) }
+	path := fs.String("history", history.DefaultPath(), "history database `file`")
+	var style render.TableStyle
+	fs.TextVar(&style, "table-style", render.StyleBox, "table `style` of reports: box, plain or borderless")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if *path == "" {
+		return fmt.Errorf("%w: history is turned off", ErrInvalidArgs)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("%w: missing history command", ErrInvalidArgs)
+	}
+
+	store, err := history.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	cmd, rest := fs.Arg(0), fs.Args()[1:]
+	if cmd == "list" {
+		return listHistory(stdout, store, style)
+	}
+	if (cmd != "show" && cmd != "render") || len(rest) != 1 {
+		fs.Usage()
+		return fmt.Errorf("%w: unknown history command %q", ErrInvalidArgs, strings.Join(fs.Args(), " "))
+	}
+	id, err := strconv.ParseUint(rest[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad run ID %q", ErrInvalidArgs, rest[0])
+	}
+	run, err := store.Get(id)
+	if err != nil {
+		return err
+	}
+	if cmd == "show" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(run)
+	}
+	for _, n := range run.Results {
+		render.Report(stdout, n.Title, n.Result, render.Options{Tick: run.Tick, Style: style, GanttLimit: render.DefaultGanttLimit, NoGantt: run.Config.NoGantt, CPUs: run.Config.CPUs})
+	}
+	return nil
+}
+
+func listHistory(w io.Writer, store *history.Store, style render.TableStyle) error {
+	runs, err := store.List()
+	if err != nil {
+		return err
+	}
+	table := render.Table{
+		Columns: []render.Column{
+			{Header: "ID"}, {Header: "TIME"}, {Header: "WORKLOAD", MaxWidth: 40}, {Header: "PROCESSES"}, {Header: "ALGORITHMS"},
+		},
+		Style: style,
+	}
+	for _, r := range runs {
+		names := make([]string, len(r.Results))
+		for i, n := range r.Results {
+			names[i] = n.Algorithm
+		}
+		table.Rows = append(table.Rows, []string{
+			fmt.Sprint(r.ID),
+			r.Time.Local().Format(time.DateTime),
+			r.Workload,
+			fmt.Sprint(len(r.Processes)),
+			strings.Join(names, ","),
+		})
+	}
+	return table.Render(w)
+}
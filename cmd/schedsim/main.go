@@ -0,0 +1,394 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+
+	"p1/internal/cache"
+	"p1/internal/examples"
+	"p1/internal/input"
+	"p1/internal/oracle"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+var ErrInvalidArgs = errors.New("invalid args")
+
+// errInterrupted ends a run cut short by an interrupt, once its partial
+// results are written.
+var errInterrupted = errors.New("interrupted; results are partial")
+
+// interrupts returns a context cancelled by the first interrupt. Tests
+// replace it to interrupt runs.
+var interrupts = func() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+// maxKept is the most processes a workload may have for its results to be
+// cached or recorded in history. Past it, each run would add hundreds of
+// megabytes to either, and rescheduling is quicker than reading them back.
+const maxKept = 100_000
+
+func main() {
+	if err := run(os.Args, os.Stdin, os.Stdout, os.Stderr); errors.Is(err, flag.ErrHelp) {
+		return
+	} else if err != nil {
+		var failed *scheduler.AssertionError
+		if errors.As(err, &failed) {
+			_, _ = fmt.Fprint(os.Stderr, failed.Dump)
+		}
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+// run parses the command line in args and writes the schedules to stdout,
+// logging to stderr. Interactive modes read commands from stdin.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) > 1 {
+		if cmd, ok := commands[args[1]]; ok {
+			return cmd(args[1:], stdin, stdout, stderr)
+		}
+	}
+
+	s, err := parseFlags(args, stderr)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: s.level})))
+	return schedule(s, stdin, stdout)
+}
+
+// schedule runs every algorithm configured by s over the workload it names
+// and writes the results to stdout.
+func schedule(s settings, stdin io.Reader, stdout io.Writer) (err error) {
+	defer func() {
+		// Under -assert the first inconsistency ends the run.
+		if v := recover(); v != nil {
+			failed, ok := v.(*scheduler.AssertionError)
+			if !ok {
+				panic(v)
+			}
+			err = failed
+		}
+	}()
+	sim, processes, err := setup(s)
+	if err != nil {
+		return err
+	}
+
+	// An interrupt stops the run in progress, whose partial result is still
+	// written, and skips the algorithms after it. Stepping is left to quit
+	// by its own command.
+	ctx := context.Background()
+	if !s.step {
+		var stop context.CancelFunc
+		ctx, stop = interrupts()
+		defer stop()
+	}
+	interrupted := func(res scheduler.Result) bool {
+		return ctx.Err() != nil && len(res.Stats) < len(processes)
+	}
+
+	opts := render.Options{Tick: s.tick, Style: s.style, GanttLimit: s.ganttLimit, NoGantt: s.cfg.NoGantt, Window: s.window}
+	opts.Timeline, opts.Suspensions = s.chart == chartTimeline, s.cfg.Suspensions
+	opts.FairShare, opts.Speeds, opts.CPUs = s.cfg.FairShare, s.cfg.Speeds, s.cfg.CPUs
+	var events *eventLog
+	if s.events != "" {
+		f, err := os.Create(s.events)
+		if err != nil {
+			return fmt.Errorf("%v: error creating events file", err)
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				slog.Warn("error closing events file", "file", s.events, "err", err)
+			}
+		}()
+		events = newEventLog(f)
+		events.window = s.window
+	}
+	var resultCache *cache.Cache
+	// Cached results were never checked by -assert.
+	if !s.noCache && !s.cfg.Assert && len(processes) <= maxKept {
+		if dir := cache.DefaultDir(); dir != "" {
+			resultCache = cache.New(dir)
+		}
+	}
+	var st *stepper
+	if s.step {
+		st = newStepper(stdin, stdout, opts, len(processes))
+		st.events = events
+	}
+	var pb *player
+	if s.playback > 0 {
+		pb = newPlayer(stdout, opts, s.playback)
+	}
+	var lines *render.Lines
+	if s.format == formatJSONL {
+		lines = render.NewLines(stdout)
+	}
+	exact := exactBursts(processes)
+	unboosted, err := withoutBoost(sim, s)
+	if err != nil {
+		return err
+	}
+	var results []render.Named
+	partial := false
+	for _, name := range s.cfg.Algorithms {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return err
+		}
+		start := time.Now()
+		var res scheduler.Result
+		if st != nil {
+			if res, err = st.schedule(sim, a, processes); errors.Is(err, errQuit) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+		} else if lines != nil {
+			res = sim.Stream(ctx, a, processes, lines.Sink(a.Name))
+			if err := lines.Summary(a.Name, res, interrupted(res)); err != nil {
+				return err
+			}
+		} else if s.stream {
+			render.Title(stdout, a.Title)
+			rows := render.NewScheduleRows(stdout, processes, opts)
+			res = sim.Stream(ctx, a, processes, scheduler.Sink{Stat: rows.Row})
+			if err := rows.Close(res); err != nil {
+				return err
+			}
+			switch {
+			case opts.NoGantt:
+			case opts.Timeline:
+				render.Timeline(stdout, res, opts)
+			default:
+				render.Gantt(stdout, res.Gantt, opts)
+			}
+		} else if pb != nil {
+			res = pb.schedule(ctx, sim, a, processes)
+		} else if events != nil {
+			if res, err = events.schedule(ctx, sim, a, processes); err != nil {
+				return err
+			}
+		} else {
+			var hit bool
+			if res, hit, err = resultCache.Schedule(ctx, sim, a, processes); err != nil {
+				slog.Warn("error caching result", "algorithm", a.Name, "err", err)
+			}
+			if hit {
+				slog.Debug("reused cached result", "algorithm", a.Name)
+			}
+		}
+		slog.Debug("scheduled", "algorithm", a.Name, "slices", len(res.Gantt), "elapsed", time.Since(start))
+		if interrupted(res) {
+			partial = true
+			n := render.Named{Algorithm: a.Name, Title: a.Title + " (partial)", Result: res, Partial: true}
+			results = append(results, n)
+			if s.format == formatText {
+				if !s.stream {
+					render.Report(stdout, n.Title, res, opts)
+				}
+				_, _ = fmt.Fprintf(stdout, "Interrupted with %d of %d processes completed.\n\n", len(res.Stats), len(processes))
+			}
+			break
+		}
+		if s.verify {
+			if err := scheduler.VerifySpeeds(processes, res, s.cfg.Speeds); err != nil {
+				return fmt.Errorf("verifying %s: %w", a.Name, err)
+			}
+		}
+		if s.crossCheck {
+			if err := crossCheck(sim, a, processes, res); err != nil {
+				return err
+			}
+		}
+		results = append(results, render.Named{Algorithm: a.Name, Title: a.Title, Result: res})
+		if s.format == formatText && !s.stream {
+			render.Report(stdout, a.Title, res, opts)
+			render.Windowed(stdout, res, opts)
+			render.Shares(stdout, res, opts)
+			render.Deadlines(stdout, res, opts)
+			render.Cores(stdout, res, opts)
+			if exact != nil {
+				render.Estimates(stdout, res, sim.Schedule(a, exact), opts)
+			}
+			if unboosted != nil {
+				render.Boost(stdout, res, unboosted.Schedule(a, processes), opts)
+			}
+		}
+	}
+	if partial {
+		slog.Debug("not recording interrupted run in history")
+	} else if s.history != "" && len(processes) <= maxKept {
+		record(s, processes, results)
+	} else if s.history != "" {
+		slog.Debug("not recording run in history", "processes", len(processes), "max", maxKept)
+	}
+
+	switch s.format {
+	case formatJSON:
+		err = render.JSON(stdout, results, opts)
+	case formatCSV:
+		err = render.GanttCSV(stdout, results, opts)
+	case formatSVG:
+		err = render.SVG(stdout, results, opts)
+	case formatHTML:
+		err = render.HTML(stdout, results, opts)
+	case formatPlantUML:
+		err = render.PlantUML(stdout, results, opts)
+	}
+	if err == nil && partial {
+		err = errInterrupted
+	}
+	return err
+}
+
+// exactBursts returns processes with their burst estimates dropped, so that
+// schedulers know every burst, or nil if none has an estimate.
+func exactBursts(processes []scheduler.Process) []scheduler.Process {
+	if !slices.ContainsFunc(processes, func(p scheduler.Process) bool { return p.Estimate != 0 }) {
+		return nil
+	}
+	exact := slices.Clone(processes)
+	for i := range exact {
+		exact[i].Estimate = 0
+	}
+	return exact
+}
+
+// withoutBoost returns a simulator like sim but for Options.ResumeBoost, to
+// show what the boost changed, or nil if there is nothing to show.
+func withoutBoost(sim *scheduler.Simulator, s settings) (*scheduler.Simulator, error) {
+	o := sim.Options()
+	if !o.ResumeBoost || len(o.Suspensions) == 0 || o.NoGantt || s.format != formatText || s.stream {
+		return nil, nil
+	}
+	o.ResumeBoost = false
+	return scheduler.NewSimulator(scheduler.WithOptions(o))
+}
+
+// crossCheck fails with oracle.ErrMismatch if res, the result of a over
+// processes, is not the oracle's.
+func crossCheck(sim *scheduler.Simulator, a scheduler.Algorithm, processes []scheduler.Process, res scheduler.Result) error {
+	want, err := oracle.Schedule(a.Name, processes, sim.Options())
+	if err != nil {
+		return fmt.Errorf("cross-checking %s: %w", a.Name, err)
+	}
+	if diffs := oracle.Diff(res, want); diffs != nil {
+		return fmt.Errorf("%w: %s: %s", oracle.ErrMismatch, a.Name, strings.Join(diffs, "; "))
+	}
+	slog.Debug("cross-checked", "algorithm", a.Name)
+	return nil
+}
+
+// setup builds the simulator configured by s and loads the workload it names.
+func setup(s settings) (*scheduler.Simulator, []scheduler.Process, error) {
+	sim, err := scheduler.NewSimulator(scheduler.WithOptions(s.cfg.Options))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+
+	if s.example != "" {
+		if s.tick > 0 {
+			return nil, nil, fmt.Errorf("%w: examples are given in bare ticks", ErrInvalidArgs)
+		}
+		processes, err := examples.Load(s.example)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		slog.Info("loaded processes", "example", s.example, "count", len(processes))
+		return sim, processes, nil
+	}
+
+	// CLI args
+	f, closeFile, err := openProcessingFile(s.args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeFile()
+
+	// Load and parse processes
+	var processes []scheduler.Process
+	if s.tick > 0 {
+		processes, err = input.LoadDurations(f, s.tick)
+	} else {
+		processes, err = input.Load(f)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	slog.Info("loaded processes", "file", f.Name(), "count", len(processes))
+	warnDuplicatePIDs(processes)
+	warnUnknownSuspensions(s.cfg.Suspensions, processes)
+	if err := checkHorizon(s, processes); err != nil {
+		return nil, nil, err
+	}
+	if err := s.cfg.FCFS.Check(processes); err != nil {
+		return nil, nil, fmt.Errorf("%w; sort the file or drop -fcfs-order strict", err)
+	}
+	return sim, processes, nil
+}
+
+// checkHorizon fails if times could overflow scheduling processes, unless
+// -saturate allows them to be held at the limit instead.
+func checkHorizon(s settings, processes []scheduler.Process) error {
+	if _, err := scheduler.Horizon(processes, s.cfg.Options); err != nil {
+		if !s.saturate {
+			return fmt.Errorf("%w; pass -saturate to run it with times held at the limit", err)
+		}
+		slog.Warn("times may saturate", "err", err)
+	}
+	return nil
+}
+
+func openProcessingFile(args ...string) (*os.File, func(), error) {
+	if len(args) != 2 {
+		return nil, nil, fmt.Errorf("%w: must give a scheduling file to process", ErrInvalidArgs)
+	}
+	// Read in CSV process CSV file
+	f, err := os.Open(args[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%v: error opening scheduling file", err)
+	}
+	slog.Debug("opened scheduling file", "file", args[1])
+	closeFn := func() {
+		if err := f.Close(); err != nil {
+			slog.Warn("error closing scheduling file", "file", args[1], "err", err)
+		}
+	}
+
+	return f, closeFn, nil
+}
+
+// warnUnknownSuspensions logs a warning for every process suspended that is
+// not in the workload, as the suspension changes nothing.
+func warnUnknownSuspensions(suspensions []scheduler.Suspension, processes []scheduler.Process) {
+	for _, s := range suspensions {
+		if !slices.ContainsFunc(processes, func(p scheduler.Process) bool { return p.ProcessID == s.PID }) {
+			slog.Warn("suspended process not in the workload", "pid", s.PID, "from", s.From)
+		}
+	}
+}
+
+// warnDuplicatePIDs logs a warning for every process ID used more than once,
+// since the Gantt chart cannot tell such processes apart.
+func warnDuplicatePIDs(processes []scheduler.Process) {
+	seen := make(map[int64]bool, len(processes))
+	for _, p := range processes {
+		if seen[p.ProcessID] {
+			slog.Warn("duplicate process ID", "pid", p.ProcessID)
+		}
+		seen[p.ProcessID] = true
+	}
+}
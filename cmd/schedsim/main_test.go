@@ -0,0 +1,1548 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"p1/internal/bundle"
+	"p1/internal/cache"
+	"p1/internal/examples"
+	"p1/internal/experiment"
+	"p1/internal/grade"
+	"p1/internal/history"
+	"p1/internal/input"
+	"p1/internal/oracle"
+	"p1/internal/paging"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+func TestMain(m *testing.M) {
+	// Keep tests from recording runs in the user's history or cache.
+	os.Setenv(history.EnvPath, "")
+	os.Setenv(cache.EnvDir, "")
+	os.Exit(m.Run())
+}
+
+func TestOpenProcessingFile(t *testing.T) {
+	if _, _, err := openProcessingFile("schedsim"); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+
+	f, closeFile, err := openProcessingFile("schedsim", "../../example_processes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeFile()
+	if f == nil {
+		t.Fatal("nil file")
+	}
+}
+
+func TestRunLogLevel(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "../../example_processes.csv"}, nil, &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("unexpected log output at default level:\n%s", stderr.String())
+	}
+	quiet := stdout.String()
+
+	stdout.Reset()
+	if err := run([]string{"schedsim", "-log-level", "debug", "../../example_processes.csv"}, nil, &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stderr.String(), "level=DEBUG") {
+		t.Errorf("missing debug logs:\n%s", stderr.String())
+	}
+	if stdout.String() != quiet {
+		t.Error("logging changed the schedule output")
+	}
+
+	if err := run([]string{"schedsim", "-log-level", "loud", "../../example_processes.csv"}, nil, &stdout, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"algorithms": ["rr", "mlfq"], "rr": {"quantum": 3}, "cpus": 2}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := parseFlags([]string{"schedsim", "-config", path, "-quantum", "7", "-mlfq-quanta", "1,2", "in.csv"}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.cfg.Algorithms; !reflect.DeepEqual(got, []string{"rr", "mlfq"}) {
+		t.Errorf("algorithms = %v, want those from the config file", got)
+	}
+	if s.cfg.RR.Quantum != 7 {
+		t.Errorf("quantum = %d, want the flag to win over the config file", s.cfg.RR.Quantum)
+	}
+	if s.cfg.CPUs != 2 {
+		t.Errorf("cpus = %d, want 2 from the config file", s.cfg.CPUs)
+	}
+	if want := (scheduler.MLFQParams{Levels: 2, Quanta: []int64{1, 2}}); !reflect.DeepEqual(s.cfg.MLFQ, want) {
+		t.Errorf("mlfq = %+v, want %+v", s.cfg.MLFQ, want)
+	}
+	if !reflect.DeepEqual(s.args, []string{"schedsim", "in.csv"}) {
+		t.Errorf("args = %v", s.args)
+	}
+
+	if _, err := parseFlags([]string{"schedsim", "-algorithms", "fcfs,nope"}, io.Discard); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestRunJSONConvert(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-format", "json", "-algorithms", "rr", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "results.json")
+	if err := os.WriteFile(path, out.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var converted bytes.Buffer
+	if err := run([]string{"schedsim", "convert", path}, nil, &converted, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if converted.String() != out.String() {
+		t.Errorf("converting a current document changed it:\n%s\nwant:\n%s", converted.String(), out.String())
+	}
+
+	if err := run([]string{"schedsim", "-format", "yaml", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestRunStep(t *testing.T) {
+	args := []string{"schedsim", "-step", "-algorithms", "rr", "../../example_processes.csv"}
+	var plain, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-algorithms", "rr", "../../example_processes.csv"}, nil, &plain, &stderr); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := run(args, strings.NewReader("\nrun to t=10\nc\n"), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"t=0  P1 arrives", "t=0  dispatch P1 on CPU 0", "t=10  preempt P2 on CPU 0", "ready: P3 (6 left, waited 4), P2 (4 left, waited 2)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "t=3  P2 arrives") {
+		t.Errorf("run to t=10 stopped early:\n%s", out.String())
+	}
+	if !strings.HasSuffix(out.String(), plain.String()) {
+		t.Errorf("stepping changed the report:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := run(args, strings.NewReader("q\n"), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "Schedule table") {
+		t.Errorf("quitting still printed the report:\n%s", out.String())
+	}
+}
+
+func TestParseRunTo(t *testing.T) {
+	st := &stepper{tick: time.Millisecond}
+	for in, want := range map[string]int64{"run to t=50": 50, "t 7": 7, "to t = 3": 3, "t=20ms": 20} {
+		if got, err := st.parseRunTo(in); err != nil || got != want {
+			t.Errorf("%q: got %d, %v, want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"run", "go to 5", "t=soon"} {
+		if _, err := st.parseRunTo(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+func TestREPL(t *testing.T) {
+	script := "add 4 2 0\nset 3 burst 12\nrm 1\nopt quantum 3\nrun rr\ncompare fcfs\ncompare\nshow\nset 9 burst 1\nopt cpus 0\nquit\n"
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "repl", "../../example_processes.csv"}, strings.NewReader(script), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"loaded 3 processes",
+		"Round-robin",
+		"| rr        |",
+		"| fcfs      |",
+		"|  3 |    12 |       6 |        3 |",
+		"error: no process 9",
+		"error: invalid option",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "|  1 |     5 |") {
+		t.Errorf("removed process still shown:\n%s", out.String())
+	}
+}
+
+func TestRunEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-events", path, "-algorithms", "fcfs,sjf", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if want := `{"algorithm":"fcfs","kind":"arrive","time":0,"pid":1,"cpu":0}`; lines[0] != want {
+		t.Errorf("first line = %s, want %s", lines[0], want)
+	}
+	var last struct {
+		Algorithm string
+		scheduler.Event
+	}
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &last); err != nil {
+		t.Fatal(err)
+	}
+	if last.Algorithm != "sjf" || last.Kind != scheduler.EventComplete || last.Time != 20 {
+		t.Errorf("last line = %s", lines[len(lines)-1])
+	}
+}
+
+func TestRunWindow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-history", "", "-events", path, "-algorithms", "fcfs", "-from", "5", "-to", "14", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Gantt schedule from 5 to 14\n|   2   |\n5\t14\n", "Window from 5 to 14\n", "|  3 |       0 |       8 |            |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	for _, line := range lines {
+		var ev scheduler.Event
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatal(err)
+		}
+		if ev.Time < 5 || ev.Time > 14 {
+			t.Errorf("event outside the window: %s", line)
+		}
+	}
+	if len(lines) != 5 {
+		t.Errorf("%d events in the window, want 5:\n%s", len(lines), b)
+	}
+
+	for _, args := range [][]string{{"-from", "5", "-to", "5"}, {"-from", "-1"}, {"-to", "soon"}} {
+		args = append(append([]string{"schedsim"}, args...), "../../example_processes.csv")
+		if err := run(args, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%v: err = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+}
+
+func TestRunInterrupted(t *testing.T) {
+	defer func(f func() (context.Context, context.CancelFunc)) { interrupts = f }(interrupts)
+	interrupts = func() (context.Context, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		return ctx, cancel
+	}
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-history", "", "-algorithms", "fcfs,rr", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, errInterrupted) {
+		t.Fatalf("err = %v, want %v", err, errInterrupted)
+	}
+	for _, want := range []string{"First-come, first-serve (partial)", "Interrupted with 0 of 3 processes completed.\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "Round-robin") {
+		t.Errorf("ran on after the interrupt:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "-history", "", "-format", "json", "-algorithms", "fcfs,rr", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, errInterrupted) {
+		t.Fatalf("err = %v, want %v", err, errInterrupted)
+	}
+	var doc render.Document
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Results) != 1 || !doc.Results[0].Partial {
+		t.Errorf("results = %+v, want one partial result", doc.Results)
+	}
+}
+
+func TestRunResumeBoost(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "interventions")
+	if err := os.WriteFile(path, []byte("t=4 suspend pid=2\nt=7 resume pid=2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-history", "", "-resume-boost", "-interventions", path, "-algorithms", "sjf", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if want := "processes waited 0.00 on average to run again instead of 5.00 without it (resumptions: 1)\n"; !strings.Contains(out.String(), want) {
+		t.Errorf("output missing %q:\n%s", want, out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "-history", "", "-interventions", path, "-algorithms", "sjf", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "Resume boost") {
+		t.Errorf("boost reported without -resume-boost:\n%s", out.String())
+	}
+}
+
+func TestRunDeadlines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deadlines.csv")
+	if err := os.WriteFile(path, []byte("id,burst,arrival,deadline\n1,6,0,10\n2,2,1,4\n3,3,2,0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-history", "", "-algorithms", "fcfs,sjf", "-table-style", "plain", path}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	// First-come, first-serve runs P2 until 8, past its deadline, while
+	// shortest-job-first runs it straight away but P1 until 11.
+	for _, want := range []string{
+		"Deadlines: 1 of 2 processes tardy, tardiness 4 in all and 4 at most, lateness 0.00 on average\n",
+		" 2         4     8         4\n",
+		"Deadlines: 1 of 2 processes tardy, tardiness 1 in all and 1 at most, lateness 0.00 on average\n",
+		" 1        10    11         1\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunSpeeds(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-history", "", "-verify", "-cross-check", "-algorithms", "fcfs", "-speeds", "2,1", "-table-style", "plain", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	// P1 and P2 run on the faster CPU 0 in 3 and 5 ticks, and P3, arriving
+	// while P2 runs, on CPU 1.
+	for _, want := range []string{
+		" 2         1      9        3        0           5           8\n",
+		"Core assignment\n",
+		"  0      2  1, 2          8       66.67%\n",
+		"  1      1          3     6       50.00%\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if err := run([]string{"schedsim", "-history", "", "-speeds", "2,1", "-cpus", "3", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("speeds of 2 of 3 CPUs: err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestRunShares(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "groups.csv")
+	if err := os.WriteFile(path, []byte("id,burst,arrival,group\n1,8,0,web\n2,8,0,batch\n3,4,2,web\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-history", "", "-algorithms", "fairshare", "-quantum", "1", "-shares", "web=3072", "-table-style", "plain", path}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Group shares of the 16 of CPU time while groups contended\n",
+		"batch          1   1024      25.00%    25.00%",
+		"web            2   3072      75.00%    75.00%",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	for _, shares := range []string{"web", "web=x", "web=0", "web=2,web=3"} {
+		if err := run([]string{"schedsim", "-history", "", "-shares", shares, path}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("-shares %s: err = %v, want %v", shares, err, ErrInvalidArgs)
+		}
+	}
+}
+
+func TestRunCSV(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-history", "", "-format", "csv", "-algorithms", "fcfs,rr", "-quantum", "4", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if lines[0] != "algorithm,pid,start,stop,cpu" || lines[1] != "fcfs,1,0,5,0" || !strings.HasPrefix(lines[len(lines)-1], "rr,") {
+		t.Errorf("output:\n%s", out.String())
+	}
+
+	if err := run([]string{"schedsim", "-format", "csv", "-no-gantt", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestRunTimeline(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "interventions")
+	if err := os.WriteFile(path, []byte("t=3 suspend pid=3\nt=9 resume pid=3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-history", "", "-chart", "timeline", "-interventions", path, "-algorithms", "rr", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	want := "Timeline\n" +
+		"P1 |█████               |\n" +
+		"P2 |   ··█████·····████ |\n" +
+		"P3 |      ~~~·█████····█|\n"
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("output missing %q:\n%s", want, out.String())
+	}
+	if strings.Contains(out.String(), "Gantt schedule") {
+		t.Errorf("output has a Gantt chart as well:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "-history", "", "-format", "plantuml", "-interventions", path, "-algorithms", "fcfs,rr", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out.String(), "@startuml\n"); n != 2 || !strings.Contains(out.String(), "\n@6\np2 is Blocked\n") {
+		t.Errorf("plantuml output:\n%s", out.String())
+	}
+
+	if err := run([]string{"schedsim", "-chart", "pie", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestRunStream(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-format", "jsonl", "-algorithms", "fcfs,rr", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if want := `{"algorithm":"fcfs","slice":{"pid":1,"start":0,"stop":5,"cpu":0}}`; lines[0] != want {
+		t.Errorf("first line = %s, want %s", lines[0], want)
+	}
+	if want := `{"algorithm":"rr","summary":{"averageWait":5,"averageTurnaround":11.666666666666666,"throughput":0.15,"contextSwitches":4}}`; lines[len(lines)-1] != want {
+		t.Errorf("last line = %s, want %s", lines[len(lines)-1], want)
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "-stream", "-algorithms", "fcfs", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Schedule table\n", "|  2 |        1 |     9 |       3 |       2 |         11 |         14 |\n", "   3.33   |", "Gantt schedule\n|   1   |   2   |   3   |\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	if err := run([]string{"schedsim", "-stream", "-format", "json", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("-stream -format json: err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestRunPlayback(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-playback", "1e6x", "-algorithms", "rr", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Playing back Round-robin at 1e+06x.\nt=0  P1 arrives\n", "t=10  preempt P2 on CPU 0\n", "Schedule table\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	// At 2x, a tick lasting a second, the first-come, first-serve events
+	// at 10, 11, 15 and 18 come 0.5s, 2s and 1.5s apart.
+	sim, err := scheduler.NewSimulator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		clock time.Time
+		slept []time.Duration
+	)
+	out.Reset()
+	p := newPlayer(&out, render.Options{}, 2)
+	p.now = func() time.Time { return clock }
+	p.sleep = func(d time.Duration) {
+		slept = append(slept, d)
+		clock = clock.Add(d)
+	}
+	a, _ := scheduler.LookupAlgorithm("fcfs")
+	p.schedule(context.Background(), sim, a, []scheduler.Process{{ProcessID: 1, BurstDuration: 5, ArrivalTime: 10}, {ProcessID: 2, BurstDuration: 3, ArrivalTime: 11}})
+	if want := []time.Duration{500 * time.Millisecond, 2 * time.Second, 1500 * time.Millisecond}; !reflect.DeepEqual(slept, want) {
+		t.Errorf("slept %v, want %v", slept, want)
+	}
+	if want := "t=15  complete P1 on CPU 0\nt=15  dispatch P2 on CPU 0\nt=18  complete P2 on CPU 0\n"; !strings.HasSuffix(out.String(), want) {
+		t.Errorf("output:\n%s\nwant suffix:\n%s", out.String(), want)
+	}
+
+	for _, args := range [][]string{
+		{"schedsim", "-playback", "0x", "../../example_processes.csv"},
+		{"schedsim", "-playback", "fast", "../../example_processes.csv"},
+		{"schedsim", "-playback", "10x", "-format", "json", "../../example_processes.csv"},
+		{"schedsim", "-playback", "10x", "-step", "../../example_processes.csv"},
+	} {
+		if err := run(args, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%v: err = %v, want %v", args[1:], err, ErrInvalidArgs)
+		}
+	}
+}
+
+func TestRunNoGantt(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-no-gantt", "-algorithms", "rr", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "Gantt") || !strings.Contains(out.String(), "Schedule table") {
+		t.Errorf("output:\n%s", out.String())
+	}
+}
+
+func TestRunCPUs(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-history", "", "-algorithms", "rr", "-cpus", "2", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"CPU 0\t|       1       |   -   |   3   |", "CPU 1\t|   -   |           2           |", "Utilization: CPU 0 91.67%, CPU 1 75.00%"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	for format, want := range map[string]string{"svg": "<svg", "html": "<!DOCTYPE html>"} {
+		out.Reset()
+		if err := run([]string{"schedsim", "-history", "", "-format", format, "-algorithms", "fcfs,rr", "-cpus", "2", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(out.String(), want) || strings.Count(out.String(), ">CPU 1</text>") != 2 {
+			t.Errorf("%s output:\n%s", format, out.String())
+		}
+	}
+}
+
+func TestRunOverflow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "huge.csv")
+	if err := os.WriteFile(path, []byte("1,9223372036854775000,0\n2,9223372036854775000,0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-algorithms", "fcfs", path}, nil, &out, &stderr); !errors.Is(err, scheduler.ErrOverflow) {
+		t.Errorf("err = %v, want %v", err, scheduler.ErrOverflow)
+	}
+	if err := run([]string{"schedsim", "-saturate", "-algorithms", "fcfs", path}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "| 9223372036854775807 |") {
+		t.Errorf("output:\n%s", out.String())
+	}
+}
+
+func TestRunFCFSOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "unsorted.csv")
+	if err := os.WriteFile(path, []byte("1,3,4\n2,2,0\n3,1,0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for order, want := range map[string]string{
+		"arrival": "|   2   |   3   |   1   |",
+		"given":   "|   1   |   2   |   3   |",
+	} {
+		var out, stderr bytes.Buffer
+		if err := run([]string{"schedsim", "-history", "", "-algorithms", "fcfs", "-fcfs-order", order, path}, nil, &out, &stderr); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("order %s output:\n%s", order, out.String())
+		}
+	}
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-algorithms", "fcfs", "-fcfs-order", "strict", path}, nil, &out, &stderr); !errors.Is(err, scheduler.ErrUnsorted) {
+		t.Errorf("err = %v, want %v", err, scheduler.ErrUnsorted)
+	}
+}
+
+func TestRunAgingRate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "starving.csv")
+	if err := os.WriteFile(path, []byte("1,2,0\n2,5,0\n3,2,2\n4,2,4\n5,2,6\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for rate, want := range map[string]string{
+		"":  "|   1   |   3   |   4   |   5   |   2   |",
+		"1": "|   1   |   3   |   2   |   4   |   5   |",
+	} {
+		args := []string{"schedsim", "-history", "", "-algorithms", "sjf", path}
+		if rate != "" {
+			args = append(args[:len(args)-1], "-aging-rate", rate, path)
+		}
+		var out, stderr bytes.Buffer
+		if err := run(args, nil, &out, &stderr); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("aging rate %q output:\n%s", rate, out.String())
+		}
+	}
+}
+
+func TestRunEstimates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "estimated.csv")
+	if err := os.WriteFile(path, []byte("id,burst,arrival,estimate\n1,8,0,2\n2,3,0,6\n3,4,1,4\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-history", "", "-algorithms", "fcfs,sjf", path}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"|   1   |   3   |   2   |",
+		"Burst estimates: 3 of 3 processes estimated, off by 3.00 on average (60.00% of their bursts)",
+		"With exact bursts: average wait 6.00 instead of 6.00, turnaround 11.00 instead of 11.00",
+		"With exact bursts: average wait 3.00 instead of 6.33, turnaround 8.00 instead of 11.33",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "-history", "", "-algorithms", "sjf", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "Burst estimates") {
+		t.Errorf("estimates reported for a workload without them:\n%s", out.String())
+	}
+}
+
+func TestRunInterventions(t *testing.T) {
+	dir := t.TempDir()
+	workload, interventions := filepath.Join(dir, "workload.csv"), filepath.Join(dir, "interventions.txt")
+	if err := os.WriteFile(workload, []byte("1,5,0\n2,3,1\n3,2,2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(interventions, []byte("t=2 suspend pid=1\nt=9 resume pid=1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-history", "", "-algorithms", "fcfs", "-interventions", interventions, workload}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if want := "|   1   |   2   |   3   |   1   |\n0\t2\t5\t9\t12"; !strings.Contains(out.String(), want) {
+		t.Errorf("output missing %q:\n%s", want, out.String())
+	}
+
+	if err := os.WriteFile(interventions, []byte("t=2 suspend pid=1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := run([]string{"schedsim", "-history", "", "-interventions", interventions, workload}, nil, &out, &stderr)
+	if !errors.Is(err, ErrInvalidArgs) || !errors.Is(err, input.ErrInvalidEvent) {
+		t.Errorf("suspended for good: err = %v, want %v and %v", err, ErrInvalidArgs, input.ErrInvalidEvent)
+	}
+}
+
+func TestRunVerify(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-history", "", "-verify", "-algorithms", "fcfs,sjf,priority,rr,mlfq,aging", "-cpus", "2", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if err := run([]string{"schedsim", "-verify", "-no-gantt", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("with -no-gantt: err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestRunAssert(t *testing.T) {
+	var want, got, stderr bytes.Buffer
+	args := []string{"-algorithms", "fcfs,sjf,priority,rr,mlfq,aging,lottery,random", "-cpus", "2", "-switch-cost", "1", "../../example_processes.csv"}
+	if err := run(append([]string{"schedsim"}, args...), nil, &want, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(append([]string{"schedsim", "-assert"}, args...), nil, &got, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("with -assert:\n%s\nwant:\n%s", got.String(), want.String())
+	}
+}
+
+func TestRunCrossCheck(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-history", "", "-cross-check", "-algorithms", "fcfs,sjf,priority,rr,mlfq,aging", "-cpus", "2", "-switch-cost", "1", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+
+	sim, err := scheduler.NewSimulator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := scheduler.LookupAlgorithm("rr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	processes := []scheduler.Process{{ProcessID: 1, BurstDuration: 2}}
+	if err := crossCheck(sim, a, processes, scheduler.Result{Stats: make([]scheduler.Stat, 1)}); !errors.Is(err, oracle.ErrMismatch) {
+		t.Errorf("err = %v, want %v", err, oracle.ErrMismatch)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	var out, stderr bytes.Buffer
+	for _, algorithms := range []string{"fcfs", "rr,sjf"} {
+		if err := run([]string{"schedsim", "-history", path, "-algorithms", algorithms, "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	second := out.String()[strings.Index(out.String(), "Round-robin")-len("----------------------\n      "):]
+
+	out.Reset()
+	if err := run([]string{"schedsim", "history", "-history", path, "list"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "| fcfs ") || !strings.Contains(out.String(), "| rr,sjf ") {
+		t.Errorf("list:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "history", "-history", path, "render", "2"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != second {
+		t.Errorf("render 2:\n%s\nwant:\n%s", out.String(), second)
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "history", "-history", path, "show", "1"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	var r history.Run
+	if err := json.Unmarshal(out.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.ID != 1 || len(r.Processes) != 3 || r.Results[0].Algorithm != "fcfs" {
+		t.Errorf("show 1 = %+v", r)
+	}
+
+	if err := run([]string{"schedsim", "history", "-history", path, "show", "9"}, nil, &out, &stderr); !errors.Is(err, history.ErrNotFound) {
+		t.Errorf("err = %v, want %v", err, history.ErrNotFound)
+	}
+	if err := run([]string{"schedsim", "history", "-history", path, "frob"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestRunCache(t *testing.T) {
+	t.Setenv(cache.EnvDir, t.TempDir())
+	args := []string{"schedsim", "-log-level", "debug", "-algorithms", "rr", "../../example_processes.csv"}
+	var first, second, stderr bytes.Buffer
+	if err := run(args, nil, &first, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(stderr.String(), "reused cached result") {
+		t.Errorf("first run hit the cache:\n%s", stderr.String())
+	}
+	stderr.Reset()
+	if err := run(args, nil, &second, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stderr.String(), "reused cached result") {
+		t.Errorf("second run missed the cache:\n%s", stderr.String())
+	}
+	if first.String() != second.String() {
+		t.Error("cached output differs")
+	}
+
+	stderr.Reset()
+	if err := run(append([]string{"schedsim", "-no-cache"}, args[1:]...), nil, &second, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(stderr.String(), "reused cached result") {
+		t.Errorf("-no-cache hit the cache:\n%s", stderr.String())
+	}
+}
+
+func TestBundle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.zip")
+	var want, out, stderr bytes.Buffer
+	args := []string{"-algorithms", "rr,mlfq", "-quantum", "3", "../../example_processes.csv"}
+	if err := run(append([]string{"schedsim"}, args...), nil, &want, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(append(append([]string{"schedsim", "export-bundle"}, args...), path), nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run([]string{"schedsim", "import-bundle", "-verify", path}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != want.String() {
+		t.Errorf("import-bundle:\n%s\nwant:\n%s", out.String(), want.String())
+	}
+	if !strings.Contains(stderr.String(), "verified bundle") {
+		t.Errorf("stderr: %s", stderr.String())
+	}
+
+	if err := run([]string{"schedsim", "export-bundle", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("no output: err = %v, want %v", err, ErrInvalidArgs)
+	}
+	if err := run([]string{"schedsim", "import-bundle", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, bundle.ErrInvalid) {
+		t.Errorf("not a bundle: err = %v, want %v", err, bundle.ErrInvalid)
+	}
+}
+
+func TestGrade(t *testing.T) {
+	dir := t.TempDir()
+	key := filepath.Join(dir, "key.json")
+	f, err := os.Create(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-format", "json", "-algorithms", "fcfs,sjf", "../../example_processes.csv"}, nil, f, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := run([]string{"schedsim", "grade", key, "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatalf("%v:\n%s", err, out.String())
+	}
+	if want := "PASS  fcfs\nPASS  sjf\n2/2 algorithms passed\n"; out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+
+	submission := filepath.Join(dir, "results.json")
+	f, err = os.Create(submission)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := run([]string{"schedsim", "-format", "json", "-algorithms", "fcfs,priority", "../../example_processes.csv"}, nil, f, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := run([]string{"schedsim", "grade", key, submission}, nil, &out, &stderr); !errors.Is(err, grade.ErrFailed) {
+		t.Errorf("err = %v, want %v", err, grade.ErrFailed)
+	}
+	if !strings.Contains(out.String(), "FAIL  sjf\n      no result submitted\n") {
+		t.Errorf("output:\n%s", out.String())
+	}
+
+	out.Reset()
+	missing := filepath.Join(dir, "missing.csv")
+	if err := run([]string{"schedsim", "grade", "-workers", "2", key, "../../example_processes.csv", submission, missing}, nil, &out, &stderr); !errors.Is(err, grade.ErrFailed) {
+		t.Errorf("err = %v, want %v", err, grade.ErrFailed)
+	}
+	for _, want := range []string{
+		"== ../../example_processes.csv\nPASS  fcfs\nPASS  sjf\n2/2 algorithms passed\n== " + submission + "\n",
+		"== " + missing + "\nERROR  ",
+		"\n1/3 submissions passed\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestQuiz(t *testing.T) {
+	dir := t.TempDir()
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "quiz", "-difficulty", "easy", "-seed", "5", "-algorithms", "fcfs,rr", dir, "ada", "alan"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if want := "ada.csv  seed 5\nalan.csv  seed 6\n"; out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+	ada, err := os.ReadFile(filepath.Join(dir, "ada.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	alan, err := os.ReadFile(filepath.Join(dir, "alan.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(ada, alan) {
+		t.Error("students got the same problem")
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "grade", filepath.Join(dir, "ada.key.json"), filepath.Join(dir, "ada.csv")}, nil, &out, &stderr); err != nil {
+		t.Errorf("grading a problem against its own key: %v\n%s", err, out.String())
+	}
+	if txt, err := os.ReadFile(filepath.Join(dir, "alan.key.txt")); err != nil || !strings.Contains(string(txt), "Round-robin") {
+		t.Errorf("text key: %v\n%s", err, txt)
+	}
+}
+
+func TestExamples(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "examples"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range examples.List() {
+		if !strings.Contains(out.String(), "| "+e.Name+" ") {
+			t.Errorf("list is missing %s:\n%s", e.Name, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "examples", "run", "-algorithms", "fcfs", "convoy"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "First-come, first-serve") || !strings.Contains(out.String(), "|  5 |") {
+		t.Errorf("run convoy:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "examples", "show", "convoy"}, nil, &out, &stderr); err != nil || !strings.HasPrefix(out.String(), "1,40,0,3\n") {
+		t.Errorf("show convoy: %v\n%s", err, out.String())
+	}
+	if err := run([]string{"schedsim", "examples", "run", "nope"}, nil, &out, &stderr); !errors.Is(err, examples.ErrUnknown) {
+		t.Errorf("err = %v, want %v", err, examples.ErrUnknown)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "explain", "-quantum", "2", "fcfs", "rr", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"First-come, first-serve vs Round-robin", "They first diverge at 4: ", "The gap comes from:"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if err := run([]string{"schedsim", "explain", "fcfs", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestSnapshotReplay(t *testing.T) {
+	var want, snap, got, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-algorithms", "mlfq", "-mlfq-quanta", "2,4", "../../example_processes.csv"}, nil, &want, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if err := run([]string{"schedsim", "snapshot", "-mlfq-quanta", "2,4", "mlfq", "7", "../../example_processes.csv"}, nil, &snap, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, snap.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run([]string{"schedsim", "replay", path}, nil, &got, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("replayed:\n%s\nwant:\n%s", got.String(), want.String())
+	}
+
+	if err := run([]string{"schedsim", "snapshot", "mlfq", "soon", "../../example_processes.csv"}, nil, &snap, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("bad time: err = %v, want %v", err, ErrInvalidArgs)
+	}
+	if err := run([]string{"schedsim", "replay", path, "../../example_processes.csv", "extra"}, nil, &got, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("two workloads: err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "sweep", "-quanta", "2-4", "-csv", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 7 || lines[0] != "quantum,algorithm,average_wait,average_turnaround,context_switches" || !strings.HasPrefix(lines[2], "2,mlfq,") {
+		t.Errorf("csv:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "sweep", "-algorithms", "rr", "-quanta", "5", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "|       5 | rr ") {
+		t.Errorf("table:\n%s", out.String())
+	}
+	if err := run([]string{"schedsim", "sweep", "-algorithms", "sjf", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+	if err := run([]string{"schedsim", "sweep", "-quanta", "9-3", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestPareto(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "pareto", "-algorithms", "fcfs,sjf,priority", "-metrics", "wait,switches", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"| fcfs      | 3.33 |        2 | yes ", "| priority  | 5.67 |        3 | no, dominated by fcfs, sjf |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if err := run([]string{"schedsim", "pareto", "-metrics", "deadlines", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+
+	// FCFS misses two deadlines when any wait is a miss, SJF only one.
+	out.Reset()
+	if err := run([]string{"schedsim", "pareto", "-algorithms", "fcfs,sjf", "-metrics", "wait,misses", "-deadline-factor", "1", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"| fcfs      | 3.33 |      2 | no, dominated by sjf |", "| sjf       | 2.67 |      1 | yes "} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if err := run([]string{"schedsim", "pareto", "-metrics", "misses", "-deadline-factor", "0.5", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "recommend", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"| minimize average wait    | sjf                          | average wait 2.67; next best fcfs at 3.33 ",
+		"| maximize fairness        | rr, mlfq, fairshare, classes | fairness index 0.9066; next best sjf at 0.9054 |",
+		"| minimize deadline misses | sjf                          | deadline misses 0 of 3; next best fcfs at 1 ",
+		"Deadlines fall at arrival plus 2 times the burst.",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if err := run([]string{"schedsim", "recommend", "-deadline-factor", "0", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestGap(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "gap", "-algorithms", "fcfs,sjf,priority", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"The best schedule waits 2.67 on average.\n", "| fcfs      |         3.33 |  25.00% |", "| sjf       |         2.67 |   0.00% |", "| priority  |         5.67 | 112.50% |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestWhatIf(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "what-if", "-change", "remove:2", "-algorithms", "fcfs,sjf", "-metrics", "wait,switches", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"What removing P2 changes\n", "| fcfs      | 3.33 → 0 (-3.33) | 2 → 1 (-1) |", "| sjf       | 2.67 → 0 (-2.67) | 3 → 1 (-2) |", "Averages without P2 are over the other processes.\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	for _, args := range [][]string{{}, {"-change", "remove:9"}, {"-change", "delay:1"}} {
+		args = append(append([]string{"schedsim", "what-if"}, args...), "../../example_processes.csv")
+		if err := run(args, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%v: err = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "w.csv")
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "generate", "-processes", "12", "-bursts", "pareto", "-io-bound", "0.5", "-phase", "4"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 13 || lines[0] != "id,burst,arrival,priority,class" {
+		t.Fatalf("workload:\n%s", out.String())
+	}
+	if err := os.WriteFile(path, out.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := run([]string{"schedsim", "-algorithms", "classes", path}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"-io-bound", "2"}, {"-bursts", "normal"}, {"w.csv"}} {
+		if err := run(append([]string{"schedsim", "generate"}, args...), nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%v: err = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+}
+
+func TestCompareTrace(t *testing.T) {
+	dir := t.TempDir()
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "-format", "csv", "-algorithms", "fcfs,rr", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	own := filepath.Join(dir, "own.csv")
+	if err := os.WriteFile(own, out.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := run([]string{"schedsim", "compare-trace", "../../example_processes.csv", own}, nil, &out, &stderr); err != nil {
+		t.Fatalf("%v:\n%s", err, out.String())
+	}
+	if want := "== trace fcfs\nSAME  fcfs\nThe trace matches fcfs.\n== trace rr\nSAME  rr\nThe trace matches rr.\n"; out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	// SJF's schedule, written out of order by a simulator of tasks and
+	// cores, and then with P2 a tick short.
+	other := filepath.Join(dir, "other.csv")
+	if err := os.WriteFile(other, []byte("task,begin,end,core\n1,0,5,0\n3,6,12,0\n2,12,20,0\n2,5,6,0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := run([]string{"schedsim", "compare-trace", "-algorithms", "fcfs,sjf", "../../example_processes.csv", other}, nil, &out, &stderr); err != nil {
+		t.Fatalf("%v:\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "SAME  sjf\nThe trace matches sjf.\n") {
+		t.Errorf("output:\n%s", out.String())
+	}
+	if err := os.WriteFile(other, []byte("task,begin,end,core\n1,0,5,0\n3,6,12,0\n2,13,20,0\n2,5,6,0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	err := run([]string{"schedsim", "compare-trace", "-algorithms", "fcfs,sjf", "../../example_processes.csv", other}, nil, &out, &stderr)
+	if !errors.Is(err, ErrTraceMismatch) {
+		t.Errorf("err = %v, want %v", err, ErrTraceMismatch)
+	}
+	for _, want := range []string{
+		"The trace does not fit the workload: result inconsistent with its Gantt chart: P2 runs for 8 in the chart, want its burst 9\n",
+		"DIFF  fcfs\n      average wait 2.667, want 3.333\n",
+		"DIFF  sjf\n      gantt slice 4 is P2 13-20 on CPU 0, want P2 12-20 on CPU 0\n",
+		"The trace matches no algorithm; sjf differs least, in 1 way.\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if err := run([]string{"schedsim", "compare-trace", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestRank(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "rank", "-algorithms", "fcfs,sjf,priority", "-weights", "wait=1,turnaround=1", "../../example_processes.csv"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"| RANK | ALGORITHM | WAIT ×1 | TURNAROUND ×1 | SCORE |", "|    1 | sjf       |    2.67 |          9.33 | 1.000 |", "|    3 | priority  |    5.67 |         12.33 | 0.000 |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if err := run([]string{"schedsim", "rank", "-weights", "wait=-1", "../../example_processes.csv"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	dir := t.TempDir()
+	workload, err := os.ReadFile("../../example_processes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "w.csv"), workload, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "matrix.json")
+	matrix := `{"workloads": ["w.csv"], "algorithms": ["fcfs", "rr"], "quanta": [2, 4], "cpus": [1, 2]}`
+	if err := os.WriteFile(path, []byte(matrix), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "matrix", "-workers", "2", path}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 7 || !strings.HasPrefix(lines[1], "w.csv,fcfs,,1,0,") || !strings.HasPrefix(lines[6], "w.csv,rr,4,2,0,") {
+		t.Errorf("csv:\n%s", out.String())
+	}
+
+	checkpoint := filepath.Join(dir, "checkpoint")
+	for range 2 {
+		var resumed bytes.Buffer
+		if err := run([]string{"schedsim", "matrix", "-checkpoint", checkpoint, path}, nil, &resumed, &stderr); err != nil {
+			t.Fatal(err)
+		}
+		if resumed.String() != out.String() {
+			t.Errorf("checkpointed csv:\n%s\nwant:\n%s", resumed.String(), out.String())
+		}
+	}
+	if err := os.WriteFile(path, []byte(`{"workloads": ["w.csv"], "algorithms": ["fcfs"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run([]string{"schedsim", "matrix", "-checkpoint", checkpoint, path}, nil, &out, &stderr); !errors.Is(err, experiment.ErrStaleCheckpoint) {
+		t.Errorf("err = %v, want %v", err, experiment.ErrStaleCheckpoint)
+	}
+}
+
+func TestDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.txt")
+	if err := os.WriteFile(path, []byte("98, 183, 37, 122, 14, 124, 65, 67\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "disk", "-head", "53", path}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Seek chart\n", "Total head movement: 640 cylinders\n", "| SCAN                     | 65, 67, 98, 122, 124, 183, 37, 14 |           331 |\n", "| C-LOOK                   | 65, 67, 98, 122, 124, 183, 14, 37 |           322 |\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "disk", "-head", "53", "-direction", "down", "-algorithms", "scan", "-format", "json"}, strings.NewReader("98 183 37 122 14 124 65 67"), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Head    int64
+		Results []struct {
+			Algorithm string
+			Movement  int64
+		}
+	}
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Head != 53 || len(doc.Results) != 1 || doc.Results[0].Algorithm != "scan" || doc.Results[0].Movement != 236 {
+		t.Errorf("json:\n%s", out.String())
+	}
+
+	for _, args := range [][]string{
+		{"schedsim", "disk", "-head", "200", path},
+		{"schedsim", "disk", "-algorithms", "elevator", path},
+		{"schedsim", "disk", "-direction", "left", path},
+	} {
+		if err := run(args, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%v: err = %v, want %v", args[1:], err, ErrInvalidArgs)
+		}
+	}
+}
+
+func TestPaging(t *testing.T) {
+	refs := "7 0 1 2 0 3 0 4 2 3 0 3 2 1 2 0 1 7 0 1\n"
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "paging", "-table-style", "borderless"}, strings.NewReader(refs), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Page faults: 15 of 20 references (75.00%), 5 hits\n", "Optimal                   9    11      45.00%\n", "fault      *  *  *  *"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "paging", "-frames", "4", "-algorithms", "lru", "-format", "json"}, strings.NewReader(refs), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		References []int64
+		Results    []struct {
+			Algorithm      string
+			Frames, Faults int
+			Steps          []struct{ Frames []int64 }
+		}
+	}
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if r := doc.Results; len(doc.References) != 20 || len(r) != 1 || r[0].Algorithm != "lru" || r[0].Frames != 4 || r[0].Faults != 8 || len(r[0].Steps[0].Frames) != 4 {
+		t.Errorf("json:\n%s", out.String())
+	}
+
+	for _, args := range [][]string{
+		{"schedsim", "paging", "-frames", "0"},
+		{"schedsim", "paging", "-algorithms", "mru"},
+		{"schedsim", "paging", "-format", "csv"},
+	} {
+		if err := run(args, strings.NewReader(refs), &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%v: err = %v, want %v", args[1:], err, ErrInvalidArgs)
+		}
+	}
+}
+
+func TestBanker(t *testing.T) {
+	state := "allocation,P0,0,1,0\nallocation,P1,2,0,0\nallocation,P2,3,0,2\nallocation,P3,2,1,1\nallocation,P4,0,0,2\n" +
+		"max,P0,7,5,3\nmax,P1,3,2,2\nmax,P2,9,0,2\nmax,P3,2,2,2\nmax,P4,4,3,3\n" +
+		"available,,3,3,2\nrequest,P1,1,0,2\nrequest,P0,0,2,0\n"
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "banker"}, strings.NewReader(state), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"The state is safe: P1, P3, P0, P2, P4\n", "| P1      | 1 0 2   | granted        | P1, P3, P0, P2, P4 |\n", "| P0      | 0 2 0   | denied: unsafe |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "banker", "-format", "json"}, strings.NewReader(state), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Safe      bool
+		Sequence  []int
+		Decisions []struct{ Outcome string }
+	}
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Safe || !reflect.DeepEqual(doc.Sequence, []int{1, 3, 0, 2, 4}) || len(doc.Decisions) != 2 || doc.Decisions[1].Outcome != "denied" {
+		t.Errorf("json:\n%s", out.String())
+	}
+
+	for _, in := range []string{state + "request,P0,8,0,0\n", "max,P0,1\n"} {
+		if err := run([]string{"schedsim", "banker"}, strings.NewReader(in), &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%q: err = %v, want %v", in, err, ErrInvalidArgs)
+		}
+	}
+}
+
+func TestDeadlock(t *testing.T) {
+	trace := "0,P1,acquire,R1\n1,P2,acquire,R2\n2,P1,request,R2\n3,P2,request,R1\n"
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "deadlock"}, strings.NewReader(trace), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Deadlock at 3: event 4, P2 requests R1, closes the cycle P2->P1->P2; deadlocked: P1, P2\n"; !strings.Contains(out.String(), want) {
+		t.Errorf("output missing %q:\n%s", want, out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "deadlock", "-format", "json"}, strings.NewReader(trace), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	var res struct {
+		Deadlocks []struct {
+			Step  int
+			Cycle []string
+		}
+	}
+	if err := json.Unmarshal(out.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if d := res.Deadlocks; len(d) != 1 || d[0].Step != 3 || !reflect.DeepEqual(d[0].Cycle, []string{"P2", "P1"}) {
+		t.Errorf("json:\n%s", out.String())
+	}
+
+	for _, in := range []string{"0,P1,release,R1\n", "0,P1,lock,R1\n"} {
+		if err := run([]string{"schedsim", "deadlock"}, strings.NewReader(in), &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%q: err = %v, want %v", in, err, ErrInvalidArgs)
+		}
+	}
+}
+
+func TestProdcons(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "prodcons", "-produce", "1", "-consume", "3", "-buffer", "2", "-items", "4"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"|   0   |   1   |   2   |   1   |   0   |\n", "| C1    | consumer |     4 |   12 |       1 |     1 |\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "prodcons", "-consume", "2,2", "-format", "json"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	var res struct {
+		Agents []struct{ Name string }
+		End    int64
+	}
+	if err := json.Unmarshal(out.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Agents) != 3 || res.Agents[2].Name != "C2" || res.End == 0 {
+		t.Errorf("json:\n%s", out.String())
+	}
+
+	for _, args := range [][]string{{"-buffer", "0"}, {"-produce", "0"}, {"-consume", "x"}, {"extra"}} {
+		if err := run(append([]string{"schedsim", "prodcons"}, args...), nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%q: err = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+}
+
+func TestPhilosophers(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "philosophers", "-duration", "30"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Deadlock at 3: P1, P2, P3, P4, P5 each hold", "| Naive (left fork first) |     0 | at 3     |        5 |\n", "| Arbitrator              |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "philosophers", "-strategies", "ordering", "-n", "3", "-format", "json"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Philosophers int
+		Results      []struct {
+			Strategy     string
+			Philosophers []struct{ Meals int }
+			Deadlock     *struct{}
+		}
+	}
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Philosophers != 3 || len(doc.Results) != 1 || len(doc.Results[0].Philosophers) != 3 || doc.Results[0].Deadlock != nil {
+		t.Errorf("json:\n%s", out.String())
+	}
+
+	for _, args := range [][]string{{"-n", "1"}, {"-strategies", "waiter"}, {"-eat", "0"}} {
+		if err := run(append([]string{"schedsim", "philosophers"}, args...), nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%q: err = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+}
+
+func TestWorkingSet(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "working-set", "-window", "3", "-format", "csv"}, strings.NewReader("1 2 1 3 3 3 4"), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if want := "reference,page,working_set_size\n1,1,1\n2,2,2\n3,1,2\n4,3,3\n5,3,2\n6,3,1\n7,4,2\n"; out.String() != want {
+		t.Errorf("csv:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "working-set"}, strings.NewReader("1,2,3"), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if want := "at most 3 pages, 2.00 on average"; !strings.Contains(out.String(), want) {
+		t.Errorf("output missing %q:\n%s", want, out.String())
+	}
+
+	for _, args := range [][]string{{"-window", "0"}, {"-format", "xml"}} {
+		if err := run(append([]string{"schedsim", "working-set"}, args...), strings.NewReader("1"), &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%q: err = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+}
+
+func TestFaultCurve(t *testing.T) {
+	belady := "1,2,3,4,1,2,5,1,2,3,4,5"
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "fault-curve", "-algorithms", "fifo"}, strings.NewReader(belady), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"|      5 |                   5 |\n", "Belady's anomaly: First-in, first-out faults 10 times with 4 frames"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "fault-curve", "-algorithms", "fifo,lru", "-max-frames", "2", "-format", "csv"}, strings.NewReader(belady), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if want := "frames,algorithm,faults,fault_rate\n1,fifo,12,1\n2,fifo,12,1\n1,lru,12,1\n2,lru,12,1\n"; out.String() != want {
+		t.Errorf("csv:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "fault-curve", "-format", "json"}, strings.NewReader(belady), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct{ Curves []paging.Curve }
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Curves) != len(paging.Algorithms()) || len(doc.Curves[0].Faults) != 5 {
+		t.Errorf("json:\n%s", out.String())
+	}
+
+	if err := run([]string{"schedsim", "fault-curve", "-algorithms", "mru"}, strings.NewReader(belady), &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func TestTLB(t *testing.T) {
+	refs := "1,2,1,3,1,2"
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "tlb", "-tlb", "2,2x1", "-table-style", "plain"}, strings.NewReader(refs), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Memory: 8 frames under Least recently used, 3 page faults of 6 references\n", "2x2:lru", "2x1:lru"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := run([]string{"schedsim", "tlb", "-tlb", "2", "-fault-time", "6ms", "-tlb-time", "0", "-memory-time", "0", "-format", "json"}, strings.NewReader(refs), &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Results []struct {
+			Hits       int
+			AccessTime time.Duration `json:"access_time"`
+		}
+	}
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Results) != 1 || doc.Results[0].Hits != 2 || doc.Results[0].AccessTime != 3*time.Millisecond {
+		t.Errorf("json:\n%s", out.String())
+	}
+
+	for _, args := range [][]string{{"-tlb", "6x4"}, {"-algorithm", "mru"}, {"-frames", "0"}} {
+		if err := run(append([]string{"schedsim", "tlb"}, args...), strings.NewReader(refs), &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("%q: err = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+}
+
+func TestBench(t *testing.T) {
+	var out, stderr bytes.Buffer
+	if err := run([]string{"schedsim", "bench", "-algorithms", "fcfs,rr", "-sizes", "10,20", "-min-time", "0"}, nil, &out, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"| fcfs      |        10 |    1 |", "| rr        |        20 |    1 |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if err := run([]string{"schedsim", "bench", "-sizes", "0"}, nil, &out, &stderr); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidArgs)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+
+	"p1/internal/experiment"
+	"p1/internal/input"
+	"p1/internal/scheduler"
+)
+
+// runMatrix runs every combination of the parameters in a matrix file, see
+// experiment.Matrix, writing one CSV row per combination.
+func runMatrix(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim matrix [-workers n] [-checkpoint file] matrix.json\nWorkloads are named relative to the matrix file.\n")
+		fs.PrintDefaults()
+	}
+	workers := fs.Int("workers", runtime.GOMAXPROCS(0), "`number` of runs to perform at once")
+	checkpoint := fs.String("checkpoint", "", "record completed runs in `file` and skip those it already holds, to resume an interrupted batch")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("%w: must give a matrix file", ErrInvalidArgs)
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("%v: error opening matrix file", err)
+	}
+	m, err := experiment.LoadMatrix(f)
+	_ = f.Close()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	dir := filepath.Dir(fs.Arg(0))
+	load := func(workload string) ([]scheduler.Process, error) {
+		if !filepath.IsAbs(workload) {
+			workload = filepath.Join(dir, workload)
+		}
+		f, err := os.Open(workload)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return input.Load(f)
+	}
+	var rows []experiment.Row
+	if *checkpoint != "" {
+		rows, err = m.Resume(ctx, *workers, load, *checkpoint)
+		if err != nil && ctx.Err() != nil {
+			return fmt.Errorf("%w; rerun with the same -checkpoint to resume", err)
+		}
+	} else {
+		rows, err = m.Execute(ctx, *workers, load)
+	}
+	if err != nil {
+		return err
+	}
+	return experiment.WriteCSV(stdout, rows)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"p1/internal/input"
+	"p1/internal/paging"
+	"p1/internal/render"
+)
+
+// defaultFrames is the frame count of schedsim paging unless told
+// otherwise, that of the usual textbook examples.
+const defaultFrames = 3
+
+// runPaging runs a page reference string through page replacement
+// algorithms and reports each one's faults and hits: "schedsim paging
+// [flags] [references]", reading the references from standard input if no
+// file is given.
+func runPaging(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim paging [flags] [references]\nReferences are page numbers separated by commas or spaces.\n")
+		fs.PrintDefaults()
+	}
+	var algorithms stringList
+	for _, a := range paging.Algorithms() {
+		algorithms = append(algorithms, a.Name)
+	}
+	fs.Var(&algorithms, "algorithms", "comma-separated `names` of the algorithms to run")
+	frames := fs.Int("frames", defaultFrames, "`number` of frames")
+	format := fs.String("format", formatText, "output `format`: text or json")
+	var opts render.Options
+	fs.BoolVar(&opts.NoGantt, "no-frames", false, "leave out the tables of the frames after each reference")
+	fs.TextVar(&opts.Style, "table-style", render.StyleBox, "table `style` of text output: box, plain or borderless")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if *format != formatText && *format != formatJSON {
+		return fmt.Errorf("%w: unknown output format %q", ErrInvalidArgs, *format)
+	}
+	refs, err := loadNumbers(fs, stdin, "references")
+	if err != nil {
+		return err
+	}
+
+	type result struct {
+		Algorithm string `json:"algorithm"`
+		Title     string `json:"title"`
+		paging.Result
+	}
+	var results []result
+	for _, name := range algorithms {
+		a, err := paging.LookupAlgorithm(name)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		res, err := paging.Simulate(a, refs, *frames)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		results = append(results, result{a.Name, a.Title, res})
+	}
+
+	if *format == formatJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			References []int64  `json:"references"`
+			Results    []result `json:"results"`
+		}{refs, results})
+	}
+	table := render.Table{
+		Columns: []render.Column{{Header: "ALGORITHM"}, {Header: "FAULTS", Align: render.AlignRight}, {Header: "HITS", Align: render.AlignRight}, {Header: "FAULT RATE", Align: render.AlignRight}},
+		Style:   opts.Style,
+	}
+	for _, res := range results {
+		render.PagingReport(stdout, res.Title, res.Result, opts)
+		table.Rows = append(table.Rows, []string{res.Title, strconv.Itoa(res.Faults), strconv.Itoa(res.Hits), fmt.Sprintf("%.2f%%", 100*res.FaultRate())})
+	}
+	return table.Render(stdout)
+}
+
+// loadNumbers reads the list of numbers in the file named by the one
+// argument left in fs, or standard input if there is none. What names what
+// the numbers are in errors.
+func loadNumbers(fs *flag.FlagSet, stdin io.Reader, what string) ([]int64, error) {
+	r, err := openInput(fs, stdin, what)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return input.LoadNumbers(r)
+}
+
+// openInput opens the file named by the one argument left in fs, or
+// standard input if there is none. What names what the file holds in
+// errors.
+func openInput(fs *flag.FlagSet, stdin io.Reader, what string) (io.ReadCloser, error) {
+	switch fs.NArg() {
+	case 0:
+		return io.NopCloser(stdin), nil
+	case 1:
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return nil, fmt.Errorf("%v: error opening %s file", err, what)
+		}
+		return f, nil
+	}
+	fs.Usage()
+	return nil, fmt.Errorf("%w: %s takes at most one %s file", ErrInvalidArgs, fs.Name(), what)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
+
+	"p1/internal/config"
+	"p1/internal/experiment"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// runPareto runs every algorithm over a workload and reports which are
+// Pareto-optimal on the metrics chosen: "schedsim pareto [flags] workload".
+// It takes the flags of schedsim itself, defaulting to every algorithm.
+func runPareto(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	defaults := config.Default()
+	defaults.Algorithms = nil
+	for _, a := range scheduler.Algorithms() {
+		defaults.Algorithms = append(defaults.Algorithms, a.Name)
+	}
+	metricNames := stringList{"wait", "fairness", "switches"}
+	var factor float64
+	s, err := parseFlagsWith(args, stderr, defaults, func(fs *flag.FlagSet) {
+		fs.Var(&metricNames, "metrics", "comma-separated `names` of the metrics to weigh: wait, turnaround, fairness, switches or misses, the deadlines missed")
+		fs.Float64Var(&factor, "deadline-factor", defaultDeadlineFactor, "for misses, a process without a deadline misses it if it finishes later than its arrival plus this `factor` times its burst")
+	})
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: s.level})))
+	misses, err := experiment.DeadlineMisses(factor)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	metrics, err := experiment.LookupMetrics(metricNames, misses)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	sim, processes, err := setup(s)
+	if err != nil {
+		return err
+	}
+	var results []render.Named
+	for _, name := range s.cfg.Algorithms {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return err
+		}
+		results = append(results, render.Named{Algorithm: a.Name, Title: a.Title, Result: sim.Schedule(a, processes)})
+	}
+
+	table := render.Table{Columns: []render.Column{{Header: "ALGORITHM"}}, Style: s.style}
+	for _, m := range metrics {
+		header := strings.ToUpper(m.Name)
+		if m.Higher {
+			header += " (HIGHER IS BETTER)"
+		}
+		table.Columns = append(table.Columns, render.Column{Header: header})
+	}
+	table.Columns = append(table.Columns, render.Column{Header: "PARETO-OPTIMAL"})
+	for _, score := range experiment.Pareto(results, metrics) {
+		row := []string{score.Algorithm}
+		for _, v := range score.Values {
+			row = append(row, metricValue(v))
+		}
+		if score.Optimal() {
+			row = append(row, "yes")
+		} else {
+			row = append(row, "no, dominated by "+strings.Join(score.DominatedBy, ", "))
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table.Render(stdout)
+}
+
+// metricValue writes the value of a metric, whole if it is.
+func metricValue(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprint(v)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"p1/internal/philosophers"
+	"p1/internal/render"
+)
+
+// runPhilosophers simulates the dining philosophers under each strategy
+// for picking up forks and reports their meals, deadlocks and starvation:
+// "schedsim philosophers [flags]".
+func runPhilosophers(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim philosophers [flags]\n")
+		fs.PrintDefaults()
+	}
+	var strategies stringList
+	for _, s := range philosophers.Strategies() {
+		strategies = append(strategies, s.Name)
+	}
+	p := philosophers.DefaultParams()
+	think, eat := int64List(p.Think), int64List(p.Eat)
+	fs.Var(&strategies, "strategies", "comma-separated `names` of the strategies to run")
+	fs.IntVar(&p.Philosophers, "n", p.Philosophers, "`number` of philosophers")
+	fs.Var(&think, "think", "comma-separated `ticks` each philosopher thinks for, repeated around the table")
+	fs.Var(&eat, "eat", "comma-separated `ticks` each philosopher eats for, repeated around the table")
+	fs.Int64Var(&p.Duration, "duration", p.Duration, "`ticks` to run for")
+	fs.Int64Var(&p.Patience, "patience", p.Patience, "`ticks` a philosopher may go hungry before counting as starving")
+	format := fs.String("format", formatText, "output `format`: text or json")
+	var opts render.Options
+	fs.BoolVar(&opts.NoGantt, "no-timeline", false, "leave out the timelines of what each philosopher does")
+	fs.TextVar(&opts.Style, "table-style", render.StyleBox, "table `style` of text output: box, plain or borderless")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if *format != formatText && *format != formatJSON {
+		return fmt.Errorf("%w: unknown output format %q", ErrInvalidArgs, *format)
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return fmt.Errorf("%w: unexpected arguments %q", ErrInvalidArgs, fs.Args())
+	}
+	p.Think, p.Eat = think, eat
+
+	type result struct {
+		Strategy string `json:"strategy"`
+		Title    string `json:"title"`
+		philosophers.Result
+	}
+	var results []result
+	for _, name := range strategies {
+		s, err := philosophers.LookupStrategy(name)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		res, err := philosophers.Simulate(s, p)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		results = append(results, result{s.Name, s.Title, res})
+	}
+
+	if *format == formatJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			philosophers.Params
+			Results []result `json:"results"`
+		}{p, results})
+	}
+	table := render.Table{
+		Columns: []render.Column{{Header: "STRATEGY"}, {Header: "MEALS"}, {Header: "DEADLOCK"}, {Header: "STARVING"}},
+		Style:   opts.Style,
+	}
+	for _, res := range results {
+		render.Title(stdout, res.Title)
+		render.Dining(stdout, res.Result, opts)
+		meals := 0
+		for _, p := range res.Philosophers {
+			meals += p.Meals
+		}
+		deadlock := "none"
+		if res.Deadlock != nil {
+			deadlock = "at " + strconv.FormatInt(res.Deadlock.Time, 10)
+		}
+		table.Rows = append(table.Rows, []string{res.Title, strconv.Itoa(meals), deadlock, strconv.Itoa(len(res.Starving()))})
+	}
+	return table.Render(stdout)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// playbackTick is how long a tick lasts in playback at 1x when times are
+// bare ticks.
+const playbackTick = time.Second
+
+// speed is a playback speed such as 10x, for -playback. The zero speed
+// turns playback off.
+type speed float64
+
+func (s *speed) String() string {
+	if *s == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(float64(*s), 'g', -1, 64) + "x"
+}
+
+func (s *speed) Set(v string) error {
+	f, err := strconv.ParseFloat(strings.TrimSuffix(v, "x"), 64)
+	if err != nil {
+		return fmt.Errorf("bad speed %q, want a factor such as 10x", v)
+	}
+	if !(f > 0) || math.IsInf(f, 0) {
+		return fmt.Errorf("speed %q must be positive", v)
+	}
+	*s = speed(f)
+	return nil
+}
+
+// player replays the events of simulations in wall-clock time, sped up by
+// a factor, writing each as it happens.
+type player struct {
+	out   io.Writer
+	opts  render.Options
+	speed speed
+	// tick is how long a tick lasts at 1x.
+	tick time.Duration
+
+	// now and sleep tell and pass the time; tests replace them.
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+func newPlayer(out io.Writer, opts render.Options, s speed) *player {
+	tick := opts.Tick
+	if tick == 0 {
+		tick = playbackTick
+	}
+	return &player{out: out, opts: opts, speed: s, tick: tick, now: time.Now, sleep: time.Sleep}
+}
+
+// schedule runs a over processes until ctx is cancelled, writing every event
+// once as much time has passed since the first arrival as separates them in
+// the run. The CPUs idling before it are shown straight away, and events
+// outside the window not at all.
+func (p *player) schedule(ctx context.Context, sim *scheduler.Simulator, a scheduler.Algorithm, processes []scheduler.Process) scheduler.Result {
+	_, _ = fmt.Fprintf(p.out, "Playing back %s at %s.\n", a.Title, p.speed.String())
+	run := sim.Simulate(ctx, a, processes)
+	var (
+		started bool
+		start   time.Time
+		origin  int64
+	)
+	for ev := range run.Events {
+		if !p.opts.Window.Contains(ev.Time) {
+			continue
+		}
+		if !started && ev.Kind != scheduler.EventIdle {
+			started, start, origin = true, p.now(), ev.Time
+		}
+		if d := p.at(ev.Time-origin) - p.now().Sub(start); started && d > 0 {
+			p.sleep(d)
+		}
+		render.Event(p.out, ev, p.opts)
+	}
+	return run.Result()
+}
+
+// at returns how long into the playback time t comes, held at the longest
+// duration for times too far off to wait for.
+func (p *player) at(t int64) time.Duration {
+	d := float64(t) * float64(p.tick) / float64(p.speed)
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
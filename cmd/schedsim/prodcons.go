@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"p1/internal/prodcons"
+	"p1/internal/render"
+)
+
+// runProdcons simulates producers and consumers sharing a bounded buffer
+// and reports how full the buffer was over time and how long each blocked:
+// "schedsim prodcons [flags]".
+func runProdcons(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim prodcons [flags]\n")
+		fs.PrintDefaults()
+	}
+	p := prodcons.DefaultParams()
+	produce, consume := int64List(p.Produce), int64List(p.Consume)
+	fs.Var(&produce, "produce", "comma-separated `ticks` each producer takes to make an item, one per producer")
+	fs.Var(&consume, "consume", "comma-separated `ticks` each consumer takes to use an item, one per consumer")
+	fs.IntVar(&p.Capacity, "buffer", p.Capacity, "`size` of the buffer in items")
+	fs.IntVar(&p.Items, "items", p.Items, "`number` of items to produce and consume")
+	format := fs.String("format", formatText, "output `format`: text or json")
+	var opts render.Options
+	fs.BoolVar(&opts.NoGantt, "no-chart", false, "leave out the chart of buffer occupancy")
+	fs.TextVar(&opts.Style, "table-style", render.StyleBox, "table `style` of text output: box, plain or borderless")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if *format != formatText && *format != formatJSON {
+		return fmt.Errorf("%w: unknown output format %q", ErrInvalidArgs, *format)
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return fmt.Errorf("%w: unexpected arguments %q", ErrInvalidArgs, fs.Args())
+	}
+	p.Produce, p.Consume = produce, consume
+	res, err := prodcons.Simulate(p)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	if *format == formatJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	}
+	opts.GanttLimit = render.DefaultGanttLimit
+	render.Title(stdout, "Bounded-buffer producer-consumer")
+	render.Buffer(stdout, res, opts)
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"p1/internal/config"
+	"p1/internal/input"
+	"p1/internal/quiz"
+	"p1/internal/render"
+)
+
+const quizUsage = `usage: schedsim quiz [flags] dir [name...]
+Writes a randomized problem for every name given, or -count numbered ones,
+to dir: the workload as name.csv, and its answer key as name.key.json, for
+schedsim grade, and name.key.txt.
+`
+
+// runQuiz generates problem sets with answer keys.
+func runQuiz(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(stderr, quizUsage)
+		fs.PrintDefaults()
+	}
+	var difficulty quiz.Difficulty
+	fs.TextVar(&difficulty, "difficulty", quiz.Medium, "`difficulty` of the problems: easy, medium or hard")
+	count := fs.Int("count", 1, "`number` of problems to write when no names are given")
+	seed := fs.Int64("seed", 1, "`seed` of the first problem; each further one adds one")
+	configPath := fs.String("config", "", "JSON config `file` of the algorithms to answer for")
+	var algorithms stringList
+	fs.Var(&algorithms, "algorithms", "comma-separated `names` of the algorithms to answer for, overriding the config")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("%w: must give a directory to write problems to", ErrInvalidArgs)
+	}
+
+	cfg := config.Default()
+	if *configPath != "" {
+		var err error
+		if cfg, err = config.LoadFile(*configPath); err != nil {
+			return err
+		}
+	}
+	if algorithms != nil {
+		cfg.Algorithms = algorithms
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+
+	dir, names := fs.Arg(0), fs.Args()[1:]
+	if len(names) == 0 {
+		for i := range *count {
+			names = append(names, fmt.Sprintf("problem-%02d", i+1))
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for i, name := range names {
+		problemSeed := *seed + int64(i)
+		if err := writeProblem(filepath.Join(dir, name), difficulty, problemSeed, cfg); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(stdout, "%s.csv  seed %d\n", name, problemSeed)
+	}
+	return nil
+}
+
+// writeProblem writes a problem and its answer keys to files starting with
+// path.
+func writeProblem(path string, difficulty quiz.Difficulty, seed int64, cfg config.Config) error {
+	processes := quiz.Generate(difficulty, seed)
+	key, err := quiz.AnswerKey(processes, cfg)
+	if err != nil {
+		return err
+	}
+	return writeFiles(map[string]func(io.Writer) error{
+		path + ".csv":      func(w io.Writer) error { return input.WriteCSV(w, processes) },
+		path + ".key.json": key.Write,
+		path + ".key.txt": func(w io.Writer) error {
+			_, _ = fmt.Fprintf(w, "Answer key for %s (%s, seed %d)\n\n", filepath.Base(path), difficulty, seed)
+			for _, n := range key.Results {
+				render.Report(w, n.Title, n.Result, render.Options{})
+			}
+			return nil
+		},
+	})
+}
+
+// writeFiles creates every file named in files with the contents its
+// function writes.
+func writeFiles(files map[string]func(io.Writer) error) error {
+	for name, write := range files {
+		f, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		if err := write(f); err != nil {
+			_ = f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"p1/internal/config"
+	"p1/internal/experiment"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// runRank runs every algorithm over a workload and ranks them on a single
+// score weighing the metrics chosen: "schedsim rank [flags] workload". It
+// takes the flags of schedsim itself, defaulting to every algorithm.
+func runRank(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	defaults := config.Default()
+	defaults.Algorithms = nil
+	for _, a := range scheduler.Algorithms() {
+		defaults.Algorithms = append(defaults.Algorithms, a.Name)
+	}
+	specs := stringList{"wait=0.5", "fairness=0.3", "switches=0.2"}
+	s, err := parseFlagsWith(args, stderr, defaults, func(fs *flag.FlagSet) {
+		fs.Var(&specs, "weights", "comma-separated `weights` of the metrics to score, such as wait=0.5, of wait, turnaround, fairness or switches")
+	})
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: s.level})))
+	weights, err := experiment.ParseWeights(specs)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	sim, processes, err := setup(s)
+	if err != nil {
+		return err
+	}
+	var results []render.Named
+	for _, name := range s.cfg.Algorithms {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return err
+		}
+		results = append(results, render.Named{Algorithm: a.Name, Title: a.Title, Result: sim.Schedule(a, processes)})
+	}
+
+	table := render.Table{Columns: []render.Column{{Header: "RANK"}, {Header: "ALGORITHM"}}, Style: s.style}
+	for _, w := range weights {
+		header := strings.ToUpper(w.Metric.Name) + " ×" + strconv.FormatFloat(w.Weight, 'g', -1, 64)
+		if w.Metric.Higher {
+			header += " (HIGHER IS BETTER)"
+		}
+		table.Columns = append(table.Columns, render.Column{Header: header})
+	}
+	table.Columns = append(table.Columns, render.Column{Header: "SCORE"})
+	for _, r := range experiment.Rank(results, weights) {
+		row := []string{strconv.Itoa(r.Rank), r.Algorithm}
+		for _, v := range r.Values {
+			row = append(row, metricValue(v))
+		}
+		table.Rows = append(table.Rows, append(row, fmt.Sprintf("%.3f", r.Score)))
+	}
+	if err := table.Render(stdout); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, "Each metric scores 1 for the best algorithm and 0 for the worst; the score is their weighted mean.")
+	return err
+}
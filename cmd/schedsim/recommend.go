@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"p1/internal/config"
+	"p1/internal/experiment"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// objectives are what recommend picks an algorithm for, in the order of the
+// metrics it weighs: the metric, by name, and how to describe it.
+var objectives = []struct {
+	metric, title, value string
+}{
+	{"wait", "minimize average wait", "average wait"},
+	{"fairness", "maximize fairness", "fairness index"},
+	{"misses", "minimize deadline misses", "deadline misses"},
+}
+
+// defaultDeadlineFactor is the slowdown past which recommend counts a
+// process as missing its deadline.
+const defaultDeadlineFactor = 2
+
+// runRecommend runs every algorithm over a workload and suggests the best
+// for each objective: "schedsim recommend [flags] workload". It takes the
+// flags of schedsim itself, defaulting to every algorithm.
+func runRecommend(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	defaults := config.Default()
+	defaults.Algorithms = nil
+	for _, a := range scheduler.Algorithms() {
+		defaults.Algorithms = append(defaults.Algorithms, a.Name)
+	}
+	var factor float64
+	s, err := parseFlagsWith(args, stderr, defaults, func(fs *flag.FlagSet) {
+		fs.Float64Var(&factor, "deadline-factor", defaultDeadlineFactor, "a process without a deadline misses it if it finishes later than its arrival plus this `factor` times its burst")
+	})
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: s.level})))
+	misses, err := experiment.DeadlineMisses(factor)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	metrics, err := experiment.LookupMetrics([]string{"wait", "fairness"})
+	if err != nil {
+		return err
+	}
+	metrics = append(metrics, misses)
+
+	sim, processes, err := setup(s)
+	if err != nil {
+		return err
+	}
+	var results []render.Named
+	for _, name := range s.cfg.Algorithms {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return err
+		}
+		results = append(results, render.Named{Algorithm: a.Name, Title: a.Title, Result: sim.Schedule(a, processes)})
+	}
+
+	table := render.Table{
+		Columns: []render.Column{{Header: "OBJECTIVE"}, {Header: "RECOMMENDED"}, {Header: "WHY"}},
+		Style:   s.style,
+	}
+	for i, rec := range experiment.Recommend(results, metrics) {
+		o := objectives[i]
+		best, next := metricValue(rec.Value), metricValue(rec.RunnerUpValue)
+		if best == next {
+			// Show enough digits to tell the runner-up apart.
+			best, next = fmt.Sprintf("%.4f", rec.Value), fmt.Sprintf("%.4f", rec.RunnerUpValue)
+		}
+		why := fmt.Sprintf("%s %s", o.value, best)
+		if o.metric == "misses" {
+			why += fmt.Sprintf(" of %d", len(processes))
+		}
+		if rec.RunnerUp == "" {
+			why += "; every algorithm ties"
+		} else {
+			why += fmt.Sprintf("; next best %s at %s", rec.RunnerUp, next)
+		}
+		table.Rows = append(table.Rows, []string{o.title, strings.Join(rec.Best, ", "), why})
+	}
+	if err := table.Render(stdout); err != nil {
+		return err
+	}
+	note := "Deadlines fall"
+	if slices.ContainsFunc(processes, func(p scheduler.Process) bool { return p.Deadline != 0 }) {
+		note = "Deadlines not given fall"
+	}
+	_, err = fmt.Fprintf(stdout, "%s at arrival plus %s times the burst.\n", note, metricValue(factor))
+	return err
+}
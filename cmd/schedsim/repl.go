@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"p1/internal/input"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+const replHelp = `commands:
+  load FILE                   load a workload file
+  show                        list the processes
+  add ID BURST ARRIVAL [PRI]  add a process
+  rm ID                       remove a process
+  set ID FIELD VALUE          change the burst, arrival or priority of a process
+  opt NAME VALUE              change fcfs-order, quantum, mlfq-quanta,
+                              aging-rate, cpus, switch-cost or seed
+  opts                        show the options
+  algorithms                  list the algorithms
+  run ALGORITHM...            schedule the workload and show the reports
+  compare [ALGORITHM...]      compare the averages of the latest runs, or of
+                              new runs of the algorithms given
+  help                        show this help
+  quit                        leave
+`
+
+// repl holds the state of an interactive session.
+type repl struct {
+	out       io.Writer
+	opts      scheduler.Options
+	processes []scheduler.Process
+	// runs are the latest results by algorithm name, in the order they were
+	// first run.
+	runs  map[string]scheduler.Result
+	order []string
+}
+
+// runREPL reads commands from stdin to edit a workload and schedule it
+// repeatedly, starting from the workload file given, if any.
+func runREPL(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim repl [workload]\n")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("%w: repl takes at most one workload file", ErrInvalidArgs)
+	}
+
+	r := &repl{out: stdout, opts: scheduler.DefaultOptions(), runs: make(map[string]scheduler.Result)}
+	if fs.NArg() == 1 {
+		if err := r.exec([]string{"load", fs.Arg(0)}); err != nil {
+			return err
+		}
+	}
+	_, _ = fmt.Fprintln(stdout, "Enter help for a list of commands.")
+	in := bufio.NewScanner(stdin)
+	for {
+		_, _ = fmt.Fprint(stdout, "schedsim> ")
+		if !in.Scan() {
+			_, _ = fmt.Fprintln(stdout)
+			return in.Err()
+		}
+		fields := strings.Fields(in.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "quit" || fields[0] == "exit" {
+			return nil
+		}
+		if err := r.exec(fields); err != nil {
+			_, _ = fmt.Fprintln(stdout, "error:", err)
+		}
+	}
+}
+
+// exec runs one command.
+func (r *repl) exec(fields []string) error {
+	cmd, args := fields[0], fields[1:]
+	switch cmd {
+	case "help", "?":
+		_, _ = fmt.Fprint(r.out, replHelp)
+	case "load":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: load FILE")
+		}
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		processes, err := input.Load(f)
+		if err != nil {
+			return err
+		}
+		r.processes = processes
+		_, _ = fmt.Fprintf(r.out, "loaded %d processes\n", len(processes))
+	case "show":
+		r.show()
+	case "add":
+		return r.add(args)
+	case "rm":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: rm ID")
+		}
+		i, err := r.find(args[0])
+		if err != nil {
+			return err
+		}
+		r.processes = append(r.processes[:i:i], r.processes[i+1:]...)
+	case "set":
+		return r.set(args)
+	case "opt":
+		return r.setOption(args)
+	case "opts":
+		_, _ = fmt.Fprintf(r.out, "fcfs-order %s, quantum %d, mlfq-quanta %v, aging-rate %g, cpus %d, switch-cost %d, seed %d\n",
+			cmp.Or(r.opts.FCFS.Order, scheduler.OrderArrival), r.opts.RR.Quantum, r.opts.MLFQ.Quanta, r.opts.Aging.Rate, r.opts.CPUs, r.opts.SwitchCost, r.opts.Seed)
+	case "algorithms":
+		for _, a := range scheduler.Algorithms() {
+			_, _ = fmt.Fprintf(r.out, "%-10s %s\n", a.Name, a.Title)
+		}
+	case "run":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: run ALGORITHM...")
+		}
+		return r.run(args, true)
+	case "compare":
+		if len(args) > 0 {
+			if err := r.run(args, false); err != nil {
+				return err
+			}
+		} else {
+			args = r.order
+		}
+		r.compare(args)
+	default:
+		return fmt.Errorf("unknown command %q; enter help for a list", cmd)
+	}
+	return nil
+}
+
+func (r *repl) show() {
+	table := render.Table{Columns: []render.Column{{Header: "ID"}, {Header: "BURST"}, {Header: "ARRIVAL"}, {Header: "PRIORITY"}}}
+	for _, p := range r.processes {
+		table.Rows = append(table.Rows, []string{
+			fmt.Sprint(p.ProcessID), fmt.Sprint(p.BurstDuration), fmt.Sprint(p.ArrivalTime), fmt.Sprint(p.Priority),
+		})
+	}
+	_ = table.Render(r.out)
+}
+
+func (r *repl) add(args []string) error {
+	if len(args) < 3 || len(args) > 4 {
+		return fmt.Errorf("usage: add ID BURST ARRIVAL [PRIORITY]")
+	}
+	v := make([]int64, 4)
+	for i, a := range args {
+		var err error
+		if v[i], err = strconv.ParseInt(a, 10, 64); err != nil {
+			return err
+		}
+	}
+	r.processes = append(r.processes, scheduler.Process{ProcessID: v[0], BurstDuration: v[1], ArrivalTime: v[2], Priority: v[3]})
+	return nil
+}
+
+func (r *repl) set(args []string) error {
+	if len(args) != 3 {
+		return fmt.Errorf("usage: set ID FIELD VALUE")
+	}
+	i, err := r.find(args[0])
+	if err != nil {
+		return err
+	}
+	v, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		return err
+	}
+	switch p := &r.processes[i]; args[1] {
+	case "burst":
+		p.BurstDuration = v
+	case "arrival":
+		p.ArrivalTime = v
+	case "priority":
+		p.Priority = v
+	default:
+		return fmt.Errorf("unknown field %q, want burst, arrival or priority", args[1])
+	}
+	return nil
+}
+
+func (r *repl) setOption(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: opt NAME VALUE")
+	}
+	o := r.opts
+	o.MLFQ.Quanta = append([]int64(nil), o.MLFQ.Quanta...)
+	var err error
+	switch args[0] {
+	case "fcfs-order":
+		o.FCFS.Order = scheduler.FCFSOrder(args[1])
+	case "quantum":
+		o.RR.Quantum, err = strconv.ParseInt(args[1], 10, 64)
+	case "mlfq-quanta":
+		var l int64List
+		err = l.Set(args[1])
+		o.MLFQ = scheduler.MLFQParams{Levels: len(l), Quanta: l}
+	case "aging-rate":
+		o.Aging.Rate, err = strconv.ParseFloat(args[1], 64)
+		o.Aging.All = true
+	case "cpus":
+		o.CPUs, err = strconv.Atoi(args[1])
+	case "switch-cost":
+		o.SwitchCost, err = strconv.ParseInt(args[1], 10, 64)
+	case "seed":
+		o.Seed, err = strconv.ParseInt(args[1], 10, 64)
+	default:
+		return fmt.Errorf("unknown option %q", args[0])
+	}
+	if err != nil {
+		return err
+	}
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	r.opts = o
+	return nil
+}
+
+// find returns the index of the process with the given ID.
+func (r *repl) find(id string) (int, error) {
+	pid, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	for i, p := range r.processes {
+		if p.ProcessID == pid {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no process %d", pid)
+}
+
+// run schedules the workload with each algorithm named, keeping the results
+// and writing their reports if report is set.
+func (r *repl) run(names []string, report bool) error {
+	sim, err := scheduler.NewSimulator(scheduler.WithOptions(r.opts))
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return err
+		}
+		res := sim.Schedule(a, r.processes)
+		if _, ok := r.runs[a.Name]; !ok {
+			r.order = append(r.order, a.Name)
+		}
+		r.runs[a.Name] = res
+		if report {
+			render.Report(r.out, a.Title, res, render.Options{})
+		}
+	}
+	return nil
+}
+
+func (r *repl) compare(names []string) {
+	table := render.Table{Columns: []render.Column{
+		{Header: "ALGORITHM"}, {Header: "AVG WAIT"}, {Header: "AVG TURNAROUND"}, {Header: "THROUGHPUT"}, {Header: "SWITCHES"},
+	}}
+	for _, name := range names {
+		res, ok := r.runs[name]
+		if !ok {
+			continue
+		}
+		table.Rows = append(table.Rows, []string{
+			name,
+			fmt.Sprintf("%.2f", res.AveWait),
+			fmt.Sprintf("%.2f", res.AveTurnaround),
+			fmt.Sprintf("%.2f", res.AveThroughput),
+			fmt.Sprint(res.ContextSwitches),
+		})
+	}
+	_ = table.Render(r.out)
+}
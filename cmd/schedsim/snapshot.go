@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"p1/internal/input"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// runSnapshot runs an algorithm over a workload up to a time and writes the
+// state of the run then as JSON: "schedsim snapshot [flags] algorithm time
+// workload". It takes the flags of schedsim itself.
+func runSnapshot(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	s, err := parseFlags(args, stderr)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: s.level})))
+	if len(s.args) != 4 {
+		return fmt.Errorf("%w: usage: schedsim snapshot [flags] algorithm time workload", ErrInvalidArgs)
+	}
+	a, err := scheduler.LookupAlgorithm(s.args[1])
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	at, err := strconv.ParseInt(s.args[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: snapshot time: %w", ErrInvalidArgs, err)
+	}
+	s.args = []string{s.args[0], s.args[3]}
+
+	sim, processes, err := setup(s)
+	if err != nil {
+		return err
+	}
+	snap, err := sim.Snapshot(a, processes, at)
+	if err != nil {
+		return err
+	}
+	slog.Debug("took snapshot", "algorithm", a.Name, "time", snap.Time)
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(snap)
+}
+
+// runReplay carries on a run from a snapshot written by schedsim snapshot
+// and writes its report: "schedsim replay [flags] snapshot.json [workload]".
+// Given a workload, the run carries on with it instead of the snapshot's.
+func runReplay(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim replay [flags] snapshot.json [workload]\n")
+		fs.PrintDefaults()
+	}
+	var style render.TableStyle
+	fs.TextVar(&style, "table-style", render.StyleBox, "table `style` of the report: box, plain or borderless")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return fmt.Errorf("%w: replay takes a snapshot and at most one workload", ErrInvalidArgs)
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("%v: error reading snapshot", err)
+	}
+	var snap scheduler.Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("%w: %s: %w", scheduler.ErrBadSnapshot, fs.Arg(0), err)
+	}
+	processes := snap.Processes
+	if fs.NArg() == 2 {
+		f, closeFile, err := openProcessingFile(fs.Args()...)
+		if err != nil {
+			return err
+		}
+		defer closeFile()
+		if processes, err = input.Load(f); err != nil {
+			return err
+		}
+	}
+	res, err := scheduler.ReplayWith(snap, processes)
+	if err != nil {
+		return err
+	}
+	a, err := scheduler.LookupAlgorithm(snap.Algorithm)
+	if err != nil {
+		return err
+	}
+	render.Report(stdout, a.Title, res, render.Options{Style: style, NoGantt: snap.Options.NoGantt, CPUs: snap.Options.CPUs})
+	return nil
+}
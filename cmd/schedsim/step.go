@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// errQuit is returned when the user quits while stepping.
+var errQuit = errors.New("quit")
+
+const stepHelp = `commands:
+  <Enter>           run to the next event
+  run to t=N, t N   run until time N
+  c, continue       run to the end without stopping
+  q, quit           stop schedsim
+`
+
+// stepper pauses a simulation after every event, printing its state and
+// reading commands from the user until told to go on.
+type stepper struct {
+	in    *bufio.Scanner
+	out   io.Writer
+	opts  render.Options
+	tick  time.Duration
+	total int
+	// events, if set, logs every event of the run.
+	events *eventLog
+
+	// until is the time before which events are passed over silently.
+	until int64
+	// free is set once the user has asked to run to the end.
+	free bool
+}
+
+func newStepper(in io.Reader, out io.Writer, opts render.Options, total int) *stepper {
+	return &stepper{in: bufio.NewScanner(in), out: out, opts: opts, tick: opts.Tick, total: total}
+}
+
+// schedule runs a over processes, pausing after each event.
+func (st *stepper) schedule(sim *scheduler.Simulator, a scheduler.Algorithm, processes []scheduler.Process) (scheduler.Result, error) {
+	st.until, st.free = 0, false
+	obs := sim.Observe(a, processes)
+	states := obs.Subscribe(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := make(chan scheduler.Result, 1)
+	go func() { results <- obs.Run(ctx) }()
+
+	_, _ = fmt.Fprintf(st.out, "Stepping through %s; enter h for help.\n", a.Title)
+	var err error
+	for s := range states {
+		if err != nil {
+			continue
+		}
+		if st.events != nil {
+			err = st.events.write(a.Name, s.Event)
+		}
+		if err == nil {
+			err = st.pause(s)
+		}
+		if err != nil {
+			cancel()
+		}
+	}
+	return <-results, err
+}
+
+// pause shows s and waits for a command, unless the user asked to run past it
+// or it falls outside the window.
+func (st *stepper) pause(s scheduler.State) error {
+	if st.free || s.Event.Time < st.until || !st.opts.Window.Contains(s.Event.Time) {
+		return nil
+	}
+	render.State(st.out, s, st.total, st.opts)
+	for {
+		_, _ = fmt.Fprint(st.out, "> ")
+		if !st.in.Scan() {
+			// Out of input: finish the run as if told to continue.
+			_, _ = fmt.Fprintln(st.out)
+			st.free = true
+			return st.in.Err()
+		}
+		cmd := strings.TrimSpace(st.in.Text())
+		switch cmd {
+		case "":
+			st.until = 0
+			return nil
+		case "c", "continue":
+			st.free = true
+			return nil
+		case "q", "quit":
+			return errQuit
+		case "h", "help", "?":
+			_, _ = fmt.Fprint(st.out, stepHelp)
+			continue
+		}
+		t, err := st.parseRunTo(cmd)
+		if err != nil {
+			_, _ = fmt.Fprintf(st.out, "%v; enter h for help\n", err)
+			continue
+		}
+		if t <= s.Event.Time {
+			_, _ = fmt.Fprintf(st.out, "already at t=%d\n", s.Event.Time)
+			continue
+		}
+		st.until = t
+		return nil
+	}
+}
+
+// parseRunTo parses a command such as "run to t=50" or "t 50" into a time in
+// ticks. Times may be durations such as 50ms when a tick length is set.
+func (st *stepper) parseRunTo(cmd string) (int64, error) {
+	rest := strings.TrimPrefix(cmd, "run")
+	rest = strings.TrimPrefix(strings.TrimSpace(rest), "to")
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, "t") {
+		return 0, fmt.Errorf("unknown command %q", cmd)
+	}
+	rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(rest, "t")), "="))
+	return parseTime(rest, st.tick)
+}
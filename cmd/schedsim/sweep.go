@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"p1/internal/config"
+	"p1/internal/experiment"
+	"p1/internal/render"
+)
+
+// runSweep runs the quantum-based algorithms over a workload once per
+// quantum and tabulates average wait and context switches against the
+// quantum: "schedsim sweep [flags] workload". It takes the flags of schedsim
+// itself, defaulting to round-robin and MLFQ.
+func runSweep(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	defaults := config.Default()
+	defaults.Algorithms = []string{"rr", "mlfq"}
+	quanta := quantumRange{1, 20}
+	var asCSV bool
+	s, err := parseFlagsWith(args, stderr, defaults, func(fs *flag.FlagSet) {
+		fs.Var(&quanta, "quanta", "`range` of quanta to sweep, such as 1-20")
+		fs.BoolVar(&asCSV, "csv", false, "write CSV instead of a table")
+	})
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: s.level})))
+
+	_, processes, err := setup(s)
+	if err != nil {
+		return err
+	}
+	var qs []int64
+	for q := quanta.from; q <= quanta.to; q++ {
+		qs = append(qs, q)
+	}
+	points, err := experiment.Sweep(processes, s.cfg.Algorithms, qs, s.cfg.Options)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	if asCSV {
+		w := csv.NewWriter(stdout)
+		_ = w.Write([]string{"quantum", "algorithm", "average_wait", "average_turnaround", "context_switches"})
+		for _, p := range points {
+			_ = w.Write([]string{
+				strconv.FormatInt(p.Quantum, 10),
+				p.Algorithm,
+				strconv.FormatFloat(p.AveWait, 'f', -1, 64),
+				strconv.FormatFloat(p.AveTurnaround, 'f', -1, 64),
+				strconv.Itoa(p.ContextSwitches),
+			})
+		}
+		w.Flush()
+		return w.Error()
+	}
+	table := render.Table{
+		Columns: []render.Column{{Header: "QUANTUM"}, {Header: "ALGORITHM"}, {Header: "AVERAGE WAIT"}, {Header: "AVERAGE TURNAROUND"}, {Header: "CONTEXT SWITCHES"}},
+		Style:   s.style,
+	}
+	for _, p := range points {
+		table.Rows = append(table.Rows, []string{
+			fmt.Sprint(p.Quantum), p.Algorithm, fmt.Sprintf("%.2f", p.AveWait), fmt.Sprintf("%.2f", p.AveTurnaround), fmt.Sprint(p.ContextSwitches),
+		})
+	}
+	return table.Render(stdout)
+}
+
+// quantumRange is a flag holding an inclusive range of quanta such as 1-20,
+// or a single quantum.
+type quantumRange struct{ from, to int64 }
+
+func (r *quantumRange) String() string { return fmt.Sprintf("%d-%d", r.from, r.to) }
+
+func (r *quantumRange) Set(s string) error {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		to = from
+	}
+	var err error
+	if r.from, err = strconv.ParseInt(from, 10, 64); err != nil {
+		return err
+	}
+	if r.to, err = strconv.ParseInt(to, 10, 64); err != nil {
+		return err
+	}
+	if r.from < 1 || r.to < r.from {
+		return fmt.Errorf("quanta must be positive and ascending, got %s", s)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"p1/internal/paging"
+	"p1/internal/render"
+	"p1/internal/tlb"
+)
+
+// defaultMemoryFrames is the frame count of memory behind the TLBs of
+// schedsim tlb unless told otherwise.
+const defaultMemoryFrames = 8
+
+// runTLB runs a page reference string through page replacement and then
+// TLBs of each shape given, and reports the hit rate and effective access
+// time of each: "schedsim tlb [flags] [references]", reading the
+// references from standard input if no file is given.
+func runTLB(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim tlb [flags] [references]\nReferences are page numbers separated by commas or spaces.\n")
+		fs.PrintDefaults()
+	}
+	configs := stringList{"4x1", "4x2", "4:fifo", "4", "8"}
+	fs.Var(&configs, "tlb", "comma-separated TLB `shapes`, each entries[xways][:policy] with policy lru or fifo")
+	frames := fs.Int("frames", defaultMemoryFrames, "`number` of frames of memory")
+	algorithm := fs.String("algorithm", "lru", "page replacement `algorithm` of memory")
+	timing := tlb.DefaultTiming()
+	fs.DurationVar(&timing.TLB, "tlb-time", timing.TLB, "`time` to search the TLB")
+	fs.DurationVar(&timing.Memory, "memory-time", timing.Memory, "`time` of a memory access")
+	fs.DurationVar(&timing.Fault, "fault-time", timing.Fault, "`time` to service a page fault")
+	format := fs.String("format", formatText, "output `format`: text or json")
+	var opts render.Options
+	fs.TextVar(&opts.Style, "table-style", render.StyleBox, "table `style` of text output: box, plain or borderless")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if *format != formatText && *format != formatJSON {
+		return fmt.Errorf("%w: unknown output format %q", ErrInvalidArgs, *format)
+	}
+	a, err := paging.LookupAlgorithm(*algorithm)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	refs, err := loadNumbers(fs, stdin, "references")
+	if err != nil {
+		return err
+	}
+	mem, err := paging.Simulate(a, refs, *frames)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	var results []tlb.Result
+	for _, s := range configs {
+		c, err := tlb.ParseConfig(s)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		res, err := tlb.Simulate(c, mem, timing)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		results = append(results, res)
+	}
+
+	if *format == formatJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Frames     int          `json:"frames"`
+			Algorithm  string       `json:"algorithm"`
+			Timing     tlb.Timing   `json:"timing"`
+			References []int64      `json:"references"`
+			Results    []tlb.Result `json:"results"`
+		}{*frames, a.Name, timing, refs, results})
+	}
+	render.Title(stdout, "TLB simulation")
+	_, _ = fmt.Fprintf(stdout, "Memory: %d frames under %s, %d page faults of %d references\n", *frames, a.Title, mem.Faults, len(refs))
+	_, _ = fmt.Fprintf(stdout, "Times: %v to search the TLB, %v a memory access, %v a page fault\n", timing.TLB, timing.Memory, timing.Fault)
+	render.TLB(stdout, results, opts)
+	return nil
+}
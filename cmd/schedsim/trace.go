@@ -0,0 +1,146 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"p1/internal/config"
+	"p1/internal/grade"
+	"p1/internal/input"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// ErrTraceMismatch is returned by compare-trace when a trace matches none of
+// the schedules it is compared against.
+var ErrTraceMismatch = errors.New("trace matches no schedule")
+
+// runCompareTrace compares the schedules another simulator produced for a
+// workload with those of schedsim: "schedsim compare-trace [flags] workload
+// trace.csv", the trace read by input.LoadTrace. It takes the flags of
+// schedsim itself, defaulting to every algorithm. A trace naming one of the
+// algorithms is compared with that algorithm's schedule, any other with
+// every algorithm's. Each difference gives the trace's value and then, as
+// wanted, schedsim's. It fails with ErrTraceMismatch unless every trace
+// matches a schedule.
+func runCompareTrace(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	defaults := config.Default()
+	defaults.Algorithms = nil
+	for _, a := range scheduler.Algorithms() {
+		defaults.Algorithms = append(defaults.Algorithms, a.Name)
+	}
+	var tolerance int64
+	s, err := parseFlagsWith(args, stderr, defaults, func(fs *flag.FlagSet) {
+		fs.Int64Var(&tolerance, "time-tolerance", 0, "allowed difference in per-process and Gantt times in `ticks`")
+	})
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: s.level})))
+	if len(s.args) != 3 {
+		return fmt.Errorf("%w: usage: schedsim compare-trace [flags] workload trace.csv", ErrInvalidArgs)
+	}
+	if len(s.cfg.Speeds) > 0 {
+		return fmt.Errorf("%w: compare-trace compares traces of CPUs of speed 1, not -speeds", ErrInvalidArgs)
+	}
+	path := s.args[2]
+	s.args = s.args[:2]
+
+	sim, processes, err := setup(s)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%v: error opening trace", err)
+	}
+	var traces []input.Trace
+	if s.tick > 0 {
+		traces, err = input.LoadTraceDurations(f, s.tick)
+	} else {
+		traces, err = input.LoadTrace(f)
+	}
+	_ = f.Close()
+	if err != nil {
+		return err
+	}
+
+	tol := grade.DefaultTolerance()
+	tol.Time = tolerance
+	tol.Average = max(tol.Average, float64(tolerance))
+	mismatched := 0
+	for _, t := range traces {
+		res := scheduler.FromGantt(processes, byStart(t.Gantt))
+		name := "(unnamed)"
+		if t.Algorithm != "" {
+			name = t.Algorithm
+		}
+		_, _ = fmt.Fprintf(stdout, "== trace %s\n", name)
+		if err := scheduler.Verify(processes, res); err != nil {
+			_, _ = fmt.Fprintf(stdout, "The trace does not fit the workload: %v\n", err)
+		}
+
+		algorithms := s.cfg.Algorithms
+		if a, err := scheduler.LookupAlgorithm(t.Algorithm); err == nil {
+			algorithms = []string{a.Name}
+		}
+		var matches []string
+		closest, fewest := "", 0
+		for _, name := range algorithms {
+			a, err := scheduler.LookupAlgorithm(name)
+			if err != nil {
+				return err
+			}
+			ours := sim.Schedule(a, processes)
+			ours.Gantt = byStart(ours.Gantt)
+			key := grade.Key{Tolerance: tol, Tick: s.tick, Results: []render.Named{{Algorithm: a.Name, Title: a.Title, Result: ours}}}
+			r := key.Grade([]render.Named{{Algorithm: a.Name, Result: res}})[0]
+			if r.Pass {
+				matches = append(matches, a.Name)
+				_, _ = fmt.Fprintf(stdout, "SAME  %s\n", a.Name)
+				continue
+			}
+			if closest == "" || len(r.Failures) < fewest {
+				closest, fewest = a.Name, len(r.Failures)
+			}
+			_, _ = fmt.Fprintf(stdout, "DIFF  %s\n", a.Name)
+			for _, f := range r.Failures {
+				_, _ = fmt.Fprintf(stdout, "      %s\n", f)
+			}
+		}
+		switch {
+		case len(matches) > 0:
+			_, _ = fmt.Fprintf(stdout, "The trace matches %s.\n", strings.Join(matches, ", "))
+		case len(algorithms) > 1:
+			mismatched++
+			ways := "ways"
+			if fewest == 1 {
+				ways = "way"
+			}
+			_, _ = fmt.Fprintf(stdout, "The trace matches no algorithm; %s differs least, in %d %s.\n", closest, fewest, ways)
+		default:
+			mismatched++
+		}
+	}
+	if mismatched > 0 {
+		return fmt.Errorf("%w: %d of %d traces", ErrTraceMismatch, mismatched, len(traces))
+	}
+	return nil
+}
+
+// byStart returns the slices of gantt in order of start and then CPU, the
+// order schedules are compared in whatever order they were written in.
+func byStart(gantt []scheduler.TimeSlice) []scheduler.TimeSlice {
+	sorted := slices.Clone(gantt)
+	slices.SortStableFunc(sorted, func(a, b scheduler.TimeSlice) int {
+		return cmp.Or(cmp.Compare(a.Start, b.Start), cmp.Compare(a.CPU, b.CPU))
+	})
+	return sorted
+}
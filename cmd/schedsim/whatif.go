@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
+
+	"p1/internal/config"
+	"p1/internal/experiment"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// runWhatIf runs every algorithm over a workload and over it perturbed by a
+// single change to one process, and reports how the change moves each
+// metric: "schedsim what-if -change change [flags] workload". It takes the
+// flags of schedsim itself, defaulting to every algorithm.
+func runWhatIf(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	defaults := config.Default()
+	defaults.Algorithms = nil
+	for _, a := range scheduler.Algorithms() {
+		defaults.Algorithms = append(defaults.Algorithms, a.Name)
+	}
+	var change experiment.Perturbation
+	metricNames := stringList{"wait", "turnaround", "fairness", "switches"}
+	s, err := parseFlagsWith(args, stderr, defaults, func(fs *flag.FlagSet) {
+		fs.TextVar(&change, "change", experiment.Perturbation{}, "the `change` to make: remove:ID, delay:ID:TICKS or halve:ID to halve the burst")
+		fs.Var(&metricNames, "metrics", "comma-separated `names` of the metrics to compare: wait, turnaround, fairness or switches")
+	})
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: s.level})))
+	if change.Change == "" {
+		return fmt.Errorf("%w: usage: schedsim what-if -change change [flags] workload", ErrInvalidArgs)
+	}
+	metrics, err := experiment.LookupMetrics(metricNames)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	sim, processes, err := setup(s)
+	if err != nil {
+		return err
+	}
+	changed, err := change.Apply(processes)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	table := render.Table{Columns: []render.Column{{Header: "ALGORITHM"}}, Style: s.style}
+	for _, m := range metrics {
+		header := strings.ToUpper(m.Name)
+		if m.Higher {
+			header += " (HIGHER IS BETTER)"
+		}
+		table.Columns = append(table.Columns, render.Column{Header: header, Align: render.AlignRight})
+	}
+	for _, name := range s.cfg.Algorithms {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return err
+		}
+		before, after := sim.Schedule(a, processes), sim.Schedule(a, changed)
+		row := []string{a.Name}
+		for _, m := range metrics {
+			b, c := m.Value(before), m.Value(after)
+			// Round the difference as the values are, so that it reads as
+			// whole if they both do.
+			d := math.Round((c-b)*100) / 100
+			delta := metricValue(d)
+			if d > 0 {
+				delta = "+" + delta
+			}
+			row = append(row, fmt.Sprintf("%s → %s (%s)", metricValue(b), metricValue(c), delta))
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	if _, err := fmt.Fprintf(stdout, "What %s changes\n", change); err != nil {
+		return err
+	}
+	if err := table.Render(stdout); err != nil {
+		return err
+	}
+	if change.Change == experiment.Remove {
+		_, err = fmt.Fprintf(stdout, "Averages without P%d are over the other processes.\n", change.PID)
+	}
+	return err
+}
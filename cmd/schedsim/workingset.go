@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"p1/internal/paging"
+	"p1/internal/render"
+)
+
+// defaultWindow is the working set window of schedsim working-set unless
+// told otherwise.
+const defaultWindow = 10
+
+// runWorkingSet reports the working set of a page reference string after
+// each reference: "schedsim working-set [flags] [references]", reading the
+// references from standard input if no file is given.
+func runWorkingSet(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim working-set [flags] [references]\nReferences are page numbers separated by commas or spaces.\n")
+		fs.PrintDefaults()
+	}
+	window := fs.Int("window", defaultWindow, "`number` of most recent references the working set covers")
+	format := fs.String("format", formatText, "output `format`: text, json or csv")
+	var opts render.Options
+	fs.TextVar(&opts.Style, "table-style", render.StyleBox, "table `style` of text output: box, plain or borderless")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if *format != formatText && *format != formatJSON && *format != formatCSV {
+		return fmt.Errorf("%w: unknown output format %q", ErrInvalidArgs, *format)
+	}
+	refs, err := loadNumbers(fs, stdin, "references")
+	if err != nil {
+		return err
+	}
+	sets, err := paging.WorkingSets(refs, *window)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	switch *format {
+	case formatJSON:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Window      int       `json:"window"`
+			References  []int64   `json:"references"`
+			WorkingSets [][]int64 `json:"working_sets"`
+		}{*window, refs, sets})
+	case formatCSV:
+		w := csv.NewWriter(stdout)
+		_ = w.Write([]string{"reference", "page", "working_set_size"})
+		for i, set := range sets {
+			_ = w.Write([]string{strconv.Itoa(i + 1), strconv.FormatInt(refs[i], 10), strconv.Itoa(len(set))})
+		}
+		w.Flush()
+		return w.Error()
+	}
+	render.WorkingSets(stdout, refs, sets, *window, opts)
+	return nil
+}
+
+// runFaultCurve runs a page reference string through page replacement
+// algorithms with every frame count from one up and reports the faults of
+// each, showing where more frames fault more: "schedsim fault-curve [flags]
+// [references]", reading the references from standard input if no file is
+// given.
+func runFaultCurve(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "usage: schedsim fault-curve [flags] [references]\nReferences are page numbers separated by commas or spaces.\n")
+		fs.PrintDefaults()
+	}
+	var algorithms stringList
+	for _, a := range paging.Algorithms() {
+		algorithms = append(algorithms, a.Name)
+	}
+	fs.Var(&algorithms, "algorithms", "comma-separated `names` of the algorithms to run")
+	frames := fs.Int("max-frames", 0, "largest `number` of frames to run with (default the number of distinct pages)")
+	format := fs.String("format", formatText, "output `format`: text, json or csv")
+	var opts render.Options
+	fs.TextVar(&opts.Style, "table-style", render.StyleBox, "table `style` of text output: box, plain or borderless")
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	if *format != formatText && *format != formatJSON && *format != formatCSV {
+		return fmt.Errorf("%w: unknown output format %q", ErrInvalidArgs, *format)
+	}
+	refs, err := loadNumbers(fs, stdin, "references")
+	if err != nil {
+		return err
+	}
+	if *frames == 0 {
+		*frames = max(len(slices.Compact(slices.Sorted(slices.Values(refs)))), 1)
+	}
+
+	var curves []paging.Curve
+	for _, name := range algorithms {
+		a, err := paging.LookupAlgorithm(name)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		c, err := paging.FaultCurve(a, refs, *frames)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		curves = append(curves, c)
+	}
+
+	switch *format {
+	case formatJSON:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			References []int64        `json:"references"`
+			Curves     []paging.Curve `json:"curves"`
+		}{refs, curves})
+	case formatCSV:
+		w := csv.NewWriter(stdout)
+		_ = w.Write([]string{"frames", "algorithm", "faults", "fault_rate"})
+		for _, c := range curves {
+			for i, faults := range c.Faults {
+				rate := 0.0
+				if len(refs) > 0 {
+					rate = float64(faults) / float64(len(refs))
+				}
+				_ = w.Write([]string{strconv.Itoa(i + 1), c.Algorithm, strconv.Itoa(faults), strconv.FormatFloat(rate, 'f', -1, 64)})
+			}
+		}
+		w.Flush()
+		return w.Error()
+	}
+	render.FaultCurves(stdout, curves, opts)
+	return nil
+}
@@ -0,0 +1,271 @@
+// Package banker implements the Banker's algorithm for deadlock avoidance:
+// whether a system can run every process to completion from a state of
+// allocated resources, and whether granting a request keeps it so.
+package banker
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+)
+
+var (
+	ErrInvalidState   = errors.New("invalid resource state")
+	ErrInvalidRow     = errors.New("invalid row")
+	ErrExceedsClaim   = errors.New("request exceeds the process's maximum claim")
+	ErrUnknownProcess = errors.New("unknown process")
+)
+
+// State is what every process holds and may claim of each resource type,
+// and what remains available.
+type State struct {
+	Processes []string `json:"processes"`
+	Available []int64  `json:"available"`
+	// Max holds the most of each resource each process may request.
+	Max [][]int64 `json:"max"`
+	// Allocation holds what each process holds of each resource.
+	Allocation [][]int64 `json:"allocation"`
+}
+
+// Check fails with ErrInvalidState unless every process has a claim and an
+// allocation of every resource, none negative and no allocation above its
+// claim.
+func (s State) Check() error {
+	n := len(s.Available)
+	if len(s.Max) != len(s.Processes) || len(s.Allocation) != len(s.Processes) {
+		return fmt.Errorf("%w: %d processes, %d claims and %d allocations", ErrInvalidState, len(s.Processes), len(s.Max), len(s.Allocation))
+	}
+	if slices.ContainsFunc(s.Available, func(v int64) bool { return v < 0 }) {
+		return fmt.Errorf("%w: available %v is negative", ErrInvalidState, s.Available)
+	}
+	for i, p := range s.Processes {
+		if len(s.Max[i]) != n || len(s.Allocation[i]) != n {
+			return fmt.Errorf("%w: %s claims %d and holds %d resource types, want %d", ErrInvalidState, p, len(s.Max[i]), len(s.Allocation[i]), n)
+		}
+		for r := range n {
+			if s.Allocation[i][r] < 0 || s.Allocation[i][r] > s.Max[i][r] {
+				return fmt.Errorf("%w: %s holds %d of resource %d, want 0 up to its claim %d", ErrInvalidState, p, s.Allocation[i][r], r, s.Max[i][r])
+			}
+		}
+	}
+	return nil
+}
+
+// Need returns what each process may still request of each resource.
+func (s State) Need() [][]int64 {
+	need := make([][]int64, len(s.Processes))
+	for i := range need {
+		need[i] = make([]int64, len(s.Available))
+		for r := range need[i] {
+			need[i][r] = s.Max[i][r] - s.Allocation[i][r]
+		}
+	}
+	return need
+}
+
+// Safe returns a sequence of the processes, by index, in which each can be
+// given all it may still need and finish, releasing what it holds for the
+// next. The lowest-numbered process able to finish goes next. If the state
+// is unsafe, it returns false and the sequence of those that can finish.
+func (s State) Safe() ([]int, bool) {
+	need := s.Need()
+	work := slices.Clone(s.Available)
+	finished := make([]bool, len(s.Processes))
+	var sequence []int
+	for len(sequence) < len(s.Processes) {
+		next := -1
+		for i := range s.Processes {
+			if !finished[i] && fits(need[i], work) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return sequence, false
+		}
+		for r := range work {
+			work[r] += s.Allocation[next][r]
+		}
+		finished[next] = true
+		sequence = append(sequence, next)
+	}
+	return sequence, true
+}
+
+// fits reports whether every count of v is at most that of limit.
+func fits(v, limit []int64) bool {
+	for r := range v {
+		if v[r] > limit[r] {
+			return false
+		}
+	}
+	return true
+}
+
+// Request is a process asking for more resources.
+type Request struct {
+	Process int     `json:"process"`
+	Counts  []int64 `json:"counts"`
+}
+
+// Outcome is what becomes of a request.
+type Outcome string
+
+const (
+	// Granted requests leave the state safe and are allocated.
+	Granted Outcome = "granted"
+	// Wait requests are for more than is available; the process must
+	// wait until others release enough.
+	Wait Outcome = "wait"
+	// Denied requests could be met but would leave the state unsafe.
+	Denied Outcome = "denied"
+)
+
+// Decision is the outcome of a request and the state it leaves.
+type Decision struct {
+	Request Request `json:"request"`
+	Outcome Outcome `json:"outcome"`
+	// Sequence is a safe sequence of the state after a granted request.
+	Sequence []int `json:"sequence,omitempty"`
+	// State is the state after the request: s with it allocated if it
+	// was granted and s itself otherwise.
+	State State `json:"-"`
+}
+
+// Evaluate decides whether to grant req in s, failing with ErrExceedsClaim
+// for a request for more than the process may still need.
+func (s State) Evaluate(req Request) (Decision, error) {
+	if req.Process < 0 || req.Process >= len(s.Processes) {
+		return Decision{}, fmt.Errorf("%w: %d", ErrUnknownProcess, req.Process)
+	}
+	if len(req.Counts) != len(s.Available) || slices.ContainsFunc(req.Counts, func(v int64) bool { return v < 0 }) {
+		return Decision{}, fmt.Errorf("%w: %s requests %v of %d resource types", ErrInvalidState, s.Processes[req.Process], req.Counts, len(s.Available))
+	}
+	if need := s.Need()[req.Process]; !fits(req.Counts, need) {
+		return Decision{}, fmt.Errorf("%w: %s requests %v, needing at most %v", ErrExceedsClaim, s.Processes[req.Process], req.Counts, need)
+	}
+	d := Decision{Request: req, State: s}
+	if !fits(req.Counts, s.Available) {
+		d.Outcome = Wait
+		return d, nil
+	}
+
+	next := State{Processes: s.Processes, Max: s.Max, Available: slices.Clone(s.Available), Allocation: slices.Clone(s.Allocation)}
+	next.Allocation[req.Process] = slices.Clone(s.Allocation[req.Process])
+	for r, v := range req.Counts {
+		next.Available[r] -= v
+		next.Allocation[req.Process][r] += v
+	}
+	sequence, safe := next.Safe()
+	if !safe {
+		d.Outcome = Denied
+		return d, nil
+	}
+	d.Outcome, d.Sequence, d.State = Granted, sequence, next
+	return d, nil
+}
+
+// EvaluateAll decides the requests in turn, each granted one allocated
+// before the next is decided.
+func (s State) EvaluateAll(requests []Request) ([]Decision, error) {
+	decisions := make([]Decision, len(requests))
+	for i, req := range requests {
+		d, err := s.Evaluate(req)
+		if err != nil {
+			return nil, fmt.Errorf("request %d: %w", i+1, err)
+		}
+		decisions[i], s = d, d.State
+	}
+	return decisions, nil
+}
+
+// Load reads a state and the requests pending in it from CSV rows of the
+// form kind,process,count... where kind is allocation, max, available or
+// request, one column of counts per resource type. Processes are numbered
+// in the order they first appear; the available row leaves its process
+// empty.
+//
+//	allocation,P0,0,1,0
+//	max,P0,7,5,3
+//	available,,3,3,2
+//	request,P0,0,2,0
+func Load(r io.Reader) (State, []Request, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	var (
+		s        State
+		requests []Request
+		index    = make(map[string]int)
+		hasMax   []bool
+		hasAlloc []bool
+	)
+	process := func(name string) int {
+		i, ok := index[name]
+		if !ok {
+			i = len(s.Processes)
+			index[name] = i
+			s.Processes = append(s.Processes, name)
+			s.Max, s.Allocation = append(s.Max, nil), append(s.Allocation, nil)
+			hasMax, hasAlloc = append(hasMax, false), append(hasAlloc, false)
+		}
+		return i
+	}
+	for line := 1; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return State{}, nil, fmt.Errorf("%w: reading CSV", err)
+		}
+		if len(row) < 3 {
+			return State{}, nil, fmt.Errorf("%w %d: expected a kind, a process and counts, got %d fields", ErrInvalidRow, line, len(row))
+		}
+		counts := make([]int64, len(row)-2)
+		for i, f := range row[2:] {
+			if counts[i], err = strconv.ParseInt(f, 10, 64); err != nil {
+				return State{}, nil, fmt.Errorf("%w %d: %w", ErrInvalidRow, line, err)
+			}
+		}
+		kind, name := row[0], row[1]
+		if kind != "available" && name == "" {
+			return State{}, nil, fmt.Errorf("%w %d: %s names no process", ErrInvalidRow, line, kind)
+		}
+		switch kind {
+		case "available":
+			if s.Available != nil {
+				return State{}, nil, fmt.Errorf("%w %d: available given twice", ErrInvalidRow, line)
+			}
+			s.Available = counts
+		case "max", "allocation":
+			i := process(name)
+			has, dst := hasMax, s.Max
+			if kind == "allocation" {
+				has, dst = hasAlloc, s.Allocation
+			}
+			if has[i] {
+				return State{}, nil, fmt.Errorf("%w %d: %s of %s given twice", ErrInvalidRow, line, kind, name)
+			}
+			has[i], dst[i] = true, counts
+		case "request":
+			requests = append(requests, Request{Process: process(name), Counts: counts})
+		default:
+			return State{}, nil, fmt.Errorf("%w %d: unknown kind %q, want allocation, max, available or request", ErrInvalidRow, line, kind)
+		}
+	}
+	if s.Available == nil {
+		return State{}, nil, fmt.Errorf("%w: no available row", ErrInvalidState)
+	}
+	for i, p := range s.Processes {
+		if !hasMax[i] || !hasAlloc[i] {
+			return State{}, nil, fmt.Errorf("%w: %s needs both a max and an allocation row", ErrInvalidState, p)
+		}
+	}
+	if err := s.Check(); err != nil {
+		return State{}, nil, err
+	}
+	return s, requests, nil
+}
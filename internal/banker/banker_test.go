@@ -0,0 +1,119 @@
+package banker
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// textbook is the usual example: five processes and three resource types,
+// with the requests its exercises go on to make.
+const textbook = `allocation,P0,0,1,0
+allocation,P1,2,0,0
+allocation,P2,3,0,2
+allocation,P3,2,1,1
+allocation,P4,0,0,2
+max,P0,7,5,3
+max,P1,3,2,2
+max,P2,9,0,2
+max,P3,2,2,2
+max,P4,4,3,3
+available,,3,3,2
+request,P1,1,0,2
+request,P4,3,3,0
+request,P0,0,2,0
+`
+
+func TestLoad(t *testing.T) {
+	s, requests, err := Load(strings.NewReader(textbook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"P0", "P1", "P2", "P3", "P4"}; !reflect.DeepEqual(s.Processes, want) {
+		t.Errorf("processes = %v, want %v", s.Processes, want)
+	}
+	if want := [][]int64{{7, 4, 3}, {1, 2, 2}, {6, 0, 0}, {0, 1, 1}, {4, 3, 1}}; !reflect.DeepEqual(s.Need(), want) {
+		t.Errorf("need = %v, want %v", s.Need(), want)
+	}
+	if want := []Request{{1, []int64{1, 0, 2}}, {4, []int64{3, 3, 0}}, {0, []int64{0, 2, 0}}}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+
+	for _, in := range []string{
+		"max,P0,1\navailable,,1\n",
+		"max,P0,1\nallocation,P0,2\navailable,,3\n",
+		"max,P0,1,1\nallocation,P0,0\navailable,,3\n",
+		"max,P0,1\nallocation,P0,0\n",
+		"max,P0,1\nmax,P0,1\nallocation,P0,0\navailable,,1\n",
+		"need,P0,1\n",
+		"max,P0,x\n",
+		"max,,1\n",
+	} {
+		if _, _, err := Load(strings.NewReader(in)); !errors.Is(err, ErrInvalidState) && !errors.Is(err, ErrInvalidRow) {
+			t.Errorf("%q: err = %v, want an invalid state or row", in, err)
+		}
+	}
+}
+
+func TestSafe(t *testing.T) {
+	s, _, err := Load(strings.NewReader(textbook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sequence, safe := s.Safe(); !safe || !reflect.DeepEqual(sequence, []int{1, 3, 0, 2, 4}) {
+		t.Errorf("safe = %v, sequence %v", safe, sequence)
+	}
+
+	// P2 can finish, but gives back nothing for P0 or P1 to finish with.
+	s = State{
+		Processes:  []string{"P0", "P1", "P2"},
+		Available:  []int64{1},
+		Max:        [][]int64{{3}, {3}, {1}},
+		Allocation: [][]int64{{1}, {1}, {0}},
+	}
+	if sequence, safe := s.Safe(); safe || !reflect.DeepEqual(sequence, []int{2}) {
+		t.Errorf("safe = %v, sequence %v", safe, sequence)
+	}
+}
+
+func TestEvaluateAll(t *testing.T) {
+	s, requests, err := Load(strings.NewReader(textbook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decisions, err := s.EvaluateAll(requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var outcomes []Outcome
+	for _, d := range decisions {
+		outcomes = append(outcomes, d.Outcome)
+	}
+	if want := []Outcome{Granted, Wait, Denied}; !reflect.DeepEqual(outcomes, want) {
+		t.Errorf("outcomes = %v, want %v", outcomes, want)
+	}
+	if d := decisions[0]; !reflect.DeepEqual(d.Sequence, []int{1, 3, 0, 2, 4}) || !reflect.DeepEqual(d.State.Available, []int64{2, 3, 0}) {
+		t.Errorf("granted: sequence %v, available %v", d.Sequence, d.State.Available)
+	}
+	if !reflect.DeepEqual(s.Available, []int64{3, 3, 2}) || !reflect.DeepEqual(s.Allocation[1], []int64{2, 0, 0}) {
+		t.Errorf("evaluating changed the state: %+v", s)
+	}
+	if d := decisions[2]; d.Sequence != nil || !reflect.DeepEqual(d.State.Available, []int64{2, 3, 0}) {
+		t.Errorf("denied: %+v", d)
+	}
+
+	for _, tc := range []struct {
+		req  Request
+		want error
+	}{
+		{Request{0, []int64{8, 0, 0}}, ErrExceedsClaim},
+		{Request{5, []int64{0, 0, 0}}, ErrUnknownProcess},
+		{Request{0, []int64{0, 0}}, ErrInvalidState},
+		{Request{0, []int64{-1, 0, 0}}, ErrInvalidState},
+	} {
+		if _, err := s.Evaluate(tc.req); !errors.Is(err, tc.want) {
+			t.Errorf("%v: err = %v, want %v", tc.req, err, tc.want)
+		}
+	}
+}
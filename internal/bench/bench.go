@@ -0,0 +1,62 @@
+// Package bench times the scheduling algorithms on synthetic workloads of
+// growing size, to catch slowdowns in the simulation loops.
+package bench
+
+import (
+	"time"
+
+	"p1/internal/scheduler"
+	"p1/internal/workload"
+)
+
+// maxBurst is the longest burst of a synthetic process.
+const maxBurst = 100
+
+// Workload returns n processes with bursts of 1 to maxBurst ticks arriving
+// at random, about as fast as cpus processors can run them, so the ready
+// queue neither drains nor grows without bound. The same seed always gives
+// the same workload.
+func Workload(n, cpus int, seed int64) []scheduler.Process {
+	processes, err := workload.Generate(workload.Spec{Processes: n, CPUs: max(1, cpus), MeanBurst: maxBurst / 2}, seed)
+	if err != nil {
+		panic(err)
+	}
+	return processes
+}
+
+// Measurement is the timing of one algorithm on one workload.
+type Measurement struct {
+	Algorithm string
+	Processes int
+	// Runs is how many times the workload was scheduled.
+	Runs int
+	// Elapsed is the mean time a run took.
+	Elapsed time.Duration
+	// Decisions is the number of dispatches a run made, counted as the
+	// slices of its Gantt chart.
+	Decisions int
+}
+
+// PerSecond returns the scheduling decisions made per second.
+func (m Measurement) PerSecond() float64 {
+	if m.Elapsed <= 0 {
+		return 0
+	}
+	return float64(m.Decisions) / m.Elapsed.Seconds()
+}
+
+// Measure schedules processes with a repeatedly until at least minTime has
+// passed, and at least once.
+func Measure(sim *scheduler.Simulator, a scheduler.Algorithm, processes []scheduler.Process, minTime time.Duration) Measurement {
+	m := Measurement{Algorithm: a.Name, Processes: len(processes)}
+	start := time.Now()
+	var total time.Duration
+	for m.Runs == 0 || total < minTime {
+		res := sim.Schedule(a, processes)
+		m.Decisions = len(res.Gantt)
+		m.Runs++
+		total = time.Since(start)
+	}
+	m.Elapsed = total / time.Duration(m.Runs)
+	return m
+}
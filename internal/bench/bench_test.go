@@ -0,0 +1,79 @@
+package bench
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"p1/internal/scheduler"
+)
+
+func TestWorkload(t *testing.T) {
+	processes := Workload(500, 2, 1)
+	if len(processes) != 500 {
+		t.Fatalf("got %d processes", len(processes))
+	}
+	for i, p := range processes {
+		if p.BurstDuration < 1 || p.BurstDuration > maxBurst || (i > 0 && p.ArrivalTime < processes[i-1].ArrivalTime) {
+			t.Fatalf("bad process %+v", p)
+		}
+	}
+	if !reflect.DeepEqual(processes, Workload(500, 2, 1)) {
+		t.Error("same seed gave different workloads")
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	sim, err := scheduler.NewSimulator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := scheduler.LookupAlgorithm("fcfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := Measure(sim, a, Workload(100, 1, 1), 0)
+	if m.Runs != 1 || m.Decisions != 100 || m.Elapsed <= 0 || m.PerSecond() <= 0 {
+		t.Errorf("got %+v", m)
+	}
+}
+
+// BenchmarkSchedule times every algorithm on workloads of growing size,
+// reporting scheduling decisions per second alongside the time per run.
+func BenchmarkSchedule(b *testing.B) {
+	sim, err := scheduler.NewSimulator()
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, a := range scheduler.Algorithms() {
+		for _, n := range []int{100, 1000, 10000} {
+			processes := Workload(n, 1, 1)
+			b.Run(fmt.Sprintf("%s/%d", a.Name, n), func(b *testing.B) {
+				var decisions int
+				for b.Loop() {
+					decisions = len(sim.Schedule(a, processes).Gantt)
+				}
+				b.ReportMetric(float64(decisions)*float64(b.N)/b.Elapsed().Seconds(), "decisions/s")
+			})
+		}
+	}
+}
+
+// BenchmarkSimulate times streaming a run's events, which Schedule skips.
+func BenchmarkSimulate(b *testing.B) {
+	sim, err := scheduler.NewSimulator()
+	if err != nil {
+		b.Fatal(err)
+	}
+	a, err := scheduler.LookupAlgorithm("rr")
+	if err != nil {
+		b.Fatal(err)
+	}
+	processes := Workload(1000, 1, 1)
+	for b.Loop() {
+		run := sim.Simulate(b.Context(), a, processes)
+		for range run.Events {
+		}
+		run.Result()
+	}
+}
@@ -0,0 +1,241 @@
+// Package bundle packs a schedsim run into a single zip archive holding
+// everything needed to reproduce it: the workload file exactly as given, the
+// configuration including seeds, the version of schedsim that ran it and the
+// results it produced.
+//
+// An archive holds
+//
+//	manifest.json        the Manifest
+//	config.json          the config.Config of the run
+//	workload/<name>      the workload file
+//	results.json         the results, as a render.Document
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"p1/internal/config"
+	"p1/internal/input"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+	"p1/internal/version"
+)
+
+// FormatVersion is the version of the archive layout written by Write.
+const FormatVersion = 1
+
+var (
+	ErrInvalid = errors.New("invalid bundle")
+	// ErrMismatch is returned by Verify when rerunning a bundle does not
+	// reproduce its results.
+	ErrMismatch = errors.New("results not reproduced")
+)
+
+const (
+	manifestName = "manifest.json"
+	configName   = "config.json"
+	resultsName  = "results.json"
+	workloadDir  = "workload/"
+)
+
+// Manifest describes the contents of a bundle and what produced them.
+type Manifest struct {
+	FormatVersion int       `json:"formatVersion"`
+	Created       time.Time `json:"created"`
+	// Tool is the version of schedsim that produced the results.
+	Tool      string `json:"tool"`
+	GoVersion string `json:"goVersion"`
+	// Workload is the base name of the workload file.
+	Workload string `json:"workload"`
+	// WorkloadSHA256 is the hex SHA-256 digest of the workload file.
+	WorkloadSHA256 string `json:"workloadSHA256"`
+	// Tick is the real length of a tick the workload was read with, or 0 if
+	// it gives bare ticks.
+	Tick time.Duration `json:"tick,omitempty"`
+}
+
+// Bundle is a reproducible run.
+type Bundle struct {
+	Manifest Manifest
+	Config   config.Config
+	Workload []byte
+	Results  []render.Named
+}
+
+// Create runs every algorithm of cfg over workload, the contents of the
+// workload file called name, and bundles the run.
+func Create(name string, workload []byte, cfg config.Config, tick time.Duration) (*Bundle, error) {
+	sum := sha256.Sum256(workload)
+	b := &Bundle{
+		Manifest: Manifest{
+			FormatVersion:  FormatVersion,
+			Created:        time.Now().UTC(),
+			Tool:           version.String(),
+			GoVersion:      runtime.Version(),
+			Workload:       path.Base(name),
+			WorkloadSHA256: hex.EncodeToString(sum[:]),
+			Tick:           tick,
+		},
+		Config:   cfg,
+		Workload: workload,
+	}
+	var err error
+	if b.Results, err = b.schedule(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Processes parses the workload of b.
+func (b *Bundle) Processes() ([]scheduler.Process, error) {
+	if b.Manifest.Tick > 0 {
+		return input.LoadDurations(bytes.NewReader(b.Workload), b.Manifest.Tick)
+	}
+	return input.Load(bytes.NewReader(b.Workload))
+}
+
+// schedule runs the algorithms of b afresh.
+func (b *Bundle) schedule() ([]render.Named, error) {
+	processes, err := b.Processes()
+	if err != nil {
+		return nil, err
+	}
+	sim, err := scheduler.NewSimulator(scheduler.WithOptions(b.Config.Options))
+	if err != nil {
+		return nil, err
+	}
+	results := make([]render.Named, 0, len(b.Config.Algorithms))
+	for _, name := range b.Config.Algorithms {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, render.Named{Algorithm: a.Name, Title: a.Title, Result: sim.Schedule(a, processes)})
+	}
+	return results, nil
+}
+
+// Verify reruns b and reports ErrMismatch, naming the algorithms concerned,
+// if the results differ from those bundled.
+func (b *Bundle) Verify() error {
+	results, err := b.schedule()
+	if err != nil {
+		return err
+	}
+	var differ []string
+	for i, n := range results {
+		if i >= len(b.Results) || b.Results[i].Algorithm != n.Algorithm || !reflect.DeepEqual(b.Results[i].Result, n.Result) {
+			differ = append(differ, n.Algorithm)
+		}
+	}
+	if len(b.Results) != len(results) {
+		differ = append(differ, fmt.Sprintf("%d results bundled, %d reproduced", len(b.Results), len(results)))
+	}
+	if len(differ) > 0 {
+		return fmt.Errorf("%w: %s", ErrMismatch, strings.Join(differ, ", "))
+	}
+	return nil
+}
+
+// Write writes b to w as a zip archive.
+func (b *Bundle) Write(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	cfg, err := json.MarshalIndent(b.Config, "", "  ")
+	if err != nil {
+		return err
+	}
+	var results bytes.Buffer
+	if err := render.JSON(&results, b.Results, render.Options{Tick: b.Manifest.Tick}); err != nil {
+		return err
+	}
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{
+		{manifestName, append(manifest, '\n')},
+		{configName, append(cfg, '\n')},
+		{workloadDir + b.Manifest.Workload, b.Workload},
+		{resultsName, results.Bytes()},
+	} {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: b.Manifest.Created})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// Read reads a bundle from the zip archive in r, which is size bytes long.
+// It checks the workload against its digest but does not rerun it; see
+// Verify.
+func Read(r io.ReaderAt, size int64) (*Bundle, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	var b Bundle
+	manifest, err := readFile(zr, manifestName)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(manifest, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("%w: reading %s: %v", ErrInvalid, manifestName, err)
+	}
+	if b.Manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("%w: format version %d, this build reads %d", ErrInvalid, b.Manifest.FormatVersion, FormatVersion)
+	}
+
+	cfg, err := readFile(zr, configName)
+	if err != nil {
+		return nil, err
+	}
+	if b.Config, err = config.Load(bytes.NewReader(cfg)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	if b.Workload, err = readFile(zr, workloadDir+b.Manifest.Workload); err != nil {
+		return nil, err
+	}
+	if sum := sha256.Sum256(b.Workload); hex.EncodeToString(sum[:]) != b.Manifest.WorkloadSHA256 {
+		return nil, fmt.Errorf("%w: workload does not match its digest", ErrInvalid)
+	}
+
+	results, err := readFile(zr, resultsName)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := render.ReadJSON(bytes.NewReader(results))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	b.Results = doc.Results
+	return &b, nil
+}
+
+func readFile(zr *zip.Reader, name string) ([]byte, error) {
+	f, err := zr.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
@@ -0,0 +1,70 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"p1/internal/config"
+)
+
+func TestRoundTrip(t *testing.T) {
+	cfg := config.Default()
+	cfg.Algorithms = []string{"fcfs", "rr"}
+	cfg.RR.Quantum = 2
+	b, err := Create("dir/workload.csv", []byte("1,5ms,0s,2\n2,3ms,1ms,1\n"), cfg, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Manifest.Workload != "workload.csv" || len(b.Results) != 2 {
+		t.Fatalf("bundle = %+v", b)
+	}
+
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Times lose their monotonic reading on the way through JSON.
+	b.Manifest.Created = b.Manifest.Created.Round(0)
+	if !reflect.DeepEqual(got, b) {
+		t.Errorf("read %+v, want %+v", got, b)
+	}
+	if err := got.Verify(); err != nil {
+		t.Error(err)
+	}
+
+	got.Results[1].Result.AveWait++
+	if err := got.Verify(); !errors.Is(err, ErrMismatch) {
+		t.Errorf("tampered results: err = %v, want %v", err, ErrMismatch)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	b, err := Create("w.csv", []byte("1,5,0\n"), config.Default(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Manifest.WorkloadSHA256 = "00"
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len())); !errors.Is(err, ErrInvalid) {
+		t.Errorf("bad digest: err = %v, want %v", err, ErrInvalid)
+	}
+
+	buf.Reset()
+	if err := zip.NewWriter(&buf).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len())); !errors.Is(err, ErrInvalid) {
+		t.Errorf("empty archive: err = %v, want %v", err, ErrInvalid)
+	}
+}
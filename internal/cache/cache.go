@@ -0,0 +1,164 @@
+// Package cache keeps scheduling results on disk, keyed by the workload,
+// algorithm and options that produced them, so repeat runs can reuse them.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"p1/internal/scheduler"
+	schedsimversion "p1/internal/version"
+)
+
+// EnvDir is the environment variable that overrides DefaultDir. Setting it
+// to the empty string turns caching off.
+const EnvDir = "SCHEDSIM_CACHE"
+
+// version is part of every key. Bump it whenever a change to the schedulers
+// alters their results, so entries written by older builds are not reused.
+const version = 3
+
+// Cache is a directory of cached results. A nil *Cache caches nothing.
+type Cache struct {
+	dir string
+}
+
+// DefaultDir returns where results are cached unless told otherwise: the
+// directory named by $SCHEDSIM_CACHE if set, or the schedsim directory of the
+// user's cache directory. It returns "" if caching is turned off.
+func DefaultDir() string {
+	if d, ok := os.LookupEnv(EnvDir); ok {
+		return d
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "schedsim", "results")
+}
+
+// New returns a Cache keeping results in dir, or nil if the running build
+// cannot be told apart from others, see build.
+func New(dir string) *Cache {
+	if build() == "" {
+		return nil
+	}
+	return &Cache{dir: dir}
+}
+
+// build identifies the running build in keys, so that entries are not shared
+// across changes to the schedulers. A build of a clean checkout goes by its
+// version and revision. Any other, such as one with uncommitted changes,
+// goes by a hash of its executable, as its version string stays the same
+// from one edit to the next. It is "" if the executable cannot be read.
+var build = sync.OnceValue(func() string {
+	if schedsimversion.Clean() {
+		return schedsimversion.String()
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	f, err := os.Open(exe)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+	return schedsimversion.String() + " " + hex.EncodeToString(h.Sum(nil))
+})
+
+// Key identifies the result of running algorithm over processes with opts.
+func Key(processes []scheduler.Process, algorithm string, opts scheduler.Options) string {
+	b, err := json.Marshal(struct {
+		Version   int                 `json:"version"`
+		Build     string              `json:"build"`
+		Algorithm string              `json:"algorithm"`
+		Options   scheduler.Options   `json:"options"`
+		Processes []scheduler.Process `json:"processes"`
+	}{version, build(), algorithm, opts, processes})
+	if err != nil {
+		panic(err) // only plain data is marshalled
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// Get returns the result cached under key, if any.
+func (c *Cache) Get(key string) (scheduler.Result, bool) {
+	if c == nil {
+		return scheduler.Result{}, false
+	}
+	b, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return scheduler.Result{}, false
+	}
+	var res scheduler.Result
+	if err := json.Unmarshal(b, &res); err != nil {
+		return scheduler.Result{}, false
+	}
+	return res, true
+}
+
+// Put caches res under key.
+func (c *Cache) Put(key string, res scheduler.Result) error {
+	if c == nil {
+		return nil
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	p := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so concurrent readers never see a
+	// partial entry.
+	f, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+// Schedule runs a over processes with sim, reusing the cached result if there
+// is one and caching it otherwise. It reports whether the result came from
+// the cache. Once ctx is cancelled the run stops early, and what it settled
+// is returned without being cached.
+func (c *Cache) Schedule(ctx context.Context, sim *scheduler.Simulator, a scheduler.Algorithm, processes []scheduler.Process) (scheduler.Result, bool, error) {
+	if c == nil {
+		return sim.Stream(ctx, a, processes, scheduler.Sink{}), false, nil
+	}
+	key := Key(processes, a.Name, sim.Options())
+	if res, ok := c.Get(key); ok {
+		return res, true, nil
+	}
+	res := sim.Stream(ctx, a, processes, scheduler.Sink{})
+	if ctx.Err() != nil {
+		return res, false, nil
+	}
+	return res, false, c.Put(key, res)
+}
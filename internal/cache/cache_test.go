@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"p1/internal/scheduler"
+	schedsimversion "p1/internal/version"
+)
+
+func TestSchedule(t *testing.T) {
+	c := New(t.TempDir())
+	sim, err := scheduler.NewSimulator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := scheduler.LookupAlgorithm("rr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	processes := []scheduler.Process{{ProcessID: 1, BurstDuration: 7}, {ProcessID: 2, BurstDuration: 3, ArrivalTime: 1}}
+
+	first, hit, err := c.Schedule(context.Background(), sim, a, processes)
+	if err != nil || hit {
+		t.Fatalf("first run: hit %v, err %v", hit, err)
+	}
+	second, hit, err := c.Schedule(context.Background(), sim, a, processes)
+	if err != nil || !hit {
+		t.Fatalf("second run: hit %v, err %v", hit, err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("cached %+v, want %+v", second, first)
+	}
+
+	other, err := scheduler.NewSimulator(scheduler.WithQuantum(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, hit, _ := c.Schedule(context.Background(), other, a, processes); hit {
+		t.Error("hit for different options")
+	}
+	if _, hit, _ := c.Schedule(context.Background(), sim, a, processes[:1]); hit {
+		t.Error("hit for a different workload")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if res, _, err := c.Schedule(ctx, sim, a, processes[1:]); err != nil || len(res.Stats) != 0 {
+		t.Fatalf("cancelled run: %+v, err %v", res, err)
+	}
+	if _, hit, _ := c.Schedule(context.Background(), sim, a, processes[1:]); hit {
+		t.Error("hit for a cancelled run")
+	}
+}
+
+func TestKey(t *testing.T) {
+	processes := []scheduler.Process{{ProcessID: 1, BurstDuration: 7}}
+	opts := scheduler.DefaultOptions()
+	k := Key(processes, "rr", opts)
+	if k != Key(processes, "rr", opts) {
+		t.Error("key is not stable")
+	}
+	if k == Key(processes, "fcfs", opts) {
+		t.Error("key ignores the algorithm")
+	}
+	opts.Seed = 9
+	if k == Key(processes, "rr", opts) {
+		t.Error("key ignores the options")
+	}
+
+	// Test binaries carry no VCS revision, so go by their executable.
+	if b := build(); b == "" || b == schedsimversion.String() {
+		t.Errorf("build = %q, want the version and a hash of the executable", b)
+	}
+}
+
+func TestNilCache(t *testing.T) {
+	var c *Cache
+	if _, ok := c.Get("00"); ok {
+		t.Error("nil cache hit")
+	}
+	if err := c.Put("00", scheduler.Result{}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,83 @@
+// Package config loads schedsim settings from a JSON file such as
+//
+//	{
+//	  "algorithms": ["rr", "mlfq"],
+//	  "cpus": 2,
+//	  "speeds": [2, 1],
+//	  "fcfs": {"order": "strict"},
+//	  "rr": {"quantum": 4},
+//	  "mlfq": {"levels": 2, "quanta": [2, 8]},
+//	  "aging": {"rate": 0.5, "all": true},
+//	  "fairShare": {"shares": [{"group": "web", "share": 2048}]},
+//	  "classes": {"realtime": "fcfs", "batch": "fairshare"},
+//	  "suspensions": [{"pid": 2, "from": 30, "to": 45}],
+//	  "resumeBoost": true
+//	}
+//
+// Settings left out keep their defaults. Aging applies to priority with
+// aging alone unless all is set, which has sjf and priority age too, and
+// fairShare gives each group of processes its share of the CPU under
+// fairshare. Speeds, one for each CPU, make some CPUs faster than others.
+// Under classes, each class of processes is scheduled by the algorithm
+// classes names for it, or else by its default.
+// Suspensions take processes out of contention between two times, and
+// resumeBoost favours them once they are back.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"p1/internal/scheduler"
+)
+
+// Config holds everything a run of schedsim can be configured with.
+type Config struct {
+	// Algorithms names the algorithms to run, in order.
+	Algorithms []string `json:"algorithms"`
+	scheduler.Options
+}
+
+// Default returns the configuration used when none is given.
+func Default() Config {
+	return Config{
+		Algorithms: []string{"fcfs", "sjf", "priority", "rr"},
+		Options:    scheduler.DefaultOptions(),
+	}
+}
+
+// Validate reports the first unknown algorithm or invalid option in c.
+func (c Config) Validate() error {
+	for _, name := range c.Algorithms {
+		if _, err := scheduler.LookupAlgorithm(name); err != nil {
+			return err
+		}
+	}
+	return c.Options.Validate()
+}
+
+// Load reads a configuration from r on top of the defaults.
+func Load(r io.Reader) (Config, error) {
+	c := Default()
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return Config{}, fmt.Errorf("%w: reading config", err)
+	}
+	if err := c.Validate(); err != nil {
+		return Config{}, err
+	}
+	return c, nil
+}
+
+// LoadFile reads the configuration stored at path.
+func LoadFile(path string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("%v: error opening config file", err)
+	}
+	defer f.Close()
+	return Load(f)
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"p1/internal/scheduler"
+)
+
+func TestLoad(t *testing.T) {
+	c, err := Load(strings.NewReader(`{"algorithms": ["mlfq"], "cpus": 2, "mlfq": {"levels": 2, "quanta": [3, 9]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Default()
+	want.Algorithms = []string{"mlfq"}
+	want.CPUs = 2
+	want.MLFQ = scheduler.MLFQParams{Levels: 2, Quanta: []int64{3, 9}}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("got %+v, want %+v", c, want)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		in   string
+		want error
+	}{
+		{`{"algorithms": ["edf"]}`, scheduler.ErrUnknownAlgorithm},
+		{`{"rr": {"quantum": -1}}`, scheduler.ErrInvalidOption},
+		{`{"mlfq": {"quanta": [1, 2]}}`, scheduler.ErrInvalidOption},
+		{`{"classes": {"batch": "edf"}}`, scheduler.ErrUnknownAlgorithm},
+	}
+	for _, tt := range tests {
+		if _, err := Load(strings.NewReader(tt.in)); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.in, err, tt.want)
+		}
+	}
+	if _, err := Load(strings.NewReader(`{"quantum": 3}`)); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
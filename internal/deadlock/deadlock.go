@@ -0,0 +1,281 @@
+// Package deadlock detects deadlocks in a trace of processes requesting,
+// acquiring and releasing resources, following the wait-for graph between
+// the processes as the trace goes on. Every resource has a single instance,
+// so a cycle in the graph is a deadlock.
+package deadlock
+
+import (
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+)
+
+var (
+	ErrInvalidTrace = errors.New("invalid trace")
+	ErrInvalidRow   = errors.New("invalid row")
+)
+
+// Kind is what happens in an event.
+type Kind string
+
+const (
+	// Request starts a process waiting for a resource.
+	Request Kind = "request"
+	// Acquire gives a free resource to a process, ending its wait for it
+	// if it had requested it.
+	Acquire Kind = "acquire"
+	// Release frees a resource the process holds.
+	Release Kind = "release"
+)
+
+// Event is one step of a trace.
+type Event struct {
+	Time     int64  `json:"time"`
+	Process  string `json:"process"`
+	Kind     Kind   `json:"kind"`
+	Resource string `json:"resource"`
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf("%s %ss %s at %d", e.Process, e.Kind, e.Resource, e.Time)
+}
+
+// Edge is a process waiting for a resource another holds.
+type Edge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Resource string `json:"resource"`
+}
+
+// Step is the wait-for graph after an event.
+type Step struct {
+	Event Event  `json:"event"`
+	Edges []Edge `json:"edges"`
+	// Deadlocked holds the processes on a cycle of the graph.
+	Deadlocked []string `json:"deadlocked"`
+}
+
+// Deadlock is an event that closed a cycle in the wait-for graph.
+type Deadlock struct {
+	// Step is the index of the event in the trace.
+	Step  int   `json:"step"`
+	Event Event `json:"event"`
+	// Cycle is the cycle the event closed, each process waiting for the
+	// next and the last for the first.
+	Cycle []string `json:"cycle"`
+	// Deadlocked holds every process then on a cycle.
+	Deadlocked []string `json:"deadlocked"`
+}
+
+// Result is the analysis of a trace.
+type Result struct {
+	Steps     []Step     `json:"steps"`
+	Deadlocks []Deadlock `json:"deadlocks"`
+}
+
+// Detect replays events, building the wait-for graph after each and
+// reporting every event that deadlocks a process not deadlocked before it.
+// It fails with ErrInvalidTrace for events that cannot happen: acquiring a
+// resource another holds, requesting one already held, or releasing one
+// not held.
+func Detect(events []Event) (Result, error) {
+	holder := make(map[string]string)
+	// waiting maps each process to the resources it has requested and not
+	// yet acquired.
+	waiting := make(map[string][]string)
+	// order numbers the processes and resources by first appearance, so
+	// output follows the trace rather than map order.
+	order := make(map[string]int)
+	seen := func(name string) {
+		if _, ok := order[name]; !ok {
+			order[name] = len(order)
+		}
+	}
+
+	res := Result{Steps: make([]Step, len(events))}
+	var deadlocked []string
+	for i, e := range events {
+		seen(e.Process)
+		seen(e.Resource)
+		if i > 0 && e.Time < events[i-1].Time {
+			return Result{}, fmt.Errorf("%w: event %d (%v) goes back in time", ErrInvalidTrace, i+1, e)
+		}
+		switch e.Kind {
+		case Request:
+			if holder[e.Resource] == e.Process || slices.Contains(waiting[e.Process], e.Resource) {
+				return Result{}, fmt.Errorf("%w: event %d (%v): it holds or has requested it already", ErrInvalidTrace, i+1, e)
+			}
+			waiting[e.Process] = append(waiting[e.Process], e.Resource)
+		case Acquire:
+			if h, ok := holder[e.Resource]; ok {
+				return Result{}, fmt.Errorf("%w: event %d (%v): %s holds it", ErrInvalidTrace, i+1, e, h)
+			}
+			holder[e.Resource] = e.Process
+			waiting[e.Process] = slices.DeleteFunc(waiting[e.Process], func(r string) bool { return r == e.Resource })
+		case Release:
+			if holder[e.Resource] != e.Process {
+				return Result{}, fmt.Errorf("%w: event %d (%v): it does not hold it", ErrInvalidTrace, i+1, e)
+			}
+			delete(holder, e.Resource)
+		default:
+			return Result{}, fmt.Errorf("%w: event %d: unknown kind %q", ErrInvalidTrace, i+1, e.Kind)
+		}
+
+		edges := graph(holder, waiting, order)
+		now := onCycles(edges, order)
+		res.Steps[i] = Step{Event: e, Edges: edges, Deadlocked: now}
+		if slices.ContainsFunc(now, func(p string) bool { return !slices.Contains(deadlocked, p) }) {
+			res.Deadlocks = append(res.Deadlocks, Deadlock{Step: i, Event: e, Cycle: cycle(edges, now, e), Deadlocked: now})
+		}
+		deadlocked = now
+	}
+	return res, nil
+}
+
+// graph returns the edges of the wait-for graph, in the order of their
+// processes and resources.
+func graph(holder map[string]string, waiting map[string][]string, order map[string]int) []Edge {
+	var edges []Edge
+	for p, resources := range waiting {
+		for _, r := range resources {
+			if h, ok := holder[r]; ok {
+				edges = append(edges, Edge{From: p, To: h, Resource: r})
+			}
+		}
+	}
+	slices.SortFunc(edges, func(a, b Edge) int {
+		return cmp.Or(cmp.Compare(order[a.From], order[b.From]), cmp.Compare(order[a.Resource], order[b.Resource]))
+	})
+	return edges
+}
+
+// onCycles returns the processes on a cycle of edges, in order, found as
+// the strongly connected components of more than one process.
+func onCycles(edges []Edge, order map[string]int) []string {
+	next := make(map[string][]string)
+	var nodes []string
+	for _, e := range edges {
+		for _, p := range []string{e.From, e.To} {
+			if _, ok := next[p]; !ok {
+				next[p] = nil
+				nodes = append(nodes, p)
+			}
+		}
+		next[e.From] = append(next[e.From], e.To)
+	}
+
+	// Tarjan's algorithm.
+	var (
+		index   = make(map[string]int)
+		low     = make(map[string]int)
+		stacked = make(map[string]bool)
+		stack   []string
+		on      []string
+		visit   func(p string)
+	)
+	visit = func(p string) {
+		index[p], low[p] = len(index), len(index)
+		stack, stacked[p] = append(stack, p), true
+		for _, q := range next[p] {
+			if _, ok := index[q]; !ok {
+				visit(q)
+				low[p] = min(low[p], low[q])
+			} else if stacked[q] {
+				low[p] = min(low[p], index[q])
+			}
+		}
+		if low[p] != index[p] {
+			return
+		}
+		i := slices.Index(stack, p)
+		component := stack[i:]
+		stack = stack[:i]
+		for _, q := range component {
+			stacked[q] = false
+		}
+		if len(component) > 1 {
+			on = append(on, component...)
+		}
+	}
+	for _, p := range nodes {
+		if _, ok := index[p]; !ok {
+			visit(p)
+		}
+	}
+	slices.SortFunc(on, func(a, b string) int { return cmp.Compare(order[a], order[b]) })
+	return on
+}
+
+// cycle returns a cycle closed by e among the deadlocked processes: one
+// through e's process if there is one, or else through the first of them.
+func cycle(edges []Edge, deadlocked []string, e Event) []string {
+	start := deadlocked[0]
+	if slices.Contains(deadlocked, e.Process) {
+		start = e.Process
+	}
+	// Breadth-first from start for the shortest way back to it.
+	prev := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, edge := range edges {
+			if edge.From != p {
+				continue
+			}
+			if edge.To == start {
+				path := []string{p}
+				for p != start {
+					p = prev[p]
+					path = append(path, p)
+				}
+				slices.Reverse(path)
+				return path
+			}
+			if _, ok := prev[edge.To]; !ok {
+				prev[edge.To] = p
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+	return nil
+}
+
+// Load reads a trace from CSV rows of the form time,process,kind,resource,
+// such as
+//
+//	0,P1,acquire,R1
+//	1,P2,acquire,R2
+//	2,P1,request,R2
+//	3,P2,request,R1
+func Load(r io.Reader) ([]Event, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 4
+	var events []Event
+	for line := 1; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading CSV", err)
+		}
+		t, err := strconv.ParseInt(row[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w %d: %w", ErrInvalidRow, line, err)
+		}
+		e := Event{Time: t, Process: row[1], Kind: Kind(row[2]), Resource: row[3]}
+		switch {
+		case e.Kind != Request && e.Kind != Acquire && e.Kind != Release:
+			return nil, fmt.Errorf("%w %d: unknown kind %q, want request, acquire or release", ErrInvalidRow, line, e.Kind)
+		case e.Process == "" || e.Resource == "":
+			return nil, fmt.Errorf("%w %d: names no process or resource", ErrInvalidRow, line)
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
@@ -0,0 +1,100 @@
+package deadlock
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ring is three processes each holding one resource and then
+// requesting the next, the last request closing the cycle. P4 then waits
+// on the deadlocked P1 without being on the cycle itself.
+const ring = `0,P1,acquire,R1
+1,P2,acquire,R2
+2,P3,acquire,R3
+3,P1,request,R2
+4,P2,request,R3
+5,P3,request,R1
+6,P4,request,R1
+`
+
+func TestDetect(t *testing.T) {
+	events, err := Load(strings.NewReader(ring))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Detect(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Deadlocks) != 1 {
+		t.Fatalf("deadlocks = %+v, want one", res.Deadlocks)
+	}
+	d := res.Deadlocks[0]
+	if d.Step != 5 || d.Event != events[5] || !reflect.DeepEqual(d.Cycle, []string{"P3", "P1", "P2"}) || !reflect.DeepEqual(d.Deadlocked, []string{"P1", "P2", "P3"}) {
+		t.Errorf("deadlock = %+v", d)
+	}
+	if want := []Edge{{"P1", "P2", "R2"}, {"P2", "P3", "R3"}}; !reflect.DeepEqual(res.Steps[4].Edges, want) || res.Steps[4].Deadlocked != nil {
+		t.Errorf("step 4 = %+v, want edges %v and no deadlock", res.Steps[4], want)
+	}
+	if s := res.Steps[6]; len(s.Edges) != 4 || !reflect.DeepEqual(s.Deadlocked, d.Deadlocked) {
+		t.Errorf("step 6 = %+v", s)
+	}
+}
+
+func TestDetectRecovery(t *testing.T) {
+	// P2 releasing R2 breaks the cycle, so P1 can acquire it; a second
+	// cycle then forms over R3.
+	events, err := Load(strings.NewReader(`0,P1,acquire,R1
+0,P2,acquire,R2
+1,P1,request,R2
+2,P2,request,R1
+3,P2,release,R2
+4,P1,acquire,R2
+5,P1,acquire,R3
+6,P2,acquire,R4
+7,P2,request,R3
+8,P1,request,R4
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Detect(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var steps []int
+	for _, d := range res.Deadlocks {
+		steps = append(steps, d.Step)
+	}
+	if !reflect.DeepEqual(steps, []int{3, 9}) || res.Steps[4].Deadlocked != nil {
+		t.Errorf("deadlocks at %v, steps %+v", steps, res.Steps)
+	}
+	if c := res.Deadlocks[1].Cycle; !reflect.DeepEqual(c, []string{"P1", "P2"}) {
+		t.Errorf("cycle = %v", c)
+	}
+}
+
+func TestDetectInvalid(t *testing.T) {
+	for _, trace := range []string{
+		"0,P1,acquire,R1\n1,P2,acquire,R1\n",
+		"0,P1,release,R1\n",
+		"0,P1,acquire,R1\n1,P1,request,R1\n",
+		"0,P1,request,R1\n1,P1,request,R1\n",
+		"1,P1,request,R1\n0,P1,request,R2\n",
+	} {
+		events, err := Load(strings.NewReader(trace))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Detect(events); !errors.Is(err, ErrInvalidTrace) {
+			t.Errorf("%q: err = %v, want %v", trace, err, ErrInvalidTrace)
+		}
+	}
+	for _, trace := range []string{"0,P1,lock,R1\n", "x,P1,request,R1\n", "0,,request,R1\n", "0,P1,request\n"} {
+		if _, err := Load(strings.NewReader(trace)); err == nil {
+			t.Errorf("%q: expected an error", trace)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Package disk simulates disk scheduling: the order in which the head of a
+// disk serves a queue of cylinder requests, and how far it travels doing so.
+package disk
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrUnknownAlgorithm = errors.New("unknown disk scheduling algorithm")
+	ErrInvalidParams    = errors.New("invalid disk parameters")
+	ErrInvalidRequest   = errors.New("invalid cylinder request")
+)
+
+// DefaultCylinders is the size of the disk unless told otherwise, that of
+// the usual textbook examples.
+const DefaultCylinders = 200
+
+// Params describes the disk and where its head starts.
+type Params struct {
+	// Head is the cylinder the head starts at.
+	Head int64 `json:"head"`
+	// Cylinders is how many cylinders the disk has, numbered from 0.
+	Cylinders int64 `json:"cylinders"`
+	// Down starts the head moving toward cylinder 0 rather than away
+	// from it, for the algorithms that sweep.
+	Down bool `json:"down,omitempty"`
+}
+
+// DefaultParams returns the parameters used unless told otherwise.
+func DefaultParams() Params {
+	return Params{Cylinders: DefaultCylinders}
+}
+
+// Check fails with ErrInvalidParams if the head is not on the disk, or with
+// ErrInvalidRequest if one of requests is not.
+func (p Params) Check(requests []int64) error {
+	if p.Cylinders < 1 {
+		return fmt.Errorf("%w: a disk needs at least one cylinder, got %d", ErrInvalidParams, p.Cylinders)
+	}
+	if p.Head < 0 || p.Head >= p.Cylinders {
+		return fmt.Errorf("%w: head %d is not within cylinders 0-%d", ErrInvalidParams, p.Head, p.Cylinders-1)
+	}
+	for i, c := range requests {
+		if c < 0 || c >= p.Cylinders {
+			return fmt.Errorf("%w %d: cylinder %d is not within 0-%d", ErrInvalidRequest, i+1, c, p.Cylinders-1)
+		}
+	}
+	return nil
+}
+
+// Seek is one movement of the head.
+type Seek struct {
+	Cylinder int64 `json:"cylinder"`
+	// Request is set if the head serves a request at the cylinder, and not
+	// if it only sweeps to an end of the disk.
+	Request bool `json:"request"`
+}
+
+// Result is the outcome of serving a queue of requests.
+type Result struct {
+	// Head is the cylinder the head starts at.
+	Head  int64  `json:"head"`
+	Seeks []Seek `json:"seeks"`
+	// Movement is how many cylinders the head travels over all its seeks,
+	// the return of a circular sweep included.
+	Movement int64 `json:"movement"`
+}
+
+// Order returns the cylinders of the requests in the order they are served.
+func (r Result) Order() []int64 {
+	var order []int64
+	for _, s := range r.Seeks {
+		if s.Request {
+			order = append(order, s.Cylinder)
+		}
+	}
+	return order
+}
+
+// Algorithm describes a disk scheduling algorithm available to front ends.
+type Algorithm struct {
+	// Name is the short identifier used on command lines.
+	Name string
+	// Title is the heading the algorithm's results are shown under.
+	Title string
+	// Plan returns the seeks serving every request, given a disk they
+	// are all on.
+	Plan func(requests []int64, p Params) []Seek
+}
+
+// Algorithms returns every algorithm in the order they are usually shown.
+func Algorithms() []Algorithm {
+	return []Algorithm{
+		{"fcfs", "First-come, first-serve", fcfs},
+		{"sstf", "Shortest-seek-time-first", sstf},
+		{"scan", "SCAN", func(r []int64, p Params) []Seek { return sweep(r, p, false, true) }},
+		{"c-scan", "C-SCAN", func(r []int64, p Params) []Seek { return sweep(r, p, true, true) }},
+		{"look", "LOOK", func(r []int64, p Params) []Seek { return sweep(r, p, false, false) }},
+		{"c-look", "C-LOOK", func(r []int64, p Params) []Seek { return sweep(r, p, true, false) }},
+	}
+}
+
+// LookupAlgorithm returns the algorithm with the given name.
+func LookupAlgorithm(name string) (Algorithm, error) {
+	for _, a := range Algorithms() {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+	return Algorithm{}, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, name)
+}
+
+// Schedule serves requests, the cylinders in the order they were made, on
+// the disk p with algorithm a.
+func Schedule(a Algorithm, requests []int64, p Params) (Result, error) {
+	if err := p.Check(requests); err != nil {
+		return Result{}, err
+	}
+	res := Result{Head: p.Head, Seeks: a.Plan(requests, p)}
+	at := p.Head
+	for _, s := range res.Seeks {
+		res.Movement += max(s.Cylinder-at, at-s.Cylinder)
+		at = s.Cylinder
+	}
+	return res, nil
+}
+
+func served(cylinders ...int64) []Seek {
+	seeks := make([]Seek, len(cylinders))
+	for i, c := range cylinders {
+		seeks[i] = Seek{Cylinder: c, Request: true}
+	}
+	return seeks
+}
+
+// fcfs serves the requests in the order they were made.
+func fcfs(requests []int64, _ Params) []Seek {
+	return served(requests...)
+}
+
+// sstf serves the request nearest the head next, the one in the direction
+// of travel if two are as near.
+func sstf(requests []int64, p Params) []Seek {
+	sorted := slices.Sorted(slices.Values(requests))
+	// The requests served so far are always sorted[lo:hi], the ones
+	// between the first and the latest served.
+	lo, _ := slices.BinarySearch(sorted, p.Head)
+	hi, at, down := lo, p.Head, p.Down
+	seeks := make([]Seek, 0, len(sorted))
+	for lo > 0 || hi < len(sorted) {
+		below, above := lo > 0, hi < len(sorted)
+		if below && above {
+			if d, u := at-sorted[lo-1], sorted[hi]-at; d != u {
+				below = d < u
+			} else {
+				below = down
+			}
+		}
+		if below {
+			lo--
+			at, down = sorted[lo], true
+		} else {
+			at, down = sorted[hi], false
+			hi++
+		}
+		seeks = append(seeks, Seek{Cylinder: at, Request: true})
+	}
+	return seeks
+}
+
+// sweep serves the requests ahead of the head in its direction of travel,
+// nearest first, then the ones behind it. The head turns back to serve
+// them, or carries on from the other end of the disk if circular. It
+// travels to the end of the disk first if toEnd, but only if there are
+// requests left behind it; otherwise it turns at the last request.
+func sweep(requests []int64, p Params, circular, toEnd bool) []Seek {
+	sorted := slices.Sorted(slices.Values(requests))
+	// Requests at the head are served before it moves.
+	var ahead, behind []int64
+	near, far := p.Cylinders-1, int64(0)
+	if p.Down {
+		i, _ := slices.BinarySearch(sorted, p.Head+1)
+		ahead, behind = slices.Clone(sorted[:i]), sorted[i:]
+		slices.Reverse(ahead)
+		near, far = far, near
+	} else {
+		i, _ := slices.BinarySearch(sorted, p.Head)
+		ahead, behind = sorted[i:], slices.Clone(sorted[:i])
+		slices.Reverse(behind)
+	}
+
+	seeks := served(ahead...)
+	if len(behind) == 0 {
+		return seeks
+	}
+	last := p.Head
+	if len(ahead) > 0 {
+		last = ahead[len(ahead)-1]
+	}
+	if toEnd && last != near {
+		seeks = append(seeks, Seek{Cylinder: near})
+	}
+	if circular {
+		slices.Reverse(behind)
+		if toEnd && behind[0] != far {
+			seeks = append(seeks, Seek{Cylinder: far})
+		}
+	}
+	return append(seeks, served(behind...)...)
+}
@@ -0,0 +1,91 @@
+package disk
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// textbook is the usual example queue, served from cylinder 53 of 200.
+var textbook = []int64{98, 183, 37, 122, 14, 124, 65, 67}
+
+func TestSchedule(t *testing.T) {
+	for _, tc := range []struct {
+		algorithm string
+		down      bool
+		order     []int64
+		movement  int64
+		ends      []int64
+	}{
+		{"fcfs", false, textbook, 640, nil},
+		{"sstf", false, []int64{65, 67, 37, 14, 98, 122, 124, 183}, 236, nil},
+		{"scan", true, []int64{37, 14, 65, 67, 98, 122, 124, 183}, 236, []int64{0}},
+		{"scan", false, []int64{65, 67, 98, 122, 124, 183, 37, 14}, 331, []int64{199}},
+		{"c-scan", false, []int64{65, 67, 98, 122, 124, 183, 14, 37}, 382, []int64{199, 0}},
+		{"c-scan", true, []int64{37, 14, 183, 124, 122, 98, 67, 65}, 386, []int64{0, 199}},
+		{"look", false, []int64{65, 67, 98, 122, 124, 183, 37, 14}, 299, nil},
+		{"c-look", false, []int64{65, 67, 98, 122, 124, 183, 14, 37}, 322, nil},
+		{"c-look", true, []int64{37, 14, 183, 124, 122, 98, 67, 65}, 326, nil},
+	} {
+		a, err := LookupAlgorithm(tc.algorithm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := DefaultParams()
+		p.Head, p.Down = 53, tc.down
+		res, err := Schedule(a, textbook, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ends []int64
+		for _, s := range res.Seeks {
+			if !s.Request {
+				ends = append(ends, s.Cylinder)
+			}
+		}
+		if !reflect.DeepEqual(res.Order(), tc.order) || res.Movement != tc.movement || !reflect.DeepEqual(ends, tc.ends) {
+			t.Errorf("%s down %v: order %v, movement %d, ends %v; want %v, %d, %v", tc.algorithm, tc.down, res.Order(), res.Movement, ends, tc.order, tc.movement, tc.ends)
+		}
+	}
+}
+
+func TestScheduleEdges(t *testing.T) {
+	p := Params{Head: 50, Cylinders: 100}
+	for _, a := range Algorithms() {
+		// Nothing behind the head: the sweeps need not reach the end.
+		res, err := Schedule(a, []int64{50, 60, 50, 70}, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := int64(20); a.Name != "fcfs" && (res.Movement != want || len(res.Seeks) != 4) {
+			t.Errorf("%s: seeks %v, movement %d, want %d", a.Name, res.Seeks, res.Movement, want)
+		}
+		if res, err = Schedule(a, nil, p); err != nil || res.Movement != 0 || len(res.Seeks) != 0 {
+			t.Errorf("%s with no requests: %+v, %v", a.Name, res, err)
+		}
+	}
+
+	a, _ := LookupAlgorithm("c-scan")
+	// Requests at both ends need no extra seeks to reach them.
+	if res, _ := Schedule(a, []int64{99, 0, 10}, p); !reflect.DeepEqual(res.Seeks, served(99, 0, 10)) || res.Movement != 49+99+10 {
+		t.Errorf("c-scan: seeks %v, movement %d", res.Seeks, res.Movement)
+	}
+
+	for _, tc := range []struct {
+		p        Params
+		requests []int64
+		want     error
+	}{
+		{Params{Cylinders: 0}, nil, ErrInvalidParams},
+		{Params{Head: 100, Cylinders: 100}, nil, ErrInvalidParams},
+		{p, []int64{1, 100}, ErrInvalidRequest},
+		{p, []int64{-1}, ErrInvalidRequest},
+	} {
+		if _, err := Schedule(a, tc.requests, tc.p); !errors.Is(err, tc.want) {
+			t.Errorf("%+v %v: err = %v, want %v", tc.p, tc.requests, err, tc.want)
+		}
+	}
+	if _, err := LookupAlgorithm("elevator"); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("err = %v, want %v", err, ErrUnknownAlgorithm)
+	}
+}
@@ -0,0 +1,56 @@
+// Package examples holds a library of canonical workloads that show off how
+// the algorithms differ, built into the binary so they need no files.
+package examples
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"p1/internal/input"
+	"p1/internal/scheduler"
+)
+
+var ErrUnknown = errors.New("unknown example")
+
+//go:embed workloads/*.csv
+var workloads embed.FS
+
+// Example describes one workload of the library.
+type Example struct {
+	Name        string
+	Description string
+}
+
+// library lists the examples in the order they are shown. Each is stored as
+// workloads/<name>.csv.
+var library = []Example{
+	{"convoy", "a long job arriving first holds up the short ones behind it under FCFS"},
+	{"starvation", "short, important jobs keep arriving so a long, unimportant one waits behind every one of them under SJF and priority"},
+	{"deadlines", "bursts of short jobs arriving together, each due twice its burst after it arrives, where response time matters most"},
+	{"io-mix", "short I/O-bound bursts mixed with long CPU-bound jobs, which MLFQ tells apart"},
+}
+
+// List returns every example.
+func List() []Example { return append([]Example(nil), library...) }
+
+// Open returns the workload file of the named example, in CSV.
+func Open(name string) (fs.File, error) {
+	for _, e := range library {
+		if e.Name == name {
+			return workloads.Open("workloads/" + name + ".csv")
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknown, name)
+}
+
+// Load returns the processes of the named example.
+func Load(name string) ([]scheduler.Process, error) {
+	f, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return input.LoadProcesses(f)
+}
@@ -0,0 +1,32 @@
+package examples
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	for _, e := range List() {
+		processes, err := Load(e.Name)
+		if err != nil {
+			t.Errorf("%s: %v", e.Name, err)
+		} else if len(processes) == 0 {
+			t.Errorf("%s: no processes", e.Name)
+		}
+	}
+	if _, err := Load("nope"); !errors.Is(err, ErrUnknown) {
+		t.Errorf("err = %v, want %v", err, ErrUnknown)
+	}
+}
+
+// TestLibrary checks that every embedded workload is listed.
+func TestLibrary(t *testing.T) {
+	files, err := fs.Glob(workloads, "workloads/*.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != len(library) {
+		t.Errorf("%d workload files embedded, %d listed", len(files), len(library))
+	}
+}
@@ -0,0 +1,172 @@
+package experiment
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"p1/internal/scheduler"
+)
+
+// ErrStaleCheckpoint is returned resuming from a checkpoint of another
+// matrix, or of workloads that have changed since.
+var ErrStaleCheckpoint = errors.New("checkpoint is of another matrix or workloads")
+
+// syncEvery is how often a checkpoint is flushed to disk as rows are added.
+const syncEvery = time.Second
+
+// header is the first line of a checkpoint file. Every line after it is a
+// Row in JSON.
+type header struct {
+	Digest string `json:"digest"`
+}
+
+// Resume performs the runs of m like Execute, appending each row to the
+// checkpoint file at path as its run completes and skipping the runs an
+// earlier call recorded there, so an interrupted batch picks up where it
+// stopped. The file is created if need be and kept once every run is done;
+// one left by another matrix or other workloads is refused with
+// ErrStaleCheckpoint. A row cut short by a crash is dropped and run again.
+func (m Matrix) Resume(ctx context.Context, workers int, load func(workload string) ([]scheduler.Process, error), path string) (rows []Row, err error) {
+	workloads, err := m.load(load)
+	if err != nil {
+		return nil, err
+	}
+	c, err := openCheckpoint(path, digest(m, workloads))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := c.close(); err == nil && cerr != nil {
+			err = fmt.Errorf("%w: closing checkpoint", cerr)
+		}
+	}()
+	return m.execute(ctx, workers, workloads, c)
+}
+
+// digest identifies m and the workloads it was loaded with.
+func digest(m Matrix, workloads map[string][]scheduler.Process) string {
+	b, _ := json.Marshal(struct {
+		Matrix    Matrix                         `json:"matrix"`
+		Workloads map[string][]scheduler.Process `json:"workloads"`
+	}{m, workloads})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// checkpoint is an open checkpoint file and the rows it holds.
+type checkpoint struct {
+	mu     sync.Mutex
+	f      *os.File
+	done   map[Run]Row
+	synced time.Time
+}
+
+// openCheckpoint opens the checkpoint at path for the matrix with digest d,
+// reading the rows already in it and truncating any cut short.
+func openCheckpoint(path, d string) (*checkpoint, error) {
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: reading checkpoint", err)
+	}
+	c := &checkpoint{done: make(map[Run]Row), synced: time.Now()}
+
+	// Only whole lines count: a write interrupted part way leaves a last
+	// line without its newline.
+	var valid int
+	for i := 0; ; i++ {
+		end := bytes.IndexByte(b[valid:], '\n')
+		if end < 0 {
+			break
+		}
+		line := b[valid : valid+end]
+		if i == 0 {
+			var h header
+			if err := json.Unmarshal(line, &h); err != nil {
+				return nil, fmt.Errorf("%w: reading checkpoint header", err)
+			}
+			if h.Digest != d {
+				return nil, fmt.Errorf("%w: %s", ErrStaleCheckpoint, path)
+			}
+		} else {
+			var r Row
+			if err := json.Unmarshal(line, &r); err != nil {
+				return nil, fmt.Errorf("%w: reading checkpoint line %d", err, i+1)
+			}
+			c.done[r.Run] = r
+		}
+		valid += end + 1
+	}
+
+	if c.f, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644); err != nil {
+		return nil, fmt.Errorf("%w: opening checkpoint", err)
+	}
+	if err := c.f.Truncate(int64(valid)); err != nil {
+		_ = c.f.Close()
+		return nil, fmt.Errorf("%w: truncating checkpoint", err)
+	}
+	if _, err := c.f.Seek(int64(valid), io.SeekStart); err != nil {
+		_ = c.f.Close()
+		return nil, fmt.Errorf("%w: seeking checkpoint", err)
+	}
+	if valid == 0 {
+		if err := c.write(header{d}); err != nil {
+			_ = c.f.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// add records r, syncing the file if it has not been for a while.
+func (c *checkpoint) add(r Row) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.write(r); err != nil {
+		return err
+	}
+	if time.Since(c.synced) < syncEvery {
+		return nil
+	}
+	c.synced = time.Now()
+	if err := c.f.Sync(); err != nil {
+		return fmt.Errorf("%w: syncing checkpoint", err)
+	}
+	return nil
+}
+
+// write appends v to the file as one line, in a single write so that an
+// interruption cuts short at most the last line.
+func (c *checkpoint) write(v any) error {
+	b, _ := json.Marshal(v)
+	if _, err := c.f.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("%w: writing checkpoint", err)
+	}
+	return nil
+}
+
+func (c *checkpoint) close() error {
+	if err := c.f.Sync(); err != nil {
+		_ = c.f.Close()
+		return err
+	}
+	return c.f.Close()
+}
+
+// row returns the row recorded for run before c was opened, if any. A nil
+// checkpoint has none.
+func (c *checkpoint) row(run Run) (Row, bool) {
+	if c == nil {
+		return Row{}, false
+	}
+	r, ok := c.done[run]
+	return r, ok
+}
@@ -0,0 +1,424 @@
+package experiment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+var workload = []scheduler.Process{
+	{ProcessID: 1, BurstDuration: 6},
+	{ProcessID: 2, BurstDuration: 4, ArrivalTime: 1},
+	{ProcessID: 3, BurstDuration: 2, ArrivalTime: 2},
+}
+
+func TestWithQuantum(t *testing.T) {
+	opts := WithQuantum(scheduler.DefaultOptions(), 3)
+	if opts.RR.Quantum != 3 || !reflect.DeepEqual(opts.MLFQ.Quanta, []int64{3, 6, 12}) {
+		t.Errorf("got rr %d, mlfq %v", opts.RR.Quantum, opts.MLFQ.Quanta)
+	}
+	if base := scheduler.DefaultOptions().MLFQ.Quanta; base[0] != scheduler.DefaultQuantum {
+		t.Errorf("defaults changed: %v", base)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	points, err := Sweep(workload, []string{"rr", "mlfq"}, []int64{1, 100}, scheduler.DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 4 || points[0].Quantum != 1 || points[1].Algorithm != "mlfq" || points[2].Quantum != 100 {
+		t.Fatalf("points = %+v", points)
+	}
+	// A quantum longer than every burst makes round-robin first-come,
+	// first-serve.
+	fcfs := scheduler.FCFS(workload)
+	if rr := points[2]; rr.AveWait != fcfs.AveWait || rr.ContextSwitches != fcfs.ContextSwitches {
+		t.Errorf("quantum 100 = %+v, want FCFS %+v", rr, fcfs)
+	}
+	if points[0].ContextSwitches <= points[2].ContextSwitches {
+		t.Errorf("quantum 1 switched %d times, quantum 100 %d", points[0].ContextSwitches, points[2].ContextSwitches)
+	}
+
+	if _, err := Sweep(workload, []string{"fcfs"}, []int64{1}, scheduler.DefaultOptions()); !errors.Is(err, scheduler.ErrInvalidOption) {
+		t.Errorf("err = %v, want %v", err, scheduler.ErrInvalidOption)
+	}
+}
+
+func TestFairness(t *testing.T) {
+	even := scheduler.Result{Stats: []scheduler.Stat{
+		{Process: scheduler.Process{BurstDuration: 2}, Turnaround: 4},
+		{Process: scheduler.Process{BurstDuration: 5}, Turnaround: 10},
+	}}
+	if f := Fairness(even); f != 1 {
+		t.Errorf("even slowdowns: fairness %v, want 1", f)
+	}
+	uneven := scheduler.Result{Stats: []scheduler.Stat{
+		{Process: scheduler.Process{BurstDuration: 1}, Turnaround: 1},
+		{Process: scheduler.Process{BurstDuration: 1}, Turnaround: 9},
+	}}
+	if f := Fairness(uneven); f >= 0.7 {
+		t.Errorf("uneven slowdowns: fairness %v, want below 0.7", f)
+	}
+}
+
+func TestPareto(t *testing.T) {
+	metrics, err := LookupMetrics([]string{"wait", "switches"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := []render.Named{
+		{Algorithm: "a", Result: scheduler.Result{AveWait: 2, ContextSwitches: 5}},
+		{Algorithm: "b", Result: scheduler.Result{AveWait: 4, ContextSwitches: 1}},
+		{Algorithm: "c", Result: scheduler.Result{AveWait: 4, ContextSwitches: 5}},
+		{Algorithm: "d", Result: scheduler.Result{AveWait: 2, ContextSwitches: 5}},
+	}
+	var got [][]string
+	for _, s := range Pareto(results, metrics) {
+		got = append(got, s.DominatedBy)
+	}
+	if want := [][]string{nil, nil, {"a", "b", "d"}, nil}; !reflect.DeepEqual(got, want) {
+		t.Errorf("dominated by %v, want %v", got, want)
+	}
+
+	if _, err := LookupMetrics([]string{"deadlines"}); !errors.Is(err, scheduler.ErrInvalidOption) {
+		t.Errorf("err = %v, want %v", err, scheduler.ErrInvalidOption)
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	metrics, err := LookupMetrics([]string{"wait", "fairness"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	misses, err := DeadlineMisses(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics = append(metrics, misses)
+	stats := func(turnarounds ...int64) []scheduler.Stat {
+		var s []scheduler.Stat
+		for _, ta := range turnarounds {
+			s = append(s, scheduler.Stat{Process: scheduler.Process{BurstDuration: 2}, Turnaround: ta})
+		}
+		return s
+	}
+	results := []render.Named{
+		{Algorithm: "a", Result: scheduler.Result{AveWait: 2, Stats: stats(2, 6)}},
+		{Algorithm: "b", Result: scheduler.Result{AveWait: 3, Stats: stats(4, 4)}},
+		{Algorithm: "c", Result: scheduler.Result{AveWait: 2, Stats: stats(4, 5)}},
+	}
+	recs := Recommend(results, metrics)
+	var got []string
+	for _, r := range recs {
+		got = append(got, fmt.Sprintf("%v %v %s %v", r.Best, r.Value, r.RunnerUp, r.RunnerUpValue))
+	}
+	// a misses one deadline, taking 6 ticks over a burst of 2, and c one.
+	if want := []string{"[a c] 2 b 3", "[b] 1 c 0.9878048780487805", "[b] 0 a 1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("recommendations %q, want %q", got, want)
+	}
+
+	// Deadlines given take the place of the factor: b misses P1's, and a
+	// no longer misses P2's.
+	for _, n := range results {
+		n.Result.Stats[0].Deadline, n.Result.Stats[0].Completion = 3, n.Result.Stats[0].Turnaround
+		n.Result.Stats[1].Deadline, n.Result.Stats[1].Completion = 6, n.Result.Stats[1].Turnaround
+	}
+	if got, want := misses.Value(results[0].Result), 0.0; got != want {
+		t.Errorf("a misses %v deadlines, want %v", got, want)
+	}
+	if got, want := misses.Value(results[1].Result), 1.0; got != want {
+		t.Errorf("b misses %v deadlines, want %v", got, want)
+	}
+
+	if recs := Recommend(results[:1], metrics); recs[0].RunnerUp != "" {
+		t.Errorf("runner-up %q of one algorithm", recs[0].RunnerUp)
+	}
+	for _, factor := range []float64{0.5, math.NaN(), math.Inf(1)} {
+		if _, err := DeadlineMisses(factor); !errors.Is(err, scheduler.ErrInvalidOption) {
+			t.Errorf("factor %v: err = %v, want %v", factor, err, scheduler.ErrInvalidOption)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	weights, err := ParseWeights([]string{"wait=3", "fairness=1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := []render.Named{
+		{Algorithm: "a", Result: scheduler.Result{AveWait: 4, Stats: []scheduler.Stat{{Process: scheduler.Process{BurstDuration: 1}, Turnaround: 1}}}},
+		{Algorithm: "b", Result: scheduler.Result{AveWait: 2, Stats: []scheduler.Stat{{Process: scheduler.Process{BurstDuration: 1}, Turnaround: 1}, {Process: scheduler.Process{BurstDuration: 1}, Turnaround: 3}}}},
+		{Algorithm: "c", Result: scheduler.Result{AveWait: 3, Stats: []scheduler.Stat{{Process: scheduler.Process{BurstDuration: 1}, Turnaround: 1}}}},
+		{Algorithm: "d", Result: scheduler.Result{AveWait: 2, Stats: []scheduler.Stat{{Process: scheduler.Process{BurstDuration: 1}, Turnaround: 1}, {Process: scheduler.Process{BurstDuration: 1}, Turnaround: 3}}}},
+	}
+	var got []string
+	for _, r := range Rank(results, weights) {
+		got = append(got, fmt.Sprintf("%d %s %.3f", r.Rank, r.Algorithm, r.Score))
+	}
+	if want := []string{"1 b 0.750", "1 d 0.750", "3 c 0.625", "4 a 0.250"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ranked %v, want %v", got, want)
+	}
+
+	for _, specs := range [][]string{{"wait"}, {"deadlines=1"}, {"wait=-1"}, {"wait=x"}, {"wait=NaN"}, {"wait=Inf"}, {"wait=1", "wait=2"}, {"wait=0"}} {
+		if _, err := ParseWeights(specs); !errors.Is(err, scheduler.ErrInvalidOption) {
+			t.Errorf("%v: err = %v, want %v", specs, err, scheduler.ErrInvalidOption)
+		}
+	}
+}
+
+func TestOptimal(t *testing.T) {
+	// Shortest remaining burst first: P1 runs for a tick, P3 from 2 to 4,
+	// P2 to 7 and P1 to 12, waiting 6, 2 and 0.
+	if got, want := Optimal(workload, scheduler.DefaultOptions()), (Optimum{8.0 / 3, true}); got != want {
+		t.Errorf("optimum %+v, want %+v", got, want)
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for run := range 300 {
+		processes := make([]scheduler.Process, 1+rng.IntN(7))
+		for i := range processes {
+			processes[i] = scheduler.Process{ProcessID: int64(i + 1), ArrivalTime: rng.Int64N(9), BurstDuration: rng.Int64N(7)}
+		}
+		opts := scheduler.DefaultOptions()
+		opts.CPUs, opts.SwitchCost = 1+rng.IntN(3), rng.Int64N(3)
+		o := Optimal(processes, opts)
+		if !o.Exact {
+			t.Fatalf("run %d: no optimum of %+v", run, processes)
+		}
+		sim, err := scheduler.NewSimulator(scheduler.WithOptions(opts))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, a := range scheduler.Algorithms() {
+			if res := sim.Schedule(a, processes); res.AveWait < o.Wait-1e-9 {
+				t.Errorf("run %d: %s waits %v on average, less than the optimum %v, on %d CPUs switching at %d: %+v", run, a.Name, res.AveWait, o.Wait, opts.CPUs, opts.SwitchCost, processes)
+			}
+		}
+		// On one CPU switching for free the search finds what running
+		// the shortest remaining burst first does.
+		release, burst := make([]int64, len(processes)), make([]int64, len(processes))
+		for i, p := range processes {
+			release[i], burst[i] = p.ArrivalTime, p.BurstDuration
+		}
+		if best, ok := searchOptimal(release, burst, 1, 0); !ok || float64(best) != completionBound(0, release, burst, []int64{1}) {
+			t.Errorf("run %d: searched %d, %v, want %v", run, best, ok, completionBound(0, release, burst, []int64{1}))
+		}
+	}
+
+	// Too many processes to search leave a lower bound.
+	var many []scheduler.Process
+	for i := range MaxOptimal + 2 {
+		many = append(many, scheduler.Process{ProcessID: int64(i + 1), ArrivalTime: int64(i), BurstDuration: int64(1 + i%4)})
+	}
+	opts := scheduler.DefaultOptions()
+	opts.CPUs = 2
+	o := Optimal(many, opts)
+	sim, err := scheduler.NewSimulator(scheduler.WithOptions(opts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := sim.Schedule(scheduler.Algorithms()[0], many); o.Exact || o.Wait > res.AveWait {
+		t.Errorf("bound %+v over %v", o, res.AveWait)
+	}
+
+	// So do CPUs of differing speeds.
+	for run := range 300 {
+		processes := make([]scheduler.Process, 1+rng.IntN(7))
+		for i := range processes {
+			processes[i] = scheduler.Process{ProcessID: int64(i + 1), ArrivalTime: rng.Int64N(9), BurstDuration: rng.Int64N(7)}
+		}
+		speeds := make([]int64, 1+rng.IntN(3))
+		for c := range speeds {
+			speeds[c] = 1 + rng.Int64N(3)
+		}
+		sim, err := scheduler.NewSimulator(scheduler.WithSpeeds(speeds...), scheduler.WithSwitchCost(rng.Int64N(3)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		o := Optimal(processes, sim.Options())
+		for _, a := range scheduler.Algorithms() {
+			if res := sim.Schedule(a, processes); res.AveWait < o.Wait-1e-9 {
+				t.Errorf("run %d: %s waits %v on average, less than the bound %v, on CPUs of speeds %v: %+v", run, a.Name, res.AveWait, o.Wait, speeds, processes)
+			}
+		}
+	}
+
+	if got := Gap(3, Optimum{Wait: 2}); got != 50 {
+		t.Errorf("gap %v, want 50", got)
+	}
+	if got := Gap(1, Optimum{}); !math.IsInf(got, 1) {
+		t.Errorf("gap over no wait %v, want +Inf", got)
+	}
+	if got := Gap(0, Optimum{}); got != 0 {
+		t.Errorf("gap at no wait %v, want 0", got)
+	}
+}
+
+func TestPerturbation(t *testing.T) {
+	for spec, want := range map[string][]scheduler.Process{
+		"remove:2":  {workload[0], workload[2]},
+		"delay:3:4": {workload[0], workload[1], {ProcessID: 3, BurstDuration: 2, ArrivalTime: 6}},
+		"halve:1":   {{ProcessID: 1, BurstDuration: 3}, workload[1], workload[2]},
+	} {
+		var p Perturbation
+		if err := p.UnmarshalText([]byte(spec)); err != nil {
+			t.Fatal(err)
+		}
+		if text, _ := p.MarshalText(); string(text) != spec {
+			t.Errorf("%s written %q", spec, text)
+		}
+		got, err := p.Apply(workload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: %+v, want %+v", p, got, want)
+		}
+	}
+	if workload[0].BurstDuration != 6 {
+		t.Errorf("workload changed: %+v", workload)
+	}
+
+	for _, spec := range []string{"", "double:1", "remove", "remove:x", "halve:1:2", "delay:1", "delay:1:0", "delay:1:-2"} {
+		var p Perturbation
+		if err := p.UnmarshalText([]byte(spec)); !errors.Is(err, scheduler.ErrInvalidOption) {
+			t.Errorf("%q: err = %v, want %v", spec, err, scheduler.ErrInvalidOption)
+		}
+	}
+	if _, err := (Perturbation{Change: Remove, PID: 9}).Apply(workload); !errors.Is(err, ErrNoProcess) {
+		t.Errorf("err = %v, want %v", err, ErrNoProcess)
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	m, err := LoadMatrix(strings.NewReader(`{"workloads": ["w"], "algorithms": ["fcfs", "rr"], "quanta": [1, 100], "cpus": [1, 2]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	runs := m.Runs()
+	want := []Run{
+		{"w", "fcfs", 0, 1, 0}, {"w", "fcfs", 0, 2, 0},
+		{"w", "rr", 1, 1, 0}, {"w", "rr", 1, 2, 0}, {"w", "rr", 100, 1, 0}, {"w", "rr", 100, 2, 0},
+	}
+	if !reflect.DeepEqual(runs, want) {
+		t.Fatalf("runs = %v, want %v", runs, want)
+	}
+
+	rows, err := m.Execute(context.Background(), 3, func(string) ([]scheduler.Process, error) { return workload, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range rows {
+		if r.Run != want[i] {
+			t.Errorf("row %d is for %v, want %v", i, r.Run, want[i])
+		}
+	}
+	if fcfs := scheduler.FCFS(workload); rows[0].AveWait != fcfs.AveWait || rows[4].AveWait != fcfs.AveWait {
+		t.Errorf("rows = %+v, want fcfs wait %v", rows, fcfs.AveWait)
+	}
+	if rows[1].AveWait >= rows[0].AveWait {
+		t.Errorf("two CPUs waited %v, one %v", rows[1].AveWait, rows[0].AveWait)
+	}
+
+	var buf strings.Builder
+	if err := WriteCSV(&buf, rows[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if want := "workload,algorithm,quantum,cpus,switch_cost,average_wait,average_turnaround,throughput,context_switches\nw,fcfs,,1,0,"; !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("csv = %q, want prefix %q", buf.String(), want)
+	}
+
+	for _, in := range []string{`{"algorithms": ["rr"]}`, `{"workloads": ["w"], "cpus": [0]}`, `{"workloads": ["w"], "algorithms": ["nope"]}`} {
+		if _, err := LoadMatrix(strings.NewReader(in)); err == nil {
+			t.Errorf("%s: expected an error", in)
+		}
+	}
+}
+
+func TestResume(t *testing.T) {
+	m, err := LoadMatrix(strings.NewReader(`{"workloads": ["w"], "algorithms": ["fcfs", "rr", "sjf"], "quanta": [1, 2], "cpus": [1, 2]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	load := func(string) ([]scheduler.Process, error) { return workload, nil }
+	want, err := m.Execute(context.Background(), 2, load)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	rows, err := m.Resume(context.Background(), 2, load, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows = %+v, want %+v", rows, want)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(b), "\n")
+	if len(lines) != len(want)+2 || lines[len(lines)-1] != "" {
+		t.Fatalf("checkpoint has %d lines for %d runs:\n%s", len(lines)-1, len(want), b)
+	}
+
+	// Keep two rows, marking one to tell it was not run again, and cut the
+	// third short as a crash would.
+	var kept Row
+	if err := json.Unmarshal([]byte(lines[1]), &kept); err != nil {
+		t.Fatal(err)
+	}
+	kept.AveWait = -1
+	marked, _ := json.Marshal(kept)
+	partial := lines[3][:len(lines[3])/2]
+	if err := os.WriteFile(path, []byte(lines[0]+string(marked)+"\n"+lines[2]+partial), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if rows, err = m.Resume(context.Background(), 2, load, path); err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range rows {
+		if w := want[i]; r.Run == kept.Run {
+			w.AveWait = -1
+			if r != w {
+				t.Errorf("row %d = %+v, want the recorded %+v", i, r, w)
+			}
+		} else if r != w {
+			t.Errorf("row %d = %+v, want %+v", i, r, w)
+		}
+	}
+	if b, err = os.ReadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(b), "\n"); n != len(want)+1 {
+		t.Errorf("checkpoint has %d lines after resuming, want %d:\n%s", n, len(want)+1, b)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if rows, err = m.Resume(ctx, 2, load, path); err != nil || !reflect.DeepEqual(rows[1:], want[1:]) {
+		t.Errorf("resuming a finished batch: rows = %+v, err = %v", rows, err)
+	}
+
+	short := func(string) ([]scheduler.Process, error) { return workload[:1], nil }
+	if _, err := m.Resume(context.Background(), 2, short, path); !errors.Is(err, ErrStaleCheckpoint) {
+		t.Errorf("other workload: err = %v, want %v", err, ErrStaleCheckpoint)
+	}
+	m.CPUs = []int{1}
+	if _, err := m.Resume(context.Background(), 2, load, path); !errors.Is(err, ErrStaleCheckpoint) {
+		t.Errorf("other matrix: err = %v, want %v", err, ErrStaleCheckpoint)
+	}
+}
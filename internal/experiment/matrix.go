@@ -0,0 +1,214 @@
+package experiment
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"p1/internal/config"
+	"p1/internal/pool"
+	"p1/internal/scheduler"
+)
+
+// Matrix is a cartesian product of experiment parameters, read from JSON
+// such as
+//
+//	{
+//	  "workloads": ["light.csv", "heavy.csv"],
+//	  "algorithms": ["fcfs", "rr", "mlfq"],
+//	  "quanta": [2, 4, 8],
+//	  "cpus": [1, 2, 4],
+//	  "options": {"switchCost": 1}
+//	}
+//
+// Every algorithm is run over every workload with every combination of the
+// other parameters; ones left out take their value from options.
+type Matrix struct {
+	Workloads   []string `json:"workloads"`
+	Algorithms  []string `json:"algorithms"`
+	Quanta      []int64  `json:"quanta"`
+	CPUs        []int    `json:"cpus"`
+	SwitchCosts []int64  `json:"switchCosts"`
+	// Options holds the settings that do not vary.
+	Options scheduler.Options `json:"options"`
+}
+
+// LoadMatrix reads a matrix from r on top of the default options and
+// algorithms.
+func LoadMatrix(r io.Reader) (Matrix, error) {
+	m := Matrix{Algorithms: config.Default().Algorithms, Options: scheduler.DefaultOptions()}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return Matrix{}, fmt.Errorf("%w: reading matrix", err)
+	}
+	if len(m.Workloads) == 0 {
+		return Matrix{}, fmt.Errorf("%w: matrix names no workloads", scheduler.ErrInvalidOption)
+	}
+	for _, run := range m.Runs() {
+		if _, err := scheduler.LookupAlgorithm(run.Algorithm); err != nil {
+			return Matrix{}, err
+		}
+		if err := m.options(run).Validate(); err != nil {
+			return Matrix{}, err
+		}
+	}
+	return m, nil
+}
+
+// Run is one combination of parameters of a matrix.
+type Run struct {
+	Workload  string
+	Algorithm string
+	// Quantum is 0 for algorithms without one.
+	Quantum    int64
+	CPUs       int
+	SwitchCost int64
+}
+
+// Runs returns every combination of m, varying the last parameter fastest.
+// Algorithms without a quantum are run once rather than once per quantum.
+func (m Matrix) Runs() []Run {
+	quanta, cpus, costs := m.Quanta, m.CPUs, m.SwitchCosts
+	if len(quanta) == 0 {
+		quanta = []int64{m.Options.RR.Quantum}
+	}
+	if len(cpus) == 0 {
+		cpus = []int{m.Options.CPUs}
+	}
+	if len(costs) == 0 {
+		costs = []int64{m.Options.SwitchCost}
+	}
+	var runs []Run
+	for _, w := range m.Workloads {
+		for _, a := range m.Algorithms {
+			qs := quanta
+			if !Swept(a) {
+				qs = []int64{0}
+			}
+			for _, q := range qs {
+				for _, c := range cpus {
+					for _, cost := range costs {
+						runs = append(runs, Run{Workload: w, Algorithm: a, Quantum: q, CPUs: c, SwitchCost: cost})
+					}
+				}
+			}
+		}
+	}
+	return runs
+}
+
+func (m Matrix) options(run Run) scheduler.Options {
+	opts := m.Options
+	if run.Quantum > 0 {
+		opts = WithQuantum(opts, run.Quantum)
+	}
+	opts.CPUs = run.CPUs
+	opts.SwitchCost = run.SwitchCost
+	return opts
+}
+
+// Row is the outcome of one run.
+type Row struct {
+	Run
+	AveWait         float64
+	AveTurnaround   float64
+	AveThroughput   float64
+	ContextSwitches int
+}
+
+// Execute performs every run of m on up to workers goroutines, loading each
+// workload once with load. The rows are in the order of Runs however the
+// runs finish; if a run fails, so does Execute.
+func (m Matrix) Execute(ctx context.Context, workers int, load func(workload string) ([]scheduler.Process, error)) ([]Row, error) {
+	workloads, err := m.load(load)
+	if err != nil {
+		return nil, err
+	}
+	return m.execute(ctx, workers, workloads, nil)
+}
+
+// load loads every workload of m once.
+func (m Matrix) load(load func(workload string) ([]scheduler.Process, error)) (map[string][]scheduler.Process, error) {
+	workloads := make(map[string][]scheduler.Process, len(m.Workloads))
+	for _, w := range m.Workloads {
+		if _, ok := workloads[w]; ok {
+			continue
+		}
+		processes, err := load(w)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", w, err)
+		}
+		workloads[w] = processes
+	}
+	return workloads, nil
+}
+
+// execute performs the runs of m over workloads, skipping those c has done
+// already and recording the rest in it as they complete, if c is not nil.
+func (m Matrix) execute(ctx context.Context, workers int, workloads map[string][]scheduler.Process, c *checkpoint) ([]Row, error) {
+	runs := m.Runs()
+	var todo []Run
+	for _, run := range runs {
+		if _, ok := c.row(run); !ok {
+			todo = append(todo, run)
+		}
+	}
+
+	done, err := pool.Values(pool.Run(ctx, workers, todo, func(_ context.Context, run Run) (Row, error) {
+		// LoadMatrix has checked the algorithms and options.
+		a, _ := scheduler.LookupAlgorithm(run.Algorithm)
+		sim, _ := scheduler.NewSimulator(scheduler.WithOptions(m.options(run)))
+		res := sim.Schedule(a, workloads[run.Workload])
+		row := Row{run, res.AveWait, res.AveTurnaround, res.AveThroughput, res.ContextSwitches}
+		if c != nil {
+			if err := c.add(row); err != nil {
+				return Row{}, err
+			}
+		}
+		return row, nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return done, nil
+	}
+	rows := make([]Row, len(runs))
+	for i, run := range runs {
+		if r, ok := c.row(run); ok {
+			rows[i] = r
+		} else {
+			rows[i], done = done[0], done[1:]
+		}
+	}
+	return rows, nil
+}
+
+// WriteCSV writes rows as CSV with a header, one row per run.
+func WriteCSV(w io.Writer, rows []Row) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"workload", "algorithm", "quantum", "cpus", "switch_cost", "average_wait", "average_turnaround", "throughput", "context_switches"})
+	for _, r := range rows {
+		quantum := ""
+		if r.Quantum > 0 {
+			quantum = strconv.FormatInt(r.Quantum, 10)
+		}
+		_ = cw.Write([]string{
+			r.Workload,
+			r.Algorithm,
+			quantum,
+			strconv.Itoa(r.CPUs),
+			strconv.FormatInt(r.SwitchCost, 10),
+			strconv.FormatFloat(r.AveWait, 'f', -1, 64),
+			strconv.FormatFloat(r.AveTurnaround, 'f', -1, 64),
+			strconv.FormatFloat(r.AveThroughput, 'f', -1, 64),
+			strconv.Itoa(r.ContextSwitches),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,356 @@
+package experiment
+
+import (
+	"cmp"
+	"encoding/binary"
+	"math"
+	"slices"
+
+	"p1/internal/scheduler"
+)
+
+// MaxOptimal is the most processes Optimal searches the schedules of.
+const MaxOptimal = 10
+
+// optimalNodes bounds the decisions Optimal tries before it settles for a
+// lower bound.
+const optimalNodes = 1 << 19
+
+// Optimum is the least average wait of any schedule of a workload, or a
+// lower bound on it.
+type Optimum struct {
+	Wait float64
+	// Exact reports whether Wait is that of a schedule. If not, no schedule
+	// waits less on average, but the best may wait more.
+	Exact bool
+}
+
+// Optimal returns the least average wait with which the processes can be
+// scheduled under opts, taking in the CPUs and the switch cost. On one CPU
+// switching for free Wait is exact, running the process of the least burst
+// left at every time being best. Otherwise, for at most MaxOptimal
+// processes and no suspensions, a branch-and-bound search finds the best of
+// the schedules that keep every CPU busy while processes are ready and
+// change which processes run only when one arrives or completes, as most
+// algorithms do. Failing that, Wait is a lower bound: that of running the
+// processes on a single CPU as fast as all of them together. So it is on CPUs
+// of speeds other than 1, on which processes wait for less than their
+// completion less their arrival and burst. Processes without a burst
+// complete as they arrive. opts must be valid.
+func Optimal(processes []scheduler.Process, opts scheduler.Options) Optimum {
+	if len(processes) == 0 {
+		return Optimum{Exact: true}
+	}
+	// Every process waits for its completion less its arrival and burst,
+	// so the least average wait is that of the least total completion, of
+	// which those without a burst make up their arrival.
+	var given, none int64
+	release := make([]int64, len(processes))
+	burst := make([]int64, len(processes))
+	for i, p := range processes {
+		given += p.ArrivalTime + p.BurstDuration
+		release[i], burst[i] = p.ArrivalTime, p.BurstDuration
+		if p.BurstDuration == 0 {
+			none += p.ArrivalTime
+		}
+	}
+	wait := func(completions float64) float64 {
+		return (float64(none) + completions - float64(given)) / float64(len(processes))
+	}
+
+	speeds := make([]int64, opts.CPUs)
+	for c := range speeds {
+		speeds[c] = opts.Speed(c)
+	}
+	bound := completionBound(0, release, burst, speeds)
+	if slices.ContainsFunc(speeds, func(v int64) bool { return v != 1 }) {
+		return Optimum{wait(bound), false}
+	}
+	if opts.CPUs == 1 && opts.SwitchCost == 0 && len(opts.Suspensions) == 0 {
+		return Optimum{wait(bound), true}
+	}
+	if len(processes) <= MaxOptimal && len(opts.Suspensions) == 0 {
+		if best, ok := searchOptimal(release, burst, opts.CPUs, opts.SwitchCost); ok {
+			return Optimum{wait(float64(best)), true}
+		}
+	}
+	return Optimum{wait(bound), false}
+}
+
+// Gap returns how much longer than the optimum o the average wait is, as a
+// percentage of it: 0 at the optimum, and +Inf if o is no wait at all and
+// wait is some.
+func Gap(wait float64, o Optimum) float64 {
+	if o.Wait == 0 {
+		if wait <= 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return 100 * (wait - o.Wait) / o.Wait
+}
+
+// completionBound returns a lower bound on the total completion of the
+// processes of the given release times and bursts left, counting those with
+// a burst left only, on CPUs of the given speeds from time now on. No
+// process can complete before its release, or now, and its burst on the
+// fastest CPU later. Nor can the processes complete sooner than they would
+// all released now, running the shortest first on CPUs all as fast as the
+// fastest, nor than on a single CPU as fast as all of them together, which
+// can do whatever the CPUs do together, running the process of the least
+// burst left at every time. Either is best for what it runs on.
+func completionBound(now int64, release, left, speeds []int64) float64 {
+	cpus := len(speeds)
+	var m, top int64
+	for _, v := range speeds {
+		m, top = m+v, max(top, v)
+	}
+	// Times are counted in units of 1/m so that the fast CPU does a unit
+	// of work in each.
+	var at, work []int64
+	var alone int64
+	for i, l := range left {
+		if l > 0 {
+			at = append(at, max(release[i], now)*m)
+			work = append(work, l)
+			alone += max(release[i], now) + (l+top-1)/top
+		}
+	}
+	shortest := slices.Sorted(slices.Values(work))
+	var released float64
+	for i := range shortest {
+		if i >= cpus {
+			shortest[i] += shortest[i-cpus]
+		}
+		released += float64(now) + float64(shortest[i])/float64(top)
+	}
+	var total int64
+	t := int64(math.MaxInt64)
+	for _, a := range at {
+		t = min(t, a)
+	}
+	for n := len(work); n > 0; {
+		run := -1
+		next := int64(math.MaxInt64)
+		for i, w := range work {
+			switch {
+			case w == 0:
+			case at[i] > t:
+				next = min(next, at[i])
+			case run < 0 || w < work[run]:
+				run = i
+			}
+		}
+		if run < 0 {
+			t = next
+			continue
+		}
+		next = min(next, t+work[run])
+		work[run] -= next - t
+		t = next
+		if work[run] == 0 {
+			total += t
+			n--
+		}
+	}
+	return max(float64(total)/float64(m), float64(alone), released)
+}
+
+// search is the state of searchOptimal at the time it is deciding on: the
+// burst each process has left, and the process each CPU runs, -1 for none.
+type search struct {
+	release, left []int64
+	cost          int64
+	cpus          []searchCPU
+	speeds        []int64 // of the CPUs, all 1
+	best          int64
+	nodes         int
+	// seen holds the least total completion each state was visited at, a
+	// state visited again at no less not being worth searching again.
+	seen map[string]int64
+	key  []byte
+}
+
+type searchCPU struct {
+	job, last int
+	// resume is when the process switched to starts making progress.
+	resume int64
+}
+
+// searchOptimal returns the least total completion of the processes of the
+// given release times and bursts, counting those with a burst only, on cpus
+// CPUs switching at cost, over the
+// schedules Optimal searches, and whether it could search them all.
+func searchOptimal(release, burst []int64, cpus int, cost int64) (int64, bool) {
+	s := &search{release: release, left: slices.Clone(burst), cost: cost, best: math.MaxInt64, seen: make(map[string]int64)}
+	for range cpus {
+		s.cpus = append(s.cpus, searchCPU{job: -1, last: -1})
+		s.speeds = append(s.speeds, 1)
+	}
+	done := 0
+	for _, b := range burst {
+		if b == 0 {
+			done++
+		}
+	}
+	t := int64(math.MaxInt64)
+	for _, r := range release {
+		t = min(t, r)
+	}
+	ok := s.visit(t, done, 0)
+	return s.best, ok
+}
+
+// visit tries every choice of processes to run from time t on, done
+// processes having completed at total times in all, and reports false if
+// it ran out of nodes.
+func (s *search) visit(t int64, done int, total int64) bool {
+	if done == len(s.left) {
+		s.best = min(s.best, total)
+		return true
+	}
+	if s.nodes++; s.nodes > optimalNodes {
+		return false
+	}
+	if float64(total)+completionBound(t, s.release, s.left, s.speeds) >= float64(s.best) {
+		return true
+	}
+	s.key = binary.AppendVarint(s.key[:0], t)
+	for _, l := range s.left {
+		s.key = binary.AppendVarint(s.key, l)
+	}
+	for _, c := range s.cpus {
+		s.key = binary.AppendVarint(s.key, int64(c.job))
+		s.key = binary.AppendVarint(s.key, int64(c.last))
+		s.key = binary.AppendVarint(s.key, max(c.resume-t, 0))
+	}
+	if seen, ok := s.seen[string(s.key)]; ok && seen <= total {
+		return true
+	}
+	s.seen[string(s.key)] = total
+	var ready []int
+	arrival := int64(math.MaxInt64)
+	for i, l := range s.left {
+		switch {
+		case l == 0:
+		case s.release[i] <= t:
+			ready = append(ready, i)
+		default:
+			arrival = min(arrival, s.release[i])
+		}
+	}
+	if len(ready) == 0 {
+		return s.visit(arrival, done, total)
+	}
+	// Trying the processes of the least burst left first finds a good
+	// schedule early, to cut the search short by.
+	slices.SortStableFunc(ready, func(a, b int) int { return cmp.Compare(s.left[a], s.left[b]) })
+	k := min(len(s.cpus), len(ready))
+	pick := make([]int, k)
+	for i := range pick {
+		pick[i] = i
+	}
+	saved := slices.Clone(s.cpus)
+	for {
+		if !s.place(t, arrival, s.keep(ready, pick), done, total) {
+			return false
+		}
+		copy(s.cpus, saved)
+		// Move on to the next k of the ready processes.
+		i := k - 1
+		for i >= 0 && pick[i] == len(ready)-k+i {
+			i--
+		}
+		if i < 0 {
+			return true
+		}
+		pick[i]++
+		for j := i + 1; j < k; j++ {
+			pick[j] = pick[j-1] + 1
+		}
+	}
+}
+
+// keep keeps those of the processes picked from ready that are running on
+// their CPUs, stops the rest of the running ones and returns the picked
+// processes left to put on a CPU.
+func (s *search) keep(ready, pick []int) []int {
+	kept := make([]bool, len(s.cpus))
+	var waiting []int
+	for _, p := range pick {
+		j := ready[p]
+		if c := slices.IndexFunc(s.cpus, func(c searchCPU) bool { return c.job == j }); c >= 0 {
+			kept[c] = true
+		} else {
+			waiting = append(waiting, j)
+		}
+	}
+	for c := range s.cpus {
+		if !kept[c] {
+			s.cpus[c].job = -1
+		}
+	}
+	return waiting
+}
+
+// place tries putting each of the waiting processes on every free CPU it
+// could go to, and then runs them from t. CPUs that never ran a process are
+// all alike, and so are all CPUs when switching is free.
+func (s *search) place(t, arrival int64, waiting []int, done int, total int64) bool {
+	if len(waiting) == 0 {
+		cpus, left := slices.Clone(s.cpus), slices.Clone(s.left)
+		next, d, c := s.run(t, arrival)
+		ok := s.visit(next, done+d, total+c)
+		copy(s.cpus, cpus)
+		copy(s.left, left)
+		return ok
+	}
+	j := waiting[0]
+	fresh := false
+	for c, cpu := range s.cpus {
+		if cpu.job >= 0 || cpu.last < 0 && fresh {
+			continue
+		}
+		fresh = fresh || cpu.last < 0
+		resume := t
+		if cpu.last >= 0 && cpu.last != j {
+			resume += s.cost
+		}
+		s.cpus[c] = searchCPU{job: j, last: j, resume: resume}
+		ok := s.place(t, arrival, waiting[1:], done, total)
+		s.cpus[c] = cpu
+		if !ok {
+			return false
+		}
+		if s.cost == 0 {
+			break
+		}
+	}
+	return true
+}
+
+// run runs the processes on the CPUs from t until the next arrival or
+// completion. It returns that time, how many processes completed then and
+// their completion times in all.
+func (s *search) run(t, arrival int64) (int64, int, int64) {
+	next := arrival
+	for _, c := range s.cpus {
+		if c.job >= 0 {
+			next = min(next, max(c.resume, t)+s.left[c.job])
+		}
+	}
+	done := 0
+	var total int64
+	for c, cpu := range s.cpus {
+		if cpu.job < 0 {
+			continue
+		}
+		s.left[cpu.job] -= max(0, next-max(cpu.resume, t))
+		if s.left[cpu.job] == 0 {
+			done++
+			total += next
+			s.cpus[c].job = -1
+		}
+	}
+	return next, done, total
+}
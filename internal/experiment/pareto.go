@@ -0,0 +1,121 @@
+package experiment
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// Metric is a figure of merit of a result.
+type Metric struct {
+	Name string
+	// Higher is set if larger values are better.
+	Higher bool
+	Value  func(scheduler.Result) float64
+}
+
+// Metrics are the metrics Pareto can weigh. Deadline misses are not among
+// them, since they depend on a factor; see DeadlineMisses.
+var Metrics = []Metric{
+	{"wait", false, func(r scheduler.Result) float64 { return r.AveWait }},
+	{"turnaround", false, func(r scheduler.Result) float64 { return r.AveTurnaround }},
+	{"fairness", true, Fairness},
+	{"switches", false, func(r scheduler.Result) float64 { return float64(r.ContextSwitches) }},
+}
+
+// LookupMetrics returns the metrics with the given names, from Metrics and
+// extra, such as a metric of DeadlineMisses.
+func LookupMetrics(names []string, extra ...Metric) ([]Metric, error) {
+	all := append(slices.Clip(Metrics), extra...)
+	ms := make([]Metric, 0, len(names))
+next:
+	for _, name := range names {
+		for _, m := range all {
+			if m.Name == name {
+				ms = append(ms, m)
+				continue next
+			}
+		}
+		known := make([]string, len(all))
+		for i, m := range all {
+			known[i] = m.Name
+		}
+		return nil, fmt.Errorf("%w: unknown metric %q, want one of %s", scheduler.ErrInvalidOption, name, strings.Join(known, ", "))
+	}
+	return ms, nil
+}
+
+// Fairness is Jain's fairness index of the slowdowns of the processes of r,
+// their turnaround divided by their burst. It is 1 when every process is
+// slowed down alike and falls towards 1/n as a few bear most of the delay.
+func Fairness(r scheduler.Result) float64 {
+	var sum, squares float64
+	n := 0
+	for _, s := range r.Stats {
+		if s.BurstDuration == 0 {
+			continue
+		}
+		x := float64(s.Turnaround) / float64(s.BurstDuration)
+		sum += x
+		squares += x * x
+		n++
+	}
+	if squares == 0 {
+		return 1
+	}
+	return sum * sum / (float64(n) * squares)
+}
+
+// Score is an algorithm's standing among others on several metrics.
+type Score struct {
+	Algorithm string
+	// Values holds the value of each metric, in order.
+	Values []float64
+	// DominatedBy names the algorithms at least as good on every metric
+	// and better on one. The algorithm is Pareto-optimal if there are none.
+	DominatedBy []string
+}
+
+// Optimal reports whether no other algorithm dominates s.
+func (s Score) Optimal() bool { return len(s.DominatedBy) == 0 }
+
+// Pareto scores results against each other on metrics.
+func Pareto(results []render.Named, metrics []Metric) []Score {
+	scores := make([]Score, len(results))
+	for i, n := range results {
+		scores[i] = Score{Algorithm: n.Algorithm, Values: make([]float64, len(metrics))}
+		for j, m := range metrics {
+			scores[i].Values[j] = m.Value(n.Result)
+		}
+	}
+	for i := range scores {
+		for k := range scores {
+			if k != i && dominates(scores[k].Values, scores[i].Values, metrics) {
+				scores[i].DominatedBy = append(scores[i].DominatedBy, scores[k].Algorithm)
+			}
+		}
+	}
+	return scores
+}
+
+// dominates reports whether a is at least as good as b on every metric and
+// better on at least one.
+func dominates(a, b []float64, metrics []Metric) bool {
+	better := false
+	for j, m := range metrics {
+		x, y := a[j], b[j]
+		if m.Higher {
+			x, y = -x, -y
+		}
+		if x > y {
+			return false
+		}
+		if x < y {
+			better = true
+		}
+	}
+	return better
+}
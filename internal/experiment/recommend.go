@@ -0,0 +1,76 @@
+package experiment
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// DeadlineMisses returns a metric counting the processes of a result that
+// complete after their deadline. A process without one misses it if it
+// completes later than its arrival plus factor times its burst, slowed down
+// by more than factor.
+func DeadlineMisses(factor float64) (Metric, error) {
+	if !(factor >= 1) || math.IsInf(factor, 0) {
+		return Metric{}, fmt.Errorf("%w: deadline factor must be a finite number of at least 1, got %v", scheduler.ErrInvalidOption, factor)
+	}
+	return Metric{"misses", false, func(r scheduler.Result) float64 {
+		misses := 0
+		for _, s := range r.Stats {
+			if s.Deadline != 0 && s.Tardiness() > 0 || s.Deadline == 0 && float64(s.Turnaround) > factor*float64(s.BurstDuration) {
+				misses++
+			}
+		}
+		return float64(misses)
+	}}, nil
+}
+
+// Recommendation is the algorithm best on one metric.
+type Recommendation struct {
+	Metric Metric
+	// Best names the algorithms with the best value, more than one if they
+	// tie, and Value is that value.
+	Best  []string
+	Value float64
+	// RunnerUp names the best of the rest and RunnerUpValue its value. It
+	// is empty if every algorithm tied.
+	RunnerUp      string
+	RunnerUpValue float64
+}
+
+// Recommend returns the best of results on each of metrics.
+func Recommend(results []render.Named, metrics []Metric) []Recommendation {
+	recs := make([]Recommendation, len(metrics))
+	for i, m := range metrics {
+		values := make([]float64, len(results))
+		for j, n := range results {
+			values[j] = m.Value(n.Result)
+		}
+		order := make([]int, len(results))
+		for j := range order {
+			order[j] = j
+		}
+		slices.SortStableFunc(order, func(a, b int) int {
+			if m.Higher {
+				return cmp.Compare(values[b], values[a])
+			}
+			return cmp.Compare(values[a], values[b])
+		})
+
+		rec := Recommendation{Metric: m}
+		for _, j := range order {
+			switch {
+			case rec.Best == nil || values[j] == rec.Value:
+				rec.Best, rec.Value = append(rec.Best, results[j].Algorithm), values[j]
+			case rec.RunnerUp == "":
+				rec.RunnerUp, rec.RunnerUpValue = results[j].Algorithm, values[j]
+			}
+		}
+		recs[i] = rec
+	}
+	return recs
+}
@@ -0,0 +1,104 @@
+package experiment
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// Weight is how much a metric counts towards a weighted score.
+type Weight struct {
+	Metric Metric
+	Weight float64
+}
+
+// ParseWeights parses weights such as "wait=0.5", one per metric of
+// Metrics. Weights must not be negative, and one must be positive.
+func ParseWeights(specs []string) ([]Weight, error) {
+	ws := make([]Weight, 0, len(specs))
+	total := 0.0
+	for _, spec := range specs {
+		name, value, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: weight %q is not metric=weight", scheduler.ErrInvalidOption, spec)
+		}
+		ms, err := LookupMetrics([]string{name})
+		if err != nil {
+			return nil, err
+		}
+		w, err := strconv.ParseFloat(value, 64)
+		if err != nil || !(w >= 0) || math.IsInf(w, 1) {
+			return nil, fmt.Errorf("%w: weight of %s must be a finite number no less than 0, got %q", scheduler.ErrInvalidOption, name, value)
+		}
+		if slices.ContainsFunc(ws, func(w Weight) bool { return w.Metric.Name == name }) {
+			return nil, fmt.Errorf("%w: %s is weighted twice", scheduler.ErrInvalidOption, name)
+		}
+		ws = append(ws, Weight{ms[0], w})
+		total += w
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("%w: no metric has a positive weight", scheduler.ErrInvalidOption)
+	}
+	return ws, nil
+}
+
+// Ranked is an algorithm's place among others on a weighted score.
+type Ranked struct {
+	Algorithm string
+	// Values holds the value of each weighted metric, in order.
+	Values []float64
+	// Score is the weighted mean of how near the algorithm comes to the
+	// best of the others on each metric, 0 being the worst value among
+	// them and 1 the best.
+	Score float64
+	// Rank is the algorithm's place by score from 1, those tying sharing
+	// the highest.
+	Rank int
+}
+
+// Rank scores results against each other on the weighted metrics and
+// returns them best first, results tying on score in the order given.
+func Rank(results []render.Named, weights []Weight) []Ranked {
+	ranked := make([]Ranked, len(results))
+	for i, n := range results {
+		ranked[i] = Ranked{Algorithm: n.Algorithm, Values: make([]float64, len(weights))}
+		for j, w := range weights {
+			ranked[i].Values[j] = w.Metric.Value(n.Result)
+		}
+	}
+	total := 0.0
+	for _, w := range weights {
+		total += w.Weight
+	}
+	for j, w := range weights {
+		lo, hi := ranked[0].Values[j], ranked[0].Values[j]
+		for _, r := range ranked {
+			lo, hi = min(lo, r.Values[j]), max(hi, r.Values[j])
+		}
+		for i := range ranked {
+			// Every algorithm is the best when they all tie.
+			near := 1.0
+			if hi > lo {
+				near = (hi - ranked[i].Values[j]) / (hi - lo)
+				if w.Metric.Higher {
+					near = 1 - near
+				}
+			}
+			ranked[i].Score += w.Weight * near / total
+		}
+	}
+	slices.SortStableFunc(ranked, func(a, b Ranked) int { return cmp.Compare(b.Score, a.Score) })
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+		if i > 0 && ranked[i].Score == ranked[i-1].Score {
+			ranked[i].Rank = ranked[i-1].Rank
+		}
+	}
+	return ranked
+}
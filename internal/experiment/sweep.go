@@ -0,0 +1,72 @@
+// Package experiment runs algorithms many times over, varying their
+// parameters, to show how the results depend on them.
+package experiment
+
+import (
+	"fmt"
+
+	"p1/internal/scheduler"
+)
+
+// Point is the outcome of one algorithm run with one quantum.
+type Point struct {
+	Quantum         int64
+	Algorithm       string
+	AveWait         float64
+	AveTurnaround   float64
+	ContextSwitches int
+}
+
+// Swept reports whether Sweep can vary the quantum of the named algorithm.
+func Swept(algorithm string) bool {
+	return algorithm == "rr" || algorithm == "mlfq" || algorithm == "lottery" || algorithm == "fairshare"
+}
+
+// WithQuantum returns opts with the quantum of round-robin, and so of the
+// lottery and fair-share scheduling, and of the top level of the multilevel
+// feedback queue set to q. Lower MLFQ levels keep their quanta in
+// proportion to the top level's.
+func WithQuantum(opts scheduler.Options, q int64) scheduler.Options {
+	opts.RR.Quantum = q
+	base := opts.MLFQ.Quanta
+	opts.MLFQ.Quanta = make([]int64, len(base))
+	for i, b := range base {
+		opts.MLFQ.Quanta[i] = max(1, q*b/base[0])
+	}
+	return opts
+}
+
+// Sweep runs each algorithm over processes once for every quantum, on top
+// of opts, returning the points ordered by quantum and then algorithm.
+func Sweep(processes []scheduler.Process, algorithms []string, quanta []int64, opts scheduler.Options) ([]Point, error) {
+	var as []scheduler.Algorithm
+	for _, name := range algorithms {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return nil, err
+		}
+		if !Swept(name) {
+			return nil, fmt.Errorf("%w: %s has no quantum to sweep", scheduler.ErrInvalidOption, name)
+		}
+		as = append(as, a)
+	}
+
+	points := make([]Point, 0, len(quanta)*len(as))
+	for _, q := range quanta {
+		sim, err := scheduler.NewSimulator(scheduler.WithOptions(WithQuantum(opts, q)))
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range as {
+			res := sim.Schedule(a, processes)
+			points = append(points, Point{
+				Quantum:         q,
+				Algorithm:       a.Name,
+				AveWait:         res.AveWait,
+				AveTurnaround:   res.AveTurnaround,
+				ContextSwitches: res.ContextSwitches,
+			})
+		}
+	}
+	return points, nil
+}
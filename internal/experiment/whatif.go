@@ -0,0 +1,118 @@
+package experiment
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"p1/internal/scheduler"
+)
+
+var ErrNoProcess = errors.New("no process to change")
+
+// Change is a kind of perturbation of a process.
+type Change string
+
+const (
+	// Remove takes the process out of the workload.
+	Remove Change = "remove"
+	// Delay has the process arrive later.
+	Delay Change = "delay"
+	// Halve halves the burst of the process, rounding down, and its
+	// estimate if it has one.
+	Halve Change = "halve"
+)
+
+// Perturbation is a single change to a workload, made to see what
+// difference the process changed makes to a schedule. It is written
+// "remove:ID", "delay:ID:TICKS" or "halve:ID".
+type Perturbation struct {
+	Change Change
+	// PID is the ID of the process changed, every one of that ID.
+	PID int64
+	// By is how much later a delayed process arrives.
+	By int64
+}
+
+func (p Perturbation) MarshalText() ([]byte, error) {
+	if p.Change == "" {
+		return nil, nil
+	}
+	s := fmt.Sprintf("%s:%d", p.Change, p.PID)
+	if p.Change == Delay {
+		s += ":" + strconv.FormatInt(p.By, 10)
+	}
+	return []byte(s), nil
+}
+
+func (p *Perturbation) UnmarshalText(text []byte) error {
+	fields := strings.Split(string(text), ":")
+	var q Perturbation
+	q.Change = Change(fields[0])
+	want := 2
+	switch q.Change {
+	case Remove, Halve:
+	case Delay:
+		want = 3
+	default:
+		return fmt.Errorf("%w: perturbation %q is not remove, delay or halve", scheduler.ErrInvalidOption, text)
+	}
+	if len(fields) != want {
+		usage := string(q.Change) + ":ID"
+		if q.Change == Delay {
+			usage += ":TICKS"
+		}
+		return fmt.Errorf("%w: perturbation %q is not %s", scheduler.ErrInvalidOption, text, usage)
+	}
+	pid, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: perturbation %q names no process ID", scheduler.ErrInvalidOption, text)
+	}
+	q.PID = pid
+	if q.Change == Delay {
+		if q.By, err = strconv.ParseInt(fields[2], 10, 64); err != nil || q.By < 1 {
+			return fmt.Errorf("%w: delay of P%d must be a whole number of ticks above 0, got %q", scheduler.ErrInvalidOption, pid, fields[2])
+		}
+	}
+	*p = q
+	return nil
+}
+
+// String describes p, such as "halving the burst of P3".
+func (p Perturbation) String() string {
+	switch p.Change {
+	case Remove:
+		return fmt.Sprintf("removing P%d", p.PID)
+	case Delay:
+		return fmt.Sprintf("delaying P%d by %d", p.PID, p.By)
+	case Halve:
+		return fmt.Sprintf("halving the burst of P%d", p.PID)
+	}
+	return "changing nothing"
+}
+
+// Apply returns processes changed by p, failing with ErrNoProcess if none
+// has the ID p changes. processes are left as they are.
+func (p Perturbation) Apply(processes []scheduler.Process) ([]scheduler.Process, error) {
+	if !slices.ContainsFunc(processes, func(q scheduler.Process) bool { return q.ProcessID == p.PID }) {
+		return nil, fmt.Errorf("%w: no P%d in the workload", ErrNoProcess, p.PID)
+	}
+	out := make([]scheduler.Process, 0, len(processes))
+	for _, q := range processes {
+		if q.ProcessID == p.PID {
+			switch p.Change {
+			case Remove:
+				continue
+			case Delay:
+				q.ArrivalTime += p.By
+			case Halve:
+				q.BurstDuration /= 2
+				q.Estimate /= 2
+			}
+		}
+		out = append(out, q)
+	}
+	return out, nil
+}
@@ -0,0 +1,273 @@
+// Package grade checks submitted schedules against an answer key of expected
+// results, within tolerances, for autograding.
+//
+// An answer key is a JSON result document, as written by schedsim -format
+// json, with optional extra fields:
+//
+//	{
+//	  "schemaVersion": 2,
+//	  "tolerance": {"average": 0.01, "time": 0, "skipGantt": false},
+//	  "config": {"rr": {"quantum": 4}},
+//	  "results": [...]
+//	}
+//
+// The config is used to schedule workloads handed in instead of results.
+package grade
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"p1/internal/config"
+	"p1/internal/input"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// ErrFailed is returned by Failed when any algorithm did not pass.
+var ErrFailed = errors.New("grading failed")
+
+// Tolerance is how far submitted results may be from the key and still pass.
+type Tolerance struct {
+	// Average bounds the difference in average wait, average turnaround
+	// and throughput.
+	Average float64 `json:"average"`
+	// Time bounds the difference in per-process times and Gantt slice
+	// boundaries, in ticks.
+	Time int64 `json:"time"`
+	// SkipGantt leaves the Gantt chart unchecked.
+	SkipGantt bool `json:"skipGantt"`
+}
+
+// Validate fails with scheduler.ErrInvalidOption if a bound is negative or
+// the average bound is not a number.
+func (t Tolerance) Validate() error {
+	switch {
+	case !(t.Average >= 0):
+		return fmt.Errorf("%w: average tolerance must be no less than 0, got %v", scheduler.ErrInvalidOption, t.Average)
+	case t.Time < 0:
+		return fmt.Errorf("%w: time tolerance must be no less than 0, got %d", scheduler.ErrInvalidOption, t.Time)
+	}
+	return nil
+}
+
+// DefaultTolerance allows for averages rounded to two decimal places, as
+// in text reports, and nothing else.
+func DefaultTolerance() Tolerance { return Tolerance{Average: 0.005} }
+
+// Key is an answer key.
+type Key struct {
+	Tolerance Tolerance
+	// Config schedules submitted workloads.
+	Config config.Config
+	// Tick is the real length of a tick, or 0 if times are bare ticks.
+	Tick    time.Duration
+	Results []render.Named
+}
+
+// LoadKey reads an answer key from r.
+func LoadKey(r io.Reader) (Key, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Key{}, err
+	}
+	doc, err := render.ReadJSON(bytes.NewReader(data))
+	if err != nil {
+		return Key{}, err
+	}
+	var extra struct {
+		Tolerance *Tolerance      `json:"tolerance"`
+		Config    json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return Key{}, fmt.Errorf("%w: reading answer key", err)
+	}
+
+	key := Key{Tolerance: DefaultTolerance(), Config: config.Default(), Results: doc.Results}
+	if extra.Tolerance != nil {
+		key.Tolerance = *extra.Tolerance
+		if err := key.Tolerance.Validate(); err != nil {
+			return Key{}, fmt.Errorf("%w: reading answer key tolerance", err)
+		}
+	}
+	if extra.Config != nil {
+		if key.Config, err = config.Load(bytes.NewReader(extra.Config)); err != nil {
+			return Key{}, err
+		}
+	}
+	if doc.Tick != "" {
+		if key.Tick, err = time.ParseDuration(doc.Tick); err != nil {
+			return Key{}, fmt.Errorf("%w: reading answer key tick", err)
+		}
+	}
+	return key, nil
+}
+
+// Write writes k as a JSON answer key that LoadKey reads back.
+func (k Key) Write(w io.Writer) error {
+	doc := struct {
+		render.Document
+		Tolerance Tolerance     `json:"tolerance"`
+		Config    config.Config `json:"config"`
+	}{render.Document{SchemaVersion: render.SchemaVersion, Results: k.Results}, k.Tolerance, k.Config}
+	if k.Tick > 0 {
+		doc.Tick = k.Tick.String()
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// Schedule runs the algorithms of the key over a submitted workload.
+func (k Key) Schedule(workload io.Reader) ([]render.Named, error) {
+	var processes []scheduler.Process
+	var err error
+	if k.Tick > 0 {
+		processes, err = input.LoadDurations(workload, k.Tick)
+	} else {
+		processes, err = input.Load(workload)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sim, err := scheduler.NewSimulator(scheduler.WithOptions(k.Config.Options))
+	if err != nil {
+		return nil, err
+	}
+	results := make([]render.Named, 0, len(k.Results))
+	for _, want := range k.Results {
+		a, err := scheduler.LookupAlgorithm(want.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, render.Named{Algorithm: a.Name, Title: a.Title, Result: sim.Schedule(a, processes)})
+	}
+	return results, nil
+}
+
+// Report is the outcome of grading one algorithm.
+type Report struct {
+	Algorithm string `json:"algorithm"`
+	Pass      bool   `json:"pass"`
+	// Failures describes every check that failed.
+	Failures []string `json:"failures,omitempty"`
+}
+
+// Grade checks every result of the key against the submitted result of the
+// same algorithm.
+func (k Key) Grade(submitted []render.Named) []Report {
+	byName := make(map[string]scheduler.Result, len(submitted))
+	for _, n := range submitted {
+		byName[n.Algorithm] = n.Result
+	}
+	reports := make([]Report, len(k.Results))
+	for i, want := range k.Results {
+		reports[i].Algorithm = want.Algorithm
+		if got, ok := byName[want.Algorithm]; ok {
+			reports[i].Failures = k.compare(got, want.Result)
+		} else {
+			reports[i].Failures = []string{"no result submitted"}
+		}
+		reports[i].Pass = len(reports[i].Failures) == 0
+	}
+	return reports
+}
+
+// Failed returns ErrFailed, counting the failures, unless every report
+// passed.
+func Failed(reports []Report) error {
+	failed := 0
+	for _, r := range reports {
+		if !r.Pass {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d algorithms", ErrFailed, failed, len(reports))
+	}
+	return nil
+}
+
+func (k Key) compare(got, want scheduler.Result) []string {
+	var failures []string
+	fail := func(format string, args ...any) { failures = append(failures, fmt.Sprintf(format, args...)) }
+	formatTime := func(ticks int64) string {
+		if k.Tick == 0 {
+			return fmt.Sprint(ticks)
+		}
+		return (time.Duration(ticks) * k.Tick).String()
+	}
+
+	for _, m := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"average wait", got.AveWait, want.AveWait},
+		{"average turnaround", got.AveTurnaround, want.AveTurnaround},
+		{"throughput", got.AveThroughput, want.AveThroughput},
+	} {
+		if math.Abs(m.got-m.want) > k.Tolerance.Average {
+			fail("%s %.4g, want %.4g", m.name, m.got, m.want)
+		}
+	}
+
+	stats := make(map[int64]scheduler.Stat, len(got.Stats))
+	for _, s := range got.Stats {
+		stats[s.ProcessID] = s
+	}
+	for _, w := range want.Stats {
+		g, ok := stats[w.ProcessID]
+		if !ok {
+			fail("P%d missing", w.ProcessID)
+			continue
+		}
+		for _, m := range []struct {
+			name      string
+			got, want int64
+		}{
+			{"wait", g.Wait, w.Wait},
+			{"turnaround", g.Turnaround, w.Turnaround},
+			{"completion", g.Completion, w.Completion},
+		} {
+			if abs(m.got-m.want) > k.Tolerance.Time {
+				fail("P%d %s %s, want %s", w.ProcessID, m.name, formatTime(m.got), formatTime(m.want))
+			}
+		}
+	}
+	if len(got.Stats) != len(want.Stats) {
+		fail("%d processes, want %d", len(got.Stats), len(want.Stats))
+	}
+
+	if k.Tolerance.SkipGantt {
+		return failures
+	}
+	slice := func(s scheduler.TimeSlice) string {
+		return fmt.Sprintf("P%d %s-%s on CPU %d", s.PID, formatTime(s.Start), formatTime(s.Stop), s.CPU)
+	}
+	// Results from before the engine joined slices are graded as if it had.
+	gotGantt, wantGantt := scheduler.Coalesce(got.Gantt), scheduler.Coalesce(want.Gantt)
+	for i := range min(len(gotGantt), len(wantGantt)) {
+		g, w := gotGantt[i], wantGantt[i]
+		if g.PID != w.PID || g.CPU != w.CPU || abs(g.Start-w.Start) > k.Tolerance.Time || abs(g.Stop-w.Stop) > k.Tolerance.Time {
+			// Later slices are likely off too, so only the first is reported.
+			fail("gantt slice %d is %s, want %s", i+1, slice(g), slice(w))
+			break
+		}
+	}
+	if len(gotGantt) != len(wantGantt) {
+		fail("gantt has %d slices, want %d", len(gotGantt), len(wantGantt))
+	}
+	return failures
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,115 @@
+package grade
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+var workload = []scheduler.Process{
+	{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 2},
+	{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Priority: 1},
+}
+
+func answerKey(t *testing.T, extra string) Key {
+	t.Helper()
+	var buf bytes.Buffer
+	results := []render.Named{
+		{Algorithm: "fcfs", Title: "First-come, first-serve", Result: scheduler.FCFS(workload)},
+		{Algorithm: "rr", Title: "Round-robin", Result: scheduler.RR(workload)},
+	}
+	if err := render.JSON(&buf, results, render.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	doc := strings.Replace(buf.String(), "{", "{"+extra, 1)
+	key, err := LoadKey(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestGrade(t *testing.T) {
+	key := answerKey(t, "")
+	if key.Tolerance != DefaultTolerance() {
+		t.Errorf("tolerance = %+v, want the default", key.Tolerance)
+	}
+	reports := key.Grade(key.Results)
+	if err := Failed(reports); err != nil {
+		t.Errorf("grading the key against itself: %v, %+v", err, reports)
+	}
+
+	wrong := []render.Named{{Algorithm: "fcfs", Result: scheduler.SJF(workload)}}
+	reports = key.Grade(wrong)
+	if err := Failed(reports); !errors.Is(err, ErrFailed) {
+		t.Errorf("err = %v, want %v", err, ErrFailed)
+	}
+	if reports[0].Pass || !strings.Contains(strings.Join(reports[0].Failures, "\n"), "gantt slice 1 is P1 0-1 on CPU 0, want P1 0-5 on CPU 0") {
+		t.Errorf("fcfs report = %+v", reports[0])
+	}
+	if want := (Report{Algorithm: "rr", Failures: []string{"no result submitted"}}); !reflect.DeepEqual(reports[1], want) {
+		t.Errorf("rr report = %+v, want %+v", reports[1], want)
+	}
+}
+
+func TestGradeTolerance(t *testing.T) {
+	key := answerKey(t, `"tolerance": {"average": 1, "time": 1, "skipGantt": true},`)
+	got := []render.Named{{Algorithm: "fcfs", Result: scheduler.FCFS(workload)}, {Algorithm: "rr", Result: scheduler.RR(workload)}}
+	got[0].Result.AveWait += 0.5
+	got[0].Result.Stats[1].Wait++
+	got[0].Result.Gantt = nil
+	if err := Failed(key.Grade(got)); err != nil {
+		t.Errorf("within tolerance: %v", err)
+	}
+	got[0].Result.Stats[1].Completion += 2
+	if reports := key.Grade(got); reports[0].Pass {
+		t.Errorf("outside tolerance passed: %+v", reports[0])
+	}
+}
+
+func TestToleranceValidate(t *testing.T) {
+	if err := DefaultTolerance().Validate(); err != nil {
+		t.Errorf("default: %v", err)
+	}
+	for _, tol := range []Tolerance{{Average: -1}, {Average: math.NaN()}, {Time: -1}} {
+		if err := tol.Validate(); !errors.Is(err, scheduler.ErrInvalidOption) {
+			t.Errorf("%+v: err = %v, want %v", tol, err, scheduler.ErrInvalidOption)
+		}
+	}
+	if _, err := LoadKey(strings.NewReader(`{"schemaVersion": 2, "tolerance": {"average": -0.5}, "results": []}`)); !errors.Is(err, scheduler.ErrInvalidOption) {
+		t.Errorf("negative tolerance in key: err = %v, want %v", err, scheduler.ErrInvalidOption)
+	}
+}
+
+func TestWriteKey(t *testing.T) {
+	key := answerKey(t, `"tolerance": {"average": 0.5, "time": 2, "skipGantt": false}, "config": {"cpus": 2},`)
+	var buf bytes.Buffer
+	if err := key.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadKey(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, key) {
+		t.Errorf("round trip = %+v, want %+v", got, key)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	key := answerKey(t, `"config": {"rr": {"quantum": 2}},`)
+	got, err := key.Schedule(strings.NewReader("1,5,0,2\n2,3,1,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reports := key.Grade(got)
+	if !reports[0].Pass || reports[1].Pass {
+		t.Errorf("fcfs should pass and rr with the key's quantum fail: %+v", reports)
+	}
+}
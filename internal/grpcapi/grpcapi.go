@@ -0,0 +1,236 @@
+// Package grpcapi implements the Simulator gRPC service defined in
+// api/schedsimpb/schedsim.proto.
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"p1/api/schedsimpb"
+	"p1/internal/config"
+	"p1/internal/limits"
+	"p1/internal/metrics"
+	"p1/internal/scheduler"
+)
+
+// Server serves the Simulator service.
+type Server struct {
+	schedsimpb.UnimplementedSimulatorServer
+	metrics *metrics.Metrics
+}
+
+// Register adds the Simulator service to s, recording to m unless it is nil.
+func Register(s *grpc.Server, m *metrics.Metrics) {
+	schedsimpb.RegisterSimulatorServer(s, &Server{metrics: m})
+}
+
+// scheduleTimeout bounds how long the algorithms of a RunSimulation call may
+// take between them.
+var scheduleTimeout = limits.Timeout
+
+func (s *Server) RunSimulation(ctx context.Context, req *schedsimpb.RunSimulationRequest) (*schedsimpb.RunSimulationResponse, error) {
+	resp, err := s.runSimulation(ctx, req)
+	s.fail(err)
+	return resp, err
+}
+
+// runSimulation runs the algorithms of req, failing with DeadlineExceeded if
+// they take longer than scheduleTimeout between them, or with the code of
+// ctx's error if it ends first.
+func (s *Server) runSimulation(ctx context.Context, req *schedsimpb.RunSimulationRequest) (*schedsimpb.RunSimulationResponse, error) {
+	sim, err := newSimulator(req.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+	names := req.GetAlgorithms()
+	if len(names) == 0 {
+		names = config.Default().Algorithms
+	}
+	processes, err := fromProcesses(sim, req.GetProcesses())
+	if err != nil {
+		return nil, err
+	}
+	s.metrics.Workload(len(processes))
+
+	ctx, cancel := context.WithTimeout(ctx, scheduleTimeout)
+	defer cancel()
+	resp := &schedsimpb.RunSimulationResponse{}
+	for _, name := range names {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		start := time.Now()
+		res := sim.Stream(ctx, a, processes, scheduler.Sink{})
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		s.metrics.Simulation(a.Name, time.Since(start))
+		resp.Results = append(resp.Results, toResult(a, res))
+	}
+	return resp, nil
+}
+
+func (s *Server) StreamSimulation(req *schedsimpb.StreamSimulationRequest, stream grpc.ServerStreamingServer[schedsimpb.StreamSimulationResponse]) error {
+	err := s.streamSimulation(req, stream)
+	s.fail(err)
+	return err
+}
+
+func (s *Server) streamSimulation(req *schedsimpb.StreamSimulationRequest, stream grpc.ServerStreamingServer[schedsimpb.StreamSimulationResponse]) error {
+	sim, err := newSimulator(req.GetOptions())
+	if err != nil {
+		return err
+	}
+	a, err := scheduler.LookupAlgorithm(req.GetAlgorithm())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	processes, err := fromProcesses(sim, req.GetProcesses())
+	if err != nil {
+		return err
+	}
+	s.metrics.Workload(len(processes))
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	start := time.Now()
+	run := sim.Simulate(ctx, a, processes)
+	var sendErr error
+	for ev := range run.Events {
+		if sendErr != nil {
+			continue
+		}
+		sendErr = stream.Send(&schedsimpb.StreamSimulationResponse{
+			Item: &schedsimpb.StreamSimulationResponse_Event{Event: toEvent(ev)},
+		})
+		if sendErr != nil {
+			cancel()
+		}
+	}
+	if sendErr != nil {
+		return sendErr
+	}
+
+	res := toResult(a, run.Result())
+	s.metrics.Simulation(a.Name, time.Since(start))
+	for _, ts := range res.Gantt {
+		if err := stream.Send(&schedsimpb.StreamSimulationResponse{
+			Item: &schedsimpb.StreamSimulationResponse_Slice{Slice: ts},
+		}); err != nil {
+			return err
+		}
+	}
+	return stream.Send(&schedsimpb.StreamSimulationResponse{
+		Item: &schedsimpb.StreamSimulationResponse_Result{Result: res},
+	})
+}
+
+// fail records err, if any, by its status code.
+func (s *Server) fail(err error) {
+	if err != nil {
+		s.metrics.Error("grpc", status.Code(err).String())
+	}
+}
+
+// newSimulator returns a simulator with the defaults overridden by the fields
+// set in opts.
+func newSimulator(opts *schedsimpb.Options) (*scheduler.Simulator, error) {
+	if opts == nil {
+		opts = &schedsimpb.Options{}
+	}
+	o := scheduler.DefaultOptions()
+	if opts.RrQuantum != nil {
+		o.RR.Quantum = opts.GetRrQuantum()
+	}
+	if len(opts.GetMlfqQuanta()) > 0 {
+		o.MLFQ = scheduler.MLFQParams{Levels: len(opts.GetMlfqQuanta()), Quanta: opts.GetMlfqQuanta()}
+	}
+	if opts.AgingRate != nil {
+		o.Aging.Rate = opts.GetAgingRate()
+	}
+	if opts.Cpus != nil {
+		o.CPUs = int(opts.GetCpus())
+	}
+	if opts.SwitchCost != nil {
+		o.SwitchCost = opts.GetSwitchCost()
+	}
+	if opts.Seed != nil {
+		o.Seed = opts.GetSeed()
+	}
+	if err := limits.CheckOptions(o); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	sim, err := scheduler.NewSimulator(scheduler.WithOptions(o))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return sim, nil
+}
+
+// fromProcesses returns the processes of ps, failing with InvalidArgument
+// if sim cannot run them within the limits.
+func fromProcesses(sim *scheduler.Simulator, ps []*schedsimpb.Process) ([]scheduler.Process, error) {
+	processes := make([]scheduler.Process, len(ps))
+	for i, p := range ps {
+		processes[i] = scheduler.Process{
+			ProcessID:     p.GetId(),
+			ArrivalTime:   p.GetArrival(),
+			BurstDuration: p.GetBurst(),
+			Priority:      p.GetPriority(),
+		}
+	}
+	if err := limits.Check(sim.Options(), processes); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return processes, nil
+}
+
+func toProcess(p scheduler.Process) *schedsimpb.Process {
+	return &schedsimpb.Process{
+		Id:       p.ProcessID,
+		Arrival:  p.ArrivalTime,
+		Burst:    p.BurstDuration,
+		Priority: p.Priority,
+	}
+}
+
+func toResult(a scheduler.Algorithm, res scheduler.Result) *schedsimpb.Result {
+	out := &schedsimpb.Result{
+		Algorithm:         a.Name,
+		Title:             a.Title,
+		AverageWait:       res.AveWait,
+		AverageTurnaround: res.AveTurnaround,
+		Throughput:        res.AveThroughput,
+		ContextSwitches:   int32(res.ContextSwitches),
+	}
+	for _, ts := range res.Gantt {
+		out.Gantt = append(out.Gantt, &schedsimpb.TimeSlice{Pid: ts.PID, Start: ts.Start, Stop: ts.Stop, Cpu: int32(ts.CPU)})
+	}
+	for _, s := range res.Stats {
+		out.Stats = append(out.Stats, &schedsimpb.Stat{
+			Process:    toProcess(s.Process),
+			Wait:       s.Wait,
+			Turnaround: s.Turnaround,
+			Completion: s.Completion,
+		})
+	}
+	return out
+}
+
+var eventKinds = map[scheduler.EventKind]schedsimpb.Event_Kind{
+	scheduler.EventArrive:   schedsimpb.Event_KIND_ARRIVE,
+	scheduler.EventDispatch: schedsimpb.Event_KIND_DISPATCH,
+	scheduler.EventPreempt:  schedsimpb.Event_KIND_PREEMPT,
+	scheduler.EventComplete: schedsimpb.Event_KIND_COMPLETE,
+	scheduler.EventIdle:     schedsimpb.Event_KIND_IDLE,
+}
+
+func toEvent(ev scheduler.Event) *schedsimpb.Event {
+	return &schedsimpb.Event{Kind: eventKinds[ev.Kind], Time: ev.Time, Pid: ev.PID, Cpu: int32(ev.CPU)}
+}
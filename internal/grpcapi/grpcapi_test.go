@@ -0,0 +1,135 @@
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	"p1/api/schedsimpb"
+	"p1/internal/limits"
+)
+
+func dial(t *testing.T) schedsimpb.SimulatorClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	Register(s, nil)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return schedsimpb.NewSimulatorClient(conn)
+}
+
+var workload = []*schedsimpb.Process{
+	{Id: 1, Burst: 5, Arrival: 0, Priority: 2},
+	{Id: 2, Burst: 9, Arrival: 3, Priority: 1},
+	{Id: 3, Burst: 6, Arrival: 6, Priority: 3},
+}
+
+func TestRunSimulation(t *testing.T) {
+	c := dial(t)
+	resp, err := c.RunSimulation(context.Background(), &schedsimpb.RunSimulationRequest{
+		Processes:  workload,
+		Algorithms: []string{"fcfs", "rr"},
+		Options:    &schedsimpb.Options{RrQuantum: proto.Int64(2)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(resp.Results))
+	}
+	if got := resp.Results[0]; got.Algorithm != "fcfs" || got.AverageWait != 10.0/3 {
+		t.Errorf("fcfs = %v", got)
+	}
+	// P1 runs alone for two quanta before P2 gets one.
+	if got := resp.Results[1].Gantt[1]; got.Pid != 2 || got.Start != 4 || got.Stop != 6 {
+		t.Errorf("second rr slice = %v, want P2 for a quantum of 2", got)
+	}
+
+	_, err = c.RunSimulation(context.Background(), &schedsimpb.RunSimulationRequest{Algorithms: []string{"nope"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown algorithm: err = %v, want %v", err, codes.InvalidArgument)
+	}
+	_, err = c.RunSimulation(context.Background(), &schedsimpb.RunSimulationRequest{Options: &schedsimpb.Options{Cpus: proto.Int32(0)}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("no CPUs: err = %v, want %v", err, codes.InvalidArgument)
+	}
+	_, err = c.RunSimulation(context.Background(), &schedsimpb.RunSimulationRequest{Options: &schedsimpb.Options{Cpus: proto.Int32(limits.MaxCPUs + 1)}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("too many CPUs: err = %v, want %v", err, codes.InvalidArgument)
+	}
+	_, err = c.RunSimulation(context.Background(), &schedsimpb.RunSimulationRequest{Processes: []*schedsimpb.Process{{Id: 1, Burst: -5}}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("negative burst: err = %v, want %v", err, codes.InvalidArgument)
+	}
+	_, err = c.RunSimulation(context.Background(), &schedsimpb.RunSimulationRequest{Processes: []*schedsimpb.Process{{Id: 1, Burst: math.MaxInt64}, {Id: 2, Burst: 1}}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("overflowing bursts: err = %v, want %v", err, codes.InvalidArgument)
+	}
+
+	// A run stops once its ctx ends, without waiting for the client.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := new(Server).RunSimulation(ctx, &schedsimpb.RunSimulationRequest{Processes: workload}); status.Code(err) != codes.Canceled {
+		t.Errorf("cancelled: err = %v, want %v", err, codes.Canceled)
+	}
+	defer func(d time.Duration) { scheduleTimeout = d }(scheduleTimeout)
+	scheduleTimeout = 0
+	if _, err := new(Server).RunSimulation(context.Background(), &schedsimpb.RunSimulationRequest{Processes: workload}); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("timed out: err = %v, want %v", err, codes.DeadlineExceeded)
+	}
+}
+
+func TestStreamSimulation(t *testing.T) {
+	c := dial(t)
+	stream, err := c.StreamSimulation(context.Background(), &schedsimpb.StreamSimulationRequest{Processes: workload, Algorithm: "rr"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events, slices int
+	var result *schedsimpb.Result
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch item := msg.Item.(type) {
+		case *schedsimpb.StreamSimulationResponse_Event:
+			if slices > 0 || result != nil {
+				t.Error("event sent after the Gantt chart")
+			}
+			events++
+		case *schedsimpb.StreamSimulationResponse_Slice:
+			slices++
+		case *schedsimpb.StreamSimulationResponse_Result:
+			result = item.Result
+		}
+	}
+	if result == nil {
+		t.Fatal("no result")
+	}
+	if events == 0 || slices != len(result.Gantt) {
+		t.Errorf("got %d events and %d slices, want events and %d slices", events, slices, len(result.Gantt))
+	}
+}
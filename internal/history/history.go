@@ -0,0 +1,130 @@
+// Package history persists the parameters and results of schedsim runs in a
+// bbolt database, so past runs can be listed and shown again without
+// rerunning them.
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"p1/internal/config"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// EnvPath is the environment variable that overrides DefaultPath. Setting it
+// to the empty string turns history off.
+const EnvPath = "SCHEDSIM_HISTORY"
+
+var ErrNotFound = errors.New("no such run")
+
+var runsBucket = []byte("runs")
+
+// Run is one recorded invocation of schedsim.
+type Run struct {
+	ID   uint64    `json:"id"`
+	Time time.Time `json:"time"`
+	// Workload names the workload file that was scheduled.
+	Workload  string              `json:"workload"`
+	Config    config.Config       `json:"config"`
+	Tick      time.Duration       `json:"tick,omitempty"`
+	Processes []scheduler.Process `json:"processes"`
+	Results   []render.Named      `json:"results"`
+}
+
+// Store is an open history database.
+type Store struct {
+	db *bolt.DB
+}
+
+// DefaultPath returns where history is kept unless told otherwise: the file
+// named by $SCHEDSIM_HISTORY if set, or history.db in the schedsim directory
+// of the user's config directory. It returns "" if history is turned off.
+func DefaultPath() string {
+	if p, ok := os.LookupEnv(EnvPath); ok {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "schedsim", "history.db")
+}
+
+// Open opens the history database at path, creating it if needed.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("%w: opening history %s", err, path)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(runsBucket)
+		return err
+	}); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error { return s.db.Close() }
+
+// Add records r, setting its ID.
+func (s *Store) Add(r *Run) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(runsBucket)
+		id, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		r.ID = id
+		v, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		return b.Put(key(id), v)
+	})
+}
+
+// Get returns the run with the given ID.
+func (s *Store) Get(id uint64) (Run, error) {
+	var r Run
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(runsBucket).Get(key(id))
+		if v == nil {
+			return fmt.Errorf("%w: %d", ErrNotFound, id)
+		}
+		return json.Unmarshal(v, &r)
+	})
+	return r, err
+}
+
+// List returns every run, oldest first.
+func (s *Store) List() ([]Run, error) {
+	var runs []Run
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(runsBucket).ForEach(func(_, v []byte) error {
+			var r Run
+			if err := json.Unmarshal(v, &r); err != nil {
+				return err
+			}
+			runs = append(runs, r)
+			return nil
+		})
+	})
+	return runs, err
+}
+
+func key(id uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, id)
+}
@@ -0,0 +1,76 @@
+package history
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"p1/internal/config"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	processes := []scheduler.Process{{ProcessID: 1, BurstDuration: 3}}
+	first := Run{
+		Time:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Workload:  "a.csv",
+		Config:    config.Default(),
+		Tick:      time.Millisecond,
+		Processes: processes,
+		Results:   []render.Named{{Algorithm: "fcfs", Title: "First-come, first-serve", Result: scheduler.FCFS(processes)}},
+	}
+	second := Run{Workload: "b.csv", Config: config.Default()}
+	for _, r := range []*Run{&first, &second} {
+		if err := s.Add(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("IDs = %d, %d, want 1, 2", first.ID, second.ID)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	got, err := s.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, first) {
+		t.Errorf("got %+v, want %+v", got, first)
+	}
+	runs, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[1].Workload != "b.csv" {
+		t.Errorf("list = %+v", runs)
+	}
+	if _, err := s.Get(3); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv(EnvPath, "")
+	if p := DefaultPath(); p != "" {
+		t.Errorf("path = %q, want history off", p)
+	}
+	t.Setenv(EnvPath, "/tmp/h.db")
+	if p := DefaultPath(); p != "/tmp/h.db" {
+		t.Errorf("path = %q, want /tmp/h.db", p)
+	}
+}
@@ -0,0 +1,73 @@
+package input
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"p1/internal/scheduler"
+)
+
+// fuzzSeeds are well-formed and malformed workloads to start fuzzing from.
+var fuzzSeeds = []string{
+	"1,5,0,2\n2,9,3,1\n3,6,6,3",
+	"1,5,0\n2,9,3",
+	"id,burst,arrival,nice\n1,5,0,-20\n2,9,3,19",
+	"1,5ms,0s,2\n2,1.5s,3ms,1",
+	` [{"id":1,"burst":5,"arrival":0,"priority":2},{"id":2,"burst":9,"arrival":3}]`,
+	`[{"id":"one"}]`,
+	"1,-5,-2,0\n2,0,0,0",
+	"1,9223372036854775807,9223372036854775807",
+	"\"1\",\"5\n\",0",
+	"",
+}
+
+func FuzzLoad(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		processes, err := Load(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		checkSchedules(t, processes)
+	})
+}
+
+func FuzzLoadDurations(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add([]byte(s), int64(time.Millisecond))
+	}
+	f.Fuzz(func(t *testing.T, data []byte, tick int64) {
+		processes, err := LoadDurations(bytes.NewReader(data), time.Duration(tick))
+		if err != nil {
+			return
+		}
+		checkSchedules(t, processes)
+	})
+}
+
+// checkSchedules runs every algorithm over a workload that loaded, on one
+// CPU and on several, if it is small enough to finish quickly.
+func checkSchedules(t *testing.T, processes []scheduler.Process) {
+	var total int64
+	for _, p := range processes {
+		total += max(0, p.BurstDuration)
+		if len(processes) > 100 || p.BurstDuration > 10_000 || total > 10_000 {
+			return
+		}
+	}
+	for _, cpus := range []int{1, 3} {
+		sim, err := scheduler.NewSimulator(scheduler.WithCPUs(cpus), scheduler.WithSwitchCost(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, a := range scheduler.Algorithms() {
+			res := sim.Schedule(a, processes)
+			if len(res.Stats) != len(processes) {
+				t.Fatalf("%s on %d CPUs: %d stats for %d processes", a.Name, cpus, len(res.Stats), len(processes))
+			}
+		}
+	}
+}
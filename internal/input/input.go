@@ -0,0 +1,473 @@
+// Package input loads process workloads for the schedulers, the lists of
+// numbers the other simulators take, and the schedules of simulators other
+// than schedsim to check its own against.
+package input
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"p1/internal/scheduler"
+)
+
+var (
+	ErrInvalidRow    = errors.New("invalid row")
+	ErrInvalidTick   = errors.New("invalid tick")
+	ErrInvalidNumber = errors.New("invalid number")
+	ErrInvalidEvent  = errors.New("invalid event")
+)
+
+// LoadProcesses reads processes from CSV rows of the form
+// ID,Burst,Arrival[,Priority]. The first row may instead be a header naming
+// the columns, in any order, from id, burst, arrival, priority, weight,
+// nice, estimate, group, class and deadline. A nice value from -20 to 19
+// stands for the priority and weight Linux gives it, see
+// scheduler.FromNice, an estimate is the burst schedulers expect the process
+// to need rather than the burst it has, a group is the name of the
+// fair-share group the process is in, a class is realtime, interactive or
+// batch, see scheduler.Class, and a deadline is the time the process should
+// complete by.
+func LoadProcesses(r io.Reader) ([]scheduler.Process, error) {
+	return loadCSV(r, parseInt)
+}
+
+// LoadDurations reads processes from CSV rows like LoadProcesses, except that
+// bursts and arrivals are durations such as 250ms or 1.5s. They are converted
+// to whole ticks of the given length.
+func LoadDurations(r io.Reader, tick time.Duration) ([]scheduler.Process, error) {
+	if tick <= 0 {
+		return nil, fmt.Errorf("%w: must be positive, got %v", ErrInvalidTick, tick)
+	}
+	return loadCSV(r, func(s string) (int64, error) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, err
+		}
+		return toTicks(d, tick)
+	})
+}
+
+// column parses a field of a CSV row into where it belongs.
+type column func(field string) error
+
+// number returns the column parsing a field into dst with parse.
+func number(dst *int64, parse func(string) (int64, error)) column {
+	return func(field string) (err error) {
+		*dst, err = parse(field)
+		return err
+	}
+}
+
+// loadCSV reads CSV rows of processes, parsing bursts and arrivals with
+// parseTime.
+func loadCSV(r io.Reader, parseTime func(string) (int64, error)) ([]scheduler.Process, error) {
+	// Rows are parsed as they are read, so a large file is never held in
+	// memory as text as well as processes.
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	processes := []scheduler.Process{}
+	var (
+		p    scheduler.Process
+		nice int64
+	)
+	known := map[string]column{
+		"id":       number(&p.ProcessID, parseInt),
+		"burst":    number(&p.BurstDuration, parseTime),
+		"arrival":  number(&p.ArrivalTime, parseTime),
+		"priority": number(&p.Priority, parseInt),
+		"weight":   number(&p.Weight, parseInt),
+		"nice":     number(&nice, parseInt),
+		"estimate": number(&p.Estimate, parseTime),
+		"deadline": number(&p.Deadline, parseTime),
+		"group": func(field string) error {
+			p.Group = strings.TrimSpace(field)
+			return nil
+		},
+		"class": func(field string) error {
+			return p.Class.UnmarshalText([]byte(strings.ToLower(strings.TrimSpace(field))))
+		},
+	}
+	columns := []column{known["id"], known["burst"], known["arrival"], known["priority"]}
+	// header is set once a header row names the columns, and niced if it
+	// names a nice column.
+	var header, niced bool
+	for i := 0; ; i++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading CSV", err)
+		}
+		if i == 0 && len(row) > 0 {
+			if _, ok := known[columnName(row[0])]; ok {
+				if columns, niced, err = parseHeader(row, known); err != nil {
+					return nil, fmt.Errorf("%w %d: %w", ErrInvalidRow, i+1, err)
+				}
+				header = true
+				continue
+			}
+		}
+		if !header && len(row) < 3 {
+			return nil, fmt.Errorf("%w %d: expected at least 3 fields, got %d", ErrInvalidRow, i+1, len(row))
+		}
+		p, nice = scheduler.Process{}, 0
+		for j, c := range columns {
+			if j >= len(row) {
+				break
+			}
+			if err = c(row[j]); err != nil {
+				return nil, fmt.Errorf("%w %d: %w", ErrInvalidRow, i+1, err)
+			}
+		}
+		if niced {
+			if p, err = scheduler.FromNice(p, nice); err != nil {
+				return nil, fmt.Errorf("%w %d: %w", ErrInvalidRow, i+1, err)
+			}
+		}
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("%w %d: %w", ErrInvalidRow, i+1, err)
+		}
+		processes = append(processes, p)
+	}
+
+	return processes, nil
+}
+
+// columnName returns the column named by a header field.
+func columnName(field string) string {
+	return strings.ToLower(strings.TrimSpace(field))
+}
+
+// parseHeader returns the columns named by a header row, and whether they
+// include a nice value. A header must name the id, burst and arrival
+// columns, and cannot name a priority or weight as well as the nice value
+// they follow from.
+func parseHeader(row []string, known map[string]column) ([]column, bool, error) {
+	columns := make([]column, len(row))
+	names := make([]string, len(row))
+	for j, field := range row {
+		name := columnName(field)
+		c, ok := known[name]
+		switch {
+		case !ok:
+			return nil, false, fmt.Errorf("unknown column %q", field)
+		case slices.Contains(names, name):
+			return nil, false, fmt.Errorf("column %q given twice", field)
+		}
+		columns[j], names[j] = c, name
+	}
+	for _, name := range []string{"id", "burst", "arrival"} {
+		if !slices.Contains(names, name) {
+			return nil, false, fmt.Errorf("no %s column", name)
+		}
+	}
+	niced := slices.Contains(names, "nice")
+	if niced && (slices.Contains(names, "priority") || slices.Contains(names, "weight")) {
+		return nil, false, errors.New("nice sets the priority and weight, so cannot be given with them")
+	}
+	return columns, niced, nil
+}
+
+func parseInt(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// DurationProcess is a process whose times are real durations rather than
+// ticks.
+type DurationProcess struct {
+	ProcessID     int64
+	ArrivalTime   time.Duration
+	BurstDuration time.Duration
+	Priority      int64
+}
+
+// FromDurations converts processes to ticks of the given length.
+func FromDurations(processes []DurationProcess, tick time.Duration) ([]scheduler.Process, error) {
+	if tick <= 0 {
+		return nil, fmt.Errorf("%w: must be positive, got %v", ErrInvalidTick, tick)
+	}
+	out := make([]scheduler.Process, len(processes))
+	for i, p := range processes {
+		out[i] = scheduler.Process{ProcessID: p.ProcessID, Priority: p.Priority}
+		var err error
+		if out[i].ArrivalTime, err = toTicks(p.ArrivalTime, tick); err != nil {
+			return nil, fmt.Errorf("process %d: %w", p.ProcessID, err)
+		}
+		if out[i].BurstDuration, err = toTicks(p.BurstDuration, tick); err != nil {
+			return nil, fmt.Errorf("process %d: %w", p.ProcessID, err)
+		}
+		if err := out[i].Validate(); err != nil {
+			return nil, fmt.Errorf("process %d: %w", p.ProcessID, err)
+		}
+	}
+	return out, nil
+}
+
+func toTicks(d, tick time.Duration) (int64, error) {
+	if d%tick != 0 {
+		return 0, fmt.Errorf("%w: %v is not a whole number of %v ticks", ErrInvalidTick, d, tick)
+	}
+	return int64(d / tick), nil
+}
+
+// LoadJSON reads processes from a JSON array of objects with the fields id,
+// burst, arrival and optionally priority and weight, or a nice value in
+// their place, estimate, group, class and deadline.
+func LoadJSON(r io.Reader) ([]scheduler.Process, error) {
+	var rows []struct {
+		scheduler.Process
+		Nice *int64 `json:"nice"`
+	}
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("%w: reading JSON", err)
+	}
+	processes := make([]scheduler.Process, len(rows))
+	for i, row := range rows {
+		p := row.Process
+		if row.Nice != nil {
+			if p.Priority != 0 || p.Weight != 0 {
+				return nil, fmt.Errorf("%w %d: nice sets the priority and weight, so cannot be given with them", ErrInvalidRow, i+1)
+			}
+			var err error
+			if p, err = scheduler.FromNice(p, *row.Nice); err != nil {
+				return nil, fmt.Errorf("%w %d: %w", ErrInvalidRow, i+1, err)
+			}
+		}
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("%w %d: %w", ErrInvalidRow, i+1, err)
+		}
+		processes[i] = p
+	}
+	return processes, nil
+}
+
+// Load reads processes from r, detecting whether it holds a JSON array or
+// CSV rows.
+func Load(r io.Reader) ([]scheduler.Process, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return LoadProcesses(br)
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = br.ReadByte()
+		case '[':
+			return LoadJSON(br)
+		default:
+			return LoadProcesses(br)
+		}
+	}
+}
+
+// WriteCSV writes processes as CSV rows that LoadProcesses reads back. If
+// any process has a weight, an estimate, a group, a class or a deadline, the
+// rows follow a header adding a column for them.
+func WriteCSV(w io.Writer, processes []scheduler.Process) error {
+	cw := csv.NewWriter(w)
+	weighted := slices.ContainsFunc(processes, func(p scheduler.Process) bool { return p.Weight != 0 })
+	estimated := slices.ContainsFunc(processes, func(p scheduler.Process) bool { return p.Estimate != 0 })
+	grouped := slices.ContainsFunc(processes, func(p scheduler.Process) bool { return p.Group != "" })
+	classed := slices.ContainsFunc(processes, func(p scheduler.Process) bool { return p.Class != "" })
+	due := slices.ContainsFunc(processes, func(p scheduler.Process) bool { return p.Deadline != 0 })
+	if weighted || estimated || grouped || classed || due {
+		header := []string{"id", "burst", "arrival", "priority"}
+		if weighted {
+			header = append(header, "weight")
+		}
+		if estimated {
+			header = append(header, "estimate")
+		}
+		if grouped {
+			header = append(header, "group")
+		}
+		if classed {
+			header = append(header, "class")
+		}
+		if due {
+			header = append(header, "deadline")
+		}
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+	}
+	for _, p := range processes {
+		row := []string{
+			strconv.FormatInt(p.ProcessID, 10),
+			strconv.FormatInt(p.BurstDuration, 10),
+			strconv.FormatInt(p.ArrivalTime, 10),
+			strconv.FormatInt(p.Priority, 10),
+		}
+		if weighted {
+			row = append(row, strconv.FormatInt(p.Weight, 10))
+		}
+		if estimated {
+			row = append(row, strconv.FormatInt(p.Estimate, 10))
+		}
+		if grouped {
+			row = append(row, p.Group)
+		}
+		if classed {
+			row = append(row, string(p.Class))
+		}
+		if due {
+			row = append(row, strconv.FormatInt(p.Deadline, 10))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// LoadNumbers reads a list of non-negative integers separated by commas,
+// spaces or newlines, such as the cylinders of disk requests.
+func LoadNumbers(r io.Reader) ([]int64, error) {
+	sc := bufio.NewScanner(r)
+	sc.Split(bufio.ScanWords)
+	var numbers []int64
+	for sc.Scan() {
+		for _, f := range strings.FieldsFunc(sc.Text(), func(r rune) bool { return r == ',' }) {
+			n, err := parseInt(f)
+			if err == nil && n < 0 {
+				err = fmt.Errorf("must not be negative, got %d", n)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w %d: %w", ErrInvalidNumber, len(numbers)+1, err)
+			}
+			numbers = append(numbers, n)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading numbers", err)
+	}
+	return numbers, nil
+}
+
+// LoadInterventions reads operator interventions, a line each of the form
+// "t=30 suspend pid=2" or "t=45 resume pid=2", and pairs them into the
+// suspensions they make. Blank lines and those starting with # are skipped.
+// Times are ticks, or durations such as 30ms converted to ticks of the given
+// length if tick is not 0. Every process suspended must be resumed later.
+func LoadInterventions(r io.Reader, tick time.Duration) ([]scheduler.Suspension, error) {
+	parseTime := parseInt
+	if tick != 0 {
+		if tick < 0 {
+			return nil, fmt.Errorf("%w: must be positive, got %v", ErrInvalidTick, tick)
+		}
+		parseTime = func(s string) (int64, error) {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return 0, err
+			}
+			return toTicks(d, tick)
+		}
+	}
+
+	var interventions []intervention
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		in, err := parseIntervention(fields, parseTime)
+		if err != nil {
+			return nil, fmt.Errorf("%w on line %d: %w", ErrInvalidEvent, line, err)
+		}
+		in.line = line
+		interventions = append(interventions, in)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading interventions", err)
+	}
+
+	slices.SortStableFunc(interventions, func(a, b intervention) int { return cmp.Compare(a.t, b.t) })
+	var suspensions []scheduler.Suspension
+	open := make(map[int64]int) // the suspension of each process suspended
+	for _, in := range interventions {
+		i, ok := open[in.pid]
+		switch {
+		case in.suspend && ok:
+			return nil, fmt.Errorf("%w on line %d: P%d is suspended at %d while suspended since %d", ErrInvalidEvent, in.line, in.pid, in.t, suspensions[i].From)
+		case in.suspend:
+			open[in.pid] = len(suspensions)
+			suspensions = append(suspensions, scheduler.Suspension{PID: in.pid, From: in.t})
+		case !ok:
+			return nil, fmt.Errorf("%w on line %d: P%d is resumed at %d but not suspended", ErrInvalidEvent, in.line, in.pid, in.t)
+		case in.t == suspensions[i].From:
+			return nil, fmt.Errorf("%w on line %d: P%d is resumed at %d, when it is suspended", ErrInvalidEvent, in.line, in.pid, in.t)
+		default:
+			suspensions[i].To = in.t
+			delete(open, in.pid)
+		}
+	}
+	for _, s := range suspensions {
+		// Only suspensions still open end at 0, the others ending after
+		// they start.
+		if s.To == 0 {
+			return nil, fmt.Errorf("%w: P%d is suspended at %d and never resumed", ErrInvalidEvent, s.PID, s.From)
+		}
+	}
+	return suspensions, nil
+}
+
+// intervention is one line of interventions: a process suspended or resumed
+// at a time.
+type intervention struct {
+	line    int
+	t, pid  int64
+	suspend bool
+}
+
+// parseIntervention parses the fields of an intervention line, in any order.
+func parseIntervention(fields []string, parseTime func(string) (int64, error)) (intervention, error) {
+	var (
+		in           intervention
+		action       string
+		hasT, hasPID bool
+	)
+	for _, f := range fields {
+		key, value, ok := strings.Cut(f, "=")
+		var err error
+		switch {
+		case !ok && (f == "suspend" || f == "resume") && action == "":
+			action = f
+		case !ok:
+			return in, fmt.Errorf("unknown or repeated action %q, want suspend or resume", f)
+		case key == "t" && !hasT:
+			in.t, err = parseTime(value)
+			hasT = true
+		case key == "pid" && !hasPID:
+			in.pid, err = parseInt(value)
+			hasPID = true
+		default:
+			return in, fmt.Errorf("unknown or repeated field %q", key)
+		}
+		if err != nil {
+			return in, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	switch {
+	case action == "":
+		return in, errors.New("no action, want suspend or resume")
+	case !hasT || !hasPID:
+		return in, errors.New("want both a time t= and a process pid=")
+	case in.t < 0:
+		return in, fmt.Errorf("time must not be negative, got %d", in.t)
+	}
+	in.suspend = action == "suspend"
+	return in, nil
+}
@@ -0,0 +1,283 @@
+package input
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"p1/internal/scheduler"
+)
+
+func TestLoadProcesses(t *testing.T) {
+	got, err := LoadProcesses(strings.NewReader("1,5,0,2\n2,9,3,1\n3,6,6,3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []scheduler.Process{
+		{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 2},
+		{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Priority: 1},
+		{ProcessID: 3, BurstDuration: 6, ArrivalTime: 6, Priority: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestLoadProcessesWithoutPriority(t *testing.T) {
+	got, err := LoadProcesses(strings.NewReader("1,5,0\n2,9,3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[1].Priority != 0 || got[1].ArrivalTime != 3 {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestLoadProcessesInvalid(t *testing.T) {
+	for _, in := range []string{"1,5", "1,x,0", "1,5,0,p", "1,-5,0", "1,5,-1"} {
+		if _, err := LoadProcesses(strings.NewReader(in)); !errors.Is(err, ErrInvalidRow) {
+			t.Errorf("%q: err = %v, want %v", in, err, ErrInvalidRow)
+		}
+	}
+}
+
+func TestLoadProcessesHeader(t *testing.T) {
+	got, err := LoadProcesses(strings.NewReader("Arrival, ID ,burst,weight\n0,1,5,300\n3,2,9,100\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []scheduler.Process{
+		{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Weight: 300},
+		{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Weight: 100},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	for _, in := range []string{
+		"id,burst,arrival,nice\n1,5,0,-20\n2,9,3,19",
+		`[{"id":1,"burst":5,"arrival":0,"nice":-20},{"id":2,"burst":9,"arrival":3,"nice":19}]`,
+	} {
+		got, err := Load(strings.NewReader(in))
+		if err != nil {
+			t.Fatalf("%q: %v", in, err)
+		}
+		want := []scheduler.Process{
+			{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 0, Weight: 88761},
+			{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Priority: 39, Weight: 15},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %v, want %v", in, got, want)
+		}
+	}
+
+	got, err = LoadDurations(strings.NewReader("id,burst,arrival,estimate,deadline\n1,5ms,0s,2ms,8ms\n"), time.Millisecond)
+	if want := []scheduler.Process{{ProcessID: 1, BurstDuration: 5, Estimate: 2, Deadline: 8}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("estimates and deadlines: got %v, %v, want %v", got, err, want)
+	}
+
+	got, err = Load(strings.NewReader("group,id,burst,arrival\n web ,1,5,0\n,2,9,3\n"))
+	if want := []scheduler.Process{{ProcessID: 1, BurstDuration: 5, Group: "web"}, {ProcessID: 2, BurstDuration: 9, ArrivalTime: 3}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("groups: got %v, %v, want %v", got, err, want)
+	}
+
+	got, err = Load(strings.NewReader("id,burst,arrival,class\n1,5,0, Realtime\n2,9,3,\n3,1,4,batch\n"))
+	if want := []scheduler.Process{{ProcessID: 1, BurstDuration: 5, Class: scheduler.ClassRealtime}, {ProcessID: 2, BurstDuration: 9, ArrivalTime: 3}, {ProcessID: 3, BurstDuration: 1, ArrivalTime: 4, Class: scheduler.ClassBatch}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("classes: got %v, %v, want %v", got, err, want)
+	}
+
+	for _, in := range []string{
+		"id,burst,deadline\n1,5,0",
+		"id,burst,arrival,estimate\n1,5,0,-2",
+		"id,burst\n1,5",
+		"id,burst,arrival,burst\n1,5,0,5",
+		"id,burst,arrival,nice,priority\n1,5,0,0,1",
+		"id,burst,arrival,nice\n1,5,0,20",
+		"id,burst,arrival,weight\n1,5,0,-1",
+		"id,burst,arrival,weight\n1,4,0,5000000000000000000\n2,4,0,5000000000000000000",
+		"id,burst,arrival,class\n1,5,0,idle",
+		"id,burst,arrival,deadline\n1,5,0,-3",
+		`[{"id":1,"burst":5,"priority":2,"nice":0}]`,
+		`[{"id":1,"burst":5,"nice":-21}]`,
+	} {
+		if _, err := Load(strings.NewReader(in)); !errors.Is(err, ErrInvalidRow) {
+			t.Errorf("%q: err = %v, want %v", in, err, ErrInvalidRow)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	want := []scheduler.Process{
+		{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 2},
+		{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3},
+	}
+	for _, in := range []string{
+		"1,5,0,2\n2,9,3,0",
+		` [{"id":1,"burst":5,"arrival":0,"priority":2},{"id":2,"burst":9,"arrival":3}]`,
+	} {
+		got, err := Load(strings.NewReader(in))
+		if err != nil {
+			t.Fatalf("%q: %v", in, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %v, want %v", in, got, want)
+		}
+	}
+
+	if _, err := Load(strings.NewReader(`[{"id":"one"}]`)); err == nil {
+		t.Error("expected an error for a malformed JSON workload")
+	}
+	if _, err := Load(strings.NewReader(`[{"id":1,"burst":-5}]`)); !errors.Is(err, ErrInvalidRow) {
+		t.Errorf("negative burst: err = %v, want %v", err, ErrInvalidRow)
+	}
+}
+
+func TestLoadDurations(t *testing.T) {
+	got, err := LoadDurations(strings.NewReader("1,5ms,0s,2\n2,1.5s,3ms,1"), time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []scheduler.Process{
+		{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 2},
+		{ProcessID: 2, BurstDuration: 1500, ArrivalTime: 3, Priority: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := LoadDurations(strings.NewReader("1,5,0"), time.Millisecond); !errors.Is(err, ErrInvalidRow) {
+		t.Errorf("bare number: err = %v, want %v", err, ErrInvalidRow)
+	}
+	if _, err := LoadDurations(strings.NewReader("1,5us,0s"), time.Millisecond); !errors.Is(err, ErrInvalidTick) {
+		t.Errorf("partial tick: err = %v, want %v", err, ErrInvalidTick)
+	}
+	if _, err := LoadDurations(strings.NewReader("1,5ms,0s"), 0); !errors.Is(err, ErrInvalidTick) {
+		t.Errorf("zero tick: err = %v, want %v", err, ErrInvalidTick)
+	}
+}
+
+func TestFromDurations(t *testing.T) {
+	got, err := FromDurations([]DurationProcess{
+		{ProcessID: 1, ArrivalTime: 20 * time.Millisecond, BurstDuration: 100 * time.Millisecond, Priority: 3},
+	}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []scheduler.Process{{ProcessID: 1, ArrivalTime: 2, BurstDuration: 10, Priority: 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	processes := []scheduler.Process{
+		{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 2},
+		{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Priority: 1},
+	}
+	var buf strings.Builder
+	if err := WriteCSV(&buf, processes); err != nil {
+		t.Fatal(err)
+	}
+	if want := "1,5,0,2\n2,9,3,1\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	processes[1].Weight = 1024
+	processes[0].Estimate = 3
+	processes[1].Group = "batch"
+	processes[1].Class = scheduler.ClassBatch
+	processes[0].Deadline = 12
+	buf.Reset()
+	if err := WriteCSV(&buf, processes); err != nil {
+		t.Fatal(err)
+	}
+	if want := "id,burst,arrival,priority,weight,estimate,group,class,deadline\n1,5,0,2,0,3,,,12\n2,9,3,1,1024,0,batch,batch,0\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	if got, err := LoadProcesses(strings.NewReader(buf.String())); err != nil || !reflect.DeepEqual(got, processes) {
+		t.Errorf("read back %v, %v, want %v", got, err, processes)
+	}
+}
+
+func TestLoadNumbers(t *testing.T) {
+	got, err := LoadNumbers(strings.NewReader("98, 183,37\n122 14,,124\n\t65 67\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{98, 183, 37, 122, 14, 124, 65, 67}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for _, in := range []string{"1, x", "1 -2"} {
+		if _, err := LoadNumbers(strings.NewReader(in)); !errors.Is(err, ErrInvalidNumber) {
+			t.Errorf("%q: err = %v, want %v", in, err, ErrInvalidNumber)
+		}
+	}
+}
+
+func TestLoadInterventions(t *testing.T) {
+	got, err := LoadInterventions(strings.NewReader("# operator\nt=30 suspend pid=2\n\nt=10 suspend pid=1\nresume pid=2 t=45\nt=12 resume pid=1\nt=50 suspend pid=2\nt=60 resume pid=2\n"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []scheduler.Suspension{{PID: 1, From: 10, To: 12}, {PID: 2, From: 30, To: 45}, {PID: 2, From: 50, To: 60}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	got, err = LoadInterventions(strings.NewReader("t=30ms suspend pid=2\nt=1s resume pid=2"), time.Millisecond)
+	if want := []scheduler.Suspension{{PID: 2, From: 30, To: 1000}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("durations: got %v, %v, want %v", got, err, want)
+	}
+
+	for _, in := range []string{
+		"t=30 stop pid=2",
+		"t=30 pid=2",
+		"t=30 suspend",
+		"t=x suspend pid=2",
+		"t=-1 suspend pid=2\nt=3 resume pid=2",
+		"t=30 suspend pid=2 cpu=1",
+		"t=30 suspend pid=2",
+		"t=30 resume pid=2",
+		"t=30 suspend pid=2\nt=40 suspend pid=2\nt=50 resume pid=2",
+		"t=30 suspend pid=2\nt=30 resume pid=2",
+	} {
+		if _, err := LoadInterventions(strings.NewReader(in), 0); !errors.Is(err, ErrInvalidEvent) {
+			t.Errorf("%q: err = %v, want %v", in, err, ErrInvalidEvent)
+		}
+	}
+}
+
+func TestLoadTrace(t *testing.T) {
+	got, err := LoadTrace(strings.NewReader("algorithm,pid,start,stop,cpu\nfcfs,1,0,5,0\nrr,2,0,3,1\nfcfs,2,5,14,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Trace{
+		{"fcfs", []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 14, CPU: 1}}},
+		{"rr", []scheduler.TimeSlice{{PID: 2, Start: 0, Stop: 3, CPU: 1}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	got, err = LoadTraceDurations(strings.NewReader("Task,Job,Begin,End\n3,1,10ms,25ms\n"), 5*time.Millisecond)
+	if want := []Trace{{Gantt: []scheduler.TimeSlice{{PID: 3, Start: 2, Stop: 5}}}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("durations: got %+v, %v, want %+v", got, err, want)
+	}
+
+	for _, in := range []string{
+		"",
+		"pid,start,stop\n",
+		"pid,start\n1,0",
+		"pid,id,start,stop\n1,1,0,2",
+		"pid,start,stop\nx,0,2",
+		"pid,start,stop\n1,2,2",
+		"pid,start,stop\n1,-1,2",
+		"pid,start,stop,cpu\n1,0,2,-1",
+	} {
+		if _, err := LoadTrace(strings.NewReader(in)); !errors.Is(err, ErrInvalidRow) {
+			t.Errorf("%q: err = %v, want %v", in, err, ErrInvalidRow)
+		}
+	}
+}
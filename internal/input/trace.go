@@ -0,0 +1,134 @@
+package input
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"p1/internal/scheduler"
+)
+
+// Trace is a schedule of a workload produced by another simulator, as the
+// slices of its Gantt chart.
+type Trace struct {
+	// Algorithm is the name the trace gives the algorithm it is of, if any.
+	Algorithm string
+	Gantt     []scheduler.TimeSlice
+}
+
+// traceColumns are the columns of a trace, by the names headers may give
+// them. Simulators such as SimSo speak of tasks and processors rather than
+// processes and CPUs.
+var traceColumns = map[string]string{
+	"pid": "pid", "id": "pid", "process": "pid", "task": "pid",
+	"start": "start", "begin": "start",
+	"stop": "stop", "end": "stop",
+	"cpu": "cpu", "core": "cpu", "processor": "cpu",
+	"algorithm": "algorithm", "scheduler": "algorithm",
+}
+
+// LoadTrace reads the schedules of another simulator from CSV rows of
+// slices, each of a process running from a start to a stop time on a CPU. A
+// header row names the columns, in any order: pid, or id, process or task,
+// start or begin, stop or end, and optionally cpu, core or processor, the
+// CPUs numbered from 0, and algorithm or scheduler. Other columns are left
+// out, and slices without a CPU ran on CPU 0. The rows of each algorithm
+// make up a trace of their own, in the order the algorithms first appear,
+// so the CSV that schedsim -format csv writes reads back.
+func LoadTrace(r io.Reader) ([]Trace, error) {
+	return loadTrace(r, parseInt)
+}
+
+// LoadTraceDurations reads traces like LoadTrace, except that times are
+// durations such as 250ms or 1.5s. They are converted to whole ticks of the
+// given length.
+func LoadTraceDurations(r io.Reader, tick time.Duration) ([]Trace, error) {
+	if tick <= 0 {
+		return nil, fmt.Errorf("%w: must be positive, got %v", ErrInvalidTick, tick)
+	}
+	return loadTrace(r, func(s string) (int64, error) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, err
+		}
+		return toTicks(d, tick)
+	})
+}
+
+func loadTrace(r io.Reader, parseTime func(string) (int64, error)) ([]Trace, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w 1: no header", ErrInvalidRow)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading CSV", err)
+	}
+	columns := make(map[string]int)
+	for j, field := range header {
+		name, ok := traceColumns[columnName(field)]
+		if !ok {
+			continue
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w 1: %s column given twice", ErrInvalidRow, name)
+		}
+		columns[name] = j
+	}
+	for _, name := range []string{"pid", "start", "stop"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w 1: no %s column", ErrInvalidRow, name)
+		}
+	}
+
+	var traces []Trace
+	for i := 2; ; i++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading CSV", err)
+		}
+		field := func(name string) string {
+			if j, ok := columns[name]; ok && j < len(row) {
+				return strings.TrimSpace(row[j])
+			}
+			return ""
+		}
+		var s scheduler.TimeSlice
+		if s.PID, err = parseInt(field("pid")); err != nil {
+			return nil, fmt.Errorf("%w %d: pid: %w", ErrInvalidRow, i, err)
+		}
+		if s.Start, err = parseTime(field("start")); err != nil {
+			return nil, fmt.Errorf("%w %d: start: %w", ErrInvalidRow, i, err)
+		}
+		if s.Stop, err = parseTime(field("stop")); err != nil {
+			return nil, fmt.Errorf("%w %d: stop: %w", ErrInvalidRow, i, err)
+		}
+		if cpu := field("cpu"); cpu != "" {
+			if s.CPU, err = strconv.Atoi(cpu); err != nil || s.CPU < 0 {
+				return nil, fmt.Errorf("%w %d: cpu must be a number from 0, got %q", ErrInvalidRow, i, cpu)
+			}
+		}
+		if s.Start < 0 || s.Stop <= s.Start {
+			return nil, fmt.Errorf("%w %d: slice must start at 0 or later and stop after it starts, got %d to %d", ErrInvalidRow, i, s.Start, s.Stop)
+		}
+		name := field("algorithm")
+		k := slices.IndexFunc(traces, func(t Trace) bool { return t.Algorithm == name })
+		if k < 0 {
+			traces = append(traces, Trace{Algorithm: name})
+			k = len(traces) - 1
+		}
+		traces[k].Gantt = append(traces[k].Gantt, s)
+	}
+	if traces == nil {
+		return nil, fmt.Errorf("%w: trace has no slices", ErrInvalidRow)
+	}
+	return traces, nil
+}
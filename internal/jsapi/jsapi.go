@@ -0,0 +1,53 @@
+// Package jsapi implements the simulate call exposed to JavaScript by the
+// WebAssembly build, independently of syscall/js so it can be tested natively.
+package jsapi
+
+import (
+	"bytes"
+
+	"p1/internal/config"
+	"p1/internal/input"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// Simulate runs the algorithms chosen by options, a JSON document in the
+// format of package config, over a workload given as CSV rows or a JSON
+// array, and returns the JSON results.
+func Simulate(workload, options []byte) ([]byte, error) {
+	cfg := config.Default()
+	if len(bytes.TrimSpace(options)) > 0 {
+		var err error
+		if cfg, err = config.Load(bytes.NewReader(options)); err != nil {
+			return nil, err
+		}
+	}
+	sim, err := scheduler.NewSimulator(scheduler.WithOptions(cfg.Options))
+	if err != nil {
+		return nil, err
+	}
+
+	processes, err := input.Load(bytes.NewReader(workload))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]render.Named, len(cfg.Algorithms))
+	for i, name := range cfg.Algorithms {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = render.Named{
+			Algorithm: a.Name,
+			Title:     a.Title,
+			Result:    sim.Schedule(a, processes),
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := render.JSON(&buf, results, render.Options{}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,56 @@
+package jsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"p1/internal/config"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+func decode(t *testing.T, out []byte) []render.Named {
+	t.Helper()
+	var doc struct{ Results []render.Named }
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc.Results
+}
+
+func TestSimulateCSV(t *testing.T) {
+	out, err := Simulate([]byte("1,5,0,2\n2,9,3,1\n3,6,6,3"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := decode(t, out)
+	if len(results) != len(config.Default().Algorithms) {
+		t.Fatalf("got %d results, want one per default algorithm", len(results))
+	}
+	if results[0].Algorithm != "fcfs" || results[0].Result.AveWait != 10.0/3 {
+		t.Errorf("first result = %+v", results[0])
+	}
+}
+
+func TestSimulateJSONOptions(t *testing.T) {
+	workload := `[{"id":1,"burst":4,"arrival":0},{"id":2,"burst":4,"arrival":0}]`
+	out, err := Simulate([]byte(workload), []byte(`{"algorithms":["rr"],"rr":{"quantum":2}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := decode(t, out)
+	if len(results) != 1 || results[0].Algorithm != "rr" {
+		t.Fatalf("results = %+v", results)
+	}
+	if got := len(results[0].Result.Gantt); got != 4 {
+		t.Errorf("got %d slices, want 4 with a quantum of 2", got)
+	}
+}
+
+func TestSimulateUnknownAlgorithm(t *testing.T) {
+	_, err := Simulate([]byte("1,5,0"), []byte(`{"algorithms":["edf"]}`))
+	if !errors.Is(err, scheduler.ErrUnknownAlgorithm) {
+		t.Errorf("err = %v, want %v", err, scheduler.ErrUnknownAlgorithm)
+	}
+}
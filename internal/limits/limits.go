@@ -0,0 +1,67 @@
+// Package limits bounds the runs the network front ends schedule for their
+// clients, so that no one request can take up a server's memory or hold it
+// for long. The HTTP and gRPC servers both check every run against them.
+package limits
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"p1/internal/scheduler"
+)
+
+// The most CPUs and MLFQ levels the options of a run may have and processes
+// its workload may, and how long its algorithms may take between them.
+const (
+	MaxCPUs      = 256
+	MaxLevels    = 64
+	MaxProcesses = 100000
+	Timeout      = 30 * time.Second
+)
+
+// ErrTooLarge is returned for runs past the limits.
+var ErrTooLarge = errors.New("too large")
+
+// CheckOptions fails with ErrTooLarge if opts has more than MaxCPUs CPUs or
+// MaxLevels MLFQ levels.
+func CheckOptions(opts scheduler.Options) error {
+	switch {
+	case opts.CPUs > MaxCPUs:
+		return fmt.Errorf("%w: at most %d CPUs, got %d", ErrTooLarge, MaxCPUs, opts.CPUs)
+	case opts.MLFQ.Levels > MaxLevels:
+		return fmt.Errorf("%w: at most %d mlfq levels, got %d", ErrTooLarge, MaxLevels, opts.MLFQ.Levels)
+	}
+	return nil
+}
+
+// CheckSize fails with ErrTooLarge if there are more than MaxProcesses
+// processes.
+func CheckSize(processes []scheduler.Process) error {
+	if len(processes) > MaxProcesses {
+		return fmt.Errorf("%w: at most %d processes, got %d", ErrTooLarge, MaxProcesses, len(processes))
+	}
+	return nil
+}
+
+// Check fails if processes cannot be run under opts for a client: if either
+// is past the limits, if a process is invalid, see Process.Validate, if
+// their times could overflow, see scheduler.Horizon, or if they are out of
+// order for a strict FCFS.
+func Check(opts scheduler.Options, processes []scheduler.Process) error {
+	if err := CheckOptions(opts); err != nil {
+		return err
+	}
+	if err := CheckSize(processes); err != nil {
+		return err
+	}
+	for _, p := range processes {
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("process %d: %w", p.ProcessID, err)
+		}
+	}
+	if _, err := scheduler.Horizon(processes, opts); err != nil {
+		return err
+	}
+	return opts.FCFS.Check(processes)
+}
@@ -0,0 +1,36 @@
+package limits
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"p1/internal/scheduler"
+)
+
+func TestCheck(t *testing.T) {
+	opts := scheduler.DefaultOptions()
+	processes := []scheduler.Process{{ProcessID: 1, BurstDuration: 5}, {ProcessID: 2, BurstDuration: 3, ArrivalTime: 1}}
+	if err := Check(opts, processes); err != nil {
+		t.Fatal(err)
+	}
+
+	many := opts
+	many.CPUs = MaxCPUs + 1
+	deep := opts
+	deep.MLFQ = scheduler.MLFQParams{Levels: MaxLevels + 1, Quanta: make([]int64, MaxLevels+1)}
+	for _, o := range []scheduler.Options{many, deep} {
+		if err := Check(o, processes); !errors.Is(err, ErrTooLarge) {
+			t.Errorf("err = %v, want %v", err, ErrTooLarge)
+		}
+	}
+	if err := Check(opts, make([]scheduler.Process, MaxProcesses+1)); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("err = %v, want %v", err, ErrTooLarge)
+	}
+	if err := Check(opts, []scheduler.Process{{ProcessID: 1, BurstDuration: -1}}); !errors.Is(err, scheduler.ErrInvalidProcess) {
+		t.Errorf("err = %v, want %v", err, scheduler.ErrInvalidProcess)
+	}
+	if err := Check(opts, []scheduler.Process{{BurstDuration: math.MaxInt64}, {BurstDuration: 1}}); !errors.Is(err, scheduler.ErrOverflow) {
+		t.Errorf("err = %v, want %v", err, scheduler.ErrOverflow)
+	}
+}
@@ -0,0 +1,85 @@
+// Package metrics records Prometheus metrics about the simulations run by the
+// schedsim servers. A nil *Metrics records nothing, so servers can run
+// without monitoring.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the collectors of one server.
+type Metrics struct {
+	registry     *prometheus.Registry
+	simulations  *prometheus.CounterVec
+	runtime      *prometheus.HistogramVec
+	workloadSize prometheus.Histogram
+	errors       *prometheus.CounterVec
+}
+
+// New returns Metrics registered with a fresh registry, along with the
+// standard Go and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		simulations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "schedsim_simulations_total",
+			Help: "Simulations run, by algorithm.",
+		}, []string{"algorithm"}),
+		runtime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "schedsim_simulation_duration_seconds",
+			Help:    "Time taken to run a simulation, by algorithm.",
+			Buckets: prometheus.ExponentialBuckets(1e-5, 4, 12),
+		}, []string{"algorithm"}),
+		workloadSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "schedsim_workload_processes",
+			Help:    "Number of processes in each workload uploaded or simulated.",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 12),
+		}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "schedsim_errors_total",
+			Help: "Requests that failed, by API and reason.",
+		}, []string{"api", "reason"}),
+	}
+	m.registry.MustRegister(
+		m.simulations, m.runtime, m.workloadSize, m.errors,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Simulation records a run of algorithm that took elapsed.
+func (m *Metrics) Simulation(algorithm string, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.simulations.WithLabelValues(algorithm).Inc()
+	m.runtime.WithLabelValues(algorithm).Observe(elapsed.Seconds())
+}
+
+// Workload records the size of a workload.
+func (m *Metrics) Workload(processes int) {
+	if m == nil {
+		return
+	}
+	m.workloadSize.Observe(float64(processes))
+}
+
+// Error records a failed request to api, such as "http" or "grpc", for a
+// reason such as "bad_request".
+func (m *Metrics) Error(api, reason string) {
+	if m == nil {
+		return
+	}
+	m.errors.WithLabelValues(api, reason).Inc()
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New()
+	m.Simulation("rr", time.Millisecond)
+	m.Simulation("rr", 2*time.Millisecond)
+	m.Workload(3)
+	m.Error("http", "not_found")
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+	for _, want := range []string{
+		`schedsim_simulations_total{algorithm="rr"} 2`,
+		`schedsim_simulation_duration_seconds_count{algorithm="rr"} 2`,
+		`schedsim_workload_processes_sum 3`,
+		`schedsim_errors_total{api="http",reason="not_found"} 1`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q", want)
+		}
+	}
+}
+
+func TestNilMetrics(t *testing.T) {
+	var m *Metrics
+	m.Simulation("rr", time.Millisecond)
+	m.Workload(1)
+	m.Error("grpc", "invalid_argument")
+}
@@ -0,0 +1,632 @@
+// Package oracle schedules workloads the slow way, one tick at a time,
+// choosing among the ready processes by scanning all of them. It shares no
+// code with the event-driven engine of package scheduler, so the two can be
+// checked against each other: they must agree on every result.
+package oracle
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"p1/internal/scheduler"
+)
+
+var (
+	// ErrTooLong is returned for workloads that take more than MaxTicks to
+	// schedule.
+	ErrTooLong = errors.New("workload too long for the oracle")
+	// ErrMismatch is returned by front ends when a result differs from the
+	// oracle's.
+	ErrMismatch = errors.New("result differs from the oracle")
+)
+
+// MaxTicks is the most ticks Schedule simulates.
+const MaxTicks = 10_000_000
+
+// job is the state of one process.
+type job struct {
+	scheduler.Process
+	index     int
+	remaining int64
+	// ready is when the job last became ready and waited how long it had
+	// been ready before then.
+	ready, waited int64
+	// level is the MLFQ level of the job and dispatched its remaining time
+	// when it last got a CPU, running whether it has run since.
+	level      int
+	dispatched int64
+	running    bool
+	// charged is what the job's fair-share group was charged for it when
+	// it last got a CPU.
+	charged int64
+	// suspended is set while a suspension of the job is in force, and held
+	// while it is out of contention for it.
+	suspended, held, done bool
+	// boosted is set under the resume boost from the job's resumption
+	// until it next leaves a CPU.
+	boosted bool
+	// saved is how many ticks fewer the job ran for than the burst it ran.
+	saved int64
+}
+
+type cpu struct {
+	job, last *job
+	// resume is when job starts running, after any context switch, and
+	// expires when its quantum runs out, or -1 if it has none.
+	resume, expires int64
+	// open is the index of the slice job is running in, or -1 after a
+	// switch.
+	open int
+}
+
+// oracle is one run of an algorithm.
+type oracle struct {
+	opts scheduler.Options
+	t    int64
+	// queues holds the ready jobs of the algorithm, or under scheduling by
+	// class those of each class, the most important first.
+	queues  []*queue
+	requeue []*job
+	cpus    []cpu
+	// order holds the CPUs in the order they are given jobs, the fastest
+	// first.
+	order []int
+	res   scheduler.Result
+}
+
+// queue is the ready jobs of one algorithm and what it keeps track of.
+type queue struct {
+	algorithm string
+	ready     []*job // in the order they were made ready
+	next      int    // the next job to dispatch in given order
+	// rand is what randomized algorithms draw from, as the engine's
+	// policies do: only to dispatch, and only with jobs ready.
+	rand scheduler.Rand
+	// used holds the CPU time each fair-share group has been charged for,
+	// and floor is the group last dispatched, with what it had used then.
+	used      map[string]int64
+	floor     string
+	floorUsed int64
+}
+
+// change is a job being suspended or resumed at a time.
+type change struct {
+	t       int64
+	job     *job
+	suspend bool
+}
+
+// Schedule runs the named algorithm over processes with opts.
+func Schedule(algorithm string, processes []scheduler.Process, opts scheduler.Options) (scheduler.Result, error) {
+	if _, err := scheduler.LookupAlgorithm(algorithm); err != nil {
+		return scheduler.Result{}, err
+	}
+	if err := opts.Validate(); err != nil {
+		return scheduler.Result{}, err
+	}
+	if h, err := scheduler.Horizon(processes, opts); err != nil || h > MaxTicks {
+		return scheduler.Result{}, fmt.Errorf("%w: it could take over %d ticks", ErrTooLong, MaxTicks)
+	}
+
+	o := &oracle{opts: opts, cpus: make([]cpu, opts.CPUs)}
+	algorithms := []string{algorithm}
+	if algorithm == "classes" {
+		algorithms = []string{
+			opts.Classes.Algorithm(scheduler.ClassRealtime),
+			opts.Classes.Algorithm(scheduler.ClassInteractive),
+			opts.Classes.Algorithm(scheduler.ClassBatch),
+		}
+	}
+	for _, a := range algorithms {
+		o.queues = append(o.queues, &queue{algorithm: a, rand: scheduler.NewRand(opts.Seed), used: make(map[string]int64)})
+	}
+	for c := range o.cpus {
+		o.cpus[c].open = -1
+		o.order = append(o.order, c)
+	}
+	slices.SortStableFunc(o.order, func(a, b int) int { return cmp.Compare(opts.Speed(b), opts.Speed(a)) })
+	jobs := make([]*job, len(processes))
+	for i, p := range processes {
+		jobs[i] = &job{Process: p, index: i, remaining: p.BurstDuration}
+	}
+	// Jobs arriving together arrive in the order they were given.
+	arrivals := slices.Clone(jobs)
+	slices.SortStableFunc(arrivals, func(a, b *job) int { return cmp.Compare(a.ArrivalTime, b.ArrivalTime) })
+	if len(arrivals) > 0 {
+		o.t = min(0, arrivals[0].ArrivalTime)
+	}
+	o.res.Stats = make([]scheduler.Stat, len(jobs))
+	// Changes due together are made in the order of their suspensions.
+	var changes []change
+	for _, s := range opts.Suspensions {
+		for _, j := range jobs {
+			if j.ProcessID == s.PID {
+				changes = append(changes, change{s.From, j, true}, change{s.To, j, false})
+			}
+		}
+	}
+	slices.SortStableFunc(changes, func(a, b change) int { return cmp.Compare(a.t, b.t) })
+
+	done := 0
+	for done < len(jobs) {
+		// Decide at o.t until only running jobs can change anything.
+		for {
+			for len(arrivals) > 0 && arrivals[0].ArrivalTime <= o.t {
+				if j := arrivals[0]; j.suspended {
+					j.held = true
+				} else {
+					o.push(j)
+				}
+				arrivals = arrivals[1:]
+			}
+			for len(changes) > 0 && changes[0].t <= o.t {
+				if changes[0].suspend {
+					o.suspend(changes[0].job)
+				} else {
+					o.resume(changes[0].job)
+				}
+				changes = changes[1:]
+			}
+			for _, j := range o.requeue {
+				o.push(j)
+			}
+			o.requeue = o.requeue[:0]
+			o.preempt()
+			o.dispatch()
+
+			finished := false
+			for c := range o.cpus {
+				if j := o.cpus[c].job; j != nil && j.remaining == 0 && o.t >= o.cpus[c].resume {
+					o.finish(c)
+					done++
+					finished = true
+				}
+			}
+			if !finished {
+				break
+			}
+		}
+		if done == len(jobs) {
+			break
+		}
+
+		for c := range o.cpus {
+			if j := o.cpus[c].job; j != nil && o.t >= o.cpus[c].resume {
+				o.run(c)
+			}
+		}
+		o.t++
+		if o.t > MaxTicks {
+			return scheduler.Result{}, fmt.Errorf("%w: still running at tick %d", ErrTooLong, o.t)
+		}
+
+		for c := range o.cpus {
+			j := o.cpus[c].job
+			switch {
+			case j == nil:
+			case j.remaining == 0 && o.t >= o.cpus[c].resume:
+				o.finish(c)
+				done++
+			case o.cpus[c].expires >= 0 && o.t >= o.cpus[c].expires:
+				o.cpus[c].job, j.boosted = nil, false
+				o.requeue = append(o.requeue, j)
+			}
+		}
+	}
+
+	if opts.NoGantt {
+		o.res.Gantt = nil
+	}
+	summarize(&o.res)
+	return o.res, nil
+}
+
+// queue returns the queue j is made ready in. Jobs of no class, or of one
+// not known, are interactive.
+func (o *oracle) queue(j *job) *queue {
+	if len(o.queues) == 1 {
+		return o.queues[0]
+	}
+	switch j.Class {
+	case scheduler.ClassRealtime:
+		return o.queues[0]
+	case scheduler.ClassBatch:
+		return o.queues[2]
+	}
+	return o.queues[1]
+}
+
+// push makes j ready at the current time.
+func (o *oracle) push(j *job) {
+	q := o.queue(j)
+	if q.algorithm == "mlfq" && j.running && j.dispatched-j.remaining >= o.opts.MLFQ.Quanta[j.level] {
+		j.level = min(j.level+1, o.opts.MLFQ.Levels-1)
+	}
+	if j.boosted {
+		j.level = 0
+	}
+	if q.algorithm == "fairshare" {
+		if j.running {
+			q.used[j.Group] += j.dispatched - j.remaining - j.charged
+		}
+		// A group is idle while none of its jobs is ready, and catches up
+		// with the floor, rounded up, as one becomes ready.
+		if !slices.ContainsFunc(q.ready, func(r *job) bool { return r.Group == j.Group }) {
+			share, floorShare := o.opts.FairShare.Share(j.Group), o.opts.FairShare.Share(q.floor)
+			if q.used[j.Group]*floorShare < q.floorUsed*share {
+				q.used[j.Group] = (q.floorUsed*share + floorShare - 1) / floorShare
+			}
+		}
+	}
+	j.running = false
+	j.ready = o.t
+	q.ready = append(q.ready, j)
+}
+
+// suspend takes j out of contention, or marks it to be once it arrives.
+func (o *oracle) suspend(j *job) {
+	if j.suspended || j.done {
+		return
+	}
+	j.suspended = true
+	if j.ArrivalTime > o.t {
+		return
+	}
+	j.held = true
+	for c := range o.cpus {
+		if o.cpus[c].job == j {
+			o.cpus[c].job, j.boosted = nil, false
+			return
+		}
+	}
+	if i := slices.Index(o.requeue, j); i >= 0 {
+		o.requeue = slices.Delete(o.requeue, i, i+1)
+		return
+	}
+	q := o.queue(j)
+	q.take(slices.Index(q.ready, j))
+	j.waited += o.t - j.ready
+}
+
+// resume puts j back in contention.
+func (o *oracle) resume(j *job) {
+	if !j.suspended {
+		return
+	}
+	j.suspended = false
+	if j.held {
+		j.held = false
+		j.boosted = o.opts.ResumeBoost
+		o.push(j)
+	}
+}
+
+// draw returns the index in q.ready of the job a randomized algorithm runs
+// next, or -1 if none is ready.
+func (q *queue) draw() int {
+	if len(q.ready) == 0 {
+		return -1
+	}
+	if q.algorithm == "random" {
+		return int(q.rand.Int64N(int64(len(q.ready))))
+	}
+	var total int64
+	for _, j := range q.ready {
+		total += j.Share()
+	}
+	n := q.rand.Int64N(total)
+	for i, j := range q.ready {
+		if n -= j.Share(); n < 0 {
+			return i
+		}
+	}
+	panic("oracle: draw past the last ticket")
+}
+
+// best returns the index in q.ready of the job to run next, or -1 if none
+// may run yet.
+func (o *oracle) best(q *queue) int {
+	best := -1
+	for i, j := range q.ready {
+		if best < 0 || o.before(q, j, q.ready[best]) {
+			best = i
+		}
+	}
+	if best >= 0 && q.algorithm == "fcfs" && o.opts.FCFS.Order == scheduler.OrderGiven && q.ready[best].index > q.next {
+		return -1
+	}
+	return best
+}
+
+// before reports whether job a, ready in q, runs before job b, ready in q
+// too. Jobs neither of which runs before the other run in the order they
+// were made ready.
+func (o *oracle) before(q *queue, a, b *job) bool {
+	if q.favours() && a.boosted != b.boosted {
+		return a.boosted
+	}
+	switch q.algorithm {
+	case "fcfs":
+		if o.opts.FCFS.Order == scheduler.OrderGiven {
+			return a.index < b.index
+		}
+		return cmp.Or(cmp.Compare(a.ArrivalTime, b.ArrivalTime), cmp.Compare(a.ProcessID, b.ProcessID), cmp.Compare(a.index, b.index)) < 0
+	case "sjf":
+		if o.agesAll() {
+			if g := o.gap(a.expected()-b.expected(), o.waited(a)-o.waited(b)); g != 0 {
+				return g < 0
+			}
+		}
+		return cmp.Or(cmp.Compare(a.expected(), b.expected()), cmp.Compare(a.index, b.index)) < 0
+	case "priority":
+		if o.agesAll() {
+			return o.agedBefore(a, b)
+		}
+		return morePressing(a, b)
+	case "mlfq":
+		return a.level < b.level
+	case "aging":
+		return o.agedBefore(a, b)
+	case "fairshare":
+		if a.Group == b.Group {
+			return false
+		}
+		ua, ub := q.used[a.Group]*o.opts.FairShare.Share(b.Group), q.used[b.Group]*o.opts.FairShare.Share(a.Group)
+		return ua < ub || ua == ub && a.Group < b.Group
+	}
+	return false
+}
+
+// preempts reports whether job j, ready in q, takes the CPU from running job
+// r of q.
+func (o *oracle) preempts(q *queue, j, r *job) bool {
+	if q.favours() && j.boosted != r.boosted {
+		return j.boosted
+	}
+	switch q.algorithm {
+	case "sjf":
+		if o.agesAll() {
+			return o.gap(j.expected()-r.expected(), o.waited(j)-r.waited) < 0
+		}
+		return j.expected() < r.expected()
+	case "priority":
+		if o.agesAll() {
+			return o.gap(j.Priority-r.Priority, o.waited(j)-r.waited) < 0
+		}
+		return cmp.Or(cmp.Compare(j.Priority, r.Priority), cmp.Compare(j.ExpectedBurst(), r.ExpectedBurst())) < 0
+	case "mlfq":
+		return j.level < r.level
+	case "aging":
+		// A running job has stopped aging.
+		return o.gap(j.Priority-r.Priority, o.waited(j)-r.waited) < 0
+	}
+	return false
+}
+
+// favours reports whether boosted jobs go ahead of every other, rather than
+// to the top MLFQ level.
+func (q *queue) favours() bool {
+	return q.algorithm == "sjf" || q.algorithm == "priority" || q.algorithm == "aging"
+}
+
+func morePressing(a, b *job) bool {
+	return cmp.Or(cmp.Compare(a.Priority, b.Priority), cmp.Compare(a.ExpectedBurst(), b.ExpectedBurst()), cmp.Compare(a.index, b.index)) < 0
+}
+
+// expected returns the time j is expected to still need, going by its
+// estimate if it has one.
+func (j *job) expected() int64 {
+	return max(0, j.ExpectedBurst()-(j.BurstDuration-j.remaining))
+}
+
+// agesAll reports whether priority and sjf age as aging does.
+func (o *oracle) agesAll() bool { return o.opts.Aging.All && o.opts.Aging.Rate > 0 }
+
+// agedBefore orders ready jobs by their aged priority values.
+func (o *oracle) agedBefore(a, b *job) bool {
+	if g := o.gap(a.Priority-b.Priority, o.waited(a)-o.waited(b)); g != 0 {
+		return g < 0
+	}
+	return morePressing(a, b)
+}
+
+// waited returns how long ready job j has been ready in all.
+func (o *oracle) waited(j *job) int64 { return j.waited + o.t - j.ready }
+
+// gap returns how much higher the effective priority value of a job is than
+// another's, if its value is dp higher and it has waited dw longer.
+func (o *oracle) gap(dp, dw int64) float64 {
+	return float64(dp) - o.opts.Aging.Rate*float64(dw)
+}
+
+// take removes q.ready[i]. A lottery moves its last job to the place, as
+// the engine does.
+func (q *queue) take(i int) {
+	if q.algorithm == "lottery" {
+		last := len(q.ready) - 1
+		q.ready[i] = q.ready[last]
+		q.ready = q.ready[:last]
+		return
+	}
+	q.ready = slices.Delete(q.ready, i, i+1)
+}
+
+func (q *queue) randomized() bool { return q.algorithm == "lottery" || q.algorithm == "random" }
+
+func (o *oracle) quantum(j *job) int64 {
+	switch o.queue(j).algorithm {
+	case "rr", "lottery", "fairshare":
+		return o.opts.RR.Quantum
+	case "mlfq":
+		return o.opts.MLFQ.Quanta[j.level]
+	}
+	return 0
+}
+
+// preempt puts back every running job a ready one takes the CPU from, once
+// it has run past the switch to it: any job ready in a queue above the
+// running job's, or one its own queue runs first.
+func (o *oracle) preempt() {
+	for c := range o.cpus {
+		r := o.cpus[c].job
+		if r == nil || o.t <= o.cpus[c].resume {
+			continue
+		}
+		q := o.queue(r)
+		preempted := slices.ContainsFunc(o.queues[:slices.Index(o.queues, q)], func(a *queue) bool { return len(a.ready) > 0 })
+		// Randomized algorithms never preempt, and must not draw to find
+		// out.
+		if !preempted && !q.randomized() {
+			i := o.best(q)
+			preempted = i >= 0 && o.preempts(q, q.ready[i], r)
+		}
+		if preempted {
+			o.cpus[c].job, r.boosted = nil, false
+			o.push(r)
+		}
+	}
+}
+
+// dispatch gives every idle CPU the best ready job, the fastest first.
+func (o *oracle) dispatch() {
+	for _, c := range o.order {
+		if o.cpus[c].job != nil {
+			continue
+		}
+		// The first queue with a job to run runs it.
+		var q *queue
+		i := -1
+		for _, q = range o.queues {
+			if q.randomized() {
+				i = q.draw()
+			} else {
+				i = o.best(q)
+			}
+			if i >= 0 {
+				break
+			}
+		}
+		if i < 0 {
+			continue
+		}
+		j := q.ready[i]
+		q.take(i)
+		if j.index == q.next {
+			q.next++
+		}
+		j.waited += o.t - j.ready
+		j.dispatched = j.remaining
+		j.running = true
+		if q.algorithm == "fairshare" {
+			if q.floorUsed*o.opts.FairShare.Share(j.Group) < q.used[j.Group]*o.opts.FairShare.Share(q.floor) {
+				q.floor, q.floorUsed = j.Group, q.used[j.Group]
+			}
+			j.charged = min(o.quantum(j), j.remaining)
+			q.used[j.Group] += j.charged
+		}
+
+		p := &o.cpus[c]
+		p.job, p.resume, p.expires = j, o.t, -1
+		if p.last != j {
+			if p.last != nil {
+				o.res.ContextSwitches++
+				p.resume += o.opts.SwitchCost
+			}
+			p.open = -1
+		}
+		p.last = j
+		if q := o.quantum(j); q > 0 {
+			p.expires = p.resume + q
+		}
+	}
+}
+
+// run runs the job on CPU c for the current tick.
+func (o *oracle) run(c int) {
+	p := &o.cpus[c]
+	burst := min(o.opts.Speed(c), p.job.remaining)
+	p.job.remaining -= burst
+	p.job.saved += burst - 1
+	if p.open >= 0 && o.res.Gantt[p.open].Stop == o.t {
+		o.res.Gantt[p.open].Stop++
+		return
+	}
+	p.open = len(o.res.Gantt)
+	o.res.Gantt = append(o.res.Gantt, scheduler.TimeSlice{PID: p.job.ProcessID, Start: o.t, Stop: o.t + 1, CPU: c})
+}
+
+// finish completes the job on CPU c at the current time.
+func (o *oracle) finish(c int) {
+	j := o.cpus[c].job
+	o.cpus[c].job = nil
+	j.done = true
+	o.res.Stats[j.index] = scheduler.Stat{
+		Process:    j.Process,
+		Wait:       o.t - j.ArrivalTime - j.BurstDuration + j.saved,
+		Turnaround: o.t - j.ArrivalTime,
+		Completion: o.t,
+	}
+}
+
+func summarize(r *scheduler.Result) {
+	var wait, turnaround, last float64
+	for _, s := range r.Stats {
+		wait += float64(s.Wait)
+		turnaround += float64(s.Turnaround)
+		last = max(last, float64(s.Completion))
+	}
+	if n := float64(len(r.Stats)); n > 0 && last > 0 {
+		r.AveWait, r.AveTurnaround, r.AveThroughput = wait/n, turnaround/n, n/last
+	}
+}
+
+// maxDiffs is the most differences Diff reports.
+const maxDiffs = 10
+
+// Diff describes how got differs from want, or returns nil if they agree.
+// Gantt charts are compared slice by slice in order of start and CPU, as
+// the order they are recorded in is not part of a result.
+func Diff(got, want scheduler.Result) []string {
+	var diffs []string
+	add := func(format string, args ...any) {
+		if len(diffs) < maxDiffs {
+			diffs = append(diffs, fmt.Sprintf(format, args...))
+		}
+	}
+	if len(got.Stats) != len(want.Stats) {
+		add("%d stats, want %d", len(got.Stats), len(want.Stats))
+	}
+	for i := range min(len(got.Stats), len(want.Stats)) {
+		if g, w := got.Stats[i], want.Stats[i]; g != w {
+			add("P%d: wait %d, turnaround %d, completion %d, want %d, %d, %d",
+				w.ProcessID, g.Wait, g.Turnaround, g.Completion, w.Wait, w.Turnaround, w.Completion)
+		}
+	}
+	if got.ContextSwitches != want.ContextSwitches {
+		add("%d context switches, want %d", got.ContextSwitches, want.ContextSwitches)
+	}
+	if got.AveWait != want.AveWait || got.AveTurnaround != want.AveTurnaround || got.AveThroughput != want.AveThroughput {
+		add("averages %v, %v, %v, want %v, %v, %v",
+			got.AveWait, got.AveTurnaround, got.AveThroughput, want.AveWait, want.AveTurnaround, want.AveThroughput)
+	}
+	g, w := sorted(got.Gantt), sorted(want.Gantt)
+	if len(g) != len(w) {
+		add("%d slices, want %d", len(g), len(w))
+	}
+	for i := range min(len(g), len(w)) {
+		if g[i] != w[i] {
+			add("slice %d: %+v, want %+v", i, g[i], w[i])
+		}
+	}
+	return diffs
+}
+
+func sorted(gantt []scheduler.TimeSlice) []scheduler.TimeSlice {
+	s := slices.Clone(gantt)
+	slices.SortFunc(s, func(a, b scheduler.TimeSlice) int {
+		return cmp.Or(cmp.Compare(a.Start, b.Start), cmp.Compare(a.CPU, b.CPU))
+	})
+	return s
+}
@@ -0,0 +1,128 @@
+package oracle
+
+import (
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+
+	"p1/internal/scheduler"
+)
+
+// randomWorkload returns up to 30 processes in no particular order, some
+// arriving together, some with no burst, some sharing a process ID, some
+// with a weight or a burst estimate, most in one of three groups and most
+// of a class.
+func randomWorkload(r *rand.Rand) []scheduler.Process {
+	processes := make([]scheduler.Process, r.IntN(30))
+	for i := range processes {
+		processes[i] = scheduler.Process{
+			ProcessID:     int64(i + 1),
+			BurstDuration: r.Int64N(10),
+			ArrivalTime:   r.Int64N(int64(3*len(processes) + 1)),
+			Priority:      r.Int64N(4),
+		}
+		if r.IntN(10) == 0 {
+			processes[i].ProcessID = int64(r.IntN(i + 1))
+		}
+		if r.IntN(4) == 0 {
+			processes[i].Weight = 1 + r.Int64N(2000)
+		}
+		if r.IntN(3) == 0 {
+			processes[i].Estimate = r.Int64N(12)
+		}
+		if g := r.IntN(4); g > 0 {
+			processes[i].Group = string(rune('a' + g - 1))
+		}
+		processes[i].Class = [...]scheduler.Class{"", scheduler.ClassRealtime, scheduler.ClassInteractive, scheduler.ClassBatch, scheduler.ClassBatch}[r.IntN(5)]
+	}
+	return processes
+}
+
+// randomSuspensions returns suspensions of some of processes, none of them
+// overlapping another of the same process, or none at all half the time.
+func randomSuspensions(r *rand.Rand, processes []scheduler.Process) []scheduler.Suspension {
+	var out []scheduler.Suspension
+	if r.IntN(2) == 0 {
+		return out
+	}
+	free := make(map[int64]int64) // when each process can next be suspended
+	for range r.IntN(len(processes) + 1) {
+		pid := processes[r.IntN(len(processes))].ProcessID
+		from := free[pid] + r.Int64N(int64(3*len(processes)+1))
+		to := from + 1 + r.Int64N(8)
+		out = append(out, scheduler.Suspension{PID: pid, From: from, To: to})
+		free[pid] = to + 1
+	}
+	return out
+}
+
+func TestEngineMatchesOracle(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	variants := [][]scheduler.Option{
+		nil,
+		{scheduler.WithQuantum(2), scheduler.WithMLFQ(scheduler.MLFQParams{Levels: 2, Quanta: []int64{1, 3}}), scheduler.WithAging(scheduler.AgingParams{Rate: 0.25})},
+		{scheduler.WithCPUs(3), scheduler.WithFCFS(scheduler.FCFSParams{Order: scheduler.OrderGiven}), scheduler.WithSeed(7)},
+		{scheduler.WithSwitchCost(2), scheduler.WithAging(scheduler.AgingParams{Rate: 1})},
+		{scheduler.WithCPUs(2), scheduler.WithSwitchCost(1), scheduler.WithQuantum(3), scheduler.WithSeed(-1)},
+		{scheduler.WithAging(scheduler.AgingParams{Rate: 0.5, All: true})},
+		{scheduler.WithCPUs(2), scheduler.WithSwitchCost(1), scheduler.WithAging(scheduler.AgingParams{Rate: 2, All: true})},
+		{scheduler.WithResumeBoost(), scheduler.WithMLFQ(scheduler.MLFQParams{Levels: 3, Quanta: []int64{1, 2, 4}}), scheduler.WithAging(scheduler.AgingParams{Rate: 0.25})},
+		{scheduler.WithResumeBoost(), scheduler.WithCPUs(2), scheduler.WithSwitchCost(1), scheduler.WithAging(scheduler.AgingParams{Rate: 0.5, All: true})},
+		{scheduler.WithQuantum(2), scheduler.WithFairShare(scheduler.FairShareParams{Shares: []scheduler.GroupShare{{Group: "a", Share: 3}, {Group: "b", Share: 100}, {Group: "", Share: 7}}})},
+		{scheduler.WithCPUs(3), scheduler.WithSwitchCost(1), scheduler.WithFairShare(scheduler.FairShareParams{Shares: []scheduler.GroupShare{{Group: "c", Share: 2048}}})},
+		{scheduler.WithResumeBoost(), scheduler.WithQuantum(2), scheduler.WithClasses(scheduler.ClassParams{Realtime: "mlfq", Interactive: "lottery", Batch: "fairshare"})},
+		{scheduler.WithCPUs(2), scheduler.WithSwitchCost(1), scheduler.WithAging(scheduler.AgingParams{Rate: 0.5}), scheduler.WithClasses(scheduler.ClassParams{Realtime: "aging", Interactive: "fcfs", Batch: "random"})},
+		{scheduler.WithSpeeds(1, 3, 2), scheduler.WithSwitchCost(1), scheduler.WithQuantum(2), scheduler.WithMLFQ(scheduler.MLFQParams{Levels: 2, Quanta: []int64{1, 3}})},
+		{scheduler.WithSpeeds(2, 2, 1, 1), scheduler.WithQuantum(3), scheduler.WithFairShare(scheduler.FairShareParams{Shares: []scheduler.GroupShare{{Group: "a", Share: 3}}}), scheduler.WithClasses(scheduler.ClassParams{Batch: "fairshare"})},
+	}
+	for i := range 300 {
+		processes := randomWorkload(r)
+		suspensions := randomSuspensions(r, processes)
+		for _, opts := range variants {
+			sim, err := scheduler.NewSimulator(append(opts, scheduler.WithSuspensions(suspensions...))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, a := range scheduler.Algorithms() {
+				want, err := Schedule(a.Name, processes, sim.Options())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diffs := Diff(sim.Schedule(a, processes), want); diffs != nil {
+					t.Fatalf("workload %d %v, %s, %+v:\n%s", i, processes, a.Name, sim.Options(), strings.Join(diffs, "\n"))
+				}
+			}
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	processes := []scheduler.Process{{ProcessID: 1, BurstDuration: 5}, {ProcessID: 2, BurstDuration: 1, ArrivalTime: 1}}
+	res := scheduler.FCFS(processes)
+	if diffs := Diff(res, res); diffs != nil {
+		t.Errorf("a result differs from itself: %v", diffs)
+	}
+	want := []string{
+		"P1: wait 0, turnaround 5, completion 5, want 1, 6, 6",
+		"P2: wait 4, turnaround 5, completion 6, want 0, 1, 2",
+		"1 context switches, want 2",
+		"averages 2, 5, 0.3333333333333333, want 0.5, 3.5, 0.3333333333333333",
+		"2 slices, want 3",
+		"slice 0: {PID:1 Start:0 Stop:5 CPU:0}, want {PID:1 Start:0 Stop:1 CPU:0}",
+		"slice 1: {PID:2 Start:5 Stop:6 CPU:0}, want {PID:2 Start:1 Stop:2 CPU:0}",
+	}
+	if diffs := Diff(res, scheduler.SJF(processes)); !reflect.DeepEqual(diffs, want) {
+		t.Errorf("FCFS against SJF:\n%s\nwant:\n%s", strings.Join(diffs, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestScheduleTooLong(t *testing.T) {
+	if _, err := Schedule("fcfs", []scheduler.Process{{ProcessID: 1, BurstDuration: MaxTicks + 1}}, scheduler.DefaultOptions()); !errors.Is(err, ErrTooLong) {
+		t.Errorf("err = %v, want %v", err, ErrTooLong)
+	}
+	if _, err := Schedule("nope", nil, scheduler.DefaultOptions()); !errors.Is(err, scheduler.ErrUnknownAlgorithm) {
+		t.Errorf("err = %v, want %v", err, scheduler.ErrUnknownAlgorithm)
+	}
+}
@@ -0,0 +1,241 @@
+// Package paging simulates page replacement: which pages a fixed number of
+// frames hold as a process references its pages, and how often a reference
+// faults.
+package paging
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	ErrUnknownAlgorithm = errors.New("unknown page replacement algorithm")
+	ErrInvalidFrames    = errors.New("invalid frame count")
+)
+
+// Empty is the page of a frame that holds none.
+const Empty = -1
+
+// Step is the outcome of one reference.
+type Step struct {
+	Page  int64 `json:"page"`
+	Fault bool  `json:"fault"`
+	// Evicted is the page replaced to make room for this one, or Empty if
+	// none was.
+	Evicted int64 `json:"evicted"`
+	// Frames holds the page in each frame after the reference.
+	Frames []int64 `json:"frames"`
+}
+
+// Result is the outcome of a reference string.
+type Result struct {
+	Frames int    `json:"frames"`
+	Steps  []Step `json:"steps"`
+	Faults int    `json:"faults"`
+	Hits   int    `json:"hits"`
+}
+
+// FaultRate returns the share of references that fault, 0 if there were
+// none.
+func (r Result) FaultRate() float64 {
+	if len(r.Steps) == 0 {
+		return 0
+	}
+	return float64(r.Faults) / float64(len(r.Steps))
+}
+
+// replacer is the policy of an algorithm: it is told about every reference
+// to a frame and picks the frame to replace once every frame is full.
+type replacer interface {
+	// hit is called when reference i is to the page in frame.
+	hit(frame, i int)
+	// load is called when the page of reference i is loaded into frame.
+	load(frame, i int)
+	// victim returns the frame to replace for reference i.
+	victim(i int) int
+}
+
+// Algorithm describes a page replacement algorithm available to front ends.
+type Algorithm struct {
+	// Name is the short identifier used on command lines.
+	Name string
+	// Title is the heading the algorithm's results are shown under.
+	Title string
+
+	new func(refs []int64, frames int) replacer
+}
+
+// Algorithms returns every algorithm in the order they are usually shown.
+func Algorithms() []Algorithm {
+	return []Algorithm{
+		{"fifo", "First-in, first-out", func(_ []int64, n int) replacer { return &fifo{n: n} }},
+		{"lru", "Least recently used", func(_ []int64, n int) replacer { return &lru{used: make([]int, n)} }},
+		{"optimal", "Optimal", newOptimal},
+		{"clock", "Clock", func(_ []int64, n int) replacer { return &clock{use: make([]bool, n)} }},
+		{"second-chance", "Second chance", func(_ []int64, n int) replacer { return &secondChance{referenced: make([]bool, n)} }},
+	}
+}
+
+// LookupAlgorithm returns the algorithm with the given name.
+func LookupAlgorithm(name string) (Algorithm, error) {
+	for _, a := range Algorithms() {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+	return Algorithm{}, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, name)
+}
+
+// Simulate runs the reference string refs through frames frames with
+// algorithm a, the frames starting empty and filling in order.
+func Simulate(a Algorithm, refs []int64, frames int) (Result, error) {
+	if frames < 1 {
+		return Result{}, fmt.Errorf("%w: need at least one frame, got %d", ErrInvalidFrames, frames)
+	}
+	r := a.new(refs, frames)
+	res := Result{Frames: frames, Steps: make([]Step, len(refs))}
+	held := make([]int64, frames)
+	for f := range held {
+		held[f] = Empty
+	}
+	where := make(map[int64]int, frames)
+	for i, page := range refs {
+		step := Step{Page: page, Evicted: Empty}
+		if f, ok := where[page]; ok {
+			r.hit(f, i)
+			res.Hits++
+		} else {
+			step.Fault = true
+			res.Faults++
+			f := len(where)
+			if f == frames {
+				f = r.victim(i)
+				step.Evicted = held[f]
+				delete(where, held[f])
+			}
+			held[f], where[page] = page, f
+			r.load(f, i)
+		}
+		step.Frames = append([]int64(nil), held...)
+		res.Steps[i] = step
+	}
+	return res, nil
+}
+
+// fifo replaces the page loaded longest ago. Frames are filled in order
+// and every replacement reuses the frame replaced, so that is the frame
+// after the one replaced last.
+type fifo struct{ n, next int }
+
+func (*fifo) hit(int, int)  {}
+func (*fifo) load(int, int) {}
+
+func (r *fifo) victim(int) int {
+	f := r.next
+	r.next = (r.next + 1) % r.n
+	return f
+}
+
+// lru replaces the page referenced longest ago.
+type lru struct{ used []int }
+
+func (r *lru) hit(f, i int)  { r.used[f] = i }
+func (r *lru) load(f, i int) { r.used[f] = i }
+
+func (r *lru) victim(int) int {
+	v := 0
+	for f, i := range r.used {
+		if i < r.used[v] {
+			v = f
+		}
+	}
+	return v
+}
+
+// optimal replaces the page referenced again furthest in the future, or
+// never, the first frame winning ties.
+type optimal struct {
+	// next[i] is the index of the next reference to the page of reference
+	// i, or math.MaxInt if there is none.
+	next []int
+	// due holds the next reference to each frame's page.
+	due []int
+}
+
+func newOptimal(refs []int64, frames int) replacer {
+	r := &optimal{next: make([]int, len(refs)), due: make([]int, frames)}
+	last := make(map[int64]int)
+	for i := len(refs) - 1; i >= 0; i-- {
+		r.next[i] = math.MaxInt
+		if j, ok := last[refs[i]]; ok {
+			r.next[i] = j
+		}
+		last[refs[i]] = i
+	}
+	return r
+}
+
+func (r *optimal) hit(f, i int)  { r.due[f] = r.next[i] }
+func (r *optimal) load(f, i int) { r.due[f] = r.next[i] }
+
+func (r *optimal) victim(int) int {
+	v := 0
+	for f, due := range r.due {
+		if due > r.due[v] {
+			v = f
+		}
+	}
+	return v
+}
+
+// clock sweeps a hand around the frames, replacing the first page whose use
+// bit is clear and clearing those it passes. A page's bit is set when it is
+// loaded and whenever it is referenced, and the hand moves on past the
+// frame it replaces.
+type clock struct {
+	use  []bool
+	hand int
+}
+
+func (r *clock) hit(f, _ int)  { r.use[f] = true }
+func (r *clock) load(f, _ int) { r.use[f] = true }
+
+func (r *clock) victim(int) int {
+	for r.use[r.hand] {
+		r.use[r.hand] = false
+		r.hand = (r.hand + 1) % len(r.use)
+	}
+	f := r.hand
+	r.hand = (r.hand + 1) % len(r.use)
+	return f
+}
+
+// secondChance keeps the frames in the order their pages were loaded and
+// replaces the oldest, unless it has been referenced since it was loaded or
+// last spared, in which case it is spared: its bit is cleared and it goes
+// to the back as if just loaded. Unlike clock, a page loaded has its bit
+// clear, so only a hit earns it a second chance.
+type secondChance struct {
+	referenced []bool
+	queue      []int
+}
+
+func (r *secondChance) hit(f, _ int) { r.referenced[f] = true }
+
+func (r *secondChance) load(f, _ int) {
+	r.referenced[f] = false
+	r.queue = append(r.queue, f)
+}
+
+func (r *secondChance) victim(int) int {
+	for {
+		f := r.queue[0]
+		r.queue = r.queue[1:]
+		if !r.referenced[f] {
+			return f
+		}
+		r.referenced[f] = false
+		r.queue = append(r.queue, f)
+	}
+}
@@ -0,0 +1,131 @@
+package paging
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// textbook is the usual example reference string, run through 3 frames.
+var textbook = []int64{7, 0, 1, 2, 0, 3, 0, 4, 2, 3, 0, 3, 2, 1, 2, 0, 1, 7, 0, 1}
+
+func TestSimulate(t *testing.T) {
+	for _, tc := range []struct {
+		algorithm string
+		faults    int
+	}{
+		{"fifo", 15},
+		{"lru", 12},
+		{"optimal", 9},
+		{"clock", 14},
+		{"second-chance", 11},
+	} {
+		a, err := LookupAlgorithm(tc.algorithm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := Simulate(a, textbook, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Faults != tc.faults || res.Hits != len(textbook)-tc.faults || len(res.Steps) != len(textbook) {
+			t.Errorf("%s: %d faults, %d hits in %d steps, want %d faults", tc.algorithm, res.Faults, res.Hits, len(res.Steps), tc.faults)
+		}
+		// Every algorithm replaces 7, loaded first and needed last.
+		if s := res.Steps[3]; !s.Fault || s.Evicted != 7 || !reflect.DeepEqual(s.Frames, []int64{2, 0, 1}) {
+			t.Errorf("%s: step 3 = %+v", tc.algorithm, s)
+		}
+		if s := res.Steps[0]; !reflect.DeepEqual(s.Frames, []int64{7, Empty, Empty}) || s.Evicted != Empty {
+			t.Errorf("%s: step 0 = %+v", tc.algorithm, s)
+		}
+	}
+}
+
+// TestReplacement checks the frames each algorithm holds where they first
+// differ on the textbook string: the reference to 4.
+func TestReplacement(t *testing.T) {
+	for name, want := range map[string][]int64{
+		"fifo":          {4, 3, 0},
+		"lru":           {4, 0, 3},
+		"optimal":       {2, 4, 3},
+		"clock":         {4, 0, 3},
+		"second-chance": {4, 0, 3},
+	} {
+		a, _ := LookupAlgorithm(name)
+		res, _ := Simulate(a, textbook, 3)
+		if got := res.Steps[7].Frames; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s holds %v after 4, want %v", name, got, want)
+		}
+	}
+
+	// Clock sets a page's bit as it is loaded, so making room for 4 its
+	// hand clears every bit, the hit on 2 counting for nothing, and 2 is
+	// replaced next. Second chance spares 2 for its hit and replaces 3.
+	refs := []int64{1, 2, 3, 2, 4, 1}
+	for name, want := range map[string][]int64{"clock": {4, 1, 3}, "second-chance": {4, 2, 1}} {
+		a, _ := LookupAlgorithm(name)
+		res, _ := Simulate(a, refs, 3)
+		if got := res.Steps[5].Frames; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s holds %v after the last reference, want %v", name, got, want)
+		}
+	}
+}
+
+func TestBelady(t *testing.T) {
+	// FIFO faults more with 4 frames than 3; LRU, a stack algorithm, never
+	// does.
+	refs := []int64{1, 2, 3, 4, 1, 2, 5, 1, 2, 3, 4, 5}
+	for name, want := range map[string][2]int{"fifo": {9, 10}, "lru": {10, 8}} {
+		a, _ := LookupAlgorithm(name)
+		three, _ := Simulate(a, refs, 3)
+		four, _ := Simulate(a, refs, 4)
+		if got := [2]int{three.Faults, four.Faults}; got != want {
+			t.Errorf("%s faults %v with 3 and 4 frames, want %v", name, got, want)
+		}
+	}
+}
+
+func TestFaultCurve(t *testing.T) {
+	refs := []int64{1, 2, 3, 4, 1, 2, 5, 1, 2, 3, 4, 5}
+	a, _ := LookupAlgorithm("fifo")
+	c, err := FaultCurve(a, refs, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{12, 12, 9, 10, 5}; !reflect.DeepEqual(c.Faults, want) {
+		t.Errorf("faults = %v, want %v", c.Faults, want)
+	}
+	if got := c.Anomalies(); !reflect.DeepEqual(got, []int{4}) {
+		t.Errorf("anomalies = %v, want [4]", got)
+	}
+	if _, err := FaultCurve(a, refs, 0); !errors.Is(err, ErrInvalidFrames) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidFrames)
+	}
+}
+
+func TestWorkingSets(t *testing.T) {
+	sets, err := WorkingSets([]int64{1, 2, 1, 3, 3, 3, 4}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]int64{{1}, {1, 2}, {1, 2}, {1, 2, 3}, {1, 3}, {3}, {3, 4}}
+	if !reflect.DeepEqual(sets, want) {
+		t.Errorf("working sets = %v, want %v", sets, want)
+	}
+	if _, err := WorkingSets(textbook, 0); !errors.Is(err, ErrInvalidWindow) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidWindow)
+	}
+}
+
+func TestSimulateInvalid(t *testing.T) {
+	a, _ := LookupAlgorithm("fifo")
+	if _, err := Simulate(a, textbook, 0); !errors.Is(err, ErrInvalidFrames) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidFrames)
+	}
+	if _, err := LookupAlgorithm("mru"); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("err = %v, want %v", err, ErrUnknownAlgorithm)
+	}
+	if res, err := Simulate(a, nil, 2); err != nil || res.FaultRate() != 0 {
+		t.Errorf("no references: %+v, %v", res, err)
+	}
+}
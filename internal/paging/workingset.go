@@ -0,0 +1,75 @@
+package paging
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var ErrInvalidWindow = errors.New("invalid working set window")
+
+// WorkingSets returns the working set after each reference of refs: the
+// distinct pages among the last window references, in ascending order.
+// A process given fewer frames than its working set thrashes.
+func WorkingSets(refs []int64, window int) ([][]int64, error) {
+	if window < 1 {
+		return nil, fmt.Errorf("%w: need a window of at least one reference, got %d", ErrInvalidWindow, window)
+	}
+	sets := make([][]int64, len(refs))
+	// count holds how many times each page is referenced in the window.
+	count := make(map[int64]int)
+	for i, page := range refs {
+		count[page]++
+		if i >= window {
+			old := refs[i-window]
+			if count[old]--; count[old] == 0 {
+				delete(count, old)
+			}
+		}
+		set := make([]int64, 0, len(count))
+		for p := range count {
+			set = append(set, p)
+		}
+		slices.Sort(set)
+		sets[i] = set
+	}
+	return sets, nil
+}
+
+// Curve is the faults of an algorithm over a reference string with each
+// number of frames from one up.
+type Curve struct {
+	Algorithm string `json:"algorithm"`
+	Title     string `json:"title"`
+	// Faults holds the faults with one frame, then two, and so on.
+	Faults []int `json:"faults"`
+}
+
+// FaultCurve runs refs through a with every number of frames from one up
+// to frames.
+func FaultCurve(a Algorithm, refs []int64, frames int) (Curve, error) {
+	if frames < 1 {
+		return Curve{}, fmt.Errorf("%w: need at least one frame, got %d", ErrInvalidFrames, frames)
+	}
+	c := Curve{Algorithm: a.Name, Title: a.Title, Faults: make([]int, frames)}
+	for n := 1; n <= frames; n++ {
+		res, err := Simulate(a, refs, n)
+		if err != nil {
+			return Curve{}, err
+		}
+		c.Faults[n-1] = res.Faults
+	}
+	return c, nil
+}
+
+// Anomalies returns the frame counts at which c faults more than with one
+// frame fewer: Belady's anomaly.
+func (c Curve) Anomalies() []int {
+	var frames []int
+	for i := 1; i < len(c.Faults); i++ {
+		if c.Faults[i] > c.Faults[i-1] {
+			frames = append(frames, i+1)
+		}
+	}
+	return frames
+}
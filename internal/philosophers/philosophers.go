@@ -0,0 +1,308 @@
+// Package philosophers simulates the dining philosophers: philosophers
+// around a table who think, grow hungry and eat, each needing the forks on
+// both sides of them to eat. A strategy decides how they pick up the
+// forks, and so whether they can deadlock or starve. Time advances in
+// ticks, so a run is deterministic.
+package philosophers
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	ErrUnknownStrategy = errors.New("unknown dining philosophers strategy")
+	ErrInvalidParams   = errors.New("invalid dining philosophers parameters")
+)
+
+// Params sets up a run.
+type Params struct {
+	// Philosophers is how many sit at the table, with a fork between
+	// each two.
+	Philosophers int `json:"philosophers"`
+	// Think and Eat hold how many ticks each philosopher thinks and eats
+	// for at a time, the lists repeating if shorter than the table.
+	Think []int64 `json:"think"`
+	Eat   []int64 `json:"eat"`
+	// Duration is how many ticks the run lasts.
+	Duration int64 `json:"duration"`
+	// Patience is the longest a philosopher goes hungry before it counts
+	// as starving.
+	Patience int64 `json:"patience"`
+}
+
+// DefaultParams returns five philosophers who all think for 3 ticks and
+// eat for 2, over 100 ticks with a patience of 20.
+func DefaultParams() Params {
+	return Params{Philosophers: 5, Think: []int64{3}, Eat: []int64{2}, Duration: 100, Patience: 20}
+}
+
+// Check fails with ErrInvalidParams unless there are at least two
+// philosophers, each thinking and eating for at least a tick.
+func (p Params) Check() error {
+	positive := func(ts []int64) bool {
+		return len(ts) > 0 && !slices.ContainsFunc(ts, func(t int64) bool { return t < 1 })
+	}
+	switch {
+	case p.Philosophers < 2:
+		return fmt.Errorf("%w: %d philosophers, want at least 2", ErrInvalidParams, p.Philosophers)
+	case !positive(p.Think) || !positive(p.Eat):
+		return fmt.Errorf("%w: think times %v and eat times %v, want at least 1", ErrInvalidParams, p.Think, p.Eat)
+	case p.Duration < 0 || p.Patience < 0:
+		return fmt.Errorf("%w: duration %d and patience %d, want neither negative", ErrInvalidParams, p.Duration, p.Patience)
+	}
+	return nil
+}
+
+// State is what a philosopher is doing.
+type State string
+
+const (
+	Thinking State = "thinking"
+	// Hungry philosophers are waiting for forks, holding some or none.
+	Hungry State = "hungry"
+	Eating State = "eating"
+)
+
+// Period is a span of ticks, from Start up to Stop, a philosopher spends in
+// one state.
+type Period struct {
+	Start int64 `json:"start"`
+	Stop  int64 `json:"stop"`
+	State State `json:"state"`
+}
+
+// Philosopher is what one philosopher did over a run.
+type Philosopher struct {
+	// Name is P1, P2... around the table. Philosopher i has fork i on
+	// the left and fork i+1 on the right, the last sharing fork 0 with
+	// the first.
+	Name  string `json:"name"`
+	Meals int    `json:"meals"`
+	// Eating and Hungry are the ticks spent in each state.
+	Eating int64 `json:"eating"`
+	Hungry int64 `json:"hungry"`
+	// LongestWait is the longest time it went hungry at once.
+	LongestWait int64 `json:"longest_wait"`
+	// Starving is set if LongestWait is above the patience of the run.
+	Starving bool     `json:"starving"`
+	Periods  []Period `json:"periods"`
+}
+
+// Deadlock is when every philosopher on a cycle held a fork the next
+// wanted.
+type Deadlock struct {
+	Time int64 `json:"time"`
+	// Cycle holds the philosophers, by index, each waiting for a fork the
+	// next holds and the last for one the first holds.
+	Cycle []int `json:"cycle"`
+}
+
+// Result is a run of the simulation.
+type Result struct {
+	Philosophers []Philosopher `json:"philosophers"`
+	// End is when the run stopped, its duration.
+	End int64 `json:"end"`
+	// Deadlock is the first deadlock of the run, after which those on it
+	// go hungry to the end.
+	Deadlock *Deadlock `json:"deadlock,omitempty"`
+}
+
+// Starving returns the indexes of the philosophers who starved.
+func (r Result) Starving() []int {
+	var starving []int
+	for i, p := range r.Philosophers {
+		if p.Starving {
+			starving = append(starving, i)
+		}
+	}
+	return starving
+}
+
+// Strategy describes a way of picking up forks available to front ends.
+type Strategy struct {
+	// Name is the short identifier used on command lines.
+	Name string
+	// Title is the heading the strategy's results are shown under.
+	Title string
+	// forks returns the forks philosopher i of n picks up, in order.
+	forks func(i, n int) [2]int
+	// arbitrated philosophers ask a waiter, who has them pick up both
+	// forks at once when both are free, serving those hungry longest
+	// first. Others pick up a fork a tick, holding the first while
+	// waiting for the second.
+	arbitrated bool
+}
+
+// Strategies returns every strategy in the order they are usually shown.
+func Strategies() []Strategy {
+	leftFirst := func(i, n int) [2]int { return [2]int{i, (i + 1) % n} }
+	return []Strategy{
+		{"naive", "Naive (left fork first)", leftFirst, false},
+		{"ordering", "Resource ordering", func(i, n int) [2]int {
+			f := leftFirst(i, n)
+			return [2]int{min(f[0], f[1]), max(f[0], f[1])}
+		}, false},
+		{"arbitrator", "Arbitrator", leftFirst, true},
+	}
+}
+
+// LookupStrategy returns the strategy with the given name.
+func LookupStrategy(name string) (Strategy, error) {
+	for _, s := range Strategies() {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return Strategy{}, fmt.Errorf("%w: %q", ErrUnknownStrategy, name)
+}
+
+// diner is a philosopher's state during a run.
+type diner struct {
+	Philosopher
+	state State
+	// since is when it entered its state, and until when it leaves it
+	// if thinking or eating.
+	since, until int64
+	// held is how many of its forks it holds.
+	held int
+}
+
+// Simulate runs p with strategy s. Everyone starts out thinking. Each tick,
+// philosophers done eating put their forks down, those done thinking grow
+// hungry, and the hungry then try for forks: in order around the table, or
+// the order the waiter serves them in. A philosopher eats from the tick it
+// holds both forks. Nothing changes once the philosophers deadlock, so the
+// run skips from there to its end.
+func Simulate(s Strategy, p Params) (Result, error) {
+	if err := p.Check(); err != nil {
+		return Result{}, err
+	}
+	n := p.Philosophers
+	diners := make([]*diner, n)
+	// holder holds the index of the philosopher holding each fork, or -1.
+	holder := make([]int, n)
+	for i := range diners {
+		diners[i] = &diner{Philosopher: Philosopher{Name: fmt.Sprintf("P%d", i+1)}}
+		holder[i] = -1
+	}
+	var now int64
+	enter := func(i int, state State) {
+		d := diners[i]
+		if d.state != "" {
+			d.Periods = append(d.Periods, Period{Start: d.since, Stop: now, State: d.state})
+		}
+		if d.state == Hungry {
+			d.LongestWait = max(d.LongestWait, now-d.since)
+		}
+		d.state, d.since = state, now
+		switch state {
+		case Thinking:
+			d.until = now + p.Think[i%len(p.Think)]
+		case Eating:
+			d.Meals++
+			d.until = now + p.Eat[i%len(p.Eat)]
+		}
+	}
+	for i := range diners {
+		enter(i, Thinking)
+	}
+
+	res := Result{End: p.Duration}
+	order := make([]int, n)
+	for ; now < p.Duration; now++ {
+		for i, d := range diners {
+			if d.state == Eating && d.until == now {
+				for _, f := range s.forks(i, n) {
+					holder[f] = -1
+				}
+				d.held = 0
+				enter(i, Thinking)
+			}
+		}
+		for i, d := range diners {
+			if d.state == Thinking && d.until == now {
+				enter(i, Hungry)
+			}
+		}
+
+		for i := range order {
+			order[i] = i
+		}
+		if s.arbitrated {
+			slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(diners[a].since, diners[b].since) })
+		}
+		for _, i := range order {
+			d := diners[i]
+			if d.state != Hungry {
+				continue
+			}
+			forks := s.forks(i, n)
+			if s.arbitrated {
+				if holder[forks[0]] < 0 && holder[forks[1]] < 0 {
+					holder[forks[0]], holder[forks[1]], d.held = i, i, 2
+					enter(i, Eating)
+				}
+				continue
+			}
+			if f := forks[d.held]; holder[f] < 0 {
+				holder[f] = i
+				if d.held++; d.held == 2 {
+					enter(i, Eating)
+				}
+			}
+		}
+
+		if !s.arbitrated {
+			if cycle := waitCycle(diners, holder, s, n); cycle != nil {
+				res.Deadlock = &Deadlock{Time: now, Cycle: cycle}
+				break
+			}
+		}
+	}
+
+	now = res.End
+	for i, d := range diners {
+		enter(i, "")
+		d.Periods = slices.DeleteFunc(d.Periods, func(p Period) bool { return p.Start == p.Stop })
+		for _, period := range d.Periods {
+			switch period.State {
+			case Eating:
+				d.Eating += period.Stop - period.Start
+			case Hungry:
+				d.Hungry += period.Stop - period.Start
+			}
+		}
+		d.Starving = d.LongestWait > p.Patience
+		res.Philosophers = append(res.Philosophers, d.Philosopher)
+	}
+	return res, nil
+}
+
+// waitCycle returns a cycle of hungry philosophers each waiting for a fork
+// the next holds, starting from the lowest-numbered, or nil if there is
+// none. A philosopher waits for one fork at a time, so following the forks
+// they wait for from each in turn finds every cycle.
+func waitCycle(diners []*diner, holder []int, s Strategy, n int) []int {
+	waitsOn := func(i int) int {
+		d := diners[i]
+		if d.state != Hungry {
+			return -1
+		}
+		return holder[s.forks(i, n)[d.held]]
+	}
+	for start := range diners {
+		var path []int
+		for i := start; i >= 0 && len(path) <= n; i = waitsOn(i) {
+			if j := slices.Index(path, i); j >= 0 {
+				cycle := path[j:]
+				first := slices.Index(cycle, slices.Min(cycle))
+				return append(slices.Clone(cycle[first:]), cycle[:first]...)
+			}
+			path = append(path, i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package philosophers
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// pair is two philosophers, who share both their forks.
+var pair = Params{Philosophers: 2, Think: []int64{1}, Eat: []int64{2}, Duration: 10, Patience: 3}
+
+func simulate(t *testing.T, name string, p Params) Result {
+	t.Helper()
+	s, err := LookupStrategy(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Simulate(s, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestNaive(t *testing.T) {
+	// Everyone picks up their left fork in the same tick, then goes hungry
+	// to the end.
+	for _, p := range []Params{pair, DefaultParams()} {
+		res := simulate(t, "naive", p)
+		want := &Deadlock{Time: 1, Cycle: []int{0, 1}}
+		if p.Philosophers == 5 {
+			want = &Deadlock{Time: 3, Cycle: []int{0, 1, 2, 3, 4}}
+		}
+		if !reflect.DeepEqual(res.Deadlock, want) || len(res.Starving()) != p.Philosophers {
+			t.Errorf("%d philosophers: deadlock %+v, starving %v, want %+v", p.Philosophers, res.Deadlock, res.Starving(), want)
+		}
+		if h := res.Philosophers[0].Hungry; h != p.Duration-want.Time {
+			t.Errorf("%d philosophers: hungry for %d, want %d", p.Philosophers, h, p.Duration-want.Time)
+		}
+	}
+}
+
+func TestOrdering(t *testing.T) {
+	res := simulate(t, "ordering", pair)
+	if res.Deadlock != nil || res.End != 10 {
+		t.Fatalf("deadlock %+v, end %d", res.Deadlock, res.End)
+	}
+	p1, p2 := res.Philosophers[0], res.Philosophers[1]
+	want := []Period{{0, 1, Thinking}, {1, 2, Hungry}, {2, 4, Eating}, {4, 5, Thinking}, {5, 8, Hungry}, {8, 10, Eating}}
+	if !reflect.DeepEqual(p1.Periods, want) || p1.Meals != 2 || p1.Eating != 4 || p1.Hungry != 4 || p1.LongestWait != 3 || p1.Starving {
+		t.Errorf("P1 = %+v", p1)
+	}
+	if p2.Meals != 1 || p2.Eating != 2 || p2.Hungry != 6 || p2.LongestWait != 4 || !p2.Starving {
+		t.Errorf("P2 = %+v", p2)
+	}
+	if got := res.Starving(); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("starving = %v, want [1]", got)
+	}
+}
+
+func TestArbitrator(t *testing.T) {
+	// The waiter serves whoever has been hungry longest, so the two take
+	// turns.
+	res := simulate(t, "arbitrator", pair)
+	var meals, longest []int64
+	for _, p := range res.Philosophers {
+		meals, longest = append(meals, int64(p.Meals)), append(longest, p.LongestWait)
+	}
+	if res.Deadlock != nil || !reflect.DeepEqual(meals, []int64{3, 2}) || !reflect.DeepEqual(longest, []int64{1, 2}) {
+		t.Errorf("deadlock %+v, meals %v, longest waits %v", res.Deadlock, meals, longest)
+	}
+	if res := simulate(t, "arbitrator", DefaultParams()); res.Deadlock != nil || res.Starving() != nil {
+		t.Errorf("default run: deadlock %+v, starving %v", res.Deadlock, res.Starving())
+	}
+}
+
+func TestSimulateInvalid(t *testing.T) {
+	s := Strategies()[0]
+	for _, p := range []Params{
+		{Philosophers: 1, Think: []int64{1}, Eat: []int64{1}},
+		{Philosophers: 2, Eat: []int64{1}},
+		{Philosophers: 2, Think: []int64{1}, Eat: []int64{0}},
+		{Philosophers: 2, Think: []int64{1}, Eat: []int64{1}, Duration: -1},
+	} {
+		if _, err := Simulate(s, p); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("%+v: err = %v, want %v", p, err, ErrInvalidParams)
+		}
+	}
+	if _, err := LookupStrategy("waiter"); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("err = %v, want %v", err, ErrUnknownStrategy)
+	}
+}
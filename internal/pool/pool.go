@@ -0,0 +1,80 @@
+// Package pool runs batches of independent jobs on a bounded number of
+// goroutines, such as the runs of an experiment or the submissions of a
+// class.
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrPanic is the error of a job that panicked.
+var ErrPanic = errors.New("job panicked")
+
+// Result is the outcome of one job.
+type Result[R any] struct {
+	Value R
+	Err   error
+}
+
+// Run calls f with every item on up to workers goroutines, at least one, and
+// returns the results in the order of items however the jobs finish. Jobs
+// are isolated from each other: one that fails or panics fails alone, a
+// panic being turned into ErrPanic. Once ctx is done, jobs not yet started
+// fail with its error.
+func Run[T, R any](ctx context.Context, workers int, items []T, f func(context.Context, T) (R, error)) []Result[R] {
+	results := make([]Result[R], len(items))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(max(1, workers), max(1, len(items))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = call(ctx, f, items[i])
+			}
+		}()
+	}
+	i := 0
+feed:
+	for ; i < len(items); i++ {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+	for ; i < len(items); i++ {
+		results[i].Err = ctx.Err()
+	}
+	return results
+}
+
+func call[T, R any](ctx context.Context, f func(context.Context, T) (R, error), item T) (res Result[R]) {
+	defer func() {
+		if v := recover(); v != nil {
+			res = Result[R]{Err: fmt.Errorf("%w: %v", ErrPanic, v)}
+		}
+	}()
+	if err := ctx.Err(); err != nil {
+		return Result[R]{Err: err}
+	}
+	v, err := f(ctx, item)
+	return Result[R]{v, err}
+}
+
+// Values returns the values of results, or the first error among them.
+func Values[R any](results []Result[R]) ([]R, error) {
+	values := make([]R, len(results))
+	for i, r := range results {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		values[i] = r.Value
+	}
+	return values, nil
+}
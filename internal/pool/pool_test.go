@@ -0,0 +1,60 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	items := []int{5, 1, 4, 2, 3, 0}
+	var running, peak atomic.Int32
+	results := Run(context.Background(), 3, items, func(_ context.Context, n int) (int, error) {
+		peak.Store(max(peak.Load(), running.Add(1)))
+		defer running.Add(-1)
+		time.Sleep(time.Duration(n) * time.Millisecond)
+		switch n {
+		case 2:
+			return 0, errors.New("two")
+		case 4:
+			panic("four")
+		}
+		return n * n, nil
+	})
+	if p := peak.Load(); p > 3 {
+		t.Errorf("%d jobs ran at once, want at most 3", p)
+	}
+	for i, n := range items {
+		r := results[i]
+		switch n {
+		case 2:
+			if r.Err == nil || r.Err.Error() != "two" {
+				t.Errorf("job %d: err = %v, want two", n, r.Err)
+			}
+		case 4:
+			if !errors.Is(r.Err, ErrPanic) {
+				t.Errorf("job %d: err = %v, want %v", n, r.Err, ErrPanic)
+			}
+		default:
+			if r.Err != nil || r.Value != n*n {
+				t.Errorf("job %d: got %d, %v, want %d", n, r.Value, r.Err, n*n)
+			}
+		}
+	}
+	if _, err := Values(results); err == nil {
+		t.Error("Values: expected an error")
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := Run(ctx, 2, []int{1, 2, 3}, func(context.Context, int) (int, error) { return 1, nil })
+	for i, r := range results {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("job %d: err = %v, want %v", i, r.Err, context.Canceled)
+		}
+	}
+}
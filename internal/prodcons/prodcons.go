@@ -0,0 +1,283 @@
+// Package prodcons simulates the bounded-buffer producer-consumer problem:
+// producers putting items into a buffer of fixed size and consumers taking
+// them out, each blocking on a semaphore while the buffer is full or empty.
+// Time advances in ticks, so a run is deterministic.
+package prodcons
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+var ErrInvalidParams = errors.New("invalid producer-consumer parameters")
+
+// Params sets up a run.
+type Params struct {
+	// Produce holds the ticks each producer takes to produce an item, one
+	// per producer.
+	Produce []int64 `json:"produce"`
+	// Consume holds the ticks each consumer takes to consume an item, one
+	// per consumer.
+	Consume []int64 `json:"consume"`
+	// Capacity is the most items the buffer holds.
+	Capacity int `json:"capacity"`
+	// Items is how many items are produced, and consumed, in all.
+	Items int `json:"items"`
+}
+
+// DefaultParams returns one producer making an item every 2 ticks and one
+// consumer taking 3 ticks over each, around a buffer of 5 and 20 items.
+func DefaultParams() Params {
+	return Params{Produce: []int64{2}, Consume: []int64{3}, Capacity: 5, Items: 20}
+}
+
+// Check fails with ErrInvalidParams unless there is a producer and a
+// consumer, each taking at least a tick per item, a buffer of at least one
+// item and no negative number of items.
+func (p Params) Check() error {
+	switch {
+	case len(p.Produce) == 0 || len(p.Consume) == 0:
+		return fmt.Errorf("%w: %d producers and %d consumers, want at least one of each", ErrInvalidParams, len(p.Produce), len(p.Consume))
+	case slices.ContainsFunc(p.Produce, func(t int64) bool { return t < 1 }):
+		return fmt.Errorf("%w: produce times %v, want at least 1", ErrInvalidParams, p.Produce)
+	case slices.ContainsFunc(p.Consume, func(t int64) bool { return t < 1 }):
+		return fmt.Errorf("%w: consume times %v, want at least 1", ErrInvalidParams, p.Consume)
+	case p.Capacity < 1:
+		return fmt.Errorf("%w: buffer size %d, want at least 1", ErrInvalidParams, p.Capacity)
+	case p.Items < 0:
+		return fmt.Errorf("%w: %d items", ErrInvalidParams, p.Items)
+	}
+	return nil
+}
+
+// Role is what an agent does with the buffer.
+type Role string
+
+const (
+	Producer Role = "producer"
+	Consumer Role = "consumer"
+)
+
+// Span is an interval of ticks, from Start up to Stop.
+type Span struct {
+	Start int64 `json:"start"`
+	Stop  int64 `json:"stop"`
+}
+
+// Agent is what a producer or consumer did over a run.
+type Agent struct {
+	// Name is P1, P2... for producers and C1, C2... for consumers.
+	Name string `json:"name"`
+	Role Role   `json:"role"`
+	// Items is how many items it produced or consumed.
+	Items int `json:"items"`
+	// Busy is the ticks it spent producing or consuming.
+	Busy int64 `json:"busy"`
+	// Blocked is the ticks it spent waiting on the buffer, over the
+	// waits in Waits.
+	Blocked int64  `json:"blocked"`
+	Waits   []Span `json:"waits"`
+}
+
+// Level is the number of items in the buffer over a span of ticks.
+type Level struct {
+	Span
+	Items int `json:"items"`
+}
+
+// Result is a run of the simulation.
+type Result struct {
+	Params Params  `json:"params"`
+	Agents []Agent `json:"agents"`
+	// Occupancy covers the run from 0 to End, a level per change in the
+	// number of items buffered.
+	Occupancy []Level `json:"occupancy"`
+	// End is when the last item was consumed.
+	End int64 `json:"end"`
+}
+
+// MaxOccupancy returns the most items the buffer held at once.
+func (r Result) MaxOccupancy() int {
+	most := 0
+	for _, l := range r.Occupancy {
+		most = max(most, l.Items)
+	}
+	return most
+}
+
+// MeanOccupancy returns the average number of items buffered over the run.
+func (r Result) MeanOccupancy() float64 {
+	if r.End == 0 {
+		return 0
+	}
+	var sum int64
+	for _, l := range r.Occupancy {
+		sum += int64(l.Items) * (l.Stop - l.Start)
+	}
+	return float64(sum) / float64(r.End)
+}
+
+// agent is an agent's state during a run.
+type agent struct {
+	Agent
+	each int64
+	// until is when its item is made or used up, if it is working.
+	until   int64
+	working bool
+}
+
+// Simulate runs p until every item is consumed. Each producer makes an
+// item, waits for room in the buffer if it is full and puts it there,
+// then starts the next while items remain to make; each consumer waits for
+// an item if the buffer is empty, takes it and consumes it. Waiters are
+// woken in the order they blocked. Agents finishing in the same tick go
+// producers first, so a consumer finishing as an item arrives takes it
+// without waiting.
+func Simulate(p Params) (Result, error) {
+	if err := p.Check(); err != nil {
+		return Result{}, err
+	}
+	agents := make([]*agent, 0, len(p.Produce)+len(p.Consume))
+	for i, t := range p.Produce {
+		agents = append(agents, &agent{Agent: Agent{Name: "P" + strconv.Itoa(i+1), Role: Producer}, each: t})
+	}
+	for i, t := range p.Consume {
+		agents = append(agents, &agent{Agent: Agent{Name: "C" + strconv.Itoa(i+1), Role: Consumer}, each: t})
+	}
+
+	var (
+		now           int64
+		count         int
+		started, used int
+		full, empty   []*agent // blocked producers and consumers, in the order they blocked
+		res           = Result{Params: p}
+	)
+	level := func() {
+		if n := len(res.Occupancy); n > 0 && res.Occupancy[n-1].Start == now {
+			res.Occupancy[n-1].Items = count
+			if n > 1 && res.Occupancy[n-2].Items == count {
+				res.Occupancy = res.Occupancy[:n-1]
+			}
+			return
+		}
+		if n := len(res.Occupancy); n > 0 {
+			if res.Occupancy[n-1].Items == count {
+				return
+			}
+			res.Occupancy[n-1].Stop = now
+		}
+		res.Occupancy = append(res.Occupancy, Level{Span: Span{Start: now}, Items: count})
+	}
+	work := func(a *agent) {
+		a.working, a.until = true, now+a.each
+		a.Busy += a.each
+	}
+	// produce starts a producer on the next item, if any remain.
+	produce := func(a *agent) {
+		if started < p.Items {
+			started++
+			work(a)
+		}
+	}
+	// wake ends a wait, dropping it if it began this tick.
+	wake := func(a *agent) {
+		w := &a.Waits[len(a.Waits)-1]
+		if w.Start == now {
+			a.Waits = a.Waits[:len(a.Waits)-1]
+			return
+		}
+		w.Stop = now
+		a.Blocked += w.Stop - w.Start
+	}
+	block := func(a *agent) {
+		a.Waits = append(a.Waits, Span{Start: now})
+	}
+	// put has a producer with an item put it in the buffer, or block.
+	put := func(a *agent) {
+		if count == p.Capacity {
+			block(a)
+			full = append(full, a)
+			return
+		}
+		a.Items++
+		count++
+		if len(empty) > 0 {
+			c := empty[0]
+			empty = empty[1:]
+			wake(c)
+			count--
+			work(c)
+		}
+		level()
+		produce(a)
+	}
+	// take has a consumer take an item from the buffer, or block.
+	take := func(a *agent) {
+		if count == 0 {
+			block(a)
+			empty = append(empty, a)
+			return
+		}
+		count--
+		work(a)
+		if len(full) > 0 {
+			w := full[0]
+			full = full[1:]
+			wake(w)
+			w.Items++
+			count++
+			produce(w)
+		}
+		level()
+	}
+
+	level()
+	for _, a := range agents {
+		if a.Role == Producer {
+			produce(a)
+		} else {
+			take(a)
+		}
+	}
+	for used < p.Items {
+		now = -1
+		for _, a := range agents {
+			if a.working && (now < 0 || a.until < now) {
+				now = a.until
+			}
+		}
+		for _, a := range agents {
+			if !a.working || a.until != now {
+				continue
+			}
+			a.working = false
+			if a.Role == Producer {
+				put(a)
+				continue
+			}
+			a.Items++
+			used++
+			take(a)
+		}
+	}
+
+	res.End = now
+	for _, a := range agents {
+		// Consumers still waiting when the last item is consumed wait to
+		// the end of the run.
+		if a.Role == Consumer && slices.Contains(empty, a) {
+			wake(a)
+		}
+		if len(a.Waits) == 0 {
+			a.Waits = nil
+		}
+		res.Agents = append(res.Agents, a.Agent)
+	}
+	res.Occupancy[len(res.Occupancy)-1].Stop = now
+	if now == 0 {
+		res.Occupancy = nil
+	}
+	return res, nil
+}
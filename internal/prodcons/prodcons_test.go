@@ -0,0 +1,77 @@
+package prodcons
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	// A fast producer fills the buffer of 2 by tick 3 but never blocks: the
+	// consumer takes an item at 4 just as the last is made. The consumer
+	// waits only for the first item.
+	res, err := Simulate(Params{Produce: []int64{1}, Consume: []int64{3}, Capacity: 2, Items: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Agent{
+		{Name: "P1", Role: Producer, Items: 4, Busy: 4},
+		{Name: "C1", Role: Consumer, Items: 4, Busy: 12, Blocked: 1, Waits: []Span{{0, 1}}},
+	}
+	if !reflect.DeepEqual(res.Agents, want) {
+		t.Errorf("agents = %+v, want %+v", res.Agents, want)
+	}
+	levels := []Level{{Span{0, 2}, 0}, {Span{2, 3}, 1}, {Span{3, 7}, 2}, {Span{7, 10}, 1}, {Span{10, 13}, 0}}
+	if res.End != 13 || !reflect.DeepEqual(res.Occupancy, levels) {
+		t.Errorf("end %d, occupancy %+v, want 13 and %+v", res.End, res.Occupancy, levels)
+	}
+	if res.MaxOccupancy() != 2 || res.MeanOccupancy() != 12.0/13 {
+		t.Errorf("max %d, mean %v", res.MaxOccupancy(), res.MeanOccupancy())
+	}
+}
+
+func TestSimulateWaiters(t *testing.T) {
+	// Two consumers outpace the producer; C1 blocked first, so it gets the
+	// first item, and both wait for the second.
+	res, err := Simulate(Params{Produce: []int64{3}, Consume: []int64{2, 2}, Capacity: 1, Items: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c1, c2 := res.Agents[1], res.Agents[2]
+	if !reflect.DeepEqual(c1.Waits, []Span{{0, 3}, {5, 8}}) || c1.Blocked != 6 || c1.Items != 1 {
+		t.Errorf("C1 = %+v", c1)
+	}
+	if !reflect.DeepEqual(c2.Waits, []Span{{0, 6}}) || c2.Blocked != 6 || c2.Items != 1 {
+		t.Errorf("C2 = %+v", c2)
+	}
+	if res.End != 8 {
+		t.Errorf("end = %d, want 8", res.End)
+	}
+
+	// Producers finishing as a consumer does hand it their item in the
+	// same tick.
+	res, err = Simulate(Params{Produce: []int64{2}, Consume: []int64{2}, Capacity: 1, Items: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := res.Agents[1]; c.Blocked != 2 || res.End != 8 {
+		t.Errorf("consumer %+v, end %d", c, res.End)
+	}
+}
+
+func TestSimulateInvalid(t *testing.T) {
+	for _, p := range []Params{
+		{Consume: []int64{1}, Capacity: 1},
+		{Produce: []int64{1}, Capacity: 1},
+		{Produce: []int64{0}, Consume: []int64{1}, Capacity: 1},
+		{Produce: []int64{1}, Consume: []int64{1}},
+		{Produce: []int64{1}, Consume: []int64{1}, Capacity: 1, Items: -1},
+	} {
+		if _, err := Simulate(p); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("%+v: err = %v, want %v", p, err, ErrInvalidParams)
+		}
+	}
+	if res, err := Simulate(Params{Produce: []int64{1}, Consume: []int64{1}, Capacity: 1}); err != nil || res.End != 0 || res.Occupancy != nil {
+		t.Errorf("no items: %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,105 @@
+// Package quiz generates randomized scheduling problems of controlled
+// difficulty along with their answer keys, so every student can be handed a
+// problem of their own.
+package quiz
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"p1/internal/config"
+	"p1/internal/grade"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// Difficulty sets how large and tangled generated workloads are.
+type Difficulty int
+
+const (
+	// Easy workloads have a few short processes arriving close together.
+	Easy Difficulty = iota
+	// Medium workloads have more, longer processes that overlap more.
+	Medium
+	// Hard workloads have many processes, some arriving after long gaps
+	// that leave the CPU idle.
+	Hard
+)
+
+var difficultyNames = []string{"easy", "medium", "hard"}
+
+func (d Difficulty) String() string {
+	if int(d) < len(difficultyNames) {
+		return difficultyNames[d]
+	}
+	return fmt.Sprintf("Difficulty(%d)", int(d))
+}
+
+func (d Difficulty) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+func (d *Difficulty) UnmarshalText(text []byte) error {
+	for i, name := range difficultyNames {
+		if string(text) == name {
+			*d = Difficulty(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown difficulty %q, want one of %s", text, strings.Join(difficultyNames, ", "))
+}
+
+// levels shapes the workloads of each difficulty.
+var levels = [...]struct {
+	minProcesses, maxProcesses int
+	maxBurst                   int64
+	// maxGap is the longest time between one arrival and the next.
+	maxGap     int64
+	priorities int64
+}{
+	Easy:   {3, 4, 8, 3, 3},
+	Medium: {5, 6, 12, 5, 5},
+	Hard:   {7, 9, 20, 12, 9},
+}
+
+// Generate returns a workload of difficulty d. The same seed always gives
+// the same workload.
+func Generate(d Difficulty, seed int64) []scheduler.Process {
+	return GenerateWith(d, rand.New(rand.NewPCG(uint64(seed), uint64(d))))
+}
+
+// GenerateWith returns a workload of difficulty d shaped by the draws of r.
+func GenerateWith(d Difficulty, r scheduler.Rand) []scheduler.Process {
+	l := levels[d]
+	processes := make([]scheduler.Process, l.minProcesses+int(r.Int64N(int64(l.maxProcesses-l.minProcesses+1))))
+	var arrival int64
+	for i := range processes {
+		if i > 0 {
+			arrival += r.Int64N(l.maxGap + 1)
+		}
+		processes[i] = scheduler.Process{
+			ProcessID:     int64(i + 1),
+			BurstDuration: 1 + r.Int64N(l.maxBurst),
+			ArrivalTime:   arrival,
+			Priority:      1 + r.Int64N(l.priorities),
+		}
+	}
+	return processes
+}
+
+// AnswerKey schedules processes with every algorithm of cfg, giving the key
+// that package grade checks answers against.
+func AnswerKey(processes []scheduler.Process, cfg config.Config) (grade.Key, error) {
+	sim, err := scheduler.NewSimulator(scheduler.WithOptions(cfg.Options))
+	if err != nil {
+		return grade.Key{}, err
+	}
+	key := grade.Key{Tolerance: grade.DefaultTolerance(), Config: cfg}
+	for _, name := range cfg.Algorithms {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return grade.Key{}, err
+		}
+		key.Results = append(key.Results, render.Named{Algorithm: a.Name, Title: a.Title, Result: sim.Schedule(a, processes)})
+	}
+	return key, nil
+}
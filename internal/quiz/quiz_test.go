@@ -0,0 +1,94 @@
+package quiz
+
+import (
+	"reflect"
+	"testing"
+
+	"p1/internal/config"
+	"p1/internal/grade"
+	"p1/internal/scheduler"
+)
+
+func TestGenerate(t *testing.T) {
+	for d := Easy; d <= Hard; d++ {
+		l := levels[d]
+		for seed := range int64(50) {
+			processes := Generate(d, seed)
+			if n := len(processes); n < l.minProcesses || n > l.maxProcesses {
+				t.Fatalf("%v seed %d: %d processes", d, seed, n)
+			}
+			for i, p := range processes {
+				if p.ProcessID != int64(i+1) || p.BurstDuration < 1 || p.BurstDuration > l.maxBurst ||
+					p.Priority < 1 || p.Priority > l.priorities || (i == 0 && p.ArrivalTime != 0) {
+					t.Fatalf("%v seed %d: bad process %+v", d, seed, p)
+				}
+				if i > 0 && p.ArrivalTime-processes[i-1].ArrivalTime > l.maxGap {
+					t.Fatalf("%v seed %d: gap before %+v", d, seed, p)
+				}
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(Generate(Medium, 7), Generate(Medium, 7)) {
+		t.Error("same seed gave different workloads")
+	}
+	if reflect.DeepEqual(Generate(Medium, 7), Generate(Medium, 8)) {
+		t.Error("different seeds gave the same workload")
+	}
+}
+
+// fixed is a Rand drawing the lowest or the highest number every time.
+type fixed bool
+
+func (high fixed) Int64N(n int64) int64 {
+	if high {
+		return n - 1
+	}
+	return 0
+}
+
+func TestGenerateWith(t *testing.T) {
+	for d := Easy; d <= Hard; d++ {
+		l := levels[d]
+		low, high := GenerateWith(d, fixed(false)), GenerateWith(d, fixed(true))
+		if len(low) != l.minProcesses || len(high) != l.maxProcesses {
+			t.Fatalf("%v: %d and %d processes, want %d and %d", d, len(low), len(high), l.minProcesses, l.maxProcesses)
+		}
+		for i, p := range low {
+			if want := (scheduler.Process{ProcessID: int64(i + 1), BurstDuration: 1, Priority: 1}); p != want {
+				t.Errorf("%v, lowest draws: %+v, want %+v", d, p, want)
+			}
+		}
+		for i, p := range high {
+			want := scheduler.Process{ProcessID: int64(i + 1), BurstDuration: l.maxBurst, ArrivalTime: int64(i) * l.maxGap, Priority: l.priorities}
+			if p != want {
+				t.Errorf("%v, highest draws: %+v, want %+v", d, p, want)
+			}
+		}
+	}
+}
+
+func TestDifficultyText(t *testing.T) {
+	var d Difficulty
+	if err := d.UnmarshalText([]byte("hard")); err != nil || d != Hard {
+		t.Errorf("got %v, %v, want %v", d, err, Hard)
+	}
+	if err := d.UnmarshalText([]byte("brutal")); err == nil {
+		t.Error("expected an error for an unknown difficulty")
+	}
+}
+
+func TestAnswerKey(t *testing.T) {
+	cfg := config.Default()
+	processes := Generate(Easy, 1)
+	key, err := AnswerKey(processes, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key.Results) != len(cfg.Algorithms) {
+		t.Fatalf("%d results, want %d", len(key.Results), len(cfg.Algorithms))
+	}
+	if err := grade.Failed(key.Grade(key.Results)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,80 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"p1/internal/banker"
+)
+
+// Banker writes a resource state and its safety: a table of what each
+// process holds, claims and needs, what is available, and a safe sequence
+// if there is one.
+func Banker(w io.Writer, s banker.State, opts Options) {
+	table := Table{
+		Columns: []Column{{Header: "PROCESS"}, {Header: "ALLOCATION"}, {Header: "MAX"}, {Header: "NEED"}},
+		Style:   opts.Style,
+	}
+	need := s.Need()
+	for i, p := range s.Processes {
+		table.Rows = append(table.Rows, []string{p, counts(s.Allocation[i]), counts(s.Max[i]), counts(need[i])})
+	}
+	_ = table.Render(w)
+	_, _ = fmt.Fprintf(w, "Available: %s\n", counts(s.Available))
+	sequence, safe := s.Safe()
+	if safe {
+		_, _ = fmt.Fprintf(w, "The state is safe: %s\n\n", processes(s, sequence))
+		return
+	}
+	var stuck []int
+	for i := range s.Processes {
+		if !slices.Contains(sequence, i) {
+			stuck = append(stuck, i)
+		}
+	}
+	if len(sequence) == 0 {
+		_, _ = fmt.Fprintf(w, "The state is unsafe: none of %s can finish.\n\n", processes(s, stuck))
+		return
+	}
+	_, _ = fmt.Fprintf(w, "The state is unsafe: after %s finish, %s cannot.\n\n", processes(s, sequence), processes(s, stuck))
+}
+
+// Decisions writes a table of what became of each request made in s.
+func Decisions(w io.Writer, s banker.State, decisions []banker.Decision, opts Options) {
+	table := Table{
+		Columns: []Column{{Header: "PROCESS"}, {Header: "REQUEST"}, {Header: "OUTCOME"}, {Header: "SAFE SEQUENCE"}},
+		Style:   opts.Style,
+	}
+	for _, d := range decisions {
+		outcome := string(d.Outcome)
+		switch d.Outcome {
+		case banker.Wait:
+			outcome = "wait: only " + counts(d.State.Available) + " available"
+		case banker.Denied:
+			outcome = "denied: unsafe"
+		}
+		table.Rows = append(table.Rows, []string{s.Processes[d.Request.Process], counts(d.Request.Counts), outcome, processes(s, d.Sequence)})
+	}
+	_ = table.Render(w)
+}
+
+// counts writes a vector of resource counts separated by spaces.
+func counts(v []int64) string {
+	s := make([]string, len(v))
+	for i, c := range v {
+		s[i] = strconv.FormatInt(c, 10)
+	}
+	return strings.Join(s, " ")
+}
+
+// processes writes the names of the processes of s at indexes.
+func processes(s banker.State, indexes []int) string {
+	names := make([]string, len(indexes))
+	for i, p := range indexes {
+		names[i] = s.Processes[p]
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,47 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"p1/internal/scheduler"
+)
+
+// Boost writes how soon the processes of res ran again once they resumed
+// from opts.Suspensions, res being a run under Options.ResumeBoost, against
+// unboosted, the same run without the boost. Nothing is written if no
+// process ran again after resuming.
+func Boost(w io.Writer, res, unboosted scheduler.Result, opts Options) {
+	n, boosted := resumeResponse(res, opts.Suspensions)
+	if n == 0 {
+		return
+	}
+	_, plain := resumeResponse(unboosted, opts.Suspensions)
+	_, _ = fmt.Fprintf(w, "Resume boost: after resuming, processes waited %s on average to run again instead of %s without it (resumptions: %d)\n\n",
+		opts.formatAverage(boosted), opts.formatAverage(plain), n)
+}
+
+// resumeResponse returns how many times a process of res ran again after
+// resuming from one of suspensions, and how long it took to on average.
+func resumeResponse(res scheduler.Result, suspensions []scheduler.Suspension) (int, float64) {
+	starts := make(map[int64][]int64)
+	for _, s := range res.Gantt {
+		starts[s.PID] = append(starts[s.PID], s.Start)
+	}
+	for _, ss := range starts {
+		slices.Sort(ss)
+	}
+	n, total := 0, 0.0
+	for _, b := range suspensions {
+		ss := starts[b.PID]
+		if i, _ := slices.BinarySearch(ss, b.To); i < len(ss) {
+			n++
+			total += float64(ss[i] - b.To)
+		}
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	return n, total / float64(n)
+}
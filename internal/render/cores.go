@@ -0,0 +1,64 @@
+package render
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"p1/internal/scheduler"
+)
+
+// Cores writes what each CPU of res did on CPUs of differing speeds, as
+// opts.Speeds gives them: the processes it ran, in the order it first ran
+// them, and how long and what share of the run it was busy, within
+// opts.Window. Nothing is written without speeds, nor for results without a
+// Gantt chart.
+func Cores(w io.Writer, res scheduler.Result, opts Options) {
+	if len(opts.Speeds) == 0 || len(res.Gantt) == 0 {
+		return
+	}
+	gantt := opts.Window.Clip(res.Gantt)
+	cpus := opts.cpus(gantt)
+	byStart := slices.Clone(gantt)
+	slices.SortStableFunc(byStart, func(a, b scheduler.TimeSlice) int { return cmp.Compare(a.Start, b.Start) })
+	ran := make([][]string, cpus)
+	seen := make([]map[int64]bool, cpus)
+	busy := make([]int64, cpus)
+	for _, s := range byStart {
+		busy[s.CPU] += s.Stop - s.Start
+		if seen[s.CPU] == nil {
+			seen[s.CPU] = make(map[int64]bool)
+		}
+		if !seen[s.CPU][s.PID] {
+			seen[s.CPU][s.PID] = true
+			ran[s.CPU] = append(ran[s.CPU], strconv.FormatInt(s.PID, 10))
+		}
+	}
+
+	table := Table{
+		Columns: []Column{
+			{Header: "CPU"}, {Header: "SPEED"}, {Header: "PROCESSES", MaxWidth: 40},
+			{Header: "BUSY", Align: AlignRight}, {Header: "UTILIZATION", Align: AlignRight},
+		},
+		Style: opts.Style,
+	}
+	for c, u := range utilization(gantt, cpus, opts.Window.From) {
+		speed := "?"
+		if c < len(opts.Speeds) {
+			speed = strconv.FormatInt(opts.Speeds[c], 10)
+		}
+		processes := strings.Join(ran[c], ", ")
+		if processes == "" {
+			processes = "(none)"
+		}
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(c), speed, processes, opts.formatTime(busy[c]), fmt.Sprintf("%.2f%%", 100*u),
+		})
+	}
+	_, _ = fmt.Fprintln(w, "Core assignment")
+	_ = table.Render(w)
+	_, _ = fmt.Fprintln(w)
+}
@@ -0,0 +1,29 @@
+package render
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// GanttCSV writes the Gantt charts of results as CSV, a row per slice with
+// its algorithm, process ID, start, stop and CPU, for charts drawn by other
+// tools. Times are in ticks, whatever opts.Tick. Only opts.Window is written
+// if it is set, but every slice in it whatever opts.GanttLimit.
+func GanttCSV(w io.Writer, results []Named, opts Options) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"algorithm", "pid", "start", "stop", "cpu"})
+	for _, n := range results {
+		for _, s := range opts.Window.Clip(n.Result.Gantt) {
+			_ = cw.Write([]string{
+				n.Algorithm,
+				strconv.FormatInt(s.PID, 10),
+				strconv.FormatInt(s.Start, 10),
+				strconv.FormatInt(s.Stop, 10),
+				strconv.Itoa(s.CPU),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
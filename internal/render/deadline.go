@@ -0,0 +1,44 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"p1/internal/scheduler"
+)
+
+// Deadlines writes how late the processes of res with a deadline completed
+// against it: how many were tardy, completing after their deadline, their
+// tardiness in all and at most, and the lateness of each, which is negative
+// for a process completing early. Nothing is written if no process has a
+// deadline.
+func Deadlines(w io.Writer, res scheduler.Result, opts Options) {
+	table := Table{
+		Columns: []Column{{Header: "ID"}, {Header: "DEADLINE"}, {Header: "EXIT"}, {Header: "LATENESS", Align: AlignRight}},
+		Style:   opts.Style,
+	}
+	var total, worst, lateness int64
+	tardy := 0
+	for _, s := range res.Stats {
+		if s.Deadline == 0 {
+			continue
+		}
+		if s.Tardiness() > 0 {
+			tardy++
+		}
+		total += s.Tardiness()
+		worst = max(worst, s.Tardiness())
+		lateness += s.Lateness()
+		table.Rows = append(table.Rows, []string{
+			strconv.FormatInt(s.ProcessID, 10), opts.formatTime(s.Deadline), opts.formatTime(s.Completion), opts.formatTime(s.Lateness()),
+		})
+	}
+	if len(table.Rows) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Deadlines: %d of %d processes tardy, tardiness %s in all and %s at most, lateness %s on average\n",
+		tardy, len(table.Rows), opts.formatTime(total), opts.formatTime(worst), opts.formatAverage(float64(lateness)/float64(len(table.Rows))))
+	_ = table.Render(w)
+	_, _ = fmt.Fprintln(w)
+}
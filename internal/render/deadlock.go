@@ -0,0 +1,42 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"p1/internal/deadlock"
+)
+
+// WaitFor writes the wait-for graph after every event of a trace as a
+// table, then each deadlock found and the event that closed its cycle.
+func WaitFor(w io.Writer, res deadlock.Result, opts Options) {
+	_, _ = fmt.Fprintln(w, "Wait-for graph")
+	table := Table{
+		Columns: []Column{{Header: "TIME"}, {Header: "EVENT"}, {Header: "WAITING FOR", MaxWidth: 60}, {Header: "DEADLOCKED"}},
+		Style:   opts.Style,
+	}
+	for _, s := range res.Steps {
+		edges := make([]string, len(s.Edges))
+		for i, e := range s.Edges {
+			edges[i] = fmt.Sprintf("%s->%s (%s)", e.From, e.To, e.Resource)
+		}
+		table.Rows = append(table.Rows, []string{
+			opts.formatTime(s.Event.Time),
+			fmt.Sprintf("%s %ss %s", s.Event.Process, s.Event.Kind, s.Event.Resource),
+			strings.Join(edges, ", "),
+			strings.Join(s.Deadlocked, ", "),
+		})
+	}
+	_ = table.Render(w)
+
+	if len(res.Deadlocks) == 0 {
+		_, _ = fmt.Fprintln(w, "No deadlocks.")
+		return
+	}
+	for _, d := range res.Deadlocks {
+		_, _ = fmt.Fprintf(w, "Deadlock at %s: event %d, %s %ss %s, closes the cycle %s->%s; deadlocked: %s\n",
+			opts.formatTime(d.Event.Time), d.Step+1, d.Event.Process, d.Event.Kind, d.Event.Resource,
+			strings.Join(d.Cycle, "->"), d.Cycle[0], strings.Join(d.Deadlocked, ", "))
+	}
+}
@@ -0,0 +1,62 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"p1/internal/disk"
+)
+
+// seekWidth is how many columns a seek chart spans from cylinder 0 to the
+// last.
+const seekWidth = 60
+
+// DiskReport outputs the title, seek chart and head movement of res, a
+// schedule of the disk p.
+func DiskReport(w io.Writer, title string, res disk.Result, p disk.Params) {
+	Title(w, title)
+	Seeks(w, res, p)
+	_, _ = fmt.Fprintf(w, "Total head movement: %d cylinders\n\n", res.Movement)
+}
+
+// Seeks writes the seek chart of res: a line per seek, the head moving
+// across the width of the disk from where the line above left it. A '*'
+// marks a request served and a '+' an end of the disk swept to.
+func Seeks(w io.Writer, res disk.Result, p disk.Params) {
+	last := strconv.FormatInt(p.Cylinders-1, 10)
+	label := len(last)
+	column := func(c int64) int {
+		if p.Cylinders <= 1 {
+			return 0
+		}
+		return int(float64(c) / float64(p.Cylinders-1) * (seekWidth - 1))
+	}
+
+	// A chart has a line per request, so write it through a buffer.
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString("Seek chart\n")
+	_, _ = fmt.Fprintf(bw, "%s0%s%s\n", spaces(label+2), spaces(seekWidth-1-len(last)), last)
+	_, _ = fmt.Fprintf(bw, "%*d  %so head\n", label, res.Head, spaces(column(res.Head)))
+	at := column(res.Head)
+	for _, s := range res.Seeks {
+		to, mark := column(s.Cylinder), byte('*')
+		if !s.Request {
+			mark = '+'
+		}
+		_, _ = fmt.Fprintf(bw, "%*d  %s", label, s.Cylinder, spaces(min(at, to)))
+		if to < at {
+			_ = bw.WriteByte(mark)
+			_, _ = bw.WriteString(strings.Repeat("-", at-to))
+		} else {
+			_, _ = bw.WriteString(strings.Repeat("-", to-at))
+			_ = bw.WriteByte(mark)
+		}
+		_ = bw.WriteByte('\n')
+		at = to
+	}
+	_ = bw.WriteByte('\n')
+	_ = bw.Flush()
+}
@@ -0,0 +1,36 @@
+package render
+
+import (
+	"fmt"
+	"io"
+
+	"p1/internal/scheduler"
+)
+
+// Estimates writes how far the burst estimates of the processes of res fell
+// from their bursts, and what that cost: the averages of exact, the result
+// of the same run with every burst known.
+func Estimates(w io.Writer, res, exact scheduler.Result, opts Options) {
+	var off, bursts int64
+	n := 0
+	for _, s := range res.Stats {
+		if s.Estimate == 0 {
+			continue
+		}
+		off += max(s.Estimate-s.BurstDuration, s.BurstDuration-s.Estimate)
+		bursts += s.BurstDuration
+		n++
+	}
+	if n == 0 {
+		return
+	}
+	share := 0.0
+	if bursts > 0 {
+		share = 100 * float64(off) / float64(bursts)
+	}
+	_, _ = fmt.Fprintf(w, "Burst estimates: %d of %d processes estimated, off by %s on average (%.2f%% of their bursts)\n",
+		n, len(res.Stats), opts.formatAverage(float64(off)/float64(n)), share)
+	_, _ = fmt.Fprintf(w, "With exact bursts: average wait %s instead of %s, turnaround %s instead of %s\n\n",
+		opts.formatAverage(exact.AveWait), opts.formatAverage(res.AveWait),
+		opts.formatAverage(exact.AveTurnaround), opts.formatAverage(res.AveTurnaround))
+}
@@ -0,0 +1,157 @@
+package render
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+
+	"p1/internal/scheduler"
+)
+
+// Explain narrates how the results of two algorithms on the same workload
+// differ: where their schedules first diverge, and which processes account
+// for the gap in average wait.
+func Explain(w io.Writer, a, b Named, opts Options) {
+	Title(w, a.Title+" vs "+b.Title)
+	explainDivergence(w, a, b, opts)
+	_, _ = fmt.Fprintln(w)
+	explainGap(w, a, b, opts)
+}
+
+// timeline returns the slices of gantt ordered by when they start, then by
+// CPU, rather than by when they end. Consecutive slices of one process on one
+// CPU are joined, since a process resuming after its own quantum expired
+// makes no visible difference.
+func timeline(gantt []scheduler.TimeSlice) []scheduler.TimeSlice {
+	sorted := slices.Clone(gantt)
+	slices.SortStableFunc(sorted, func(x, y scheduler.TimeSlice) int {
+		return cmp.Or(cmp.Compare(x.Start, y.Start), cmp.Compare(x.CPU, y.CPU))
+	})
+	var joined []scheduler.TimeSlice
+	last := map[int]int{}
+	for _, s := range sorted {
+		if i, ok := last[s.CPU]; ok && joined[i].PID == s.PID && joined[i].Stop == s.Start {
+			joined[i].Stop = s.Stop
+			continue
+		}
+		last[s.CPU] = len(joined)
+		joined = append(joined, s)
+	}
+	return joined
+}
+
+func explainDivergence(w io.Writer, a, b Named, opts Options) {
+	ga, gb := timeline(a.Result.Gantt), timeline(b.Result.Gantt)
+	i := 0
+	for i < len(ga) && i < len(gb) && ga[i] == gb[i] {
+		i++
+	}
+	if i == len(ga) && i == len(gb) {
+		_, _ = fmt.Fprintln(w, "The schedules are identical.")
+		return
+	}
+
+	if i > 0 {
+		last := ga[i-1]
+		_, _ = fmt.Fprintf(w, "The schedules agree up to %s, when P%d stops.\n", opts.formatTime(last.Stop), last.PID)
+	}
+	if i < len(ga) && i < len(gb) && ga[i].PID == gb[i].PID && ga[i].CPU == gb[i].CPU && ga[i].Start == gb[i].Start {
+		// Both run the same process but one of them stops it sooner.
+		first, long, short := ga[i], a, b
+		if gb[i].Stop > ga[i].Stop {
+			first, long, short = gb[i], b, a
+		}
+		stop := min(ga[i].Stop, gb[i].Stop)
+		_, _ = fmt.Fprintf(w, "They first diverge at %s: both run P%d on CPU %d from %s, but %s keeps it running until %s while %s takes it off at %s",
+			opts.formatTime(stop), first.PID, first.CPU, opts.formatTime(first.Start), long.Title, opts.formatTime(first.Stop), short.Title, opts.formatTime(stop))
+		if next := nextOn(timeline(short.Result.Gantt), first.CPU, stop); next != nil {
+			_, _ = fmt.Fprintf(w, " to run P%d", next.PID)
+		}
+		_, _ = fmt.Fprintln(w, ".")
+		return
+	}
+
+	describe := func(n Named, g []scheduler.TimeSlice) string {
+		if i == len(g) {
+			return n.Title + " has finished"
+		}
+		s := g[i]
+		return fmt.Sprintf("%s runs P%d on CPU %d from %s to %s", n.Title, s.PID, s.CPU, opts.formatTime(s.Start), opts.formatTime(s.Stop))
+	}
+	var at int64
+	switch {
+	case i == len(ga):
+		at = gb[i].Start
+	case i == len(gb):
+		at = ga[i].Start
+	default:
+		at = min(ga[i].Start, gb[i].Start)
+	}
+	_, _ = fmt.Fprintf(w, "They first diverge at %s: %s, while %s.\n", opts.formatTime(at), describe(a, ga), describe(b, gb))
+}
+
+// nextOn returns the first slice of gantt on CPU c starting at or after t.
+func nextOn(gantt []scheduler.TimeSlice, c int, t int64) *scheduler.TimeSlice {
+	for i := range gantt {
+		if gantt[i].CPU == c && gantt[i].Start >= t {
+			return &gantt[i]
+		}
+	}
+	return nil
+}
+
+func explainGap(w io.Writer, a, b Named, opts Options) {
+	ra, rb := a.Result, b.Result
+	_, _ = fmt.Fprintf(w, "Average wait is %s under %s and %s under %s.\n", opts.formatAverage(ra.AveWait), a.Title, opts.formatAverage(rb.AveWait), b.Title)
+	_, _ = fmt.Fprintf(w, "Average turnaround is %s under %s and %s under %s.\n", opts.formatAverage(ra.AveTurnaround), a.Title, opts.formatAverage(rb.AveTurnaround), b.Title)
+	if ra.ContextSwitches != rb.ContextSwitches {
+		_, _ = fmt.Fprintf(w, "Context switches: %d under %s, %d under %s.\n", ra.ContextSwitches, a.Title, rb.ContextSwitches, b.Title)
+	}
+
+	type gap struct {
+		stat   scheduler.Stat
+		wa, wb int64
+	}
+	var gaps []gap
+	var total int64
+	for i := range min(len(ra.Stats), len(rb.Stats)) {
+		if d := ra.Stats[i].Wait - rb.Stats[i].Wait; d != 0 {
+			gaps = append(gaps, gap{ra.Stats[i], ra.Stats[i].Wait, rb.Stats[i].Wait})
+			total += d
+		}
+	}
+	if len(gaps) == 0 {
+		_, _ = fmt.Fprintln(w, "Every process waits just as long under both.")
+		return
+	}
+	slices.SortStableFunc(gaps, func(x, y gap) int {
+		return cmp.Compare(abs(y.wa-y.wb), abs(x.wa-x.wb))
+	})
+	switch {
+	case total > 0:
+		_, _ = fmt.Fprintf(w, "In all, processes wait %s longer under %s. The gap comes from:\n", opts.formatTime(total), a.Title)
+	case total < 0:
+		_, _ = fmt.Fprintf(w, "In all, processes wait %s longer under %s. The gap comes from:\n", opts.formatTime(-total), b.Title)
+	default:
+		_, _ = fmt.Fprintln(w, "In all, processes wait just as long under both, but not the same processes:")
+	}
+	for _, g := range gaps {
+		_, _ = fmt.Fprintf(w, "  P%d waits %s under %s and %s under %s (%s)\n",
+			g.stat.ProcessID, opts.formatTime(g.wa), a.Title, opts.formatTime(g.wb), b.Title, signed(g.wa-g.wb, opts))
+	}
+}
+
+func signed(ticks int64, opts Options) string {
+	if ticks > 0 {
+		return "+" + opts.formatTime(ticks)
+	}
+	return "-" + opts.formatTime(-ticks)
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
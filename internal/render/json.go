@@ -0,0 +1,236 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"p1/internal/scheduler"
+)
+
+// SchemaVersion is the version of the JSON result document written by JSON.
+//
+// Compatibility policy: the version changes only when a field is removed or
+// renamed, or its meaning changes. New fields may be added without a version
+// change, so readers must ignore fields they do not know. ReadJSON accepts
+// every version ever written and converts it to the current one.
+//
+// Versions:
+//
+//	1  {"results": [...]}, written before documents carried a version.
+//	2  adds "schemaVersion", and "tick" when times are in real units.
+const SchemaVersion = 2
+
+var ErrUnsupportedSchema = errors.New("unsupported schema version")
+
+// Named pairs a result with the algorithm that produced it.
+type Named struct {
+	Algorithm string           `json:"algorithm"`
+	Title     string           `json:"title"`
+	Result    scheduler.Result `json:"result"`
+	// Partial is set for a run interrupted before every process completed.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// Document is the JSON result document.
+type Document struct {
+	SchemaVersion int `json:"schemaVersion"`
+	// Tick is the real length of a tick, such as 1ms, if times are meant as
+	// durations rather than bare ticks.
+	Tick    string  `json:"tick,omitempty"`
+	Results []Named `json:"results"`
+}
+
+// JSON writes results as an indented JSON document.
+func JSON(w io.Writer, results []Named, opts Options) error {
+	doc := Document{SchemaVersion: SchemaVersion, Results: results}
+	if opts.Tick > 0 {
+		doc.Tick = opts.Tick.String()
+	}
+	return writeDocument(w, doc)
+}
+
+// writeDocument writes doc as json.Encoder would with an indent of two
+// spaces, but a Gantt slice or process row at a time, so large results are
+// not held in memory a second time as JSON.
+func writeDocument(w io.Writer, doc Document) error {
+	bw := bufio.NewWriter(w)
+	d := docWriter{w: bw}
+	d.printf("{\n  \"schemaVersion\": %d,\n", doc.SchemaVersion)
+	if doc.Tick != "" {
+		d.printf("  \"tick\": ")
+		d.value("", doc.Tick)
+		d.printf(",\n")
+	}
+	d.printf("  \"results\": ")
+	array(&d, "  ", doc.Results, func(indent string, n Named) {
+		d.printf("{\n%s  \"algorithm\": ", indent)
+		d.value("", n.Algorithm)
+		d.printf(",\n%s  \"title\": ", indent)
+		d.value("", n.Title)
+		if n.Partial {
+			d.printf(",\n%s  \"partial\": true", indent)
+		}
+		d.printf(",\n%s  \"result\": ", indent)
+		d.result(indent+"  ", n.Result)
+		d.printf("\n%s}", indent)
+	})
+	d.printf("\n}\n")
+	if d.err != nil {
+		return d.err
+	}
+	return bw.Flush()
+}
+
+// docWriter writes parts of a result document, keeping the first error.
+type docWriter struct {
+	w   *bufio.Writer
+	buf []byte
+	err error
+}
+
+func (d *docWriter) printf(format string, args ...any) {
+	if d.err == nil {
+		_, d.err = fmt.Fprintf(d.w, format, args...)
+	}
+}
+
+// value writes v as indented JSON whose lines after the first start with
+// indent.
+func (d *docWriter) value(indent string, v any) {
+	if d.err != nil {
+		return
+	}
+	b, err := json.MarshalIndent(v, indent, "  ")
+	if err != nil {
+		d.err = err
+		return
+	}
+	_, d.err = d.w.Write(b)
+}
+
+func (d *docWriter) result(indent string, r scheduler.Result) {
+	d.printf("{\n%s  \"gantt\": ", indent)
+	array(d, indent+"  ", r.Gantt, d.slice)
+	d.printf(",\n%s  \"stats\": ", indent)
+	array(d, indent+"  ", r.Stats, func(indent string, s scheduler.Stat) { d.value(indent, s) })
+	for _, f := range []struct {
+		name  string
+		value any
+	}{
+		{"averageWait", r.AveWait},
+		{"averageTurnaround", r.AveTurnaround},
+		{"throughput", r.AveThroughput},
+		{"contextSwitches", r.ContextSwitches},
+	} {
+		d.printf(",\n%s  %q: ", indent, f.name)
+		d.value("", f.value)
+	}
+	d.printf("\n%s}", indent)
+}
+
+// slice writes s by hand rather than with encoding/json, the Gantt chart
+// being the bulk of most documents.
+func (d *docWriter) slice(indent string, s scheduler.TimeSlice) {
+	if d.err != nil {
+		return
+	}
+	b := d.buf[:0]
+	for i, f := range []struct {
+		name  string
+		value int64
+	}{{"pid", s.PID}, {"start", s.Start}, {"stop", s.Stop}, {"cpu", int64(s.CPU)}} {
+		if i == 0 {
+			b = append(b, '{')
+		} else {
+			b = append(b, ',')
+		}
+		b = append(append(append(b, '\n'), indent...), "  \""...)
+		b = append(append(b, f.name...), "\": "...)
+		b = strconv.AppendInt(b, f.value, 10)
+	}
+	b = append(append(append(b, '\n'), indent...), '}')
+	d.buf = b
+	_, d.err = d.w.Write(b)
+}
+
+// array writes items as a JSON array whose lines after the first start
+// with indent, using elem to write each item one level deeper.
+func array[T any](d *docWriter, indent string, items []T, elem func(indent string, item T)) {
+	if items == nil {
+		d.printf("null")
+		return
+	}
+	if len(items) == 0 {
+		d.printf("[]")
+		return
+	}
+	d.printf("[")
+	for i, item := range items {
+		if i > 0 {
+			d.printf(",")
+		}
+		d.printf("\n%s  ", indent)
+		elem(indent+"  ", item)
+		if d.err != nil {
+			return
+		}
+	}
+	d.printf("\n%s]", indent)
+}
+
+// upgrades converts a raw document of version v into one of version v+1.
+var upgrades = map[int]func(map[string]json.RawMessage) error{
+	1: func(doc map[string]json.RawMessage) error {
+		doc["schemaVersion"] = json.RawMessage("2")
+		return nil
+	},
+}
+
+// ReadJSON reads a JSON result document of any supported version, converting
+// it to the current one.
+func ReadJSON(r io.Reader) (Document, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return Document{}, fmt.Errorf("%w: reading JSON results", err)
+	}
+
+	version := 1
+	if v, ok := raw["schemaVersion"]; ok {
+		if err := json.Unmarshal(v, &version); err != nil {
+			return Document{}, fmt.Errorf("%w: %s", ErrUnsupportedSchema, v)
+		}
+	}
+	if version < 1 || version > SchemaVersion {
+		return Document{}, fmt.Errorf("%w: %d, this build reads 1 to %d", ErrUnsupportedSchema, version, SchemaVersion)
+	}
+	for ; version < SchemaVersion; version++ {
+		if err := upgrades[version](raw); err != nil {
+			return Document{}, fmt.Errorf("upgrading schema version %d: %w", version, err)
+		}
+	}
+
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return Document{}, err
+	}
+	var doc Document
+	if err := json.NewDecoder(bytes.NewReader(b)).Decode(&doc); err != nil {
+		return Document{}, fmt.Errorf("%w: reading JSON results", err)
+	}
+	return doc, nil
+}
+
+// Convert rewrites a JSON result document of any supported version in the
+// current one.
+func Convert(w io.Writer, r io.Reader) error {
+	doc, err := ReadJSON(r)
+	if err != nil {
+		return err
+	}
+	return writeDocument(w, doc)
+}
@@ -0,0 +1,112 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"p1/internal/paging"
+)
+
+// framesPerTable is how many references a frame table shows before the
+// rest carry on in another table below it.
+const framesPerTable = 20
+
+// PagingReport outputs the title, frame tables and fault count of res.
+func PagingReport(w io.Writer, title string, res paging.Result, opts Options) {
+	Title(w, title)
+	if !opts.NoGantt {
+		Frames(w, res, opts)
+	}
+	_, _ = fmt.Fprintf(w, "Page faults: %d of %d references (%.2f%%), %d hits\n\n", res.Faults, len(res.Steps), 100*res.FaultRate(), res.Hits)
+}
+
+// Frames writes the pages held by every frame after each reference, a
+// column per reference with faults marked below, framesPerTable
+// references to a table.
+func Frames(w io.Writer, res paging.Result, opts Options) {
+	_, _ = fmt.Fprintln(w, "Frames")
+	for start := 0; start < len(res.Steps); start += framesPerTable {
+		steps := res.Steps[start:min(start+framesPerTable, len(res.Steps))]
+		table := Table{Columns: []Column{{Header: "REFERENCE"}}, Style: opts.Style}
+		rows := make([][]string, res.Frames+1)
+		for f := range res.Frames {
+			rows[f] = []string{"frame " + strconv.Itoa(f)}
+		}
+		rows[res.Frames] = []string{"fault"}
+		for _, s := range steps {
+			table.Columns = append(table.Columns, Column{Header: strconv.FormatInt(s.Page, 10), Align: AlignRight})
+			for f, page := range s.Frames {
+				cell := ""
+				if page != paging.Empty {
+					cell = strconv.FormatInt(page, 10)
+				}
+				rows[f] = append(rows[f], cell)
+			}
+			fault := ""
+			if s.Fault {
+				fault = "*"
+			}
+			rows[res.Frames] = append(rows[res.Frames], fault)
+		}
+		table.Rows = rows
+		_ = table.Render(w)
+	}
+}
+
+// WorkingSets writes the working set after each reference of refs as a
+// table, with its largest and average size.
+func WorkingSets(w io.Writer, refs []int64, sets [][]int64, window int, opts Options) {
+	_, _ = fmt.Fprintf(w, "Working set (window of %d references)\n", window)
+	table := Table{
+		Columns: []Column{{Header: "REFERENCE"}, {Header: "PAGE"}, {Header: "SIZE"}, {Header: "WORKING SET", MaxWidth: 60, Align: AlignLeft}},
+		Style:   opts.Style,
+	}
+	largest, total := 0, 0
+	for i, set := range sets {
+		table.Rows = append(table.Rows, []string{strconv.Itoa(i + 1), strconv.FormatInt(refs[i], 10), strconv.Itoa(len(set)), counts(set)})
+		largest, total = max(largest, len(set)), total+len(set)
+	}
+	_ = table.Render(w)
+	if len(sets) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Working set size: at most %d pages, %.2f on average; with fewer frames than that the process thrashes.\n",
+		largest, float64(total)/float64(len(sets)))
+}
+
+// FaultCurves writes the faults of each curve by frame count as a table,
+// a column per algorithm, then every Belady's anomaly in them.
+func FaultCurves(w io.Writer, curves []paging.Curve, opts Options) {
+	_, _ = fmt.Fprintln(w, "Page faults by frame count")
+	table := Table{Columns: []Column{{Header: "FRAMES"}}, Style: opts.Style}
+	frames := 0
+	for _, c := range curves {
+		table.Columns = append(table.Columns, Column{Header: strings.ToUpper(c.Title), Align: AlignRight})
+		frames = max(frames, len(c.Faults))
+	}
+	for n := range frames {
+		row := []string{strconv.Itoa(n + 1)}
+		for _, c := range curves {
+			cell := ""
+			if n < len(c.Faults) {
+				cell = strconv.Itoa(c.Faults[n])
+			}
+			row = append(row, cell)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	_ = table.Render(w)
+	anomalies := false
+	for _, c := range curves {
+		for _, n := range c.Anomalies() {
+			_, _ = fmt.Fprintf(w, "Belady's anomaly: %s faults %d times with %d frames, more than %d with %d.\n",
+				c.Title, c.Faults[n-1], n, c.Faults[n-2], n-1)
+			anomalies = true
+		}
+	}
+	if !anomalies {
+		_, _ = fmt.Fprintln(w, "No algorithm faults more with more frames.")
+	}
+}
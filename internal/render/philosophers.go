@@ -0,0 +1,72 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"p1/internal/philosophers"
+)
+
+// timelineTicks is the most ticks drawn in a philosophers timeline.
+const timelineTicks = 80
+
+// stateMarks are the letters of a philosophers timeline.
+var stateMarks = map[philosophers.State]byte{philosophers.Thinking: 'T', philosophers.Hungry: 'H', philosophers.Eating: 'E'}
+
+// Dining writes a dining philosophers run: a timeline of what each
+// philosopher did every tick, unless opts.NoGantt is set, a table of their
+// meals and waits, and any deadlock or starvation.
+func Dining(w io.Writer, res philosophers.Result, opts Options) {
+	if !opts.NoGantt && res.End > 0 {
+		ticks := min(res.End, timelineTicks)
+		_, _ = fmt.Fprintln(w, "Timeline (T thinking, H hungry, E eating)")
+		for _, p := range res.Philosophers {
+			line := make([]byte, 0, ticks)
+			for _, period := range p.Periods {
+				for t := period.Start; t < min(period.Stop, ticks); t++ {
+					line = append(line, stateMarks[period.State])
+				}
+			}
+			_, _ = fmt.Fprintf(w, "%-4s %s\n", p.Name, line)
+		}
+		if ticks < res.End {
+			_, _ = fmt.Fprintf(w, "(first %d of %d ticks shown)\n", ticks, res.End)
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+
+	table := Table{
+		Columns: []Column{{Header: "PHILOSOPHER"}, {Header: "MEALS"}, {Header: "EATING"}, {Header: "HUNGRY"}, {Header: "LONGEST WAIT"}, {Header: "STARVING"}},
+		Style:   opts.Style,
+	}
+	for _, p := range res.Philosophers {
+		starving := ""
+		if p.Starving {
+			starving = "yes"
+		}
+		table.Rows = append(table.Rows, []string{
+			p.Name, strconv.Itoa(p.Meals), opts.formatTime(p.Eating), opts.formatTime(p.Hungry), opts.formatTime(p.LongestWait), starving,
+		})
+	}
+	_ = table.Render(w)
+
+	if d := res.Deadlock; d != nil {
+		names := make([]string, len(d.Cycle))
+		for i, p := range d.Cycle {
+			names[i] = res.Philosophers[p].Name
+		}
+		_, _ = fmt.Fprintf(w, "Deadlock at %s: %s each hold a fork the next is waiting for.\n", opts.formatTime(d.Time), strings.Join(names, ", "))
+	} else {
+		_, _ = fmt.Fprintln(w, "No deadlock.")
+	}
+	if starving := res.Starving(); len(starving) > 0 {
+		names := make([]string, len(starving))
+		for i, p := range starving {
+			names[i] = res.Philosophers[p].Name
+		}
+		_, _ = fmt.Fprintf(w, "Starving: %s\n", strings.Join(names, ", "))
+	}
+	_, _ = fmt.Fprintln(w)
+}
@@ -0,0 +1,69 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"p1/internal/scheduler"
+)
+
+// umlStates are the names of each state in a PlantUML timing diagram.
+var umlStates = [...]string{stateReady: "Ready", stateBlocked: "Blocked", stateRunning: "Running"}
+
+// PlantUML writes results as PlantUML timing diagrams, one after the other,
+// each with a lifeline per process moving between running, ready and
+// blocked by one of opts.Suspensions from its arrival to its completion, as
+// a Timeline draws them. Times are in ticks, whatever opts.Tick. Only
+// opts.Window is drawn if it is set, and at most opts.GanttLimit lifelines
+// if it is positive.
+func PlantUML(w io.Writer, results []Named, opts Options) error {
+	bw := bufio.NewWriter(w)
+	for _, n := range results {
+		timingDiagram(bw, n, opts)
+	}
+	return bw.Flush()
+}
+
+// timingDiagram writes the timing diagram of n.
+func timingDiagram(w *bufio.Writer, n Named, opts Options) {
+	ran := make(map[int64][]scheduler.TimeSlice)
+	for _, s := range opts.Window.Clip(n.Result.Gantt) {
+		ran[s.PID] = append(ran[s.PID], s)
+	}
+	stats := n.Result.Stats
+	if opts.GanttLimit > 0 && len(stats) > opts.GanttLimit {
+		stats = stats[:opts.GanttLimit]
+	}
+
+	_, _ = fmt.Fprintf(w, "@startuml\ntitle %s\n", opts.heading(n.Title))
+	// Lifelines are named by their place, as processes may share an ID.
+	changes := make(map[int64][]string)
+	for i, s := range stats {
+		_, _ = fmt.Fprintf(w, "concise \"P%d\" as p%d\n", s.ProcessID, i)
+		completion := s.Completion
+		if !opts.Window.IsZero() {
+			s.ArrivalTime = max(s.ArrivalTime, opts.Window.From)
+			s.Completion = max(s.ArrivalTime, min(s.Completion, opts.Window.To))
+		}
+		phases := lifeline(s, ran[s.ProcessID], opts.Suspensions)
+		for _, p := range phases {
+			changes[p.start] = append(changes[p.start], fmt.Sprintf("p%d is %s", i, umlStates[p.state]))
+		}
+		// A lifeline leaving the window before its process completes
+		// runs on to the diagram's end.
+		if len(phases) > 0 && phases[len(phases)-1].stop == completion {
+			changes[completion] = append(changes[completion], fmt.Sprintf("p%d is {-}", i))
+		}
+	}
+	for _, t := range slices.Sorted(maps.Keys(changes)) {
+		_, _ = fmt.Fprintf(w, "\n@%d\n%s\n", t, strings.Join(changes[t], "\n"))
+	}
+	if len(stats) < len(n.Result.Stats) {
+		_, _ = fmt.Fprintf(w, "\ncaption first %d of %d processes shown\n", len(stats), len(n.Result.Stats))
+	}
+	_, _ = w.WriteString("@enduml\n")
+}
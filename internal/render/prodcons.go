@@ -0,0 +1,37 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"p1/internal/prodcons"
+)
+
+// Buffer writes a producer-consumer run: a chart of how many items the
+// buffer held over time, unless opts.NoGantt is set, and a table of how
+// long each producer and consumer worked and blocked.
+func Buffer(w io.Writer, res prodcons.Result, opts Options) {
+	if !opts.NoGantt {
+		chart(w, "Buffer occupancy", "levels", len(res.Occupancy), func(b []byte, i int) ([]byte, int64, int64) {
+			l := res.Occupancy[i]
+			return strconv.AppendInt(b, int64(l.Items), 10), l.Start, l.Stop
+		}, opts)
+	}
+
+	_, _ = fmt.Fprintln(w, "Blocking times")
+	table := Table{
+		Columns: []Column{{Header: "AGENT"}, {Header: "ROLE"}, {Header: "ITEMS"}, {Header: "BUSY"}, {Header: "BLOCKED"}, {Header: "WAITS"}},
+		Style:   opts.Style,
+	}
+	var blocked int64
+	for _, a := range res.Agents {
+		table.Rows = append(table.Rows, []string{
+			a.Name, string(a.Role), strconv.Itoa(a.Items), opts.formatTime(a.Busy), opts.formatTime(a.Blocked), strconv.Itoa(len(a.Waits)),
+		})
+		blocked += a.Blocked
+	}
+	_ = table.Render(w)
+	_, _ = fmt.Fprintf(w, "Blocked for %s in all; the buffer of %d held at most %d items, %.2f on average, until %s\n",
+		opts.formatTime(blocked), res.Params.Capacity, res.MaxOccupancy(), res.MeanOccupancy(), opts.formatTime(res.End))
+}
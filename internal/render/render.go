@@ -0,0 +1,335 @@
+// Package render writes scheduler results as text: a title banner, an ASCII
+// Gantt chart or timeline and a table of per-process timings. Results can
+// also be drawn as SVG, as an HTML page of charts and tables, or as PlantUML
+// timing diagrams, and their Gantt charts written as CSV.
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"p1/internal/scheduler"
+)
+
+// Options controls how results are written.
+type Options struct {
+	// Tick is the real duration of one tick. When set, times are written
+	// as durations such as 5ms instead of bare tick counts.
+	Tick time.Duration
+	// Style is how the schedule table is drawn.
+	Style TableStyle
+	// GanttLimit, if positive, is the most slices drawn in a Gantt chart.
+	// Longer charts are cut short with a note; JSON output always has
+	// every slice.
+	GanttLimit int
+	// NoGantt leaves the Gantt chart out of reports, and the frame tables
+	// out of paging reports.
+	NoGantt bool
+	// Window restricts Gantt charts to a span of time, see Windowed for
+	// the timings within it.
+	Window Window
+	// Timeline draws a Timeline in reports in place of the Gantt chart,
+	// with processes blocked while Suspensions hold them.
+	Timeline    bool
+	Suspensions []scheduler.Suspension
+	// FairShare holds the group shares Shares reports against.
+	FairShare scheduler.FairShareParams
+	// Speeds holds the speeds of the CPUs Cores reports on.
+	Speeds []int64
+	// CPUs is the number of CPUs schedules ran on, so that charts give a
+	// row to those left idle. Otherwise it is taken from the slices.
+	CPUs int
+}
+
+// DefaultGanttLimit is the GanttLimit of command line output.
+const DefaultGanttLimit = 500
+
+// cpus returns the number of CPUs gantt ran on: opts.CPUs, or more if
+// gantt or opts.Speeds have more.
+func (o Options) cpus(gantt []scheduler.TimeSlice) int {
+	return max(o.CPUs, len(o.Speeds), cpuCount(gantt))
+}
+
+// formatTime writes a time or length of time given in ticks.
+func (o Options) formatTime(ticks int64) string {
+	if o.Tick == 0 {
+		return strconv.FormatInt(ticks, 10)
+	}
+	return (time.Duration(ticks) * o.Tick).String()
+}
+
+// formatAverage writes an average time given in ticks.
+func (o Options) formatAverage(ticks float64) string {
+	if o.Tick == 0 {
+		return fmt.Sprintf("%.2f", ticks)
+	}
+	return time.Duration(ticks * float64(o.Tick)).Round(time.Microsecond).String()
+}
+
+// formatThroughput writes a throughput given in processes per tick.
+func (o Options) formatThroughput(perTick float64) string {
+	if o.Tick == 0 {
+		return fmt.Sprintf("%.2f/T", perTick)
+	}
+	return fmt.Sprintf("%.2f/s", perTick/o.Tick.Seconds())
+}
+
+// Report outputs the title, Gantt chart and schedule table of res.
+func Report(w io.Writer, title string, res scheduler.Result, opts Options) {
+	Title(w, title)
+	switch {
+	case opts.NoGantt:
+	case opts.Timeline:
+		Timeline(w, res, opts)
+	default:
+		Gantt(w, res.Gantt, opts)
+	}
+	Schedule(w, res, opts)
+}
+
+func Title(w io.Writer, title string) {
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+}
+
+// Gantt writes gantt as a Gantt chart. A schedule across several CPUs gets
+// a row per CPU, cut at every time a slice on any of them starts or stops so
+// the rows line up, followed by how busy each CPU was. A chart restricted
+// to opts.Window shows only the parts of slices within it.
+func Gantt(w io.Writer, gantt []scheduler.TimeSlice, opts Options) {
+	gantt = opts.Window.Clip(gantt)
+	cpus := opts.cpus(gantt)
+	if cpus > 1 {
+		cpuGantt(w, gantt, cpus, opts)
+		return
+	}
+	chart(w, opts.heading("Gantt schedule"), "slices", len(gantt), func(b []byte, i int) ([]byte, int64, int64) {
+		return strconv.AppendInt(b, gantt[i].PID, 10), gantt[i].Start, gantt[i].Stop
+	}, opts)
+}
+
+// chart writes a chart in the form of a Gantt chart under heading: a row of
+// n cells, each labelled and spanning the ticks cell returns for it, with
+// the times they start below. what names the cells in the note on a chart
+// cut short by opts.GanttLimit.
+func chart(w io.Writer, heading, what string, n int, cell func(label []byte, i int) ([]byte, int64, int64), opts Options) {
+	all := n
+	if opts.GanttLimit > 0 && all > opts.GanttLimit {
+		n = opts.GanttLimit
+	}
+	// A chart has a cell per slice, so write it through a buffer rather
+	// than a call to w per cell.
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString(heading)
+	_, _ = bw.WriteString("\n|")
+	var label []byte
+	for i := range n {
+		label, _, _ = cell(label[:0], i)
+		padding := spaces((8 - len(label)) / 2)
+		_, _ = bw.WriteString(padding)
+		_, _ = bw.Write(label)
+		_, _ = bw.WriteString(padding)
+		_ = bw.WriteByte('|')
+	}
+	_ = bw.WriteByte('\n')
+	for i := range n {
+		var start, stop int64
+		label, start, stop = cell(label[:0], i)
+		_, _ = bw.WriteString(opts.formatTime(start))
+		_ = bw.WriteByte('\t')
+		if n-1 == i {
+			_, _ = bw.WriteString(opts.formatTime(stop))
+		}
+	}
+	_ = bw.WriteByte('\n')
+	if n < all {
+		_, _ = fmt.Fprintf(bw, "(first %d of %d %s shown)\n", n, all, what)
+	}
+	_ = bw.WriteByte('\n')
+	_ = bw.Flush()
+}
+
+// cpuGantt writes the Gantt chart of gantt with a row for each of cpus
+// CPUs. Each column spans the time between two slice boundaries, with a
+// dash for a CPU left idle, and a run of columns alike on a row is drawn
+// as one cell spanning them.
+func cpuGantt(w io.Writer, gantt []scheduler.TimeSlice, cpus int, opts Options) {
+	times := boundaries(gantt)
+	if len(times) < 2 {
+		chart(w, opts.heading("Gantt schedule"), "columns", 0, nil, opts)
+		return
+	}
+	n := len(times) - 1
+	all := n
+	if opts.GanttLimit > 0 && all > opts.GanttLimit {
+		n = opts.GanttLimit
+	}
+	// rows holds the label of each column of each CPU's row.
+	rows := make([][]string, cpus)
+	for c := range rows {
+		rows[c] = make([]string, n)
+	}
+	for _, s := range gantt {
+		k, _ := slices.BinarySearch(times, s.Start)
+		for ; k < n && times[k] < s.Stop; k++ {
+			rows[s.CPU][k] = strconv.FormatInt(s.PID, 10)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString(opts.heading("Gantt schedule"))
+	_ = bw.WriteByte('\n')
+	for c, row := range rows {
+		_, _ = fmt.Fprintf(bw, "CPU %d\t|", c)
+		for k := 0; k < len(row); {
+			span := 1
+			for k+span < len(row) && row[k+span] == row[k] {
+				span++
+			}
+			label := row[k]
+			if label == "" {
+				label = "-"
+			}
+			// A cell spanning columns is as wide as they would be, bars
+			// between them included.
+			width := span*(len(label)+(8-len(label))/2*2) + span - 1
+			left := (width - len(label)) / 2
+			_, _ = bw.WriteString(spaces(left))
+			_, _ = bw.WriteString(label)
+			_, _ = bw.WriteString(spaces(width - len(label) - left))
+			_ = bw.WriteByte('|')
+			k += span
+		}
+		_ = bw.WriteByte('\n')
+	}
+	for _, t := range times[:n+1] {
+		_ = bw.WriteByte('\t')
+		_, _ = bw.WriteString(opts.formatTime(t))
+	}
+	_ = bw.WriteByte('\n')
+	if n < all {
+		_, _ = fmt.Fprintf(bw, "(first %d of %d columns shown)\n", n, all)
+	}
+	busy := make([]string, cpus)
+	for c, u := range utilization(gantt, cpus, opts.Window.From) {
+		busy[c] = fmt.Sprintf("CPU %d %.2f%%", c, 100*u)
+	}
+	_, _ = fmt.Fprintf(bw, "Utilization: %s\n\n", strings.Join(busy, ", "))
+	_ = bw.Flush()
+}
+
+// cpuCount returns how many CPUs gantt runs on, counting up to the highest
+// numbered.
+func cpuCount(gantt []scheduler.TimeSlice) int {
+	n := 0
+	for _, s := range gantt {
+		n = max(n, s.CPU+1)
+	}
+	return n
+}
+
+// boundaries returns every time a slice of gantt starts or stops, in
+// ascending order.
+func boundaries(gantt []scheduler.TimeSlice) []int64 {
+	times := make([]int64, 0, 2*len(gantt))
+	for _, s := range gantt {
+		times = append(times, s.Start, s.Stop)
+	}
+	slices.Sort(times)
+	return slices.Compact(times)
+}
+
+// utilization returns the share of the time from from to the end of gantt
+// each of cpus CPUs spent running a process.
+func utilization(gantt []scheduler.TimeSlice, cpus int, from int64) []float64 {
+	busy := make([]float64, cpus)
+	end := from
+	for _, s := range gantt {
+		busy[s.CPU] += float64(s.Stop - s.Start)
+		end = max(end, s.Stop)
+	}
+	if end > from {
+		for c := range busy {
+			busy[c] /= float64(end - from)
+		}
+	}
+	return busy
+}
+
+// heading returns the heading of a text chart, naming the window it is
+// restricted to.
+func (o Options) heading(chart string) string {
+	if o.Window.IsZero() {
+		return chart
+	}
+	return chart + " " + o.Window.format(o)
+}
+
+// spaces returns n spaces, or none if n is negative.
+func spaces(n int) string {
+	const blank = "                                "
+	if n <= 0 {
+		return ""
+	}
+	if n <= len(blank) {
+		return blank[:n]
+	}
+	return strings.Repeat(" ", n)
+}
+
+func Schedule(w io.Writer, res scheduler.Result, opts Options) {
+	_, _ = fmt.Fprintln(w, "Schedule table")
+	table := scheduleTable(opts)
+	table.Footer = scheduleFooter(res, opts)
+	// The rows are formatted as drawn rather than held, a table having a
+	// row per process.
+	table.Body = func(yield func([]string) bool) {
+		row := make([]string, len(table.Columns))
+		for _, s := range res.Stats {
+			if !yield(scheduleRow(row, s, opts)) {
+				return
+			}
+		}
+	}
+	_ = table.Render(w)
+}
+
+// scheduleTable returns a schedule table without rows.
+func scheduleTable(opts Options) Table {
+	return Table{
+		Columns: []Column{
+			{Header: "ID"}, {Header: "PRIORITY"}, {Header: "BURST"}, {Header: "ARRIVAL"},
+			{Header: "WAIT"}, {Header: "TURNAROUND"}, {Header: "EXIT"},
+		},
+		Style: opts.Style,
+	}
+}
+
+// scheduleRow fills row with the cells of s and returns it.
+func scheduleRow(row []string, s scheduler.Stat, opts Options) []string {
+	row[0] = strconv.FormatInt(s.ProcessID, 10)
+	row[1] = strconv.FormatInt(s.Priority, 10)
+	row[2] = opts.formatTime(s.BurstDuration)
+	row[3] = opts.formatTime(s.ArrivalTime)
+	row[4] = opts.formatTime(s.Wait)
+	row[5] = opts.formatTime(s.Turnaround)
+	row[6] = opts.formatTime(s.Completion)
+	return row
+}
+
+// scheduleFooter returns the footer rows of the schedule table of res.
+func scheduleFooter(res scheduler.Result, opts Options) [][]string {
+	return [][]string{
+		{"", "", "", "", "AVERAGE", "AVERAGE", "THROUGHPUT"},
+		{"", "", "", "",
+			opts.formatAverage(res.AveWait),
+			opts.formatAverage(res.AveTurnaround),
+			opts.formatThroughput(res.AveThroughput)},
+	}
+}
@@ -0,0 +1,895 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"p1/internal/banker"
+	"p1/internal/deadlock"
+	"p1/internal/disk"
+	"p1/internal/paging"
+	"p1/internal/philosophers"
+	"p1/internal/prodcons"
+	"p1/internal/scheduler"
+	"p1/internal/tlb"
+)
+
+func TestTitle(t *testing.T) {
+	var buf bytes.Buffer
+	Title(&buf, "FCFS")
+	want := "--------\n   FCFS\n--------\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestGantt(t *testing.T) {
+	var buf bytes.Buffer
+	Gantt(&buf, []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 12, Start: 5, Stop: 9}}, Options{})
+	want := "Gantt schedule\n|   1   |   12   |\n0\t5\t9\n\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestGanttLimit(t *testing.T) {
+	gantt := []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 5}, {PID: 1, Start: 5, Stop: 6}}
+	var buf bytes.Buffer
+	Gantt(&buf, gantt, Options{GanttLimit: 2})
+	want := "Gantt schedule\n|   1   |   2   |\n0\t2\t5\n(first 2 of 3 slices shown)\n\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestGanttCPUs(t *testing.T) {
+	gantt := []scheduler.TimeSlice{{PID: 2, Start: 0, Stop: 3, CPU: 1}, {PID: 1, Start: 0, Stop: 4}, {PID: 3, Start: 5, Stop: 6, CPU: 1}}
+	var buf bytes.Buffer
+	Gantt(&buf, gantt, Options{})
+	// Runs of a process, or of idling, on a CPU are drawn as one cell.
+	want := "Gantt schedule\n" +
+		"CPU 0\t|       1       |       -       |\n" +
+		"CPU 1\t|   2   |       -       |   3   |\n" +
+		"\t0\t3\t4\t5\t6\n" +
+		"Utilization: CPU 0 66.67%, CPU 1 66.67%\n\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	// A CPU left idle throughout still gets a row.
+	buf.Reset()
+	Gantt(&buf, gantt, Options{CPUs: 3})
+	if want := "CPU 2\t|               -               |\n"; !strings.Contains(buf.String(), want) || !strings.Contains(buf.String(), "CPU 2 0.00%") {
+		t.Errorf("chart of 3 CPUs:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	Gantt(&buf, gantt, Options{GanttLimit: 2})
+	if !strings.Contains(buf.String(), "\t0\t3\t4\n(first 2 of 4 columns shown)\n") {
+		t.Errorf("limited chart:\n%s", buf.String())
+	}
+}
+
+func TestGanttEmptyWindow(t *testing.T) {
+	gantt := []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 3, Stop: 12, CPU: 1}}
+	var buf bytes.Buffer
+	Gantt(&buf, gantt, Options{Window: Window{From: 100, To: 200}})
+	if want := "Gantt schedule from 100 to 200\n|\n\n\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	buf.Reset()
+	cpuGantt(&buf, nil, 2, Options{})
+	if want := "Gantt schedule\n|\n\n\n"; buf.String() != want {
+		t.Errorf("no slices on 2 CPUs: got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestSVG(t *testing.T) {
+	results := []Named{
+		{Title: "One <CPU>", Result: scheduler.Result{Gantt: []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 4}}}},
+		{Title: "Two", Result: scheduler.Result{Gantt: []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 8}, {PID: 2, Start: 0, Stop: 2, CPU: 1}}}},
+	}
+	var buf bytes.Buffer
+	if err := SVG(&buf, results, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="920" height="184"`,
+		"One &lt;CPU&gt;",
+		// Both charts share a scale, so the first ends halfway along.
+		`<rect x="56.0" y="26" width="400.0"`,
+		`<rect x="56.0" y="106" width="800.0"`,
+		`<title>P2: 0 to 2</title>`,
+		`>CPU 1</text>`,
+		`>25.00%</text>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	res := scheduler.FCFS([]scheduler.Process{{ProcessID: 1, BurstDuration: 2}})
+	var buf bytes.Buffer
+	if err := HTML(&buf, []Named{{Algorithm: "fcfs", Title: "First-come, first-serve", Result: res}}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"<h2>First-come, first-serve</h2>", "<svg", "<th>TURNAROUND</th>", "<tr><td>1</td><td>0</td><td>2</td>", "<td>0.50/T</td>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	_ = HTML(&buf, []Named{{Title: "FCFS", Result: res}}, Options{NoGantt: true})
+	if strings.Contains(buf.String(), "<svg") {
+		t.Errorf("chart drawn with NoGantt:\n%s", buf.String())
+	}
+}
+
+func TestPlantUML(t *testing.T) {
+	res := scheduler.Result{
+		Gantt: []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 5}, {PID: 1, Start: 5, Stop: 7}},
+		Stats: []scheduler.Stat{
+			{Process: scheduler.Process{ProcessID: 1, BurstDuration: 5}, Completion: 7},
+			{Process: scheduler.Process{ProcessID: 2, BurstDuration: 2, ArrivalTime: 1}, Completion: 5},
+		},
+	}
+	opts := Options{Suspensions: []scheduler.Suspension{{PID: 2, From: 1, To: 2}}}
+	var buf bytes.Buffer
+	if err := PlantUML(&buf, []Named{{Title: "FCFS", Result: res}}, opts); err != nil {
+		t.Fatal(err)
+	}
+	want := "@startuml\ntitle FCFS\nconcise \"P1\" as p0\nconcise \"P2\" as p1\n" +
+		"\n@0\np0 is Running\n" +
+		"\n@1\np1 is Blocked\n" +
+		"\n@2\np1 is Ready\n" +
+		"\n@3\np0 is Ready\np1 is Running\n" +
+		"\n@5\np0 is Running\np1 is {-}\n" +
+		"\n@7\np0 is {-}\n" +
+		"@enduml\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	opts.Window, opts.GanttLimit = Window{From: 4, To: 6}, 1
+	_ = PlantUML(&buf, []Named{{Title: "FCFS", Result: res}}, opts)
+	want = "@startuml\ntitle FCFS from 4 to 6\nconcise \"P1\" as p0\n" +
+		"\n@4\np0 is Ready\n" +
+		"\n@5\np0 is Running\n" +
+		"\ncaption first 1 of 2 processes shown\n" +
+		"@enduml\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestGanttCSV(t *testing.T) {
+	results := []Named{
+		{Algorithm: "fcfs", Result: scheduler.Result{Gantt: []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 9, CPU: 1}}}},
+		{Algorithm: "rr", Result: scheduler.Result{Gantt: []scheduler.TimeSlice{{PID: 2, Start: 2, Stop: 4}}}},
+	}
+	var buf bytes.Buffer
+	if err := GanttCSV(&buf, results, Options{Tick: time.Millisecond, GanttLimit: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "algorithm,pid,start,stop,cpu\nfcfs,1,0,5,0\nfcfs,2,5,9,1\nrr,2,2,4,0\n"; buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	_ = GanttCSV(&buf, results, Options{Window: Window{From: 3, To: 6}})
+	if want := "algorithm,pid,start,stop,cpu\nfcfs,1,3,5,0\nfcfs,2,5,6,1\nrr,2,3,4,0\n"; buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestEstimates(t *testing.T) {
+	res := scheduler.Result{
+		Stats: []scheduler.Stat{
+			{Process: scheduler.Process{BurstDuration: 8, Estimate: 2}},
+			{Process: scheduler.Process{BurstDuration: 4}},
+			{Process: scheduler.Process{BurstDuration: 3, Estimate: 6}},
+		},
+		AveWait: 6.5, AveTurnaround: 11,
+	}
+	var buf bytes.Buffer
+	Estimates(&buf, res, scheduler.Result{AveWait: 3, AveTurnaround: 8}, Options{})
+	want := "Burst estimates: 2 of 3 processes estimated, off by 4.50 on average (81.82% of their bursts)\n" +
+		"With exact bursts: average wait 3.00 instead of 6.50, turnaround 8.00 instead of 11.00\n\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if Estimates(&buf, scheduler.Result{Stats: res.Stats[1:2]}, res, Options{}); buf.Len() != 0 {
+		t.Errorf("wrote %q without estimates", buf.String())
+	}
+}
+
+func TestDeadlines(t *testing.T) {
+	res := scheduler.Result{
+		Stats: []scheduler.Stat{
+			{Process: scheduler.Process{ProcessID: 1, Deadline: 6}, Completion: 4},
+			{Process: scheduler.Process{ProcessID: 2}, Completion: 9},
+			{Process: scheduler.Process{ProcessID: 3, Deadline: 5}, Completion: 12},
+			{Process: scheduler.Process{ProcessID: 4, Deadline: 10}, Completion: 11},
+		},
+	}
+	var buf bytes.Buffer
+	Deadlines(&buf, res, Options{Style: StylePlain})
+	for _, want := range []string{
+		"Deadlines: 2 of 3 processes tardy, tardiness 8 in all and 7 at most, lateness 2.00 on average\n",
+		" 1         6     4        -2\n",
+		" 3         5    12         7\n",
+		" 4        10    11         1\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "\n 2 ") {
+		t.Errorf("P2, without a deadline, is listed:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if Deadlines(&buf, scheduler.Result{Stats: res.Stats[1:2]}, Options{}); buf.Len() != 0 {
+		t.Errorf("wrote %q without deadlines", buf.String())
+	}
+}
+
+func TestCores(t *testing.T) {
+	res := scheduler.Result{
+		Gantt: []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 3, CPU: 1}, {PID: 2, Start: 3, Stop: 8, CPU: 1}, {PID: 3, Start: 6, Stop: 12, CPU: 0}, {PID: 1, Start: 8, Stop: 9, CPU: 1}},
+	}
+	var buf bytes.Buffer
+	Cores(&buf, res, Options{Style: StylePlain, Speeds: []int64{1, 2, 1}})
+	for _, want := range []string{
+		"Core assignment\n",
+		"  0      1          3     6       50.00%\n",
+		"  1      2  1, 2          9       75.00%\n",
+		"  2      1  (none)        0        0.00%\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if Cores(&buf, res, Options{}); buf.Len() != 0 {
+		t.Errorf("wrote %q without speeds", buf.String())
+	}
+}
+
+func TestWindowed(t *testing.T) {
+	res := scheduler.Result{
+		Gantt: []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 9}, {PID: 3, Start: 12, Stop: 14}},
+		Stats: []scheduler.Stat{
+			{Process: scheduler.Process{ProcessID: 1, BurstDuration: 5}, Completion: 5},
+			{Process: scheduler.Process{ProcessID: 2, BurstDuration: 4, ArrivalTime: 3}, Completion: 9},
+			{Process: scheduler.Process{ProcessID: 3, BurstDuration: 2, ArrivalTime: 12}, Completion: 14},
+		},
+	}
+	opts := Options{Window: Window{From: 4, To: 10}}
+	var buf bytes.Buffer
+	Gantt(&buf, res.Gantt, opts)
+	if want := "Gantt schedule from 4 to 10\n|   1   |   2   |\n4\t5\t9\n\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	Windowed(&buf, res, opts)
+	want := "Window from 4 to 10\n" +
+		"+----+--------+---------+------------+\n" +
+		"| ID |  RUN   |  WAIT   |    EXIT    |\n" +
+		"+----+--------+---------+------------+\n" +
+		"|  1 |      1 |       0 |          5 |\n" +
+		"|  2 |      4 |       1 |          9 |\n" +
+		"+----+--------+---------+------------+\n" +
+		"|       BUSY  | AVERAGE | THROUGHPUT |\n" +
+		"|      83.33% |  0.50   |   0.33/T   |\n" +
+		"+----+--------+---------+------------+\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if Windowed(&buf, res, Options{}); buf.Len() != 0 {
+		t.Errorf("wrote %q without a window", buf.String())
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	res := scheduler.Result{
+		Gantt: []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 5}, {PID: 1, Start: 5, Stop: 7}},
+		Stats: []scheduler.Stat{
+			{Process: scheduler.Process{ProcessID: 1, BurstDuration: 5}, Completion: 7},
+			{Process: scheduler.Process{ProcessID: 2, BurstDuration: 2, ArrivalTime: 1}, Completion: 5},
+		},
+	}
+	opts := Options{Suspensions: []scheduler.Suspension{{PID: 2, From: 1, To: 2}}}
+	var buf bytes.Buffer
+	Timeline(&buf, res, opts)
+	want := "Timeline\n" +
+		"P1 |███··██|\n" +
+		"P2 | ~·██  |\n" +
+		"    0\n" +
+		"█ running, · ready, ~ blocked\n\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	opts.Window, opts.GanttLimit = Window{From: 3, To: 6}, 1
+	Timeline(&buf, res, opts)
+	want = "Timeline from 3 to 6\n" +
+		"P1 |··█|\n" +
+		"    3\n" +
+		"(first 1 of 2 processes shown)\n" +
+		"█ running, · ready, ~ blocked\n\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	res = scheduler.Result{
+		Gantt: []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 200}},
+		Stats: []scheduler.Stat{{Process: scheduler.Process{ProcessID: 1, BurstDuration: 200}, Completion: 200}},
+	}
+	Timeline(&buf, res, Options{})
+	if want := "P1 |" + strings.Repeat("█", 67) + "|\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("got\n%s\nwant a row of 67 columns", buf.String())
+	}
+	if want := "; each column is 3\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("got\n%s\nwant %q", buf.String(), want)
+	}
+}
+
+func TestShares(t *testing.T) {
+	res := scheduler.Result{
+		Gantt: []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 4, Stop: 6}, {PID: 3, Start: 6, Stop: 7}},
+		Stats: []scheduler.Stat{
+			{Process: scheduler.Process{ProcessID: 1, Group: "web"}, Completion: 4},
+			{Process: scheduler.Process{ProcessID: 2, ArrivalTime: 2, Group: "batch"}, Completion: 6},
+			{Process: scheduler.Process{ProcessID: 3, ArrivalTime: 5}, Completion: 7},
+		},
+	}
+	opts := Options{Style: StylePlain, FairShare: scheduler.FairShareParams{Shares: []scheduler.GroupShare{{Group: "web", Share: 3072}}}}
+	var buf bytes.Buffer
+	Shares(&buf, res, opts)
+	// Web and batch contend from 2 to 4, and batch and the ungrouped P3
+	// from 5 to 6.
+	for _, want := range []string{
+		"Group shares of the 3 of CPU time while groups contended\n",
+		"(none)          1   1024      16.67%     0.00%",
+		"batch           1   1024      33.33%    33.33%",
+		"web             1   3072      50.00%    66.67%",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	for i := range res.Stats {
+		res.Stats[i].Group = "web"
+	}
+	if Shares(&buf, res, opts); buf.Len() != 0 {
+		t.Errorf("wrote %q for a single group", buf.String())
+	}
+}
+
+func TestBoost(t *testing.T) {
+	opts := Options{Suspensions: []scheduler.Suspension{{PID: 1, From: 2, To: 4}, {PID: 2, From: 1, To: 3}, {PID: 3, From: 0, To: 9}}}
+	res := scheduler.Result{Gantt: []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 3, Stop: 5}, {PID: 1, Start: 5, Stop: 7}}}
+	unboosted := scheduler.Result{Gantt: []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 1, Start: 4, Stop: 6}, {PID: 2, Start: 6, Stop: 8}}}
+	var buf bytes.Buffer
+	Boost(&buf, res, unboosted, opts)
+	if want := "Resume boost: after resuming, processes waited 0.50 on average to run again instead of 1.50 without it (resumptions: 2)\n\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if Boost(&buf, res, unboosted, Options{}); buf.Len() != 0 {
+		t.Errorf("wrote %q without suspensions", buf.String())
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	var buf bytes.Buffer
+	Schedule(&buf, scheduler.Result{
+		Stats: []scheduler.Stat{{
+			Process:    scheduler.Process{ProcessID: 7, BurstDuration: 4, ArrivalTime: 1, Priority: 2},
+			Wait:       3,
+			Turnaround: 7,
+			Completion: 8,
+		}},
+		AveWait:       3,
+		AveTurnaround: 7,
+		AveThroughput: 0.125,
+	}, Options{})
+	out := buf.String()
+	for _, want := range []string{"Schedule table", "|  7 |", "3.00", "7.00", "0.12/T"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := JSON(&buf, []Named{{
+		Algorithm: "fcfs",
+		Title:     "First-come, first-serve",
+		Result:    scheduler.FCFS([]scheduler.Process{{ProcessID: 1, BurstDuration: 2}}),
+	}}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"schemaVersion": 2`, `"algorithm": "fcfs"`, `"gantt": [`, `"completion": 2`, `"throughput": 0.5`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+// TestJSONEncoding checks the streamed document against encoding/json, so
+// a field added to a result but not to writeDocument is caught.
+func TestJSONEncoding(t *testing.T) {
+	docs := []Document{
+		{SchemaVersion: SchemaVersion},
+		{SchemaVersion: SchemaVersion, Results: []Named{}},
+		{SchemaVersion: SchemaVersion, Tick: "1ms", Results: []Named{
+			{Algorithm: "rr", Title: "Round-robin <RR>", Result: scheduler.RR([]scheduler.Process{
+				{ProcessID: 1, BurstDuration: 7, Priority: 2},
+				{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1},
+			})},
+			{Algorithm: "none", Result: scheduler.Result{Gantt: []scheduler.TimeSlice{}}},
+		}},
+	}
+	for _, doc := range docs {
+		var got, want bytes.Buffer
+		if err := writeDocument(&got, doc); err != nil {
+			t.Fatal(err)
+		}
+		enc := json.NewEncoder(&want)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(doc); err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != want.String() {
+			t.Errorf("got\n%s\nwant\n%s", got.String(), want.String())
+		}
+	}
+}
+
+func TestScheduleTick(t *testing.T) {
+	var buf bytes.Buffer
+	res := scheduler.FCFS([]scheduler.Process{{ProcessID: 1, BurstDuration: 4, ArrivalTime: 0}, {ProcessID: 2, BurstDuration: 2, ArrivalTime: 1}})
+	Report(&buf, "FCFS", res, Options{Tick: time.Millisecond})
+	out := buf.String()
+	for _, want := range []string{"0s\t4ms\t6ms", "|  2 |        0 | 2ms   | 1ms     | 3ms  ", "1.5ms", "333.33/s"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	var buf bytes.Buffer
+	results := []Named{{Algorithm: "rr", Title: "Round-robin", Result: scheduler.RR([]scheduler.Process{{ProcessID: 1, BurstDuration: 7}})}}
+	if err := JSON(&buf, results, Options{Tick: time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Document{SchemaVersion: SchemaVersion, Tick: "1ms", Results: results}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("round trip = %+v, want %+v", doc, want)
+	}
+}
+
+func TestReadJSONVersion1(t *testing.T) {
+	v1 := `{"results": [{"algorithm": "fcfs", "title": "First-come, first-serve", "result": {
+		"gantt": [{"pid": 1, "start": 0, "stop": 2, "cpu": 0}],
+		"stats": [{"id": 1, "arrival": 0, "burst": 2, "priority": 0, "wait": 0, "turnaround": 2, "completion": 2}],
+		"averageWait": 0, "averageTurnaround": 2, "throughput": 0.5}}]}`
+	doc, err := ReadJSON(strings.NewReader(v1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.SchemaVersion != SchemaVersion {
+		t.Errorf("version = %d, want %d", doc.SchemaVersion, SchemaVersion)
+	}
+	if want := scheduler.FCFS([]scheduler.Process{{ProcessID: 1, BurstDuration: 2}}); !reflect.DeepEqual(doc.Results[0].Result, want) {
+		t.Errorf("result = %+v, want %+v", doc.Results[0].Result, want)
+	}
+}
+
+func TestReadJSONUnsupported(t *testing.T) {
+	for _, in := range []string{`{"schemaVersion": 99, "results": []}`, `{"schemaVersion": "two"}`, `{"schemaVersion": 0}`} {
+		if _, err := ReadJSON(strings.NewReader(in)); !errors.Is(err, ErrUnsupportedSchema) {
+			t.Errorf("%s: err = %v, want %v", in, err, ErrUnsupportedSchema)
+		}
+	}
+}
+
+func TestTable(t *testing.T) {
+	table := Table{
+		Columns: []Column{{Header: "NAME"}, {Header: "N", Align: AlignCenter}, {Header: "NOTE", MaxWidth: 6}},
+		Rows:    [][]string{{"a", "1", "short"}, {"bb", "22", "much too long"}, {"c"}},
+		Footer:  [][]string{{"", "TOTAL", "x"}},
+	}
+	for _, tt := range []struct {
+		style TableStyle
+		want  string
+	}{
+		{StyleBox, "" +
+			"+------+-------+--------+\n" +
+			"| NAME |   N   |  NOTE  |\n" +
+			"+------+-------+--------+\n" +
+			"| a    |   1   | short  |\n" +
+			"| bb   |  22   | much … |\n" +
+			"| c    |       |        |\n" +
+			"+------+-------+--------+\n" +
+			"|        TOTAL |   x    |\n" +
+			"+------+-------+--------+\n"},
+		{StylePlain, "" +
+			"NAME    N     NOTE\n" +
+			"----  -----  ------\n" +
+			"a       1    short\n" +
+			"bb     22    much …\n" +
+			"c\n" +
+			"----  -----  ------\n" +
+			"      TOTAL    x\n"},
+		{StyleBorderless, "" +
+			"NAME    N     NOTE\n" +
+			"a       1    short\n" +
+			"bb     22    much …\n" +
+			"c\n" +
+			"      TOTAL    x\n"},
+	} {
+		table.Style = tt.style
+		var buf bytes.Buffer
+		if err := table.Render(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%v: got\n%s\nwant\n%s", tt.style, buf.String(), tt.want)
+		}
+
+		// A body yielding the rows in a reused slice draws the same table.
+		streamed := table
+		streamed.Rows = nil
+		streamed.Body = func(yield func([]string) bool) {
+			var row []string
+			for _, r := range table.Rows {
+				row = append(row[:0], r...)
+				if !yield(row) {
+					return
+				}
+			}
+		}
+		buf.Reset()
+		if err := streamed.Render(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%v body: got\n%s\nwant\n%s", tt.style, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestTableStyleText(t *testing.T) {
+	var s TableStyle
+	if err := s.UnmarshalText([]byte("plain")); err != nil || s != StylePlain {
+		t.Errorf("got %v, %v, want %v", s, err, StylePlain)
+	}
+	if err := s.UnmarshalText([]byte("fancy")); err == nil {
+		t.Error("expected an error for an unknown style")
+	}
+}
+
+func TestState(t *testing.T) {
+	var buf bytes.Buffer
+	State(&buf, scheduler.State{
+		Event: scheduler.Event{Kind: scheduler.EventDispatch, Time: 4, PID: 2},
+		CPUs:  []scheduler.CPUState{{Job: scheduler.JobState{PID: 2, Remaining: 3}}, {Idle: true}},
+		Ready: []scheduler.JobState{{PID: 3, Remaining: 6, Waited: 1}},
+	}, 3, Options{Tick: time.Millisecond})
+	want := "t=4ms  dispatch P2 on CPU 0\n  CPU 0: P2, 3ms left\n  CPU 1: idle\n  ready: P3 (6ms left, waited 1ms)\n  completed: 0/3\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDiskReport(t *testing.T) {
+	// A column per cylinder.
+	p := disk.Params{Head: 10, Cylinders: 60, Down: true}
+	scan, _ := disk.LookupAlgorithm("scan")
+	res, err := disk.Schedule(scan, []int64{12, 5}, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	DiskReport(&buf, "SCAN", res, p)
+	want := "" +
+		"--------\n   SCAN\n--------\n" +
+		"Seek chart\n" +
+		"    0" + strings.Repeat(" ", 57) + "59\n" +
+		"10            o head\n" +
+		" 5       *-----\n" +
+		" 0  +-----\n" +
+		"12  ------------*\n" +
+		"\n" +
+		"Total head movement: 22 cylinders\n\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestPagingReport(t *testing.T) {
+	fifo, _ := paging.LookupAlgorithm("fifo")
+	res, err := paging.Simulate(fifo, []int64{1, 2, 1, 3}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	PagingReport(&buf, "FIFO", res, Options{Style: StylePlain})
+	want := "" +
+		"--------\n   FIFO\n--------\n" +
+		"Frames\n" +
+		"REFERENCE  1  2  1  3\n" +
+		"---------  -  -  -  -\n" +
+		"frame 0    1  1  1  3\n" +
+		"frame 1       2  2  2\n" +
+		"fault      *  *     *\n" +
+		"Page faults: 3 of 4 references (75.00%), 1 hits\n\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	PagingReport(&buf, "FIFO", res, Options{NoGantt: true})
+	if strings.Contains(buf.String(), "Frames") {
+		t.Errorf("NoGantt output:\n%s", buf.String())
+	}
+}
+
+func TestBanker(t *testing.T) {
+	s := banker.State{
+		Processes:  []string{"A", "B", "C"},
+		Available:  []int64{1},
+		Max:        [][]int64{{3}, {3}, {1}},
+		Allocation: [][]int64{{1}, {1}, {0}},
+	}
+	var buf bytes.Buffer
+	Banker(&buf, s, Options{Style: StylePlain})
+	want := "" +
+		"PROCESS  ALLOCATION  MAX  NEED\n" +
+		"-------  ----------  ---  ----\n" +
+		"A                 1    3     2\n" +
+		"B                 1    3     2\n" +
+		"C                 0    1     1\n" +
+		"Available: 1\n" +
+		"The state is unsafe: after C finish, A, B cannot.\n\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%q\nwant:\n%q", buf.String(), want)
+	}
+
+	s.Available = []int64{2}
+	decisions, err := s.EvaluateAll([]banker.Request{{Process: 0, Counts: []int64{2}}, {Process: 2, Counts: []int64{1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	Decisions(&buf, s, decisions, Options{Style: StylePlain})
+	for _, want := range []string{"A              2  granted                 A, B, C\n", "C              1  wait: only 0 available\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	events := []deadlock.Event{
+		{Time: 0, Process: "A", Kind: deadlock.Acquire, Resource: "X"},
+		{Time: 1, Process: "B", Kind: deadlock.Acquire, Resource: "Y"},
+		{Time: 2, Process: "A", Kind: deadlock.Request, Resource: "Y"},
+		{Time: 3, Process: "B", Kind: deadlock.Request, Resource: "X"},
+	}
+	res, err := deadlock.Detect(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	WaitFor(&buf, res, Options{Style: StyleBorderless})
+	for _, want := range []string{
+		"   2  A requests Y  A->B (Y)\n",
+		"   3  B requests X  A->B (Y), B->A (X)  A, B",
+		"Deadlock at 3: event 4, B requests X, closes the cycle B->A->B; deadlocked: A, B\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	WaitFor(&buf, deadlock.Result{}, Options{})
+	if !strings.HasSuffix(buf.String(), "No deadlocks.\n") {
+		t.Errorf("output:\n%s", buf.String())
+	}
+}
+
+func TestBuffer(t *testing.T) {
+	res, err := prodcons.Simulate(prodcons.Params{Produce: []int64{1}, Consume: []int64{3}, Capacity: 2, Items: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	Buffer(&buf, res, Options{Style: StyleBorderless, GanttLimit: 3})
+	for _, want := range []string{
+		"Buffer occupancy\n|   0   |   1   |   2   |\n0\t2\t3\t7\n(first 3 of 5 levels shown)\n",
+		"C1     consumer      4    12        1      1\n",
+		"Blocked for 1 in all; the buffer of 2 held at most 2 items, 0.92 on average, until 13\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	Buffer(&buf, res, Options{NoGantt: true})
+	if strings.Contains(buf.String(), "Buffer occupancy") {
+		t.Errorf("chart written with NoGantt:\n%s", buf.String())
+	}
+}
+
+func TestDining(t *testing.T) {
+	p := philosophers.Params{Philosophers: 2, Think: []int64{1}, Eat: []int64{2}, Duration: 6, Patience: 3}
+	s, err := philosophers.LookupStrategy("naive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := philosophers.Simulate(s, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	Dining(&buf, res, Options{Style: StyleBorderless})
+	for _, want := range []string{
+		"P1   THHHHH\n",
+		"P2               0       0       5             5  yes\n",
+		"Deadlock at 1: P1, P2 each hold a fork the next is waiting for.\nStarving: P1, P2\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	s, _ = philosophers.LookupStrategy("arbitrator")
+	res, _ = philosophers.Simulate(s, p)
+	Dining(&buf, res, Options{NoGantt: true})
+	if strings.Contains(buf.String(), "Timeline") || !strings.Contains(buf.String(), "No deadlock.\n") {
+		t.Errorf("output:\n%s", buf.String())
+	}
+}
+
+func TestWorkingSets(t *testing.T) {
+	refs := []int64{1, 2, 1, 3}
+	sets, err := paging.WorkingSets(refs, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	WorkingSets(&buf, refs, sets, 2, Options{Style: StyleBorderless})
+	for _, want := range []string{
+		"Working set (window of 2 references)\n",
+		"        3     1     2  1 2\n        4     3     2  1 3\n",
+		"at most 2 pages, 1.75 on average",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestFaultCurves(t *testing.T) {
+	refs := []int64{1, 2, 3, 4, 1, 2, 5, 1, 2, 3, 4, 5}
+	var curves []paging.Curve
+	for _, name := range []string{"fifo", "optimal"} {
+		a, _ := paging.LookupAlgorithm(name)
+		c, err := paging.FaultCurve(a, refs, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		curves = append(curves, c)
+	}
+	var buf bytes.Buffer
+	FaultCurves(&buf, curves, Options{Style: StyleBorderless})
+	for _, want := range []string{
+		"FRAMES  FIRST-IN, FIRST-OUT  OPTIMAL\n",
+		"     4                   10        6\n",
+		"Belady's anomaly: First-in, first-out faults 10 times with 4 frames, more than 9 with 3.\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	FaultCurves(&buf, curves[1:], Options{})
+	if !strings.HasSuffix(buf.String(), "No algorithm faults more with more frames.\n") {
+		t.Errorf("output:\n%s", buf.String())
+	}
+}
+
+func TestTLB(t *testing.T) {
+	a, _ := paging.LookupAlgorithm("lru")
+	mem, err := paging.Simulate(a, []int64{1, 2, 1, 3, 1, 2}, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []tlb.Result
+	for _, s := range []string{"2x1", "4:fifo"} {
+		c, _ := tlb.ParseConfig(s)
+		res, err := tlb.Simulate(c, mem, tlb.DefaultTiming())
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, res)
+	}
+	var buf bytes.Buffer
+	TLB(&buf, results, Options{Style: StyleBorderless})
+	for _, want := range []string{
+		"2x1:lru         2     2     1  lru        2       4    33.33%        186ns\n",
+		"4x4:fifo        4     1     4  fifo       3       3    50.00%        170ns\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	processes := []scheduler.Process{{ProcessID: 1, BurstDuration: 8}, {ProcessID: 2, BurstDuration: 2, ArrivalTime: 1}}
+	var buf bytes.Buffer
+	Explain(&buf,
+		Named{Algorithm: "fcfs", Title: "FCFS", Result: scheduler.FCFS(processes)},
+		Named{Algorithm: "sjf", Title: "SJF", Result: scheduler.SJF(processes)},
+		Options{})
+	want := "" +
+		"----------------------\n" +
+		"      FCFS vs SJF\n" +
+		"----------------------\n" +
+		"They first diverge at 1: both run P1 on CPU 0 from 0, but FCFS keeps it running until 8 while SJF takes it off at 1 to run P2.\n" +
+		"\n" +
+		"Average wait is 3.50 under FCFS and 1.00 under SJF.\n" +
+		"Average turnaround is 8.50 under FCFS and 6.00 under SJF.\n" +
+		"Context switches: 1 under FCFS, 2 under SJF.\n" +
+		"In all, processes wait 5 longer under FCFS. The gap comes from:\n" +
+		"  P2 waits 7 under FCFS and 0 under SJF (+7)\n" +
+		"  P1 waits 0 under FCFS and 2 under SJF (-2)\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	n := Named{Title: "FCFS", Result: scheduler.FCFS(processes)}
+	Explain(&buf, n, n, Options{})
+	if out := buf.String(); !strings.Contains(out, "The schedules are identical.\n") || !strings.Contains(out, "Every process waits just as long under both.\n") {
+		t.Errorf("identical results:\n%s", out)
+	}
+}
@@ -0,0 +1,130 @@
+package render
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+
+	"p1/internal/scheduler"
+)
+
+// presence is a process of a group arriving, d being 1, or completing, -1.
+type presence struct {
+	t     int64
+	group string
+	d     int
+}
+
+// Shares writes how the CPU time of res was divided between the groups of
+// its processes while they contended for it, that is while processes of
+// more than one group were between their arrival and completion: the share
+// of that time each group got against the share it should have had by
+// opts.FairShare. Over each span of contention a group should have had its
+// share over those of the groups then present, and spans count for as much
+// as the CPUs were busy in them. Processes sharing an ID are taken to be in
+// the group of the first. Nothing is written unless groups contended while
+// a CPU was busy, and so nothing for results without a Gantt chart.
+func Shares(w io.Writer, res scheduler.Result, opts Options) {
+	groupOf := make(map[int64]string)
+	members := make(map[string]int)
+	var events []presence
+	var times []int64
+	for _, s := range res.Stats {
+		if _, ok := groupOf[s.ProcessID]; !ok {
+			groupOf[s.ProcessID] = s.Group
+		}
+		members[s.Group]++
+		events = append(events, presence{s.ArrivalTime, s.Group, 1}, presence{s.Completion, s.Group, -1})
+		times = append(times, s.ArrivalTime, s.Completion)
+	}
+	if len(members) < 2 {
+		return
+	}
+	groups := slices.Sorted(maps.Keys(members))
+	// The run is cut at every time a process arrives or completes, or a
+	// slice starts or stops, so that neither the groups present nor how
+	// busy the CPUs are changes between one cut and the next.
+	for _, g := range res.Gantt {
+		times = append(times, g.Start, g.Stop)
+	}
+	slices.Sort(times)
+	times = slices.Compact(times)
+	slices.SortStableFunc(events, func(a, b presence) int { return cmp.Compare(a.t, b.t) })
+	// sweep calls f with each span between cuts in turn and how many
+	// processes of each group are present in it.
+	sweep := func(f func(k int, counts map[string]int)) {
+		counts := make(map[string]int)
+		e := 0
+		for k := 0; k+1 < len(times); k++ {
+			for ; e < len(events) && events[e].t <= times[k]; e++ {
+				counts[events[e].group] += events[e].d
+			}
+			f(k, counts)
+		}
+	}
+
+	contended := make([]bool, len(times))
+	sweep(func(k int, counts map[string]int) {
+		n := 0
+		for _, c := range counts {
+			if c > 0 {
+				n++
+			}
+		}
+		contended[k] = n > 1
+	})
+	busy := make([]int64, len(times))
+	got := make(map[string]int64)
+	var total int64
+	for _, g := range res.Gantt {
+		k, _ := slices.BinarySearch(times, g.Start)
+		for ; times[k] < g.Stop; k++ {
+			if contended[k] {
+				span := times[k+1] - times[k]
+				busy[k] += span
+				got[groupOf[g.PID]] += span
+				total += span
+			}
+		}
+	}
+	if total == 0 {
+		return
+	}
+	due := make(map[string]float64)
+	sweep(func(k int, counts map[string]int) {
+		if !contended[k] || busy[k] == 0 {
+			return
+		}
+		var shares int64
+		for _, g := range groups {
+			if counts[g] > 0 {
+				shares += opts.FairShare.Share(g)
+			}
+		}
+		for _, g := range groups {
+			if counts[g] > 0 {
+				due[g] += float64(busy[k]) * float64(opts.FairShare.Share(g)) / float64(shares)
+			}
+		}
+	})
+
+	table := Table{
+		Columns: []Column{{Header: "GROUP"}, {Header: "PROCESSES"}, {Header: "SHARE"}, {Header: "CONFIGURED", Align: AlignRight}, {Header: "ACHIEVED", Align: AlignRight}},
+		Style:   opts.Style,
+	}
+	for _, g := range groups {
+		name := g
+		if name == "" {
+			name = "(none)"
+		}
+		table.Rows = append(table.Rows, []string{
+			name, strconv.Itoa(members[g]), strconv.FormatInt(opts.FairShare.Share(g), 10),
+			fmt.Sprintf("%.2f%%", 100*due[g]/float64(total)), fmt.Sprintf("%.2f%%", 100*float64(got[g])/float64(total)),
+		})
+	}
+	_, _ = fmt.Fprintf(w, "Group shares of the %s of CPU time while groups contended\n", opts.formatTime(total))
+	_ = table.Render(w)
+}
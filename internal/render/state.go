@@ -0,0 +1,54 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"p1/internal/scheduler"
+)
+
+// State writes a snapshot of a simulation: the event that led to it, what
+// each CPU is running and the ready queue in dispatch order.
+func State(w io.Writer, s scheduler.State, total int, opts Options) {
+	Event(w, s.Event, opts)
+	for i, c := range s.CPUs {
+		if c.Idle {
+			_, _ = fmt.Fprintf(w, "  CPU %d: idle\n", i)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "  CPU %d: P%d, %s left\n", i, c.Job.PID, opts.formatTime(c.Job.Remaining))
+	}
+	ready := make([]string, len(s.Ready))
+	for i, j := range s.Ready {
+		ready[i] = fmt.Sprintf("P%d (%s left, waited %s)", j.PID, opts.formatTime(j.Remaining), opts.formatTime(j.Waited))
+	}
+	if len(ready) == 0 {
+		ready = append(ready, "empty")
+	}
+	_, _ = fmt.Fprintf(w, "  ready: %s\n", strings.Join(ready, ", "))
+	if len(s.Suspended) > 0 {
+		suspended := make([]string, len(s.Suspended))
+		for i, j := range s.Suspended {
+			suspended[i] = fmt.Sprintf("P%d (%s left)", j.PID, opts.formatTime(j.Remaining))
+		}
+		_, _ = fmt.Fprintf(w, "  suspended: %s\n", strings.Join(suspended, ", "))
+	}
+	_, _ = fmt.Fprintf(w, "  completed: %d/%d\n", s.Completed, total)
+}
+
+// Event writes a line describing ev.
+func Event(w io.Writer, ev scheduler.Event, opts Options) {
+	switch ev.Kind {
+	case scheduler.EventIdle:
+		_, _ = fmt.Fprintf(w, "t=%s  CPU %d idle\n", opts.formatTime(ev.Time), ev.CPU)
+	case scheduler.EventArrive:
+		_, _ = fmt.Fprintf(w, "t=%s  P%d arrives\n", opts.formatTime(ev.Time), ev.PID)
+	case scheduler.EventSuspend:
+		_, _ = fmt.Fprintf(w, "t=%s  P%d suspended\n", opts.formatTime(ev.Time), ev.PID)
+	case scheduler.EventResume:
+		_, _ = fmt.Fprintf(w, "t=%s  P%d resumed\n", opts.formatTime(ev.Time), ev.PID)
+	default:
+		_, _ = fmt.Fprintf(w, "t=%s  %s P%d on CPU %d\n", opts.formatTime(ev.Time), ev.Kind, ev.PID, ev.CPU)
+	}
+}
@@ -0,0 +1,189 @@
+package render
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"p1/internal/scheduler"
+)
+
+// flushInterval is how long streamed output may sit in a buffer. It bounds
+// what a killed run loses while sparing a write per line.
+const flushInterval = 100 * time.Millisecond
+
+// streamWriter buffers writes, flushing them once flushInterval has passed
+// since the last flush.
+type streamWriter struct {
+	w    *bufio.Writer
+	last time.Time
+}
+
+func newStreamWriter(w io.Writer) *streamWriter {
+	return &streamWriter{w: bufio.NewWriter(w), last: time.Now()}
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	n, err := s.w.Write(p)
+	if err == nil && time.Since(s.last) >= flushInterval {
+		err = s.Flush()
+	}
+	return n, err
+}
+
+func (s *streamWriter) Flush() error {
+	s.last = time.Now()
+	return s.w.Flush()
+}
+
+// Lines writes results as JSON lines while they are produced: a line per
+// Gantt slice and per completed process, and a summary once a run is over.
+//
+//	{"algorithm":"rr","slice":{"pid":1,"start":0,"stop":5,"cpu":0}}
+//	{"algorithm":"rr","process":{"id":1,...,"completion":12}}
+//	{"algorithm":"rr","summary":{"averageWait":3.5,...}}
+type Lines struct {
+	w   *streamWriter
+	enc *json.Encoder
+	err error
+}
+
+// Summary is the summary line of a run written by Lines.
+type Summary struct {
+	AveWait         float64 `json:"averageWait"`
+	AveTurnaround   float64 `json:"averageTurnaround"`
+	AveThroughput   float64 `json:"throughput"`
+	ContextSwitches int     `json:"contextSwitches"`
+	// Partial is set for a run interrupted before every process completed.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// line is one line written by Lines, with exactly one of its parts set.
+type line struct {
+	Algorithm string               `json:"algorithm"`
+	Slice     *scheduler.TimeSlice `json:"slice,omitempty"`
+	Process   *scheduler.Stat      `json:"process,omitempty"`
+	Summary   *Summary             `json:"summary,omitempty"`
+}
+
+// NewLines returns a Lines writing to w.
+func NewLines(w io.Writer) *Lines {
+	s := newStreamWriter(w)
+	return &Lines{w: s, enc: json.NewEncoder(s)}
+}
+
+func (l *Lines) write(v line) {
+	if l.err == nil {
+		l.err = l.enc.Encode(v)
+	}
+}
+
+// Sink returns a sink writing the slices and processes of a run of
+// algorithm.
+func (l *Lines) Sink(algorithm string) scheduler.Sink {
+	return scheduler.Sink{
+		Slice: func(s scheduler.TimeSlice) { l.write(line{Algorithm: algorithm, Slice: &s}) },
+		Stat:  func(s scheduler.Stat) { l.write(line{Algorithm: algorithm, Process: &s}) },
+	}
+}
+
+// Summary writes the summary of a finished run of algorithm, partial if it
+// was interrupted, and flushes everything written so far, returning the
+// first error writing any line.
+func (l *Lines) Summary(algorithm string, res scheduler.Result, partial bool) error {
+	l.write(line{Algorithm: algorithm, Summary: &Summary{res.AveWait, res.AveTurnaround, res.AveThroughput, res.ContextSwitches, partial}})
+	if l.err == nil {
+		l.err = l.w.Flush()
+	}
+	return l.err
+}
+
+// ScheduleRows draws a schedule table a row at a time as processes
+// complete, rather than once a run is over. Its columns are sized up front
+// from the workload, the times a run can reach being bounded by the last
+// arrival plus every burst; a switch cost can push a cell past that.
+type ScheduleRows struct {
+	w      *streamWriter
+	table  Table
+	widths []int
+	row    []string
+	opts   Options
+	buf    []byte
+}
+
+// NewScheduleRows writes the heading of a schedule table for processes and
+// returns a ScheduleRows to write its rows.
+func NewScheduleRows(w io.Writer, processes []scheduler.Process, opts Options) *ScheduleRows {
+	r := &ScheduleRows{w: newStreamWriter(w), table: scheduleTable(opts), opts: opts}
+	var horizon int64
+	widths := make([]int, len(r.table.Columns))
+	for i, c := range r.table.Columns {
+		widths[i] = utf8.RuneCountInString(c.Header)
+	}
+	grow := func(i int, cell string) { widths[i] = max(widths[i], utf8.RuneCountInString(cell)) }
+	for _, p := range processes {
+		grow(0, strconv.FormatInt(p.ProcessID, 10))
+		grow(1, strconv.FormatInt(p.Priority, 10))
+		grow(2, opts.formatTime(p.BurstDuration))
+		grow(3, opts.formatTime(p.ArrivalTime))
+		horizon = max(horizon, p.ArrivalTime) + p.BurstDuration
+	}
+	for i := 4; i < len(widths); i++ {
+		grow(i, opts.formatTime(horizon))
+	}
+	// Leave room for the footer, whose averages are bounded alike and
+	// whose throughput is at most a process per tick.
+	for i, cell := range scheduleFooter(scheduler.Result{AveWait: float64(horizon), AveTurnaround: float64(horizon), AveThroughput: float64(max(1, len(processes)))}, opts)[1] {
+		grow(i, cell)
+	}
+	for i, cell := range []string{"AVERAGE", "AVERAGE", "THROUGHPUT"} {
+		grow(4+i, cell)
+	}
+	r.widths = widths
+	r.row = make([]string, len(widths))
+
+	_, _ = io.WriteString(r.w, "Schedule table\n")
+	rule := r.table.rule(widths)
+	_, _ = io.WriteString(r.w, r.table.border(rule))
+	r.write(r.header(), nil, func(int) Align { return AlignCenter })
+	_, _ = io.WriteString(r.w, rule)
+	return r
+}
+
+func (r *ScheduleRows) header() []string {
+	header := make([]string, len(r.table.Columns))
+	for i, c := range r.table.Columns {
+		header[i] = c.Header
+	}
+	return header
+}
+
+func (r *ScheduleRows) write(row []string, merged []bool, align func(int) Align) {
+	r.buf = r.table.line(r.buf[:0], row, r.widths, merged, align)
+	_, _ = r.w.Write(r.buf)
+}
+
+// Row writes the row of a completed process.
+func (r *ScheduleRows) Row(s scheduler.Stat) {
+	scheduleRow(r.row, s, r.opts)
+	r.write(r.row, nil, func(i int) Align { return r.table.Columns[i].Align })
+}
+
+// Close writes the footer of the table with the averages of res.
+func (r *ScheduleRows) Close(res scheduler.Result) error {
+	footer := scheduleFooter(res, r.opts)
+	rule := r.table.rule(r.widths)
+	_, _ = io.WriteString(r.w, rule)
+	merged := make([]bool, len(footer[0]))
+	for i, cell := range footer[0] {
+		merged[i] = cell == ""
+	}
+	for _, f := range footer {
+		r.write(f, merged, func(int) Align { return AlignCenter })
+	}
+	_, _ = io.WriteString(r.w, r.table.border(rule))
+	return r.w.Flush()
+}
@@ -0,0 +1,192 @@
+package render
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"slices"
+
+	"p1/internal/scheduler"
+)
+
+// Layout of an SVG Gantt chart, in pixels.
+const (
+	// svgLeft is the room left of the rows for their CPU labels, and
+	// svgRight that right of them for their utilization.
+	svgLeft, svgRight = 56, 64
+	// svgAxis is the width of the time axis, scaled to fit the longest
+	// schedule drawn.
+	svgAxis = 800
+	// svgTitle is the height of a chart's title, svgRow that of a CPU's
+	// row, and svgTicks that of the time axis labels under the rows.
+	svgTitle, svgRow, svgTicks = 24, 24, 32
+	// svgMinTick is the least room between time axis labels.
+	svgMinTick = 32
+)
+
+// SVG writes results as an SVG image of their Gantt charts, one above the
+// other. Each has a row per CPU and how busy that CPU was, with every row
+// and chart on the same time scale.
+func SVG(w io.Writer, results []Named, opts Options) error {
+	var charts bytes.Buffer
+	height := 0
+	end := drawnEnd(results, opts)
+	for _, n := range results {
+		height += ganttSVG(&charts, n.Title, n.Result.Gantt, height, end, opts)
+	}
+	return svgElement(w, height, charts.Bytes())
+}
+
+// svgElement writes an svg element of the given height holding body.
+func svgElement(w io.Writer, height int, body []byte) error {
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="12">
+%s</svg>
+`, svgLeft+svgAxis+svgRight, height, body)
+	return err
+}
+
+// drawnSlices returns the slices of gantt drawn in a chart in order of
+// their start, at most opts.GanttLimit of them.
+func drawnSlices(gantt []scheduler.TimeSlice, opts Options) []scheduler.TimeSlice {
+	gantt = slices.SortedStableFunc(slices.Values(gantt), func(a, b scheduler.TimeSlice) int { return cmp.Compare(a.Start, b.Start) })
+	if opts.GanttLimit > 0 && len(gantt) > opts.GanttLimit {
+		gantt = gantt[:opts.GanttLimit]
+	}
+	return gantt
+}
+
+// drawnEnd returns the last time drawn in the charts of results.
+func drawnEnd(results []Named, opts Options) int64 {
+	var end int64
+	for _, n := range results {
+		for _, s := range drawnSlices(opts.Window.Clip(n.Result.Gantt), opts) {
+			end = max(end, s.Stop)
+		}
+	}
+	return end
+}
+
+// ganttSVG writes the chart of gantt under title at y, on a time axis
+// from the start of opts.Window to end, and returns its height.
+func ganttSVG(w io.Writer, title string, gantt []scheduler.TimeSlice, y int, end int64, opts Options) int {
+	cpus := max(opts.cpus(gantt), 1)
+	gantt = opts.Window.Clip(gantt)
+	drawn := drawnSlices(gantt, opts)
+	from := opts.Window.From
+	x := func(t int64) float64 {
+		if end <= from {
+			return svgLeft
+		}
+		return svgLeft + float64(t-from)*svgAxis/float64(end-from)
+	}
+	printf := func(format string, args ...any) { _, _ = fmt.Fprintf(w, format, args...) }
+
+	printf(`<text x="0" y="%d" font-size="14" font-weight="bold">%s</text>`+"\n", y+16, html.EscapeString(title))
+	if len(drawn) < len(gantt) {
+		printf(`<text x="%d" y="%d" fill="#666" text-anchor="end">(first %d of %d slices shown)</text>`+"\n", svgLeft+svgAxis, y+16, len(drawn), len(gantt))
+	}
+	top := y + svgTitle
+	for c, u := range utilization(gantt, cpus, from) {
+		row := top + c*svgRow
+		printf(`<text x="0" y="%d">CPU %d</text>`+"\n", row+16, c)
+		printf(`<rect x="%d" y="%d" width="%d" height="%d" fill="#f4f4f4"/>`+"\n", svgLeft, row+2, svgAxis, svgRow-4)
+		printf(`<text x="%d" y="%d">%.2f%%</text>`+"\n", svgLeft+svgAxis+8, row+16, 100*u)
+	}
+	for _, s := range drawn {
+		left, right := x(s.Start), x(s.Stop)
+		row := top + s.CPU*svgRow
+		printf(`<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="hsl(%d, 60%%, 70%%)" stroke="#fff"><title>P%d: %s to %s</title></rect>`+"\n",
+			left, row+2, right-left, svgRow-4, s.PID*137%360, s.PID, opts.formatTime(s.Start), opts.formatTime(s.Stop))
+		if label := fmt.Sprint(s.PID); right-left >= float64(8*len(label)) {
+			printf(`<text x="%.1f" y="%d" text-anchor="middle">%s</text>`+"\n", (left+right)/2, row+16, label)
+		}
+	}
+
+	axis := top + cpus*svgRow
+	printf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#000"/>`+"\n", svgLeft, axis, svgLeft+svgAxis, axis)
+	last := -float64(svgMinTick)
+	for _, t := range boundaries(drawn) {
+		if tx := x(t); tx-last >= svgMinTick {
+			printf(`<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="#000"/>`+"\n", tx, axis, tx, axis+4)
+			printf(`<text x="%.1f" y="%d" text-anchor="middle">%s</text>`+"\n", tx, axis+16, opts.formatTime(t))
+			last = tx
+		}
+	}
+	return axis + svgTicks - y
+}
+
+// page is the HTML document written by HTML.
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Schedules</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin: 1em 0 2em; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.75em; text-align: right; }
+tfoot td { font-weight: bold; }
+</style>
+</head>
+<body>
+{{- range .}}
+<section>
+<h2>{{.Title}}</h2>
+{{with .Chart}}{{.}}{{end -}}
+<table>
+<thead><tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+<tfoot>
+{{- range .Footer}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</tfoot>
+</table>
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+// section is the part of the HTML document on one result.
+type section struct {
+	Title string
+	// Chart is the SVG Gantt chart, left out with opts.NoGantt.
+	Chart   template.HTML
+	Columns []string
+	Rows    [][]string
+	Footer  [][]string
+}
+
+// HTML writes results as an HTML page with, for each, its Gantt chart as
+// SVG would draw it and its schedule table.
+func HTML(w io.Writer, results []Named, opts Options) error {
+	end := drawnEnd(results, opts)
+	sections := make([]section, len(results))
+	for i, n := range results {
+		table := scheduleTable(opts)
+		s := section{Title: n.Title, Footer: scheduleFooter(n.Result, opts)}
+		for _, c := range table.Columns {
+			s.Columns = append(s.Columns, c.Header)
+		}
+		for _, stat := range n.Result.Stats {
+			s.Rows = append(s.Rows, scheduleRow(make([]string, len(s.Columns)), stat, opts))
+		}
+		if !opts.NoGantt {
+			var chart, doc bytes.Buffer
+			height := ganttSVG(&chart, "Gantt schedule", n.Result.Gantt, 0, end, opts)
+			_ = svgElement(&doc, height, chart.Bytes())
+			// The chart is built from escaped text and numbers alone.
+			s.Chart = template.HTML(doc.String())
+		}
+		sections[i] = s
+	}
+	return page.Execute(w, sections)
+}
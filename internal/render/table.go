@@ -0,0 +1,251 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Align is the alignment of text within a table column.
+type Align int
+
+const (
+	// AlignAuto right-aligns numbers and left-aligns everything else.
+	AlignAuto Align = iota
+	AlignLeft
+	AlignRight
+	AlignCenter
+)
+
+// TableStyle selects how the lines of a table are drawn.
+type TableStyle int
+
+const (
+	// StyleBox draws an ASCII box around every cell.
+	StyleBox TableStyle = iota
+	// StylePlain draws no borders, only rules under the header and above
+	// the footer.
+	StylePlain
+	// StyleBorderless draws no lines at all.
+	StyleBorderless
+)
+
+var styleNames = []string{"box", "plain", "borderless"}
+
+func (s TableStyle) String() string {
+	if int(s) < len(styleNames) {
+		return styleNames[s]
+	}
+	return fmt.Sprintf("TableStyle(%d)", int(s))
+}
+
+func (s TableStyle) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+func (s *TableStyle) UnmarshalText(text []byte) error {
+	for i, name := range styleNames {
+		if string(text) == name {
+			*s = TableStyle(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown table style %q, want one of %s", text, strings.Join(styleNames, ", "))
+}
+
+// Column describes one column of a table.
+type Column struct {
+	Header string
+	// Align applies to the body rows; headers and footers are centered.
+	Align Align
+	// MaxWidth, if positive, is the width past which cells are cut short
+	// and end in an ellipsis.
+	MaxWidth int
+}
+
+// Table is a grid of text cells. Footer rows are drawn below the body; where
+// a cell of the first footer row is left empty, the box style draws no
+// separator after that column in any footer row, so the footer reads as
+// one cell with its neighbour to the right. Each cell is still sized to
+// its own column.
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+	// Body, if set, yields the body rows in place of Rows, for tables too
+	// large to hold as text. It is called twice, once to size the columns
+	// and once to draw them, and may reuse the slice it yields.
+	Body   iter.Seq[[]string]
+	Footer [][]string
+	Style  TableStyle
+}
+
+// Render writes the table to w.
+func (t *Table) Render(w io.Writer) error {
+	header := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		header[i] = c.Header
+	}
+	header = t.clip(header)
+	body := t.Body
+	if body == nil {
+		body = slices.Values(t.Rows)
+	}
+	footer := make([][]string, len(t.Footer))
+	for i, r := range t.Footer {
+		footer[i] = t.clip(r)
+	}
+
+	widths := make([]int, len(t.Columns))
+	measure := func(r []string) {
+		for i, cell := range r {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	measure(header)
+	for r := range body {
+		measure(t.clip(r))
+	}
+	for _, r := range footer {
+		measure(r)
+	}
+
+	var merged []bool
+	if len(footer) > 0 {
+		merged = make([]bool, len(t.Columns))
+		for i, cell := range footer[0] {
+			merged[i] = cell == ""
+		}
+	}
+
+	// Tables can have a row per process, so lines go through a buffer
+	// rather than the whole table being built up first.
+	bw := bufio.NewWriter(w)
+	var l []byte
+	rule := t.rule(widths)
+	_, _ = bw.WriteString(t.border(rule))
+	l = t.line(l[:0], header, widths, nil, func(int) Align { return AlignCenter })
+	_, _ = bw.Write(l)
+	_, _ = bw.WriteString(rule)
+	align := func(i int) Align { return t.Columns[i].Align }
+	for r := range body {
+		l = t.line(l[:0], t.clip(r), widths, nil, align)
+		_, _ = bw.Write(l)
+	}
+	if len(footer) > 0 {
+		_, _ = bw.WriteString(rule)
+		for _, r := range footer {
+			l = t.line(l[:0], r, widths, merged, func(int) Align { return AlignCenter })
+			_, _ = bw.Write(l)
+		}
+	}
+	_, _ = bw.WriteString(t.border(rule))
+	return bw.Flush()
+}
+
+// clip returns row with exactly one cell per column, each cut to its
+// column's maximum width. A row that already fits is returned as is.
+func (t *Table) clip(row []string) []string {
+	if t.fits(row) {
+		return row
+	}
+	out := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		if i >= len(row) {
+			continue
+		}
+		out[i] = row[i]
+		if c.MaxWidth > 0 && utf8.RuneCountInString(out[i]) > c.MaxWidth {
+			out[i] = string([]rune(out[i])[:c.MaxWidth-1]) + "…"
+		}
+	}
+	return out
+}
+
+// fits reports whether row has one cell per column and none too wide.
+func (t *Table) fits(row []string) bool {
+	if len(row) != len(t.Columns) {
+		return false
+	}
+	for i, c := range t.Columns {
+		if c.MaxWidth > 0 && utf8.RuneCountInString(row[i]) > c.MaxWidth {
+			return false
+		}
+	}
+	return true
+}
+
+// rule returns the horizontal line drawn below the header and above the
+// footer, or nothing if the style has no lines.
+func (t *Table) rule(widths []int) string {
+	switch t.Style {
+	case StyleBox:
+		var b strings.Builder
+		b.WriteString("+")
+		for _, w := range widths {
+			b.WriteString(strings.Repeat("-", w+2))
+			b.WriteString("+")
+		}
+		b.WriteString("\n")
+		return b.String()
+	case StylePlain:
+		parts := make([]string, len(widths))
+		for i, w := range widths {
+			parts[i] = strings.Repeat("-", w)
+		}
+		return strings.Join(parts, "  ") + "\n"
+	}
+	return ""
+}
+
+// border returns rule if the style draws the outer border of the table.
+func (t *Table) border(rule string) string {
+	if t.Style == StyleBox {
+		return rule
+	}
+	return ""
+}
+
+// line appends one row of cells to l, leaving out the separator after
+// columns that are merged into the next.
+func (t *Table) line(l []byte, row []string, widths []int, merged []bool, align func(int) Align) []byte {
+	if t.Style == StyleBox {
+		l = append(l, '|')
+	}
+	for i, cell := range row {
+		sep := "  "
+		if t.Style == StyleBox {
+			l = append(l, ' ')
+			if merged == nil || !merged[i] {
+				sep = " |"
+			}
+		}
+		l = pad(l, cell, widths[i], align(i))
+		l = append(l, sep...)
+	}
+	if t.Style != StyleBox {
+		l = bytes.TrimRight(l, " ")
+	}
+	return append(l, '\n')
+}
+
+// pad appends cell to l, filled with spaces to width runes.
+func pad(l []byte, cell string, width int, align Align) []byte {
+	gap := width - utf8.RuneCountInString(cell)
+	if align == AlignAuto {
+		align = AlignLeft
+		if _, err := strconv.ParseFloat(cell, 64); err == nil {
+			align = AlignRight
+		}
+	}
+	switch align {
+	case AlignRight:
+		return append(append(l, spaces(gap)...), cell...)
+	case AlignCenter:
+		return append(append(append(l, spaces(gap/2)...), cell...), spaces(gap-gap/2)...)
+	}
+	return append(append(l, cell...), spaces(gap)...)
+}
@@ -0,0 +1,160 @@
+package render
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"p1/internal/scheduler"
+)
+
+// timelineWidth is the most columns a timeline spans. Longer runs are drawn
+// with several ticks to a column.
+const timelineWidth = 80
+
+// state is what a process is doing between its arrival and completion, in
+// order of precedence in a timeline column.
+type state int
+
+const (
+	stateReady state = iota
+	stateBlocked
+	stateRunning
+)
+
+// marks are the marks of each state in a timeline.
+var marks = [...]rune{stateReady: '·', stateBlocked: '~', stateRunning: '█'}
+
+// phase is a span of time a process spent in one state.
+type phase struct {
+	start, stop int64
+	state       state
+}
+
+// lifeline returns the phases of the process s from its arrival to its
+// completion in time order, running in the slices ran, else blocked by one
+// of suspensions, else ready.
+func lifeline(s scheduler.Stat, ran []scheduler.TimeSlice, suspensions []scheduler.Suspension) []phase {
+	times := []int64{s.ArrivalTime, s.Completion}
+	for _, g := range ran {
+		times = append(times, g.Start, g.Stop)
+	}
+	for _, b := range suspensions {
+		if b.PID == s.ProcessID {
+			times = append(times, b.From, b.To)
+		}
+	}
+	for i := range times {
+		times[i] = min(max(times[i], s.ArrivalTime), s.Completion)
+	}
+	slices.Sort(times)
+	times = slices.Compact(times)
+
+	var out []phase
+	for i := 1; i < len(times); i++ {
+		t, st := times[i-1], stateReady
+		switch {
+		case slices.ContainsFunc(ran, func(g scheduler.TimeSlice) bool { return g.Start <= t && t < g.Stop }):
+			st = stateRunning
+		case slices.ContainsFunc(suspensions, func(b scheduler.Suspension) bool { return b.PID == s.ProcessID && b.From <= t && t < b.To }):
+			st = stateBlocked
+		}
+		if n := len(out); n > 0 && out[n-1].state == st {
+			out[n-1].stop = times[i]
+		} else {
+			out = append(out, phase{t, times[i], st})
+		}
+	}
+	return out
+}
+
+// Timeline writes res as a chart with a row per process, marking every
+// tick from its arrival to its completion as running, ready or blocked by
+// one of opts.Suspensions. A column covering several ticks shows the
+// process running if it ran in any of them, else blocked if it was blocked
+// in any. Processes sharing an ID cannot be told apart in the Gantt chart,
+// so each is drawn running whenever any of them is. Only opts.Window is
+// drawn if it is set, and at most opts.GanttLimit rows if it is positive.
+func Timeline(w io.Writer, res scheduler.Result, opts Options) {
+	from, end := opts.Window.From, int64(0)
+	for _, s := range res.Stats {
+		end = max(end, s.Completion)
+	}
+	if !opts.Window.IsZero() {
+		end = min(end, opts.Window.To)
+	}
+	scale := max(1, (end-from+timelineWidth-1)/timelineWidth)
+	cols := max(0, int((end-from+scale-1)/scale))
+	// col returns the columns the span from start to stop covers.
+	col := func(start, stop int64) (int, int) {
+		start, stop = max(start, from), min(stop, end)
+		if stop <= start {
+			return 0, 0
+		}
+		return int((start - from) / scale), int((stop - from + scale - 1) / scale)
+	}
+
+	ran := make(map[int64][]scheduler.TimeSlice)
+	for _, s := range res.Gantt {
+		ran[s.PID] = append(ran[s.PID], s)
+	}
+	stats := res.Stats
+	if opts.GanttLimit > 0 && len(stats) > opts.GanttLimit {
+		stats = stats[:opts.GanttLimit]
+	}
+	labels := make([]string, len(stats))
+	pad := 0
+	for i, s := range stats {
+		labels[i] = "P" + strconv.FormatInt(s.ProcessID, 10)
+		pad = max(pad, len(labels[i]))
+	}
+
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString(opts.heading("Timeline"))
+	_ = bw.WriteByte('\n')
+	row := make([]rune, cols)
+	for i, s := range stats {
+		for k := range row {
+			row[k] = ' '
+		}
+		// Marks drawn later win a column.
+		phases := lifeline(s, ran[s.ProcessID], opts.Suspensions)
+		slices.SortStableFunc(phases, func(a, b phase) int { return cmp.Compare(a.state, b.state) })
+		for _, p := range phases {
+			lo, hi := col(p.start, p.stop)
+			for k := lo; k < hi; k++ {
+				row[k] = marks[p.state]
+			}
+		}
+		_, _ = fmt.Fprintf(bw, "%-*s |%s|\n", pad, labels[i], string(row))
+	}
+	_, _ = bw.WriteString(timelineAxis(pad+2, cols, from, scale, opts))
+	if len(stats) < len(res.Stats) {
+		_, _ = fmt.Fprintf(bw, "(first %d of %d processes shown)\n", len(stats), len(res.Stats))
+	}
+	legend := fmt.Sprintf("%c running, %c ready, %c blocked", marks[stateRunning], marks[stateReady], marks[stateBlocked])
+	if scale > 1 {
+		legend += fmt.Sprintf("; each column is %s", opts.formatTime(scale))
+	}
+	_, _ = fmt.Fprintf(bw, "%s\n\n", legend)
+	_ = bw.Flush()
+}
+
+// timelineAxis returns the time axis under a timeline of cols columns
+// starting indent characters in, labelling every tenth column with the time
+// it starts at.
+func timelineAxis(indent, cols int, from, scale int64, opts Options) string {
+	axis := []byte(strings.Repeat(" ", indent))
+	for k := 0; k <= cols; k += 10 {
+		if len(axis) > indent+k {
+			continue // the last label ran into this one
+		}
+		axis = append(axis, strings.Repeat(" ", indent+k-len(axis))...)
+		axis = append(axis, opts.formatTime(from+int64(k)*scale)...)
+	}
+	return strings.TrimRight(string(axis), " ") + "\n"
+}
@@ -0,0 +1,27 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"p1/internal/tlb"
+)
+
+// TLB writes a table comparing runs of a reference string through TLBs of
+// different shapes: the hits and misses of each and its effective access
+// time.
+func TLB(w io.Writer, results []tlb.Result, opts Options) {
+	table := Table{
+		Columns: []Column{{Header: "TLB"}, {Header: "ENTRIES"}, {Header: "SETS"}, {Header: "WAYS"}, {Header: "POLICY"}, {Header: "HITS"}, {Header: "MISSES"}, {Header: "HIT RATE", Align: AlignRight}, {Header: "ACCESS TIME", Align: AlignRight}},
+		Style:   opts.Style,
+	}
+	for _, res := range results {
+		c := res.Config
+		table.Rows = append(table.Rows, []string{
+			c.String(), strconv.Itoa(c.Entries), strconv.Itoa(c.Sets()), strconv.Itoa(c.Ways), string(c.Policy),
+			strconv.Itoa(res.Hits), strconv.Itoa(res.Misses), fmt.Sprintf("%.2f%%", 100*res.HitRate()), res.AccessTime.String(),
+		})
+	}
+	_ = table.Render(w)
+}
@@ -0,0 +1,110 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"p1/internal/scheduler"
+)
+
+// Window is the span of time from From to To that reports are restricted
+// to. The zero Window is the whole run, and a To of math.MaxInt64 runs to
+// its end.
+type Window struct {
+	From, To int64
+}
+
+// IsZero reports whether w is the whole run.
+func (w Window) IsZero() bool { return w == Window{} }
+
+// Contains reports whether time t falls in w, its ends included.
+func (w Window) Contains(t int64) bool { return w.IsZero() || w.From <= t && t <= w.To }
+
+// Clip returns the parts of the slices of gantt within w, dropping those
+// outside it.
+func (w Window) Clip(gantt []scheduler.TimeSlice) []scheduler.TimeSlice {
+	if w.IsZero() {
+		return gantt
+	}
+	var out []scheduler.TimeSlice
+	for _, s := range gantt {
+		s.Start, s.Stop = max(s.Start, w.From), min(s.Stop, w.To)
+		if s.Stop > s.Start {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// format writes w as "from 10 to 30", or "from 10 on" if it runs to the end.
+func (w Window) format(opts Options) string {
+	if w.To == math.MaxInt64 {
+		return "from " + opts.formatTime(w.From) + " on"
+	}
+	return "from " + opts.formatTime(w.From) + " to " + opts.formatTime(w.To)
+}
+
+// Windowed writes the timings of res within opts.Window: for every process
+// present in it, how long it ran and waited there, and how busy the CPUs
+// were and how many processes completed. Waiting is all the time a process
+// spent in the window between its arrival and completion but not running.
+// Processes sharing an ID cannot be told apart in the Gantt chart, so they
+// are each given the running time of them all. Nothing is written for the
+// zero Window.
+func Windowed(w io.Writer, res scheduler.Result, opts Options) {
+	win := opts.Window
+	if win.IsZero() {
+		return
+	}
+	ran := make(map[int64]int64)
+	var busy, end int64
+	for _, s := range res.Gantt {
+		end = max(end, s.Stop)
+	}
+	for _, s := range win.Clip(res.Gantt) {
+		ran[s.PID] += s.Stop - s.Start
+		busy += s.Stop - s.Start
+	}
+	cpus := max(opts.cpus(res.Gantt), 1)
+
+	table := Table{
+		Columns: []Column{{Header: "ID"}, {Header: "RUN"}, {Header: "WAIT"}, {Header: "EXIT"}},
+		Style:   opts.Style,
+	}
+	var waited float64
+	completed := 0
+	for _, s := range res.Stats {
+		end = max(end, s.Completion)
+		from, to := max(s.ArrivalTime, win.From), min(s.Completion, win.To)
+		// A process arriving as the window closes is not in it, but one
+		// completing as it opens is.
+		if to < from || to == from && s.Completion != to {
+			continue
+		}
+		wait := max(0, to-from-ran[s.ProcessID])
+		exit := ""
+		if win.Contains(s.Completion) {
+			exit = opts.formatTime(s.Completion)
+			completed++
+		}
+		table.Rows = append(table.Rows, []string{strconv.FormatInt(s.ProcessID, 10), opts.formatTime(ran[s.ProcessID]), opts.formatTime(wait), exit})
+		waited += float64(wait)
+	}
+	span := min(win.To, end) - win.From
+	average, share, throughput := 0.0, 0.0, 0.0
+	if n := len(table.Rows); n > 0 {
+		average = waited / float64(n)
+	}
+	if span > 0 {
+		share = 100 * float64(busy) / float64(span) / float64(cpus)
+		throughput = float64(completed) / float64(span)
+	}
+	table.Footer = [][]string{
+		{"", "BUSY", "AVERAGE", "THROUGHPUT"},
+		{"", fmt.Sprintf("%.2f%%", share), opts.formatAverage(average), opts.formatThroughput(throughput)},
+	}
+	_, _ = fmt.Fprintf(w, "Window %s\n", win.format(opts))
+	_ = table.Render(w)
+}
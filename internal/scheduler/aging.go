@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// aging is scheduling by a value, lowest first, where the value of a job
+// drops the longer it has waited, so jobs with high values cannot starve. A
+// dispatched job keeps the boost it has earned. The value is the priority
+// of priority scheduling or the remaining time of shortest-job-first.
+//
+// Every ready job ages at the same rate, so their order never changes while
+// they wait and a heap can hold them, ordered by value and the time they had
+// waited when they became ready.
+type aging struct {
+	ready jobHeap
+	rate  float64
+	// value returns the value of a job before aging, and tie orders jobs
+	// whose aged values are equal.
+	value func(*Job) int64
+	tie   func(a, b *Job) bool
+}
+
+// NewAgingPriority returns a preemptive priority policy with aging. p must be
+// valid, see AgingParams.Validate.
+func NewAgingPriority(p AgingParams) Policy {
+	return newAging(p, func(j *Job) int64 { return j.Priority }, morePressing)
+}
+
+// NewAgingSJF returns a preemptive shortest-job-first policy with aging,
+// where a job's remaining time counts for less the longer it has waited. p
+// must be valid, see AgingParams.Validate.
+func NewAgingSJF(p AgingParams) Policy {
+	return newAging(p, (*Job).Expected, shorter)
+}
+
+func newAging(p AgingParams, value func(*Job) int64, tie func(a, b *Job) bool) *aging {
+	a := &aging{rate: p.Rate, value: value, tie: tie}
+	a.ready.less = boostedFirst(a.before)
+	return a
+}
+
+// gap returns how much higher the priority value of a job is than that of
+// another, if its value is dp above and it has waited dw ticks longer.
+// Taking the differences first rounds once, so jobs that tie on paper, such
+// as ones 1 apart in priority and 10 ticks in waiting at a rate of 0.1, tie.
+func (p *aging) gap(dp, dw int64) float64 {
+	return float64(dp) - p.rate*float64(dw)
+}
+
+// before orders ready jobs by their aged value at any time.
+func (p *aging) before(a, b *Job) bool {
+	if g := p.gap(p.value(a)-p.value(b), (a.Waited-a.Ready)-(b.Waited-b.Ready)); g != 0 {
+		return g < 0
+	}
+	return p.tie(a, b)
+}
+
+// over returns the gap between ready job j at time now and running job r,
+// which stops aging once dispatched.
+func (p *aging) over(j, r *Job, now int64) float64 {
+	return p.gap(p.value(j)-p.value(r), j.Waited+now-j.Ready-r.Waited)
+}
+
+func (p *aging) Push(j *Job, _ int64) { p.ready.push(j) }
+func (p *aging) Pop(int64) *Job       { return p.ready.pop() }
+
+// Preempt reports whether a ready job has strictly overtaken the running
+// job. Ties leave the running job be so two jobs aging at the same rate do
+// not take turns every tick.
+func (p *aging) Preempt(running *Job, now int64) bool {
+	j := p.ready.peek()
+	if j == nil {
+		return false
+	}
+	if first, ok := favoured(j, running); ok {
+		return first
+	}
+	return p.over(j, running, now) < 0
+}
+
+func (p *aging) Quantum(*Job) int64 { return 0 }
+
+func (p *aging) SaveState() (json.RawMessage, error) { return p.ready.save() }
+
+func (p *aging) RestoreState(state json.RawMessage, job func(int) *Job) error {
+	return p.ready.restore(state, job)
+}
+
+func (p *aging) List(int64) []*Job { return sortedJobs(p.ready.jobs, p.ready.less) }
+
+// Wake returns when the most pressing ready job will have aged past the
+// least pressing running one boosted alike; a boosted job takes the CPU of
+// one not boosted straight away, and never the other way around. It may be
+// early, which only costs a check that preempts nothing: by a tick, or
+// under shortest-job-first by however much the running job's remaining time
+// falls in the meantime.
+func (p *aging) Wake(now int64, running []*Job) int64 {
+	j := p.ready.peek()
+	if p.rate == 0 || j == nil {
+		return math.MaxInt64
+	}
+	var worst *Job
+	for _, r := range running {
+		if r.Boosted == j.Boosted && (worst == nil || p.gap(p.value(r)-p.value(worst), r.Waited-worst.Waited) > 0) {
+			worst = r
+		}
+	}
+	if worst == nil {
+		return math.MaxInt64
+	}
+	// j overtakes once dp < rate*(dw+t), where dw is how much longer than
+	// worst it has waited at time 0.
+	dw := j.Waited - j.Ready - worst.Waited
+	t := math.Floor(float64(p.value(j)-p.value(worst))/p.rate) - float64(dw)
+	if t >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return max(now, int64(t))
+}
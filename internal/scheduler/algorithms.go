@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DefaultQuantum is the round-robin time quantum used unless told otherwise.
+const DefaultQuantum = 5
+
+var ErrUnknownAlgorithm = errors.New("unknown algorithm")
+
+// Algorithm describes a scheduling algorithm available to front ends.
+type Algorithm struct {
+	// Name is the short identifier used on command lines and in APIs.
+	Name string
+	// Title is the heading the algorithm's results are shown under.
+	Title string
+	// New returns a fresh policy configured by opts.
+	New func(opts Options) Policy
+}
+
+// Algorithms returns every algorithm in the order they are usually shown.
+func Algorithms() []Algorithm {
+	return []Algorithm{
+		{"fcfs", "First-come, first-serve", func(o Options) Policy { return NewFCFS(o.FCFS) }},
+		{"sjf", "Shortest-job-first", func(o Options) Policy {
+			if o.Aging.applies() {
+				return NewAgingSJF(o.Aging)
+			}
+			return NewSJF()
+		}},
+		{"priority", "Priority", func(o Options) Policy {
+			if o.Aging.applies() {
+				return NewAgingPriority(o.Aging)
+			}
+			return NewPriority()
+		}},
+		{"rr", "Round-robin", func(o Options) Policy { return NewRR(o.RR) }},
+		{"mlfq", "Multilevel feedback queue", func(o Options) Policy { return NewMLFQ(o.MLFQ) }},
+		{"aging", "Priority with aging", func(o Options) Policy { return NewAgingPriority(o.Aging) }},
+		{"lottery", "Lottery", func(o Options) Policy { return NewLottery(o.RR, NewRand(o.Seed)) }},
+		{"random", "Random", func(o Options) Policy { return NewRandom(NewRand(o.Seed)) }},
+		{"fairshare", "Fair-share groups", func(o Options) Policy { return NewFairShare(o.FairShare, o.RR) }},
+		{"classes", "Process classes", NewClasses},
+	}
+}
+
+// LookupAlgorithm returns the algorithm with the given name.
+func LookupAlgorithm(name string) (Algorithm, error) {
+	for _, a := range Algorithms() {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+	return Algorithm{}, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, name)
+}
+
+// Schedule runs policy over processes to completion on a single CPU.
+func Schedule(policy Policy, processes []Process) Result {
+	return run(context.Background(), policy, processes, DefaultOptions(), nil)
+}
@@ -0,0 +1,150 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrAssertion is wrapped by every AssertionError.
+var ErrAssertion = errors.New("assertion failed")
+
+// AssertionError is what a run under Options.Assert panics with once its
+// state breaks an invariant.
+type AssertionError struct {
+	// Time is when the run was found broken.
+	Time    int64
+	Problem string
+	// Dump describes the state of the run then, a line per CPU, queue and
+	// tally.
+	Dump string
+}
+
+func (e *AssertionError) Error() string {
+	return fmt.Sprintf("%v at time %d: %s", ErrAssertion, e.Time, e.Problem)
+}
+
+func (e *AssertionError) Unwrap() error { return ErrAssertion }
+
+// assert panics with an *AssertionError if the state of e, between steps,
+// is inconsistent.
+func (e *engine) assert() {
+	if problem := e.inconsistency(); problem != "" {
+		panic(&AssertionError{Time: e.t, Problem: problem, Dump: e.dump()})
+	}
+}
+
+// inconsistency describes the first way the state of e is inconsistent, or
+// returns "" if it is not: every arrived job must be running on one CPU,
+// waiting in one queue, set aside by a suspension or complete, no job may have a negative remaining
+// time, nor run, wait or complete before it arrives. The ready queue of a
+// policy is checked only when it is a Lister.
+func (e *engine) inconsistency() string {
+	name := func(j *Job) string { return fmt.Sprintf("P%d (process %d)", j.ProcessID, j.Index) }
+	arrived := make([]bool, len(e.jobs))
+	for _, j := range e.arrivals[:e.next] {
+		arrived[j.Index] = true
+	}
+	completed := 0
+	for i := range e.jobs {
+		j := &e.jobs[i]
+		switch {
+		case j.Remaining < 0:
+			return fmt.Sprintf("%s has %d remaining", name(j), j.Remaining)
+		case j.Remaining > j.BurstDuration:
+			return fmt.Sprintf("%s has %d remaining of its burst %d", name(j), j.Remaining, j.BurstDuration)
+		case !arrived[i] && (j.completed || j.Remaining != j.BurstDuration):
+			return fmt.Sprintf("%s has run before arriving at %d", name(j), j.ArrivalTime)
+		case arrived[i] && j.ArrivalTime > e.t:
+			return fmt.Sprintf("%s has arrived before %d", name(j), j.ArrivalTime)
+		}
+		if j.completed {
+			completed++
+		}
+	}
+	if completed != e.done {
+		return fmt.Sprintf("counted %d jobs complete, but %d are", e.done, completed)
+	}
+
+	// where says where each arrived job is, if anywhere.
+	where := make([]string, len(e.jobs))
+	place := func(j *Job, at string) string {
+		switch {
+		case !arrived[j.Index]:
+			return fmt.Sprintf("%s is %s before arriving at %d", name(j), at, j.ArrivalTime)
+		case j.completed:
+			return fmt.Sprintf("%s is %s but complete", name(j), at)
+		case where[j.Index] != "":
+			return fmt.Sprintf("%s is %s and %s at once", name(j), where[j.Index], at)
+		}
+		where[j.Index] = at
+		return ""
+	}
+	for c := range e.cpus {
+		if j := e.cpus[c].job; j != nil {
+			if problem := place(j, fmt.Sprintf("running on CPU %d", c)); problem != "" {
+				return problem
+			}
+		}
+	}
+	for _, j := range e.requeue {
+		if problem := place(j, "requeued"); problem != "" {
+			return problem
+		}
+	}
+	for _, j := range e.heldJobs() {
+		if problem := place(j, "suspended"); problem != "" {
+			return problem
+		}
+	}
+	l, ok := e.policy.(Lister)
+	if !ok {
+		return ""
+	}
+	for _, j := range l.List(e.t) {
+		if problem := place(j, "ready"); problem != "" {
+			return problem
+		}
+	}
+	for _, j := range e.arrivals[:e.next] {
+		if !j.completed && where[j.Index] == "" {
+			return fmt.Sprintf("%s is neither running, ready nor complete", name(j))
+		}
+	}
+	return ""
+}
+
+// dump describes the state of e for an AssertionError.
+func (e *engine) dump() string {
+	var b strings.Builder
+	job := func(j *Job) string {
+		return fmt.Sprintf("P%d (process %d, remaining %d, ready %d, waited %d)", j.ProcessID, j.Index, j.Remaining, j.Ready, j.Waited)
+	}
+	jobs := func(js []*Job) string {
+		if len(js) == 0 {
+			return "none"
+		}
+		s := make([]string, len(js))
+		for i, j := range js {
+			s[i] = job(j)
+		}
+		return strings.Join(s, ", ")
+	}
+	fmt.Fprintf(&b, "time %d\n", e.t)
+	for c, p := range e.cpus {
+		if p.job == nil {
+			fmt.Fprintf(&b, "cpu %d: idle\n", c)
+			continue
+		}
+		fmt.Fprintf(&b, "cpu %d: %s, resumes %d, expires %d\n", c, job(p.job), p.resume, p.expires)
+	}
+	if l, ok := e.policy.(Lister); ok {
+		fmt.Fprintf(&b, "ready: %s\n", jobs(l.List(e.t)))
+	} else {
+		fmt.Fprintf(&b, "ready: unknown, %T lists none\n", e.policy)
+	}
+	fmt.Fprintf(&b, "requeued: %s\n", jobs(e.requeue))
+	fmt.Fprintf(&b, "suspended: %s\n", jobs(e.heldJobs()))
+	fmt.Fprintf(&b, "arrived %d of %d, completed %d\n", e.next, len(e.jobs), e.done)
+	return b.String()
+}
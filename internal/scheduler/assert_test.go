@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAssertBuiltins(t *testing.T) {
+	r := rand.New(rand.NewPCG(9, 10))
+	for range 50 {
+		processes := randomWorkload(r)
+		for _, opts := range [][]Option{
+			{WithAssertions()},
+			{WithAssertions(), WithCPUs(3), WithSwitchCost(1), WithQuantum(2)},
+		} {
+			sim := mustSimulator(t, opts...)
+			plain := mustSimulator(t, WithOptions(sim.Options()), func(o *Options) { o.Assert = false })
+			for _, a := range Algorithms() {
+				if got, want := sim.Schedule(a, processes), plain.Schedule(a, processes); !reflect.DeepEqual(got, want) {
+					t.Fatalf("%s: asserting changed the result", a.Name)
+				}
+			}
+		}
+	}
+}
+
+// rerun dispatches its last job once more after it completes.
+type rerun struct {
+	fifo
+	last *Job
+}
+
+func (p *rerun) Push(j *Job, _ int64) { p.push(j) }
+
+func (p *rerun) Pop(int64) *Job {
+	if j := p.last; p.len() == 0 && j != nil && j.completed {
+		p.last = nil
+		return j
+	}
+	p.last = p.pop()
+	return p.last
+}
+
+func (p *rerun) Preempt(*Job, int64) bool { return false }
+func (p *rerun) Quantum(*Job) int64       { return 0 }
+
+// forget lists no ready jobs.
+type forget struct{ rr }
+
+func (p *forget) List(int64) []*Job { return nil }
+
+func TestAssertBroken(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		policy    Policy
+		processes []Process
+		want      string
+	}{
+		{"rerun", &rerun{}, []Process{{ProcessID: 1, BurstDuration: 5}, {ProcessID: 2, BurstDuration: 3, ArrivalTime: 10}}, "counted 2 jobs complete, but 1 are"},
+		{"forget", &forget{rr{quantum: 5}}, example(), "is neither running, ready nor complete"},
+	} {
+		func() {
+			defer func() {
+				failed, ok := recover().(*AssertionError)
+				switch {
+				case !ok:
+					t.Errorf("%s: no assertion failed", tt.name)
+				case !errors.Is(failed, ErrAssertion) || !strings.Contains(failed.Problem, tt.want):
+					t.Errorf("%s: %v, want %q", tt.name, failed, tt.want)
+				case !strings.Contains(failed.Dump, "cpu 0: "):
+					t.Errorf("%s: dump without the CPUs:\n%s", tt.name, failed.Dump)
+				}
+			}()
+			opts := DefaultOptions()
+			opts.Assert = true
+			run(context.Background(), tt.policy, tt.processes, opts, nil)
+		}()
+	}
+}
+
+func TestAssertSimulate(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Assert = true
+	sim := simulate(context.Background(), &forget{rr{quantum: 5}}, example(), opts)
+	for range sim.Events {
+	}
+	defer func() {
+		if _, ok := recover().(*AssertionError); !ok {
+			t.Error("Result did not fail with the assertion")
+		}
+	}()
+	sim.Result()
+}
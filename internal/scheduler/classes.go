@@ -0,0 +1,229 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Class is the scheduling class of a process. Scheduling by class runs each
+// class under an algorithm of its own, and the processes of a class only
+// while none of a class above it is ready, as Linux runs SCHED_FIFO tasks
+// ahead of CFS ones and those ahead of SCHED_IDLE ones.
+type Class string
+
+const (
+	ClassRealtime Class = "realtime"
+	// ClassInteractive is the class of processes given none, also meant by
+	// the empty class.
+	ClassInteractive Class = "interactive"
+	ClassBatch       Class = "batch"
+)
+
+var ErrUnknownClass = errors.New("unknown class")
+
+// classes holds every class, the most important first.
+var classes = [...]Class{ClassRealtime, ClassInteractive, ClassBatch}
+
+// rank returns the place of c in classes. Classes not known rank as
+// interactive.
+func (c Class) rank() int {
+	switch c {
+	case ClassRealtime:
+		return 0
+	case ClassBatch:
+		return 2
+	}
+	return 1
+}
+
+func (c *Class) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*c = ""
+		return nil
+	}
+	for _, k := range classes {
+		if string(text) == string(k) {
+			*c = k
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %q, want realtime, interactive or batch", ErrUnknownClass, text)
+}
+
+// DefaultClassAlgorithm returns the name of the algorithm scheduling class
+// c unless ClassParams name another: priority for realtime processes,
+// round-robin for interactive ones and shortest-job-first for batch ones.
+func DefaultClassAlgorithm(c Class) string {
+	switch c.rank() {
+	case 0:
+		return "priority"
+	case 2:
+		return "sjf"
+	}
+	return "rr"
+}
+
+// classPolicy is what scheduling by class needs of the policy of a class,
+// which every built-in policy is.
+type classPolicy interface {
+	Policy
+	Remover
+	Snapshotter
+}
+
+// byClass runs the jobs of each class under a policy of its own, those of a
+// class only while no job of a class above it is ready, and preempts a job
+// as soon as one of a class above it is ready.
+type byClass struct {
+	policies [len(classes)]classPolicy
+	// ready counts the ready jobs of each class.
+	ready [len(classes)]int
+	// running holds the running jobs of the class Wake is asking about.
+	running []*Job
+}
+
+// listedByClass is a byClass whose classes all list their ready jobs.
+type listedByClass struct{ *byClass }
+
+// NewClasses returns a policy scheduling each class of jobs by the
+// algorithm o.Classes names for it, configured by o. o must be valid, see
+// Options.Validate.
+func NewClasses(o Options) Policy {
+	p := &byClass{}
+	lists := true
+	for c, class := range classes {
+		a, _ := LookupAlgorithm(o.Classes.Algorithm(class))
+		p.policies[c] = a.New(o).(classPolicy)
+		_, ok := p.policies[c].(Lister)
+		lists = lists && ok
+	}
+	if lists {
+		return listedByClass{p}
+	}
+	return p
+}
+
+func (p *byClass) Push(j *Job, now int64) {
+	c := j.Class.rank()
+	p.policies[c].Push(j, now)
+	p.ready[c]++
+}
+
+func (p *byClass) Pop(now int64) *Job {
+	for c, policy := range p.policies {
+		if p.ready[c] == 0 {
+			continue
+		}
+		if j := policy.Pop(now); j != nil {
+			p.ready[c]--
+			return j
+		}
+	}
+	return nil
+}
+
+func (p *byClass) Preempt(running *Job, now int64) bool {
+	c := running.Class.rank()
+	for above := range c {
+		if p.ready[above] > 0 {
+			return true
+		}
+	}
+	return p.policies[c].Preempt(running, now)
+}
+
+func (p *byClass) Quantum(j *Job) int64 { return p.policies[j.Class.rank()].Quantum(j) }
+
+func (p *byClass) Remove(j *Job) {
+	c := j.Class.rank()
+	p.policies[c].Remove(j)
+	p.ready[c]--
+}
+
+// Wake returns the earliest time the policy of any class waking up wants
+// its running jobs checked.
+func (p *byClass) Wake(now int64, running []*Job) int64 {
+	next := int64(math.MaxInt64)
+	for c, policy := range p.policies {
+		w, ok := policy.(Waker)
+		if !ok {
+			continue
+		}
+		p.running = p.running[:0]
+		for _, j := range running {
+			if j.Class.rank() == c {
+				p.running = append(p.running, j)
+			}
+		}
+		next = min(next, w.Wake(now, p.running))
+	}
+	clear(p.running)
+	return next
+}
+
+func (p listedByClass) List(now int64) []*Job {
+	var out []*Job
+	for _, policy := range p.policies {
+		out = append(out, policy.(Lister).List(now)...)
+	}
+	return out
+}
+
+// byClassSaved is the state of a byClass: the state of the policy of each
+// class, the most important first, and how many jobs it has ready.
+type byClassSaved struct {
+	Classes []byClassSavedClass `json:"classes"`
+}
+
+type byClassSavedClass struct {
+	Class  Class           `json:"class"`
+	Ready  int             `json:"ready"`
+	Policy json.RawMessage `json:"policy"`
+}
+
+func (p *byClass) SaveState() (json.RawMessage, error) {
+	var saved byClassSaved
+	for c, policy := range p.policies {
+		state, err := policy.SaveState()
+		if err != nil {
+			return nil, fmt.Errorf("%s class: %w", classes[c], err)
+		}
+		saved.Classes = append(saved.Classes, byClassSavedClass{classes[c], p.ready[c], state})
+	}
+	return json.Marshal(saved)
+}
+
+func (p *byClass) RestoreState(state json.RawMessage, job func(int) *Job) error {
+	var saved byClassSaved
+	if err := json.Unmarshal(state, &saved); err != nil {
+		return err
+	}
+	if len(saved.Classes) != len(classes) {
+		return fmt.Errorf("%d classes, want %d", len(saved.Classes), len(classes))
+	}
+	for c, s := range saved.Classes {
+		if s.Class != classes[c] {
+			return fmt.Errorf("class %q in place of %s", s.Class, classes[c])
+		}
+		// Jobs of other classes cannot be ready in this one.
+		err := p.policies[c].RestoreState(s.Policy, func(i int) *Job {
+			if j := job(i); j != nil && j.Class.rank() == c {
+				return j
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("%s class: %w", s.Class, err)
+		}
+		if l, ok := p.policies[c].(Lister); ok && len(l.List(0)) != s.Ready {
+			return fmt.Errorf("%s class has %d jobs ready, not %d", s.Class, len(l.List(0)), s.Ready)
+		}
+		if s.Ready < 0 {
+			return fmt.Errorf("%s class has %d jobs ready", s.Class, s.Ready)
+		}
+		p.ready[c] = s.Ready
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// The tests in this file are meant to be run with -race; they pass without it
+// but only the race detector proves the package shares no mutable state.
+
+func TestConcurrentSchedule(t *testing.T) {
+	sim := mustSimulator(t, WithCPUs(2), WithSwitchCost(1))
+	processes := largeWorkload(300)
+
+	want := make(map[string]Result)
+	for _, a := range Algorithms() {
+		want[a.Name] = sim.Schedule(a, processes)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		for _, a := range Algorithms() {
+			wg.Add(1)
+			go func(a Algorithm) {
+				defer wg.Done()
+				if got := sim.Schedule(a, processes); !reflect.DeepEqual(got, want[a.Name]) {
+					t.Errorf("%s: concurrent result differs from sequential one", a.Name)
+				}
+			}(a)
+		}
+	}
+	wg.Wait()
+
+	if !reflect.DeepEqual(processes, largeWorkload(300)) {
+		t.Error("concurrent runs modified the shared workload")
+	}
+}
+
+func TestConcurrentSimulate(t *testing.T) {
+	sim := mustSimulator(t)
+	processes := largeWorkload(100)
+
+	var wg sync.WaitGroup
+	for _, a := range Algorithms() {
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(a Algorithm) {
+				defer wg.Done()
+				run := sim.Simulate(context.Background(), a, processes)
+				completed := 0
+				for ev := range run.Events {
+					if ev.Kind == EventComplete {
+						completed++
+					}
+				}
+				if completed != len(processes) {
+					t.Errorf("%s: got %d completions, want %d", a.Name, completed, len(processes))
+				}
+				if !reflect.DeepEqual(run.Result(), sim.Schedule(a, processes)) {
+					t.Errorf("%s: streamed result differs from Schedule", a.Name)
+				}
+			}(a)
+		}
+	}
+	wg.Wait()
+}
+
+func TestConcurrentSimulators(t *testing.T) {
+	processes := example()
+	var wg sync.WaitGroup
+	for q := int64(1); q <= 8; q++ {
+		wg.Add(1)
+		go func(q int64) {
+			defer wg.Done()
+			sim, err := NewSimulator(WithQuantum(q), WithMLFQ(MLFQParams{Levels: 2, Quanta: []int64{q, 2 * q}}))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			opts := sim.Options()
+			opts.MLFQ.Quanta[0] = 100 // must not leak into sim
+			for _, a := range Algorithms() {
+				sim.Schedule(a, processes)
+			}
+			if sim.Options().MLFQ.Quanta[0] != q {
+				t.Error("Options exposed the simulator's internal state")
+			}
+		}(q)
+	}
+	wg.Wait()
+}
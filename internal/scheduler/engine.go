@@ -0,0 +1,590 @@
+package scheduler
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+)
+
+type (
+	// Job is the working state of one process during a simulation. Policies
+	// receive jobs from the engine and must not change their fields.
+	Job struct {
+		Process
+		// Index is the position of the process in the input workload.
+		Index int
+		// Remaining is the burst time still to be run.
+		Remaining int64
+		// Ready is the time the job last became ready to run.
+		Ready int64
+		// Waited is the time the job spent ready but not running before it
+		// last became ready.
+		Waited int64
+		// Boosted is set under Options.ResumeBoost from when the job
+		// resumes from a suspension until it next leaves a CPU. Shortest
+		// job first and priority scheduling, with aging or without, run
+		// boosted jobs ahead of every other, and MLFQ puts them back on its
+		// top level.
+		Boosted bool
+
+		// saved is how much less time the job has spent running than the
+		// burst it ran, which CPUs faster than speed 1 save it.
+		saved     int64
+		completed bool
+		// suspended is set while a suspension of the job is in force, and
+		// held once the engine has set it aside for it.
+		suspended, held bool
+	}
+
+	// Policy decides which ready job runs next. A Policy holds the ready
+	// queue of a single simulation, so a fresh one is needed for every run.
+	Policy interface {
+		// Push makes j ready to run at time now.
+		Push(j *Job, now int64)
+		// Pop removes and returns the next job to dispatch, or nil if no
+		// ready job should run yet.
+		Pop(now int64) *Job
+		// Preempt reports whether running should give up the CPU to a job
+		// that is ready at time now.
+		Preempt(running *Job, now int64) bool
+		// Quantum returns how long j may run once dispatched before it is
+		// preempted, or 0 to let it run until it completes.
+		Quantum(j *Job) int64
+	}
+
+	// Waker is implemented by policies whose choices change with the passage
+	// of time alone, rather than only when jobs arrive or leave a CPU.
+	Waker interface {
+		// Wake returns the next time after now at which the running jobs
+		// should be checked for preemption again. running must not be
+		// kept after Wake returns.
+		Wake(now int64, running []*Job) int64
+	}
+
+	// Placer is implemented by policies that choose which of the free CPUs
+	// a job runs on, as those of differing Options.Speeds call for. Other
+	// policies put it on the fastest, the lowest numbered of those as fast.
+	Placer interface {
+		// Place returns the index in free of the CPU j is dispatched to at
+		// time now. free holds the CPUs without a job, the fastest first,
+		// and must not be kept after Place returns.
+		Place(j *Job, free []int, now int64) int
+	}
+
+	EventKind int
+
+	// Event is a single state change in a simulation.
+	Event struct {
+		Kind EventKind `json:"kind"`
+		Time int64     `json:"time"`
+		PID  int64     `json:"pid"`
+		CPU  int       `json:"cpu"`
+	}
+)
+
+const (
+	// EventArrive is sent when a process arrives and becomes ready.
+	EventArrive EventKind = iota
+	// EventDispatch is sent when a process is given a CPU.
+	EventDispatch
+	// EventPreempt is sent when a running process loses its CPU before
+	// completing, either to another process or because its quantum expired.
+	EventPreempt
+	// EventComplete is sent when a process finishes its burst.
+	EventComplete
+	// EventIdle is sent when a CPU has nothing to run.
+	EventIdle
+	// EventSuspend is sent when an arrived process is set aside by a
+	// Suspension, with the CPU it gives up if it was running, and
+	// EventResume when it is ready again.
+	EventSuspend
+	EventResume
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventArrive:
+		return "arrive"
+	case EventDispatch:
+		return "dispatch"
+	case EventPreempt:
+		return "preempt"
+	case EventComplete:
+		return "complete"
+	case EventIdle:
+		return "idle"
+	case EventSuspend:
+		return "suspend"
+	case EventResume:
+		return "resume"
+	}
+	return "unknown"
+}
+
+func (k EventKind) MarshalText() ([]byte, error) { return []byte(k.String()), nil }
+
+func (k *EventKind) UnmarshalText(text []byte) error {
+	for c := EventArrive; c <= EventResume; c++ {
+		if c.String() == string(text) {
+			*k = c
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown event kind %q", text)
+}
+
+// Expected returns the time the job is expected to still need: what is left
+// of its expected burst once the time it has run is taken off, or none if
+// it has run longer. It is Remaining unless the process has an estimate.
+func (j *Job) Expected() int64 {
+	return max(0, j.ExpectedBurst()-(j.BurstDuration-j.Remaining))
+}
+
+// Simulation is a run of a policy in progress, see Simulate.
+type Simulation struct {
+	// Events delivers every event of the run in time order and is closed
+	// once the run is over.
+	Events <-chan Event
+
+	done   chan struct{}
+	res    Result
+	failed *AssertionError
+}
+
+// Simulate runs policy over processes in the background, streaming its events
+// as they occur. Events must be drained for the run to make progress; once
+// ctx is cancelled the run stops early.
+func Simulate(ctx context.Context, policy Policy, processes []Process) *Simulation {
+	return simulate(ctx, policy, processes, DefaultOptions())
+}
+
+func simulate(ctx context.Context, policy Policy, processes []Process, opts Options) *Simulation {
+	events := make(chan Event)
+	sim := &Simulation{Events: events, done: make(chan struct{})}
+	go func() {
+		defer close(sim.done)
+		defer close(events)
+		defer func() {
+			// An assertion failing in the background fails Result instead.
+			if v := recover(); v != nil {
+				failed, ok := v.(*AssertionError)
+				if !ok {
+					panic(v)
+				}
+				sim.failed = failed
+			}
+		}()
+		sim.res = run(ctx, policy, processes, opts, func(ev Event) {
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return sim
+}
+
+// Result waits for the simulation to finish and returns its result. If the
+// run was cancelled only processes completed by then are in its Stats. Result
+// panics with the *AssertionError of a run under Options.Assert that
+// failed one.
+func (s *Simulation) Result() Result {
+	<-s.done
+	if s.failed != nil {
+		panic(s.failed)
+	}
+	return s.res
+}
+
+// cpu is the state of a single processor.
+type cpu struct {
+	job *Job
+	// last is the job that ran most recently, to tell context switches apart
+	// from a job resuming where it left off.
+	last *Job
+	// resume is when the running job starts making progress, after any
+	// context switch.
+	resume int64
+	// expires is when the running job's quantum runs out.
+	expires int64
+	idle    bool
+	// slice is one more than the index in the Gantt chart of the last slice
+	// the CPU ran, or 0 before its first.
+	slice int
+}
+
+// engine is the state of one run of a policy over a workload.
+type engine struct {
+	policy  Policy
+	opts    Options
+	buffers *jobBuffers
+	// onEvent and observe, if set, are told about every event, the latter
+	// along with a snapshot of the state it left the run in.
+	onEvent func(Event)
+	observe func(State)
+	sink    Sink
+
+	jobs     []Job
+	arrivals []*Job
+	cpus     []cpu
+	// order holds the CPUs fastest first, and free those of them without a
+	// job while jobs are dispatched.
+	order   []int
+	free    []int
+	requeue []*Job
+	running []*Job
+	// changes are the suspensions and resumptions of the run, of which
+	// those before changed have been made.
+	changes []change
+	changed int
+	res     Result
+	t       int64
+	next    int
+	done    int
+}
+
+// jobBuffers are the per-job arrays of an engine. They are kept in
+// jobPool between runs, so runs back to back, as in sweeps and benchmarks,
+// do not allocate them afresh.
+type jobBuffers struct {
+	jobs     []Job
+	arrivals []*Job
+}
+
+var jobPool = sync.Pool{New: func() any { return new(jobBuffers) }}
+
+func newEngine(policy Policy, processes []Process, opts Options) *engine {
+	n := len(processes)
+	b := jobPool.Get().(*jobBuffers)
+	if cap(b.jobs) < n {
+		b.jobs, b.arrivals = make([]Job, n), make([]*Job, n)
+	}
+	e := &engine{
+		policy:   policy,
+		opts:     opts,
+		buffers:  b,
+		jobs:     b.jobs[:n],
+		arrivals: b.arrivals[:n],
+		cpus:     make([]cpu, opts.CPUs),
+		order:    make([]int, opts.CPUs),
+		res:      Result{Stats: make([]Stat, n)},
+	}
+	for c := range e.order {
+		e.order[c] = c
+	}
+	slices.SortStableFunc(e.order, func(a, b int) int { return cmp.Compare(opts.Speed(b), opts.Speed(a)) })
+	if !opts.NoGantt {
+		// Most processes run in a single slice.
+		e.res.Gantt = make([]TimeSlice, 0, n)
+	}
+	for i := range processes {
+		// A negative burst, which Process.Validate rejects, runs as none
+		// rather than never reaching its end.
+		e.jobs[i] = Job{Process: processes[i], Index: i, Remaining: max(0, processes[i].BurstDuration)}
+		e.arrivals[i] = &e.jobs[i]
+	}
+	e.changes = changes(e.jobs, opts.Suspensions)
+	slices.SortStableFunc(e.arrivals, func(a, b *Job) int {
+		return cmp.Compare(a.ArrivalTime, b.ArrivalTime)
+	})
+	if n > 0 {
+		e.t = min(e.t, e.arrivals[0].ArrivalTime)
+	}
+	return e
+}
+
+// release returns the buffers of a finished run to jobPool. Runs stopped
+// early are not released, as their policy may still hold jobs.
+func (e *engine) release() {
+	if e.done < len(e.jobs) || e.buffers == nil {
+		return
+	}
+	clear(e.arrivals)
+	jobPool.Put(e.buffers)
+	e.buffers, e.jobs, e.arrivals = nil, nil, nil
+}
+
+// run drives policy over processes until all of them complete or ctx is
+// cancelled, passing every event to emit if it is not nil.
+func run(ctx context.Context, policy Policy, processes []Process, opts Options, emit func(Event)) Result {
+	e := newEngine(policy, processes, opts)
+	e.onEvent = emit
+	return e.run(ctx)
+}
+
+func (e *engine) emit(ev Event) {
+	if e.onEvent != nil {
+		e.onEvent(ev)
+	}
+	if e.observe != nil {
+		e.observe(e.snapshot(ev))
+	}
+}
+
+// stop takes the job off CPU c, recording the slice it ran for.
+func (e *engine) stop(c int) *Job {
+	j := e.cpus[c].job
+	if e.t > e.cpus[c].resume && !e.opts.NoGantt {
+		// A job put straight back on the CPU it left, such as the only
+		// ready job at the end of its quantum, extends its last slice.
+		if i := e.cpus[c].slice - 1; i >= 0 && e.res.Gantt[i].PID == j.ProcessID && e.res.Gantt[i].Stop == e.cpus[c].resume {
+			e.res.Gantt[i].Stop = e.t
+		} else {
+			e.flush(c)
+			e.res.Gantt = append(e.res.Gantt, TimeSlice{PID: j.ProcessID, Start: e.cpus[c].resume, Stop: e.t, CPU: c})
+			e.cpus[c].slice = len(e.res.Gantt)
+		}
+	}
+	e.cpus[c].job = nil
+	j.Boosted = false
+	return j
+}
+
+// flush passes the last slice of CPU c to the sink, once no more can be
+// added to it.
+func (e *engine) flush(c int) {
+	if i := e.cpus[c].slice - 1; i >= 0 && e.sink.Slice != nil {
+		e.sink.Slice(e.res.Gantt[i])
+	}
+}
+
+func (e *engine) run(ctx context.Context) Result {
+	n := len(e.jobs)
+	for e.done < n && ctx.Err() == nil {
+		e.step()
+		if e.opts.Assert {
+			e.assert()
+		}
+	}
+	for c := range e.cpus {
+		e.flush(c)
+	}
+	if e.done < n {
+		e.settled()
+	}
+	e.release()
+	e.res.summarize()
+	return e.res
+}
+
+// settled drops the timings of the processes yet to complete from a run
+// stopped early.
+func (e *engine) settled() {
+	stats := e.res.Stats[:0]
+	for i, s := range e.res.Stats {
+		if e.jobs[i].completed {
+			stats = append(stats, s)
+		}
+	}
+	e.res.Stats = stats
+}
+
+// step makes every scheduling decision due at the current time and then
+// advances to the next arrival, completion, quantum expiry, suspension or
+// resumption.
+func (e *engine) step() {
+	n := len(e.jobs)
+
+	// Arrivals are queued ahead of jobs whose quantum just expired.
+	for ; e.next < n && e.arrivals[e.next].ArrivalTime <= e.t; e.next++ {
+		j := e.arrivals[e.next]
+		j.Ready = e.t
+		if j.suspended {
+			e.emit(Event{Kind: EventArrive, Time: e.t, PID: j.ProcessID})
+			e.hold(j, 0)
+			continue
+		}
+		e.policy.Push(j, e.t)
+		e.emit(Event{Kind: EventArrive, Time: e.t, PID: j.ProcessID})
+	}
+	// Jobs resumed are queued along with arrivals, ahead of those whose
+	// quantum expired.
+	for ; e.changed < len(e.changes) && e.changes[e.changed].t <= e.t; e.changed++ {
+		if ch := e.changes[e.changed]; ch.suspend {
+			e.suspend(ch.job)
+		} else {
+			e.resume(ch.job)
+		}
+	}
+	for i, j := range e.requeue {
+		e.requeue[i] = nil
+		e.policy.Push(j, e.t)
+	}
+	e.requeue = e.requeue[:0]
+
+	// A job is not preempted until it has run past the context switch to it,
+	// lest two jobs aging past each other, say, take turns switching and
+	// neither runs; see the wake-up below.
+	for c := range e.cpus {
+		if e.cpus[c].job != nil && e.t > e.cpus[c].resume && e.policy.Preempt(e.cpus[c].job, e.t) {
+			j := e.stop(c)
+			j.Ready = e.t
+			e.policy.Push(j, e.t)
+			e.emit(Event{Kind: EventPreempt, Time: e.t, PID: j.ProcessID, CPU: c})
+		}
+	}
+
+	e.free = e.free[:0]
+	for _, c := range e.order {
+		if e.cpus[c].job == nil {
+			e.free = append(e.free, c)
+		}
+	}
+	placer, _ := e.policy.(Placer)
+	for len(e.free) > 0 {
+		j := e.policy.Pop(e.t)
+		for ; j != nil && j.suspended; j = e.policy.Pop(e.t) {
+			j.Waited += e.t - j.Ready
+			e.hold(j, 0)
+		}
+		if j == nil {
+			break
+		}
+		k := 0
+		if placer != nil && len(e.free) > 1 {
+			if k = placer.Place(j, e.free, e.t); k < 0 || k >= len(e.free) {
+				panic("scheduler: policy placed a job on no free CPU")
+			}
+		}
+		c := e.free[k]
+		e.free = slices.Delete(e.free, k, k+1)
+		j.Waited += e.t - j.Ready
+		resume, slice := e.t, e.cpus[c].slice
+		if last := e.cpus[c].last; last != j {
+			if last != nil {
+				e.res.ContextSwitches++
+				resume = add(resume, e.opts.SwitchCost)
+			}
+			// Only j's own last slice can be extended, even by a process
+			// sharing its ID.
+			e.flush(c)
+			slice = 0
+		}
+		e.cpus[c] = cpu{job: j, last: j, resume: resume, expires: math.MaxInt64, slice: slice}
+		if q := e.policy.Quantum(j); q > 0 {
+			e.cpus[c].expires = add(resume, q)
+		}
+		e.emit(Event{Kind: EventDispatch, Time: e.t, PID: j.ProcessID, CPU: c})
+	}
+	for _, c := range e.free {
+		if !e.cpus[c].idle {
+			e.cpus[c].idle = true
+			e.emit(Event{Kind: EventIdle, Time: e.t, CPU: c})
+		}
+	}
+
+	nextT := int64(math.MaxInt64)
+	if e.next < n {
+		nextT = e.arrivals[e.next].ArrivalTime
+	}
+	if e.changed < len(e.changes) {
+		nextT = min(nextT, e.changes[e.changed].t)
+	}
+	for c := range e.cpus {
+		if j := e.cpus[c].job; j != nil {
+			nextT = min(nextT, add(max(e.t, e.cpus[c].resume), ticks(j.Remaining, e.opts.Speed(c))), e.cpus[c].expires)
+			if e.t <= e.cpus[c].resume && e.policy.Preempt(j, e.t) {
+				nextT = min(nextT, add(e.cpus[c].resume, 1))
+			}
+		}
+	}
+	if w, ok := e.policy.(Waker); ok {
+		e.running = e.running[:0]
+		for c := range e.cpus {
+			if j := e.cpus[c].job; j != nil {
+				e.running = append(e.running, j)
+			}
+		}
+		nextT = min(nextT, max(add(e.t, 1), w.Wake(e.t, e.running)))
+	}
+	if nextT == math.MaxInt64 {
+		switch {
+		case slices.ContainsFunc(e.cpus, func(c cpu) bool { return c.job != nil }):
+			e.saturate()
+			return
+		case e.next == n && e.changed == len(e.changes):
+			panic("scheduler: policy left ready processes unscheduled")
+		}
+		// Nothing runs until a job arrives or resumes at the last time
+		// there is.
+	}
+	for c := range e.cpus {
+		if j := e.cpus[c].job; j != nil && nextT > e.cpus[c].resume {
+			// A job completing part way through a tick still has the
+			// whole tick.
+			ran := nextT - max(e.t, e.cpus[c].resume)
+			burst := min(j.Remaining, mul(ran, e.opts.Speed(c)))
+			j.Remaining -= burst
+			j.saved += burst - ran
+		}
+	}
+	e.t = nextT
+
+	for c := range e.cpus {
+		j := e.cpus[c].job
+		switch {
+		case j == nil:
+		case j.Remaining == 0 && e.t >= e.cpus[c].resume:
+			e.finish(c)
+		case e.t >= e.cpus[c].expires:
+			e.stop(c)
+			j.Ready = e.t
+			e.requeue = append(e.requeue, j)
+			e.emit(Event{Kind: EventPreempt, Time: e.t, PID: j.ProcessID, CPU: c})
+		}
+	}
+}
+
+// finish takes the job off CPU c once it is complete and records its stats.
+func (e *engine) finish(c int) {
+	j := e.stop(c)
+	// The slice of a completed job can grow no longer.
+	e.flush(c)
+	e.cpus[c].slice = 0
+	e.complete(j, c)
+}
+
+func (e *engine) complete(j *Job, c int) {
+	e.done++
+	j.completed = true
+	e.res.Stats[j.Index] = Stat{
+		Process:    j.Process,
+		Wait:       e.t - j.ArrivalTime - max(0, j.BurstDuration) + j.saved,
+		Turnaround: e.t - j.ArrivalTime,
+		Completion: e.t,
+	}
+	if e.sink.Stat != nil {
+		e.sink.Stat(e.res.Stats[j.Index])
+	}
+	e.emit(Event{Kind: EventComplete, Time: e.t, PID: j.ProcessID, CPU: c})
+}
+
+// saturate ends a run whose time has reached math.MaxInt64, which only
+// workloads past their Horizon do: every process not yet complete,
+// running or not, completes there.
+func (e *engine) saturate() {
+	e.t = math.MaxInt64
+	for c := range e.cpus {
+		if j := e.cpus[c].job; j != nil {
+			j.Remaining = 0
+			e.finish(c)
+		}
+	}
+	for j := e.policy.Pop(e.t); j != nil; j = e.policy.Pop(e.t) {
+		e.complete(j, 0)
+	}
+	for _, j := range e.requeue {
+		e.complete(j, 0)
+	}
+	e.requeue = e.requeue[:0]
+	for _, j := range e.heldJobs() {
+		j.held = false
+		e.complete(j, 0)
+	}
+	for ; e.next < len(e.jobs); e.next++ {
+		e.complete(e.arrivals[e.next], 0)
+	}
+}
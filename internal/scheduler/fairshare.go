@@ -0,0 +1,212 @@
+package scheduler
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// fairShare divides the CPU between groups of jobs by their shares, as
+// cgroups do, and runs the jobs of a group round-robin. The ready group
+// that has used the least CPU time for its share runs next, ties going by
+// name, and it is charged a quantum, or what its job has left if that is
+// less, as the job is dispatched, and refunded what the job did not run
+// once it is back. A group that had no job ready is brought level with the
+// floor, the group last dispatched as it was then, so it cannot bank time
+// while it is idle.
+type fairShare struct {
+	params  FairShareParams
+	quantum int64
+	// groups holds every group a job has been ready in, by name.
+	groups []*shareGroup
+	floor  usage
+	// states holds the state of each job by its index, grown as jobs
+	// arrive.
+	states []fairShareState
+}
+
+type shareGroup struct {
+	name string
+	usage
+	ready fifo
+}
+
+// usage is the CPU time a group has been charged for and its share.
+type usage struct {
+	used, share int64
+}
+
+// behind reports whether u has used less for its share than v. The cross
+// products are taken in 128 bits, as times near the horizon overflow int64
+// once multiplied by a share.
+func (u usage) behind(v usage) bool {
+	uHi, uLo := bits.Mul64(uint64(u.used), uint64(v.share))
+	vHi, vLo := bits.Mul64(uint64(v.used), uint64(u.share))
+	return uHi < vHi || uHi == vHi && uLo < vLo
+}
+
+// levelWith raises the time u has used, if it is behind v, to as much for
+// its share as v has used for its own, rounded up and saturating at
+// math.MaxInt64.
+func (u *usage) levelWith(v usage) {
+	if !u.behind(v) {
+		return
+	}
+	hi, lo := bits.Mul64(uint64(v.used), uint64(u.share))
+	lo, carry := bits.Add64(lo, uint64(v.share-1), 0)
+	hi += carry
+	if hi >= uint64(v.share) {
+		u.used = math.MaxInt64
+		return
+	}
+	level, _ := bits.Div64(hi, lo, uint64(v.share))
+	u.used = int64(min(level, math.MaxInt64))
+}
+
+type fairShareState struct {
+	// dispatched is the job's remaining time when it last got a CPU, and
+	// charged what its group was charged for it then.
+	dispatched, charged int64
+	running             bool
+}
+
+// NewFairShare returns a fair-share policy with the group shares of p, in
+// which a job runs for a quantum of rr at a time. p and rr must be valid,
+// see their Validate methods.
+func NewFairShare(p FairShareParams, rr RRParams) Policy {
+	return &fairShare{params: p, quantum: rr.Quantum, floor: usage{share: 1}}
+}
+
+// state returns the state of the job at index i.
+func (p *fairShare) state(i int) *fairShareState {
+	if i >= len(p.states) {
+		p.states = append(p.states, make([]fairShareState, i+1-len(p.states))...)
+	}
+	return &p.states[i]
+}
+
+// group returns the group named name, adding it if it is new.
+func (p *fairShare) group(name string) *shareGroup {
+	i, ok := slices.BinarySearchFunc(p.groups, name, func(g *shareGroup, name string) int { return cmp.Compare(g.name, name) })
+	if !ok {
+		p.groups = slices.Insert(p.groups, i, &shareGroup{name: name, usage: usage{share: p.params.Share(name)}})
+	}
+	return p.groups[i]
+}
+
+func (p *fairShare) Push(j *Job, _ int64) {
+	g, s := p.group(j.Group), p.state(j.Index)
+	if s.running {
+		g.used = add(g.used, s.dispatched-j.Remaining-s.charged)
+		s.running = false
+	}
+	if g.ready.len() == 0 {
+		g.levelWith(p.floor)
+	}
+	g.ready.push(j)
+}
+
+func (p *fairShare) Pop(int64) *Job {
+	var next *shareGroup
+	for _, g := range p.groups {
+		if g.ready.len() > 0 && (next == nil || g.behind(next.usage)) {
+			next = g
+		}
+	}
+	if next == nil {
+		return nil
+	}
+	j := next.ready.pop()
+	s := p.state(j.Index)
+	s.dispatched, s.charged, s.running = j.Remaining, min(p.quantum, j.Remaining), true
+	if p.floor.behind(next.usage) {
+		p.floor = next.usage
+	}
+	next.used = add(next.used, s.charged)
+	return j
+}
+
+func (p *fairShare) Preempt(*Job, int64) bool { return false }
+func (p *fairShare) Quantum(*Job) int64       { return p.quantum }
+
+func (p *fairShare) Remove(j *Job) { p.group(j.Group).ready.remove(j) }
+
+func (p *fairShare) List(int64) []*Job {
+	var out []*Job
+	for _, g := range p.groups {
+		out = append(out, g.ready.ready()...)
+	}
+	return out
+}
+
+// fairShareSaved is the state of a fairShare: its floor, every group with
+// the time it has used and its ready jobs, oldest first, and the state of
+// every job on a CPU or set aside from one.
+type fairShareSaved struct {
+	FloorUsed  int64                 `json:"floorUsed"`
+	FloorShare int64                 `json:"floorShare"`
+	Groups     []fairShareSavedGroup `json:"groups"`
+	Jobs       []fairShareSavedJob   `json:"jobs,omitempty"`
+}
+
+type fairShareSavedGroup struct {
+	Group string `json:"group"`
+	Used  int64  `json:"used"`
+	Ready []int  `json:"ready"`
+}
+
+type fairShareSavedJob struct {
+	Index      int   `json:"index"`
+	Dispatched int64 `json:"dispatched"`
+	Charged    int64 `json:"charged"`
+}
+
+func (p *fairShare) SaveState() (json.RawMessage, error) {
+	saved := fairShareSaved{FloorUsed: p.floor.used, FloorShare: p.floor.share}
+	for _, g := range p.groups {
+		saved.Groups = append(saved.Groups, fairShareSavedGroup{g.name, g.used, indexes(g.ready.ready())})
+	}
+	for i, s := range p.states {
+		if s.running {
+			saved.Jobs = append(saved.Jobs, fairShareSavedJob{i, s.dispatched, s.charged})
+		}
+	}
+	return json.Marshal(saved)
+}
+
+func (p *fairShare) RestoreState(state json.RawMessage, job func(int) *Job) error {
+	var saved fairShareSaved
+	if err := json.Unmarshal(state, &saved); err != nil {
+		return err
+	}
+	if saved.FloorShare < 1 || saved.FloorUsed < 0 {
+		return fmt.Errorf("floor of %d used for a share of %d", saved.FloorUsed, saved.FloorShare)
+	}
+	p.floor = usage{saved.FloorUsed, saved.FloorShare}
+	for _, sg := range saved.Groups {
+		jobs, err := lookup(sg.Ready, job)
+		if err != nil {
+			return err
+		}
+		g := p.group(sg.Group)
+		g.used = sg.Used
+		for _, j := range jobs {
+			if j.Group != g.name {
+				return fmt.Errorf("job %d of group %q is ready in group %q", j.Index, j.Group, g.name)
+			}
+			g.ready.push(j)
+		}
+	}
+	for _, s := range saved.Jobs {
+		if s.Index < 0 {
+			return fmt.Errorf("job %d is not of a process", s.Index)
+		}
+		// Jobs set aside need their state when they resume, though they
+		// cannot be looked up until then.
+		*p.state(s.Index) = fairShareState{dispatched: s.Dispatched, charged: s.Charged, running: true}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package scheduler
+
+import "encoding/json"
+
+// fcfs dispatches processes in the order of its ready queue. Lined up as
+// given, a job must also wait for every job given before it to be
+// dispatched; lined up by arrival, the jobs that have arrived always come
+// before those yet to. A job dispatched once and suspended since comes
+// before every job yet to be dispatched.
+type fcfs struct {
+	ready jobHeap
+	given bool
+	next  int
+}
+
+// NewFCFS returns a first-come, first-serve policy. p must be valid, see
+// FCFSParams.Validate.
+func NewFCFS(p FCFSParams) Policy {
+	if p.Order == OrderGiven {
+		return &fcfs{ready: jobHeap{less: func(a, b *Job) bool { return a.Index < b.Index }}, given: true}
+	}
+	return &fcfs{ready: jobHeap{less: arrivesFirst}}
+}
+
+// arrivesFirst orders jobs by arrival time, then process ID, then the order
+// they were given in.
+func arrivesFirst(a, b *Job) bool {
+	switch {
+	case a.ArrivalTime != b.ArrivalTime:
+		return a.ArrivalTime < b.ArrivalTime
+	case a.ProcessID != b.ProcessID:
+		return a.ProcessID < b.ProcessID
+	}
+	return a.Index < b.Index
+}
+
+func (p *fcfs) Push(j *Job, _ int64) { p.ready.push(j) }
+
+func (p *fcfs) Pop(int64) *Job {
+	if !p.given {
+		return p.ready.pop()
+	}
+	j := p.ready.peek()
+	switch {
+	case j == nil || j.Index > p.next:
+		return nil
+	case j.Index == p.next:
+		p.next++
+	}
+	return p.ready.pop()
+}
+
+func (p *fcfs) Preempt(*Job, int64) bool { return false }
+func (p *fcfs) Quantum(*Job) int64       { return 0 }
+
+func (p *fcfs) SaveState() (json.RawMessage, error) {
+	return json.Marshal(readyState{Ready: indexes(p.ready.jobs), Next: p.next})
+}
+
+func (p *fcfs) RestoreState(state json.RawMessage, job func(int) *Job) error {
+	s, jobs, err := restoreReady(state, job)
+	for _, j := range jobs {
+		p.ready.push(j)
+	}
+	p.next = s.Next
+	return err
+}
+
+func (p *fcfs) List(int64) []*Job { return sortedJobs(p.ready.jobs, p.ready.less) }
+
+// FCFS schedules processes first-come, first-serve by arrival time.
+func FCFS(processes []Process) Result {
+	return Schedule(NewFCFS(FCFSParams{}), processes)
+}
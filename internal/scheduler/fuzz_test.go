@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// FuzzSchedule runs every algorithm over workloads built straight from
+// bytes, valid or not, as library callers may pass them: three signed bytes
+// per process give its burst, arrival and priority. Every run must end with
+// every process complete.
+func FuzzSchedule(f *testing.F) {
+	f.Add([]byte{5, 0, 2, 9, 3, 1, 6, 6, 3}, uint8(1), uint8(2))
+	f.Add([]byte{0xfd, 0, 0, 2, 0, 0}, uint8(1), uint8(1))
+	f.Add([]byte{3, 0xfb, 0, 0, 0, 0, 0x80, 0x80, 0x80}, uint8(3), uint8(0))
+	f.Fuzz(func(t *testing.T, data []byte, cpus, quantum uint8) {
+		processes := make([]Process, 0, len(data)/3)
+		for i := 0; i+3 <= len(data) && len(processes) < 30; i += 3 {
+			processes = append(processes, Process{
+				ProcessID:     int64(len(processes) + 1),
+				BurstDuration: int64(int8(data[i])),
+				ArrivalTime:   int64(int8(data[i+1])),
+				Priority:      int64(int8(data[i+2])),
+			})
+		}
+		sim, err := NewSimulator(WithCPUs(1+int(cpus%4)), WithQuantum(1+int64(quantum%8)), WithSwitchCost(int64(quantum%2)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, a := range Algorithms() {
+			done := make(chan Result, 1)
+			go func() { done <- sim.Schedule(a, processes) }()
+			select {
+			case res := <-done:
+				if len(res.Stats) != len(processes) {
+					t.Fatalf("%s: %d stats for %d processes %v", a.Name, len(res.Stats), len(processes), processes)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatalf("%s: no end to the run of %v", a.Name, processes)
+			}
+		}
+	})
+}
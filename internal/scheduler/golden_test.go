@@ -0,0 +1,100 @@
+package scheduler_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"p1/internal/input"
+	"p1/internal/scheduler"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden with the current results")
+
+// goldenVariants are the options every workload in testdata is scheduled
+// under, each with a golden file of its own.
+var goldenVariants = []struct {
+	name string
+	opts []scheduler.Option
+}{
+	{"default", nil},
+	{"cpus2", []scheduler.Option{scheduler.WithCPUs(2)}},
+	{"switch1", []scheduler.Option{scheduler.WithSwitchCost(1)}},
+}
+
+// TestGolden schedules every workload in testdata with every algorithm and
+// compares the results with testdata/golden/<workload>-<variant>.txt. Run
+// go test -run Golden -update to accept a change in results, and review the
+// diff of the golden files like any other.
+func TestGolden(t *testing.T) {
+	workloads, err := filepath.Glob(filepath.Join("testdata", "*.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workloads) == 0 {
+		t.Fatal("no workloads in testdata")
+	}
+	for _, path := range workloads {
+		name := strings.TrimSuffix(filepath.Base(path), ".csv")
+		processes := loadWorkload(t, path)
+		for _, v := range goldenVariants {
+			t.Run(name+"/"+v.name, func(t *testing.T) {
+				sim, err := scheduler.NewSimulator(v.opts...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var got bytes.Buffer
+				for _, a := range scheduler.Algorithms() {
+					writeGolden(&got, a, sim.Schedule(a, processes))
+				}
+
+				golden := filepath.Join("testdata", "golden", name+"-"+v.name+".txt")
+				if *update {
+					if err := os.WriteFile(golden, got.Bytes(), 0o644); err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatalf("%v; run go test -run Golden -update to create it", err)
+				}
+				if !bytes.Equal(got.Bytes(), want) {
+					t.Errorf("results differ from %s; run go test -run Golden -update to accept them\ngot:\n%s", golden, got.Bytes())
+				}
+			})
+		}
+	}
+}
+
+func loadWorkload(t *testing.T, path string) []scheduler.Process {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	processes, err := input.Load(f)
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return processes
+}
+
+// writeGolden writes res in the plain text of the golden files: the Gantt
+// chart a slice per line, each process's timings and the averages.
+func writeGolden(w *bytes.Buffer, a scheduler.Algorithm, res scheduler.Result) {
+	fmt.Fprintf(w, "== %s\n", a.Name)
+	for _, s := range res.Gantt {
+		fmt.Fprintf(w, "slice  cpu %d  pid %d  %d-%d\n", s.CPU, s.PID, s.Start, s.Stop)
+	}
+	for _, s := range res.Stats {
+		fmt.Fprintf(w, "stat   pid %d  wait %d  turnaround %d  completion %d\n", s.ProcessID, s.Wait, s.Turnaround, s.Completion)
+	}
+	fmt.Fprintf(w, "average  wait %.4f  turnaround %.4f  throughput %.4f  switches %d\n\n",
+		res.AveWait, res.AveTurnaround, res.AveThroughput, res.ContextSwitches)
+}
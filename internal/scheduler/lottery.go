@@ -0,0 +1,130 @@
+package scheduler
+
+import "encoding/json"
+
+// MaxTickets is the number of lottery tickets a process of priority 0
+// holds. Each unit of priority value above 0 costs a ticket, down to the
+// one ticket every process holds.
+const MaxTickets = 10
+
+// MaxWeight is the largest weight a process can be given, small enough that
+// the tickets of any workload that fits in memory add up without
+// overflowing.
+const MaxWeight = 1 << 30
+
+// Tickets returns the number of lottery tickets a process of priority value
+// p holds.
+func Tickets(p int64) int64 { return max(1, MaxTickets-max(p, 0)) }
+
+// Share returns the number of lottery tickets p holds: its weight, if it
+// has one, or the Tickets of its priority.
+func (p Process) Share() int64 {
+	if p.Weight > 0 {
+		return p.Weight
+	}
+	return Tickets(p.Priority)
+}
+
+// lottery draws the next job to run at random, each ready job as likely to
+// win as the tickets it holds, and draws again every quantum.
+type lottery struct {
+	// ready holds the ready jobs. A job leaving it has its place taken by
+	// the last.
+	ready []*Job
+	// tickets is a Fenwick tree of the tickets the jobs in ready hold, by
+	// their place there from 1, so that a draw takes O(log n). Its length
+	// less one is a power of two at least that of ready.
+	tickets []int64
+	total   int64
+	quantum int64
+	rand    Rand
+}
+
+// NewLottery returns a lottery policy drawing from r, in which a job runs
+// for a quantum of p at a time. p must be valid, see RRParams.Validate.
+func NewLottery(p RRParams, r Rand) Policy {
+	return &lottery{quantum: p.Quantum, rand: r}
+}
+
+func (p *lottery) Push(j *Job, _ int64) {
+	if len(p.ready)+1 >= len(p.tickets) {
+		p.grow()
+	}
+	p.ready = append(p.ready, j)
+	p.add(len(p.ready)-1, j.Share())
+}
+
+func (p *lottery) Pop(int64) *Job {
+	if len(p.ready) == 0 {
+		return nil
+	}
+	// Descend the tree to the first job whose tickets, with those of the
+	// jobs before it, pass the draw.
+	draw, i := p.rand.Int64N(p.total), 0
+	for step := len(p.tickets) - 1; step > 0; step >>= 1 {
+		if t := p.tickets[i+step]; t <= draw {
+			i += step
+			draw -= t
+		}
+	}
+	j := p.ready[i]
+	p.remove(i)
+	return j
+}
+
+// add adds n tickets to those of the job at ready[i].
+func (p *lottery) add(i int, n int64) {
+	for k := i + 1; k < len(p.tickets); k += k & -k {
+		p.tickets[k] += n
+	}
+	p.total += n
+}
+
+// remove takes ready[i] out of the lottery, moving the last job to its
+// place.
+func (p *lottery) remove(i int) {
+	last := len(p.ready) - 1
+	p.add(i, -p.ready[i].Share())
+	if i != last {
+		p.add(last, -p.ready[last].Share())
+		p.add(i, p.ready[last].Share())
+		p.ready[i] = p.ready[last]
+	}
+	p.ready[last] = nil
+	p.ready = p.ready[:last]
+}
+
+// grow doubles the room in the tree, rebuilding it from ready.
+func (p *lottery) grow() {
+	n := max(8, 2*(len(p.tickets)-1))
+	p.tickets = make([]int64, n+1)
+	for i, j := range p.ready {
+		k := i + 1
+		p.tickets[k] += j.Share()
+		if parent := k + k&-k; parent <= n {
+			p.tickets[parent] += p.tickets[k]
+		}
+	}
+}
+
+func (p *lottery) Preempt(*Job, int64) bool { return false }
+func (p *lottery) Quantum(*Job) int64       { return p.quantum }
+
+func (p *lottery) SaveState() (json.RawMessage, error) {
+	r, err := saveRand(p.rand)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(readyState{Ready: indexes(p.ready), Rand: r})
+}
+
+func (p *lottery) RestoreState(state json.RawMessage, job func(int) *Job) error {
+	s, jobs, err := restoreReady(state, job)
+	if err != nil {
+		return err
+	}
+	for _, j := range jobs {
+		p.Push(j, 0)
+	}
+	return restoreRand(p.rand, s.Rand)
+}
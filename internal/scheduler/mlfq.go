@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// mlfq keeps one FIFO queue per level. New jobs enter the top level, jobs
+// using up their quantum drop a level, and jobs on a higher level preempt
+// those below.
+type mlfq struct {
+	levels []fifo
+	quanta []int64
+	// states holds the state of each job by its index, grown as jobs
+	// arrive.
+	states []mlfqState
+}
+
+type mlfqState struct {
+	level int
+	// dispatched is the job's remaining time when it last got a CPU.
+	dispatched int64
+	running    bool
+}
+
+// NewMLFQ returns a multilevel feedback queue policy. p must be valid, see
+// MLFQParams.Validate.
+func NewMLFQ(p MLFQParams) Policy {
+	return &mlfq{
+		levels: make([]fifo, p.Levels),
+		quanta: p.Quanta,
+	}
+}
+
+// state returns the state of j, which starts on the top level.
+func (p *mlfq) state(j *Job) *mlfqState {
+	if j.Index >= len(p.states) {
+		p.states = append(p.states, make([]mlfqState, j.Index+1-len(p.states))...)
+	}
+	return &p.states[j.Index]
+}
+
+func (p *mlfq) Push(j *Job, _ int64) {
+	s := p.state(j)
+	if s.running && s.dispatched-j.Remaining >= p.quanta[s.level] {
+		s.level = min(s.level+1, len(p.levels)-1)
+	}
+	if j.Boosted {
+		s.level = 0
+	}
+	s.running = false
+	p.levels[s.level].push(j)
+}
+
+func (p *mlfq) Pop(int64) *Job {
+	for i := range p.levels {
+		if j := p.levels[i].pop(); j != nil {
+			s := p.state(j)
+			s.dispatched = j.Remaining
+			s.running = true
+			return j
+		}
+	}
+	return nil
+}
+
+func (p *mlfq) Preempt(running *Job, _ int64) bool {
+	return p.top() < p.state(running).level
+}
+
+// top returns the highest level with a ready job.
+func (p *mlfq) top() int {
+	for i := range p.levels {
+		if p.levels[i].len() > 0 {
+			return i
+		}
+	}
+	return math.MaxInt
+}
+
+func (p *mlfq) Quantum(j *Job) int64 { return p.quanta[p.state(j).level] }
+
+func (p *mlfq) List(int64) []*Job {
+	var out []*Job
+	for i := range p.levels {
+		out = append(out, p.levels[i].ready()...)
+	}
+	return out
+}
+
+// mlfqSaved is the state of an mlfq: its levels, oldest job first, and the
+// state of every job that has left the top level or got a CPU.
+type mlfqSaved struct {
+	Levels [][]int        `json:"levels"`
+	Jobs   []mlfqSavedJob `json:"jobs"`
+}
+
+type mlfqSavedJob struct {
+	Index      int   `json:"index"`
+	Level      int   `json:"level"`
+	Dispatched int64 `json:"dispatched"`
+	Running    bool  `json:"running,omitempty"`
+}
+
+func (p *mlfq) SaveState() (json.RawMessage, error) {
+	var saved mlfqSaved
+	for i := range p.levels {
+		saved.Levels = append(saved.Levels, indexes(p.levels[i].ready()))
+	}
+	for i, s := range p.states {
+		if s != (mlfqState{}) {
+			saved.Jobs = append(saved.Jobs, mlfqSavedJob{i, s.level, s.dispatched, s.running})
+		}
+	}
+	return json.Marshal(saved)
+}
+
+func (p *mlfq) RestoreState(state json.RawMessage, job func(int) *Job) error {
+	var saved mlfqSaved
+	if err := json.Unmarshal(state, &saved); err != nil {
+		return err
+	}
+	if len(saved.Levels) != len(p.levels) {
+		return fmt.Errorf("%d levels, want %d", len(saved.Levels), len(p.levels))
+	}
+	for i, level := range saved.Levels {
+		jobs, err := lookup(level, job)
+		if err != nil {
+			return err
+		}
+		for _, j := range jobs {
+			p.levels[i].push(j)
+		}
+	}
+	for _, s := range saved.Jobs {
+		if s.Level < 0 || s.Level >= len(p.levels) {
+			return fmt.Errorf("job %d on level %d of %d", s.Index, s.Level, len(p.levels))
+		}
+		// Completed jobs need their state no longer.
+		if j := job(s.Index); j != nil {
+			*p.state(j) = mlfqState{level: s.Level, dispatched: s.Dispatched, running: s.Running}
+		}
+	}
+	return nil
+}
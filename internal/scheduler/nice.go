@@ -0,0 +1,36 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidNice = errors.New("invalid nice value")
+
+// Nice values run from MinNice, the most favoured, to MaxNice.
+const (
+	MinNice = -20
+	MaxNice = 19
+)
+
+// niceWeights holds the load weight Linux gives each nice value from
+// MinNice up: 1024 at nice 0, each step up getting about 1/1.25 of the
+// CPU of the one below.
+var niceWeights = [MaxNice - MinNice + 1]int64{
+	88761, 71755, 56483, 46273, 36291, 29154, 23254, 18705, 14949, 11916,
+	9548, 7620, 6100, 4904, 3906, 3121, 2501, 1991, 1586, 1277,
+	1024, 820, 655, 526, 423, 335, 272, 215, 172, 137,
+	110, 87, 70, 56, 45, 36, 29, 23, 18, 15,
+}
+
+// FromNice returns p with the priority and weight Linux gives a process of
+// the given nice value: priority nice+20, as top shows it, so that lower
+// runs first, and the load weight the Linux scheduler divides CPU time by.
+func FromNice(p Process, nice int64) (Process, error) {
+	if nice < MinNice || nice > MaxNice {
+		return Process{}, fmt.Errorf("%w: %d, want %d to %d", ErrInvalidNice, nice, MinNice, MaxNice)
+	}
+	p.Priority = nice - MinNice
+	p.Weight = niceWeights[nice-MinNice]
+	return p, nil
+}
@@ -0,0 +1,163 @@
+package scheduler
+
+import (
+	"context"
+	"sort"
+)
+
+type (
+	// JobState is a snapshot of one job.
+	JobState struct {
+		PID       int64 `json:"pid"`
+		Remaining int64 `json:"remaining"`
+		Waited    int64 `json:"waited"`
+	}
+
+	// CPUState is a snapshot of one processor.
+	CPUState struct {
+		Idle bool `json:"idle"`
+		// Job is the running job, unless the CPU is idle.
+		Job JobState `json:"job"`
+	}
+
+	// State is a snapshot of a simulation taken right after an event.
+	State struct {
+		Event Event      `json:"event"`
+		CPUs  []CPUState `json:"cpus"`
+		// Ready holds the ready jobs in the order they will be dispatched,
+		// as far as the policy can tell; see Lister.
+		Ready []JobState `json:"ready"`
+		// Suspended holds the jobs set aside by a suspension.
+		Suspended []JobState `json:"suspended,omitempty"`
+		Completed int        `json:"completed"`
+	}
+
+	// Lister is implemented by policies that can list their ready jobs in
+	// the order they would dispatch them. The ready queue of a State is
+	// ordered by arrival instead for policies that are not Listers.
+	Lister interface {
+		List(now int64) []*Job
+	}
+)
+
+func jobState(j *Job, now int64, ready bool) JobState {
+	s := JobState{PID: j.ProcessID, Remaining: j.Remaining, Waited: j.Waited}
+	if ready {
+		s.Waited += now - j.Ready
+	}
+	return s
+}
+
+// snapshot captures the state of e right after ev.
+func (e *engine) snapshot(ev Event) State {
+	s := State{Event: ev, CPUs: make([]CPUState, len(e.cpus)), Completed: e.done}
+	running := make(map[*Job]bool, len(e.cpus))
+	for c := range e.cpus {
+		if j := e.cpus[c].job; j != nil {
+			running[j] = true
+			s.CPUs[c].Job = jobState(j, e.t, false)
+		} else {
+			s.CPUs[c].Idle = true
+		}
+	}
+
+	var ready []*Job
+	if l, ok := e.policy.(Lister); ok {
+		ready = l.List(e.t)
+	} else {
+		for _, j := range e.arrivals[:e.next] {
+			if j.Remaining > 0 && !running[j] && !j.held && !contains(e.requeue, j) {
+				ready = append(ready, j)
+			}
+		}
+	}
+	// Jobs whose quantum just expired are queued once the arrivals due at
+	// the same time have been.
+	ready = append(ready, e.requeue...)
+	for _, j := range ready {
+		s.Ready = append(s.Ready, jobState(j, e.t, true))
+	}
+	for _, j := range e.heldJobs() {
+		s.Suspended = append(s.Suspended, jobState(j, e.t, false))
+	}
+	return s
+}
+
+// heldJobs returns the jobs set aside by a suspension, in the order they
+// were given.
+func (e *engine) heldJobs() []*Job {
+	var out []*Job
+	for i := range e.jobs {
+		if e.jobs[i].held {
+			out = append(out, &e.jobs[i])
+		}
+	}
+	return out
+}
+
+func contains(jobs []*Job, j *Job) bool {
+	for _, k := range jobs {
+		if k == j {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedJobs returns a copy of jobs sorted by less.
+func sortedJobs(jobs []*Job, less func(a, b *Job) bool) []*Job {
+	out := append([]*Job(nil), jobs...)
+	sort.SliceStable(out, func(i, j int) bool { return less(out[i], out[j]) })
+	return out
+}
+
+// Observation is a simulation that delivers a State after every event to any
+// number of subscribers. Subscribe to it, then Run it.
+type Observation struct {
+	policy    Policy
+	processes []Process
+	opts      Options
+	subs      []chan State
+}
+
+// Observe prepares a run of a over processes for observation.
+func (s *Simulator) Observe(a Algorithm, processes []Process) *Observation {
+	return &Observation{policy: a.New(s.opts), processes: processes, opts: s.opts}
+}
+
+// Subscribe registers a subscriber, returning the channel its states are
+// delivered on. The channel buffers up to buffer states; once it is full the
+// simulation waits for the subscriber to catch up, so a slow subscriber slows
+// the run down rather than missing states. The channel is closed when the
+// run ends. States are shared between subscribers and must not be modified.
+// Subscribe must not be called once Run has started.
+func (o *Observation) Subscribe(buffer int) <-chan State {
+	ch := make(chan State, buffer)
+	o.subs = append(o.subs, ch)
+	return ch
+}
+
+// Run runs the simulation, delivering every state to every subscriber in
+// turn, and returns its result. Subscribers must be drained concurrently.
+// Once ctx is cancelled the run stops early and pending deliveries are
+// dropped.
+func (o *Observation) Run(ctx context.Context) Result {
+	defer func() {
+		for _, ch := range o.subs {
+			close(ch)
+		}
+	}()
+	e := newEngine(o.policy, o.processes, o.opts)
+	if len(o.subs) > 0 {
+		e.observe = func(s State) {
+			for _, ch := range o.subs {
+				select {
+				case ch <- s:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+	return e.run(ctx)
+}
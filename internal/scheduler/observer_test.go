@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func collect(ch <-chan State, wg *sync.WaitGroup, out *[]State) {
+	defer wg.Done()
+	for s := range ch {
+		*out = append(*out, s)
+	}
+}
+
+func TestObservationSubscribers(t *testing.T) {
+	obs := mustSimulator(t).Observe(mustAlgorithm(t, "rr"), example())
+	var a, b []State
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go collect(obs.Subscribe(0), &wg, &a)
+	go collect(obs.Subscribe(64), &wg, &b)
+	res := obs.Run(context.Background())
+	wg.Wait()
+
+	if !reflect.DeepEqual(res, RR(example())) {
+		t.Errorf("result = %+v, want plain RR", res)
+	}
+	if len(a) == 0 || !reflect.DeepEqual(a, b) {
+		t.Fatalf("subscribers saw different states:\n%v\n%v", a, b)
+	}
+
+	// P2's quantum expires at 10 while P3 waits: P2 queues up behind P3.
+	var got *State
+	for i := range a {
+		if ev := a[i].Event; ev.Kind == EventPreempt && ev.Time == 10 {
+			got = &a[i]
+		}
+	}
+	if got == nil {
+		t.Fatal("no preemption at 10")
+	}
+	want := State{
+		Event: Event{Kind: EventPreempt, Time: 10, PID: 2},
+		CPUs:  []CPUState{{Idle: true}},
+		Ready: []JobState{
+			{PID: 3, Remaining: 6, Waited: 4},
+			{PID: 2, Remaining: 4, Waited: 2},
+		},
+		Completed: 1,
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("state = %+v, want %+v", *got, want)
+	}
+	if last := a[len(a)-1]; last.Completed != 3 || len(last.Ready) != 0 {
+		t.Errorf("final state = %+v", last)
+	}
+}
+
+func TestObservationBackpressure(t *testing.T) {
+	obs := mustSimulator(t).Observe(mustAlgorithm(t, "fcfs"), example())
+	states := obs.Subscribe(0)
+	done := make(chan struct{})
+	go func() {
+		obs.Run(context.Background())
+		close(done)
+	}()
+
+	<-states
+	select {
+	case <-done:
+		t.Fatal("run finished while its subscriber was not reading")
+	default:
+	}
+	for range states {
+	}
+	<-done
+}
+
+func TestObservationCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	obs := mustSimulator(t).Observe(mustAlgorithm(t, "sjf"), largeWorkload(500))
+	states := obs.Subscribe(0)
+	done := make(chan Result)
+	go func() { done <- obs.Run(ctx) }()
+	<-states
+	cancel()
+	res := <-done
+	if len(res.Stats) == 500 {
+		t.Error("cancelled run completed every process")
+	}
+	if _, ok := <-states; ok {
+		for range states {
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// ErrOverflow is returned for workloads whose times could pass the largest
+// int64.
+var ErrOverflow = errors.New("times overflow")
+
+// Horizon returns a time no run of processes under opts can pass: the last
+// arrival or resumption, plus every burst, plus a context switch per tick of
+// burst and per process. It fails with ErrOverflow if that is past math.MaxInt64, in
+// which case a run may saturate: the engine never wraps, but holds times at
+// math.MaxInt64, so every process still running there completes at it.
+func Horizon(processes []Process, opts Options) (int64, error) {
+	var last, bursts uint64
+	for _, p := range processes {
+		last = max(last, uint64(max(0, p.ArrivalTime)))
+		var carry uint64
+		if bursts, carry = bits.Add64(bursts, uint64(max(0, p.BurstDuration)), 0); carry != 0 {
+			return 0, fmt.Errorf("%w: bursts add up past %d", ErrOverflow, int64(math.MaxInt64))
+		}
+	}
+	for _, s := range opts.Suspensions {
+		last = max(last, uint64(max(0, s.To)))
+	}
+	hi, switches := bits.Mul64(uint64(max(0, opts.SwitchCost)), bursts+uint64(len(processes)))
+	horizon, carry := bits.Add64(last, bursts, 0)
+	horizon, carry2 := bits.Add64(horizon, switches, 0)
+	if hi != 0 || carry != 0 || carry2 != 0 || horizon > math.MaxInt64 {
+		return 0, fmt.Errorf("%w: the last arrival or resumption at %d plus every burst and context switch can pass %d", ErrOverflow, last, int64(math.MaxInt64))
+	}
+	return int64(horizon), nil
+}
+
+// ticks returns how many ticks a CPU of the given speed takes to run burst.
+func ticks(burst, speed int64) int64 {
+	n := burst / speed
+	if burst%speed != 0 {
+		n++
+	}
+	return n
+}
+
+// mul returns a*b for a and b that are not negative, saturating at
+// math.MaxInt64 rather than wrapping.
+func mul(a, b int64) int64 {
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	if hi != 0 || lo > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(lo)
+}
+
+// add returns a+b, saturating at the bounds of int64 rather than wrapping.
+func add(a, b int64) int64 {
+	c := a + b
+	switch {
+	case b > 0 && c < a:
+		return math.MaxInt64
+	case b < 0 && c > a:
+		return math.MinInt64
+	}
+	return c
+}
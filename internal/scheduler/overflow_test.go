@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestHorizon(t *testing.T) {
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 10},
+	}
+	opts := DefaultOptions()
+	opts.SwitchCost = 2
+	if h, err := Horizon(processes, opts); err != nil || h != 10+8+2*(8+2) {
+		t.Errorf("Horizon = %d, %v, want 38", h, err)
+	}
+
+	huge := []Process{
+		{ProcessID: 1, BurstDuration: math.MaxInt64/2 + 1},
+		{ProcessID: 2, BurstDuration: math.MaxInt64/2 + 1},
+	}
+	if _, err := Horizon(huge, DefaultOptions()); !errors.Is(err, ErrOverflow) {
+		t.Errorf("err = %v, want %v", err, ErrOverflow)
+	}
+	opts.SwitchCost = math.MaxInt64 / 4
+	if _, err := Horizon(processes, opts); !errors.Is(err, ErrOverflow) {
+		t.Errorf("switch cost: err = %v, want %v", err, ErrOverflow)
+	}
+}
+
+func TestScheduleSaturates(t *testing.T) {
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: math.MaxInt64/2 + 1},
+		{ProcessID: 2, BurstDuration: math.MaxInt64/2 + 1},
+		{ProcessID: 3, BurstDuration: 1, ArrivalTime: 1},
+	}
+	// A quantum this long makes expiries overflow too.
+	sim := mustSimulator(t, WithQuantum(math.MaxInt64/4), WithSwitchCost(1))
+	for _, name := range []string{"fcfs", "sjf", "rr"} {
+		res := sim.Schedule(mustAlgorithm(t, name), processes)
+		for _, s := range res.Stats {
+			if s.Completion <= 0 || s.Wait < 0 || s.Turnaround < 0 {
+				t.Errorf("%s: P%d wrapped: %+v", name, s.ProcessID, s)
+			}
+		}
+		if c := res.Stats[1].Completion; c != math.MaxInt64 {
+			t.Errorf("%s: P2 completes at %d, want %d", name, c, int64(math.MaxInt64))
+		}
+		for _, g := range res.Gantt {
+			if g.Stop < g.Start {
+				t.Errorf("%s: slice %v wrapped", name, g)
+			}
+		}
+	}
+}
+
+func TestScheduleArrivingAtLastTime(t *testing.T) {
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 2},
+		{ProcessID: 2, ArrivalTime: math.MaxInt64},
+	}
+	for _, a := range Algorithms() {
+		res := mustSimulator(t).Schedule(a, processes)
+		if c := res.Stats[1].Completion; c != math.MaxInt64 {
+			t.Errorf("%s: P2 completes at %d, want %d", a.Name, c, int64(math.MaxInt64))
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// FCFSOrder is the order first-come, first-serve lines processes up in.
+type FCFSOrder string
+
+const (
+	// OrderArrival runs processes by arrival time, those arriving together
+	// by process ID. It is the default, also meant by the empty order.
+	OrderArrival FCFSOrder = "arrival"
+	// OrderGiven runs processes in the order they were given, whatever
+	// their arrival times, a process waiting for those before it.
+	OrderGiven FCFSOrder = "given"
+	// OrderStrict is OrderArrival for workloads already sorted by arrival
+	// time; see FCFSParams.Check.
+	OrderStrict FCFSOrder = "strict"
+)
+
+// ErrUnsorted is returned for workloads not sorted by arrival time under
+// OrderStrict.
+var ErrUnsorted = errors.New("processes not sorted by arrival time")
+
+type (
+	// FCFSParams configures first-come, first-serve.
+	FCFSParams struct {
+		Order FCFSOrder `json:"order,omitempty"`
+	}
+
+	// RRParams configures round-robin, and the quantum of the lottery.
+	RRParams struct {
+		// Quantum is the longest a process runs before yielding the CPU.
+		Quantum int64 `json:"quantum"`
+	}
+
+	// MLFQParams configures the multilevel feedback queue.
+	MLFQParams struct {
+		// Levels is the number of queues, the first being the most
+		// important.
+		Levels int `json:"levels"`
+		// Quanta holds the time quantum of each level. A process using up
+		// its whole quantum moves down a level.
+		Quanta []int64 `json:"quanta"`
+	}
+
+	// FairShareParams configures fair-share scheduling.
+	FairShareParams struct {
+		// Shares holds the CPU share of each group, as cgroup cpu.shares
+		// do. Groups not listed hold DefaultShare.
+		Shares []GroupShare `json:"shares,omitempty"`
+	}
+
+	// GroupShare is the CPU share of the processes of one group.
+	GroupShare struct {
+		Group string `json:"group"`
+		Share int64  `json:"share"`
+	}
+
+	// ClassParams configures scheduling by process class, naming the
+	// algorithm that schedules the processes of each class. A class named
+	// no algorithm is scheduled by its default, see DefaultClassAlgorithm.
+	ClassParams struct {
+		Realtime    string `json:"realtime,omitempty"`
+		Interactive string `json:"interactive,omitempty"`
+		Batch       string `json:"batch,omitempty"`
+	}
+
+	// AgingParams configures priority scheduling with aging, and aging
+	// in the other priority-based and shortest-job-first policies.
+	AgingParams struct {
+		// Rate is how much a waiting process's priority value drops per
+		// unit of time spent ready but not running.
+		Rate float64 `json:"rate"`
+		// All has priority and shortest-job-first age ready processes at
+		// Rate too, shortest-job-first counting a process's remaining time
+		// for Rate less per unit of waiting. They are left as they are
+		// when Rate is 0.
+		All bool `json:"all,omitempty"`
+	}
+)
+
+func DefaultRRParams() RRParams { return RRParams{Quantum: DefaultQuantum} }
+
+func DefaultMLFQParams() MLFQParams {
+	return MLFQParams{Levels: 3, Quanta: []int64{DefaultQuantum, 2 * DefaultQuantum, 4 * DefaultQuantum}}
+}
+
+func DefaultAgingParams() AgingParams { return AgingParams{Rate: 0.1} }
+
+// DefaultShare is the CPU share of a group given none, as it is of a cgroup,
+// and MaxShare the largest a group can be given.
+const (
+	DefaultShare = 1024
+	MaxShare     = 262144
+)
+
+// Share returns the CPU share of group.
+func (p FairShareParams) Share(group string) int64 {
+	for _, s := range p.Shares {
+		if s.Group == group {
+			return s.Share
+		}
+	}
+	return DefaultShare
+}
+
+// Algorithm returns the name of the algorithm scheduling class c.
+func (p ClassParams) Algorithm(c Class) string {
+	var name string
+	switch c.rank() {
+	case 0:
+		name = p.Realtime
+	case 1:
+		name = p.Interactive
+	case 2:
+		name = p.Batch
+	}
+	return cmp.Or(name, DefaultClassAlgorithm(c))
+}
+
+func (p FCFSParams) Validate() error {
+	switch p.Order {
+	case "", OrderArrival, OrderGiven, OrderStrict:
+		return nil
+	}
+	return fmt.Errorf("%w: fcfs order must be arrival, given or strict, got %q", ErrInvalidOption, p.Order)
+}
+
+// Check fails with ErrUnsorted if p is strict and a process arrives before
+// the one given before it.
+func (p FCFSParams) Check(processes []Process) error {
+	if p.Order != OrderStrict {
+		return nil
+	}
+	for i := 1; i < len(processes); i++ {
+		if processes[i].ArrivalTime < processes[i-1].ArrivalTime {
+			return fmt.Errorf("%w: process %d, arriving at %d, comes after process %d, arriving at %d",
+				ErrUnsorted, processes[i].ProcessID, processes[i].ArrivalTime, processes[i-1].ProcessID, processes[i-1].ArrivalTime)
+		}
+	}
+	return nil
+}
+
+func (p RRParams) Validate() error {
+	if p.Quantum < 1 {
+		return fmt.Errorf("%w: rr quantum must be positive, got %d", ErrInvalidOption, p.Quantum)
+	}
+	return nil
+}
+
+func (p MLFQParams) Validate() error {
+	if p.Levels < 1 {
+		return fmt.Errorf("%w: mlfq needs at least one level, got %d", ErrInvalidOption, p.Levels)
+	}
+	if len(p.Quanta) != p.Levels {
+		return fmt.Errorf("%w: mlfq needs one quantum per level, got %d for %d levels", ErrInvalidOption, len(p.Quanta), p.Levels)
+	}
+	for i, q := range p.Quanta {
+		if q < 1 {
+			return fmt.Errorf("%w: mlfq quantum of level %d must be positive, got %d", ErrInvalidOption, i, q)
+		}
+	}
+	return nil
+}
+
+func (p FairShareParams) Validate() error {
+	for i, s := range p.Shares {
+		switch {
+		case s.Share < 1 || s.Share > MaxShare:
+			return fmt.Errorf("%w: share of group %q must be from 1 to %d, got %d", ErrInvalidOption, s.Group, MaxShare, s.Share)
+		case slices.ContainsFunc(p.Shares[:i], func(o GroupShare) bool { return o.Group == s.Group }):
+			return fmt.Errorf("%w: group %q is given a share twice", ErrInvalidOption, s.Group)
+		}
+	}
+	return nil
+}
+
+func (p ClassParams) Validate() error {
+	for _, c := range classes {
+		name := p.Algorithm(c)
+		if name == "classes" {
+			return fmt.Errorf("%w: the %s class cannot be scheduled by class", ErrInvalidOption, c)
+		}
+		if _, err := LookupAlgorithm(name); err != nil {
+			return fmt.Errorf("%w: %s class: %w", ErrInvalidOption, c, err)
+		}
+	}
+	return nil
+}
+
+// applies reports whether p has priority and shortest-job-first age.
+func (p AgingParams) applies() bool { return p.All && p.Rate > 0 }
+
+func (p AgingParams) Validate() error {
+	if p.Rate < 0 || math.IsNaN(p.Rate) || math.IsInf(p.Rate, 0) {
+		return fmt.Errorf("%w: aging rate must be a finite number no less than 0, got %v", ErrInvalidOption, p.Rate)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+func TestParamsValidate(t *testing.T) {
+	for _, p := range []interface{ Validate() error }{
+		FCFSParams{Order: "random"},
+		RRParams{Quantum: 0},
+		MLFQParams{Levels: 0},
+		MLFQParams{Levels: 2, Quanta: []int64{4}},
+		MLFQParams{Levels: 2, Quanta: []int64{4, 0}},
+		AgingParams{Rate: -1},
+		AgingParams{Rate: math.NaN()},
+		AgingParams{Rate: math.Inf(1)},
+	} {
+		if err := p.Validate(); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("%+v: err = %v, want %v", p, err, ErrInvalidOption)
+		}
+	}
+	for _, p := range []interface{ Validate() error }{FCFSParams{}, DefaultRRParams(), DefaultMLFQParams(), DefaultAgingParams()} {
+		if err := p.Validate(); err != nil {
+			t.Errorf("default %+v: %v", p, err)
+		}
+	}
+}
+
+func TestFCFSOrder(t *testing.T) {
+	processes := []Process{
+		{ProcessID: 3, BurstDuration: 2, ArrivalTime: 5},
+		{ProcessID: 2, BurstDuration: 2, ArrivalTime: 0},
+		{ProcessID: 1, BurstDuration: 2, ArrivalTime: 0},
+	}
+	for _, order := range []FCFSOrder{"", OrderArrival, OrderStrict} {
+		want := []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 3, Start: 5, Stop: 7}}
+		if res := Schedule(NewFCFS(FCFSParams{Order: order}), processes); !reflect.DeepEqual(res.Gantt, want) {
+			t.Errorf("order %q: gantt = %v, want %v", order, res.Gantt, want)
+		}
+	}
+	want := []TimeSlice{{PID: 3, Start: 5, Stop: 7}, {PID: 2, Start: 7, Stop: 9}, {PID: 1, Start: 9, Stop: 11}}
+	if res := Schedule(NewFCFS(FCFSParams{Order: OrderGiven}), processes); !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("as given: gantt = %v, want %v", res.Gantt, want)
+	}
+
+	if err := (FCFSParams{Order: OrderStrict}).Check(processes); !errors.Is(err, ErrUnsorted) {
+		t.Errorf("strict: err = %v, want %v", err, ErrUnsorted)
+	}
+	if err := (FCFSParams{Order: OrderStrict}).Check(processes[1:]); err != nil {
+		t.Errorf("strict, sorted: %v", err)
+	}
+	if err := (FCFSParams{}).Check(processes); err != nil {
+		t.Errorf("by arrival: %v", err)
+	}
+}
+
+func TestMLFQ(t *testing.T) {
+	res := Schedule(NewMLFQ(MLFQParams{Levels: 2, Quanta: []int64{2, 4}}), example())
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 3},
+		{PID: 2, Start: 3, Stop: 5},
+		{PID: 1, Start: 5, Stop: 6},
+		{PID: 3, Start: 6, Stop: 8},
+		{PID: 2, Start: 8, Stop: 12},
+		{PID: 1, Start: 12, Stop: 13},
+		{PID: 3, Start: 13, Stop: 17},
+		{PID: 2, Start: 17, Stop: 20},
+	}
+	if !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("gantt = %v, want %v", res.Gantt, want)
+	}
+}
+
+func TestAgingPriority(t *testing.T) {
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 10, ArrivalTime: 0, Priority: 0},
+		{ProcessID: 2, BurstDuration: 2, ArrivalTime: 1, Priority: 3},
+	}
+
+	if res := Schedule(NewAgingPriority(AgingParams{Rate: 0}), processes); !reflect.DeepEqual(res.Gantt, SJFPriority(processes).Gantt) {
+		t.Errorf("without aging gantt = %v, want plain priority", res.Gantt)
+	}
+
+	// P2 overtakes P1 once it has waited 7 ticks and keeps its boost while
+	// it runs.
+	res := Schedule(NewAgingPriority(AgingParams{Rate: 0.5}), processes)
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 8},
+		{PID: 2, Start: 8, Stop: 10},
+		{PID: 1, Start: 10, Stop: 12},
+	}
+	if !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("gantt = %v, want %v", res.Gantt, want)
+	}
+}
+
+func TestAgingAll(t *testing.T) {
+	// Short jobs keep arriving while P2 waits.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 2, ArrivalTime: 0},
+		{ProcessID: 2, BurstDuration: 5, ArrivalTime: 0},
+		{ProcessID: 3, BurstDuration: 2, ArrivalTime: 2},
+		{ProcessID: 4, BurstDuration: 2, ArrivalTime: 4},
+		{ProcessID: 5, BurstDuration: 2, ArrivalTime: 6},
+	}
+	sjf := mustAlgorithm(t, "sjf")
+	opts := DefaultOptions()
+	if res := run(context.Background(), sjf.New(opts), processes, opts, nil); res.Gantt[len(res.Gantt)-1].PID != 2 {
+		t.Errorf("without aging gantt = %v, want P2 last", res.Gantt)
+	}
+
+	// With aging, P2's 5 ticks count for 1 less per tick it waits, so by 4
+	// it is ahead of P4, and P4 does not catch up with it running.
+	opts.Aging = AgingParams{Rate: 1, All: true}
+	res := run(context.Background(), sjf.New(opts), processes, opts, nil)
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 2},
+		{PID: 3, Start: 2, Stop: 4},
+		{PID: 2, Start: 4, Stop: 9},
+		{PID: 4, Start: 9, Stop: 11},
+		{PID: 5, Start: 11, Stop: 13},
+	}
+	if !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("with aging gantt = %v, want %v", res.Gantt, want)
+	}
+
+	// Left to the aging algorithm alone, the rate changes nothing else.
+	opts.Aging.All = false
+	if res := run(context.Background(), sjf.New(opts), processes, opts, nil); !reflect.DeepEqual(res.Gantt, SJF(processes).Gantt) {
+		t.Errorf("aging left off gantt = %v, want plain sjf", res.Gantt)
+	}
+	opts.Aging = AgingParams{All: true}
+	if p := mustAlgorithm(t, "priority").New(opts); reflect.TypeOf(p) != reflect.TypeOf(NewPriority()) {
+		t.Errorf("priority at rate 0 is a %T, want plain priority", p)
+	}
+}
+
+// tickAging is aging by rescanning every ready job and checking for
+// preemption every tick. The heap-based policy must schedule exactly as it
+// does.
+type tickAging struct {
+	aging
+	jobs []*Job
+}
+
+func (p *tickAging) best(now int64) int {
+	best := -1
+	for i, j := range p.jobs {
+		if best < 0 || p.ahead(j, p.jobs[best], now) {
+			best = i
+		}
+	}
+	return best
+}
+
+func (p *tickAging) ahead(a, b *Job, now int64) bool {
+	if g := p.gap(a.Priority-b.Priority, (a.Waited+now-a.Ready)-(b.Waited+now-b.Ready)); g != 0 {
+		return g < 0
+	}
+	return morePressing(a, b)
+}
+
+func (p *tickAging) Push(j *Job, _ int64) { p.jobs = append(p.jobs, j) }
+
+func (p *tickAging) Pop(now int64) *Job {
+	i := p.best(now)
+	if i < 0 {
+		return nil
+	}
+	j := p.jobs[i]
+	p.jobs = append(p.jobs[:i], p.jobs[i+1:]...)
+	return j
+}
+
+func (p *tickAging) Preempt(running *Job, now int64) bool {
+	i := p.best(now)
+	return i >= 0 && p.over(p.jobs[i], running, now) < 0
+}
+
+func (p *tickAging) Wake(now int64, _ []*Job) int64 {
+	if p.rate == 0 || len(p.jobs) == 0 {
+		return math.MaxInt64
+	}
+	return now + 1
+}
+
+func TestAgingPriorityMatchesTicks(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, rate := range []float64{0.1, 0.25, 1.0 / 3, 2} {
+		for _, cpus := range []int{1, 3} {
+			processes := make([]Process, 300)
+			var arrival int64
+			for i := range processes {
+				processes[i] = Process{ProcessID: int64(i + 1), BurstDuration: 1 + r.Int64N(20), ArrivalTime: arrival, Priority: r.Int64N(10)}
+				arrival += r.Int64N(int64(12 / cpus))
+			}
+			opts := DefaultOptions()
+			opts.CPUs = cpus
+			got := run(context.Background(), NewAgingPriority(AgingParams{Rate: rate}), processes, opts, nil)
+			want := run(context.Background(), &tickAging{aging: *NewAgingPriority(AgingParams{Rate: rate}).(*aging)}, processes, opts, nil)
+			if !reflect.DeepEqual(got.Gantt, want.Gantt) {
+				t.Errorf("rate %v on %d CPUs: gantt differs from ticking every tick", rate, cpus)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package scheduler
+
+import "encoding/json"
+
+// priority runs the job with the lowest priority value, preempting the
+// running job when a more important one arrives.
+type priority struct {
+	ready jobHeap
+}
+
+// NewPriority returns a preemptive priority policy, where a lower value means
+// a higher priority. Ties are broken by the shorter expected burst, and a
+// job preempts the running one only if it is ahead on one or the other.
+func NewPriority() Policy {
+	return &priority{ready: jobHeap{less: boostedFirst(morePressing)}}
+}
+
+func morePressing(a, b *Job) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	if a, b := a.ExpectedBurst(), b.ExpectedBurst(); a != b {
+		return a < b
+	}
+	return a.Index < b.Index
+}
+
+func (p *priority) Push(j *Job, _ int64) { p.ready.push(j) }
+func (p *priority) Pop(int64) *Job       { return p.ready.pop() }
+
+func (p *priority) Preempt(running *Job, _ int64) bool {
+	j := p.ready.peek()
+	if j == nil {
+		return false
+	}
+	if first, ok := favoured(j, running); ok {
+		return first
+	}
+	// Unlike the ready queue, the CPU goes by no tie below the burst: a job
+	// alike in both waits for the running one.
+	if j.Priority != running.Priority {
+		return j.Priority < running.Priority
+	}
+	return j.ExpectedBurst() < running.ExpectedBurst()
+}
+
+func (p *priority) Quantum(*Job) int64 { return 0 }
+
+func (p *priority) SaveState() (json.RawMessage, error) { return p.ready.save() }
+
+func (p *priority) RestoreState(state json.RawMessage, job func(int) *Job) error {
+	return p.ready.restore(state, job)
+}
+
+func (p *priority) List(int64) []*Job { return sortedJobs(p.ready.jobs, p.ready.less) }
+
+// SJFPriority schedules processes by preemptive priority, where a lower
+// value means a higher priority. Ties are broken by the shorter burst.
+func SJFPriority(processes []Process) Result {
+	return Schedule(NewPriority(), processes)
+}
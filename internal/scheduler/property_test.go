@@ -0,0 +1,172 @@
+package scheduler
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// randomWorkload returns up to 40 processes given in no particular order,
+// some arriving together, some with no burst and some after idle gaps, in
+// three groups and most of a class.
+func randomWorkload(r *rand.Rand) []Process {
+	processes := make([]Process, r.IntN(40))
+	for i := range processes {
+		processes[i] = Process{
+			ProcessID:     int64(i + 1),
+			BurstDuration: r.Int64N(12),
+			ArrivalTime:   r.Int64N(int64(4 * len(processes))),
+			Priority:      r.Int64N(5),
+			Group:         string(rune('a' + i%3)),
+			Class:         [...]Class{"", ClassRealtime, ClassBatch, ClassInteractive}[i%4],
+		}
+	}
+	r.Shuffle(len(processes), func(i, j int) { processes[i], processes[j] = processes[j], processes[i] })
+	return processes
+}
+
+// TestInvariants checks what holds for every algorithm on any workload,
+// randomized ones whatever they draw: each process's timings add up,
+// slices on a CPU never overlap, a process runs on one CPU at a time within
+// its lifetime and for exactly its burst, and a single CPU switching for
+// free is busy whenever some process has arrived and not completed.
+func TestInvariants(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	for i := range 200 {
+		processes := randomWorkload(r)
+		for _, opts := range [][]Option{
+			nil,
+			{WithQuantum(2), WithMLFQ(MLFQParams{Levels: 2, Quanta: []int64{1, 3}})},
+			{WithCPUs(3)},
+			{WithSwitchCost(2)},
+			{WithCPUs(2), WithSwitchCost(1), WithQuantum(3)},
+		} {
+			sim := mustSimulator(t, opts...)
+			for _, a := range Algorithms() {
+				name := fmt.Sprintf("workload %d, %s, %+v", i, a.Name, sim.Options())
+				checkInvariants(t, name, processes, sim.Options(), sim.Schedule(a, processes))
+			}
+			o := sim.Options()
+			for _, r := range []Rand{lowest{}, highest{}} {
+				for _, p := range []Policy{NewLottery(o.RR, r), NewRandom(r)} {
+					name := fmt.Sprintf("workload %d, %T drawing %T, %+v", i, p, r, o)
+					checkInvariants(t, name, processes, o, run(context.Background(), p, processes, o, nil))
+				}
+			}
+		}
+	}
+}
+
+// lowest and highest are the edge cases of randomized policies, drawing
+// the first and the last of what they draw from every time.
+type (
+	lowest  struct{}
+	highest struct{}
+)
+
+func (lowest) Int64N(int64) int64    { return 0 }
+func (highest) Int64N(n int64) int64 { return n - 1 }
+
+func checkInvariants(t *testing.T, name string, processes []Process, opts Options, res Result) {
+	t.Helper()
+	if len(res.Stats) != len(processes) {
+		t.Fatalf("%s: %d stats for %d processes", name, len(res.Stats), len(processes))
+	}
+	if err := Verify(processes, res); err != nil {
+		t.Errorf("%s: %v", name, err)
+	}
+	var bursts int64
+	for i, s := range res.Stats {
+		p := processes[i]
+		bursts += p.BurstDuration
+		switch {
+		case s.Process != p:
+			t.Errorf("%s: stat %d is of %+v, want %+v", name, i, s.Process, p)
+		case s.Turnaround != s.Wait+s.BurstDuration:
+			t.Errorf("%s: P%d turnaround %d != wait %d + burst %d", name, p.ProcessID, s.Turnaround, s.Wait, s.BurstDuration)
+		case s.Completion < p.ArrivalTime+p.BurstDuration:
+			t.Errorf("%s: P%d completes at %d, before arrival %d + burst %d", name, p.ProcessID, s.Completion, p.ArrivalTime, p.BurstDuration)
+		case s.Wait < 0:
+			t.Errorf("%s: P%d waits %d", name, p.ProcessID, s.Wait)
+		}
+	}
+
+	// Process IDs are unique in these workloads, so slices map back to
+	// their process.
+	stats := make(map[int64]Stat, len(res.Stats))
+	for _, s := range res.Stats {
+		stats[s.ProcessID] = s
+	}
+	ran := make(map[int64]int64)
+	var scheduled int64
+	for _, g := range res.Gantt {
+		s := stats[g.PID]
+		switch {
+		case g.Stop <= g.Start:
+			t.Errorf("%s: empty slice %v", name, g)
+		case g.CPU < 0 || g.CPU >= opts.CPUs:
+			t.Errorf("%s: slice %v on no CPU", name, g)
+		case g.Start < s.ArrivalTime || g.Stop > s.Completion:
+			t.Errorf("%s: slice %v outside P%d's lifetime %d-%d", name, g, g.PID, s.ArrivalTime, s.Completion)
+		}
+		ran[g.PID] += g.Stop - g.Start
+		scheduled += g.Stop - g.Start
+	}
+	for _, s := range res.Stats {
+		if ran[s.ProcessID] != s.BurstDuration {
+			t.Errorf("%s: P%d runs for %d, want its burst %d", name, s.ProcessID, ran[s.ProcessID], s.BurstDuration)
+		}
+	}
+	if scheduled != bursts {
+		t.Errorf("%s: %d scheduled, want the total burst %d", name, scheduled, bursts)
+	}
+
+	byCPU := slices.Clone(res.Gantt)
+	slices.SortFunc(byCPU, func(a, b TimeSlice) int {
+		return cmp.Or(cmp.Compare(a.CPU, b.CPU), cmp.Compare(a.Start, b.Start))
+	})
+	for i := 1; i < len(byCPU); i++ {
+		if a, b := byCPU[i-1], byCPU[i]; a.CPU == b.CPU && b.Start < a.Stop {
+			t.Errorf("%s: slices %v and %v overlap", name, a, b)
+		}
+	}
+	byPID := slices.Clone(res.Gantt)
+	slices.SortFunc(byPID, func(a, b TimeSlice) int {
+		return cmp.Or(cmp.Compare(a.PID, b.PID), cmp.Compare(a.Start, b.Start))
+	})
+	for i := 1; i < len(byPID); i++ {
+		if a, b := byPID[i-1], byPID[i]; a.PID == b.PID && b.Start < a.Stop {
+			t.Errorf("%s: P%d runs twice at once in %v and %v", name, a.PID, a, b)
+		}
+	}
+
+	if opts.CPUs == 1 && opts.SwitchCost == 0 {
+		var lifetimes []TimeSlice
+		for _, s := range res.Stats {
+			if s.BurstDuration > 0 {
+				lifetimes = append(lifetimes, TimeSlice{Start: s.ArrivalTime, Stop: s.Completion})
+			}
+		}
+		slices.SortFunc(lifetimes, func(a, b TimeSlice) int { return cmp.Compare(a.Start, b.Start) })
+		if busy, cover := merge(lifetimes), merge(byCPU); !slices.Equal(busy, cover) {
+			t.Errorf("%s: slices cover %v, want the busy time %v", name, cover, busy)
+		}
+	}
+}
+
+// merge joins sorted intervals that touch or overlap, keeping only their
+// start and stop.
+func merge(intervals []TimeSlice) []TimeSlice {
+	var out []TimeSlice
+	for _, s := range intervals {
+		if n := len(out); n > 0 && s.Start <= out[n-1].Stop {
+			out[n-1].Stop = max(out[n-1].Stop, s.Stop)
+			continue
+		}
+		out = append(out, TimeSlice{Start: s.Start, Stop: s.Stop})
+	}
+	return out
+}
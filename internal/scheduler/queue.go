@@ -0,0 +1,73 @@
+package scheduler
+
+import "container/heap"
+
+// jobHeap is a ready queue ordered by less, used by the policies that pick
+// the best job rather than the oldest.
+type jobHeap struct {
+	jobs []*Job
+	less func(a, b *Job) bool
+}
+
+func (h *jobHeap) Len() int           { return len(h.jobs) }
+func (h *jobHeap) Less(i, j int) bool { return h.less(h.jobs[i], h.jobs[j]) }
+func (h *jobHeap) Swap(i, j int)      { h.jobs[i], h.jobs[j] = h.jobs[j], h.jobs[i] }
+func (h *jobHeap) Push(x any)         { h.jobs = append(h.jobs, x.(*Job)) }
+
+func (h *jobHeap) Pop() any {
+	j := h.jobs[len(h.jobs)-1]
+	h.jobs[len(h.jobs)-1] = nil
+	h.jobs = h.jobs[:len(h.jobs)-1]
+	return j
+}
+
+func (h *jobHeap) push(j *Job) { heap.Push(h, j) }
+
+func (h *jobHeap) pop() *Job {
+	if len(h.jobs) == 0 {
+		return nil
+	}
+	return heap.Pop(h).(*Job)
+}
+
+// peek returns the best job without removing it, or nil if h is empty.
+func (h *jobHeap) peek() *Job {
+	if len(h.jobs) == 0 {
+		return nil
+	}
+	return h.jobs[0]
+}
+
+// fifo is a first-in, first-out ready queue. Popped jobs are not sliced
+// off but marked by head and compacted away once they make up half the
+// slice, so a queue cycling jobs, as round-robin does, reuses its array
+// rather than growing a new one.
+type fifo struct {
+	jobs []*Job
+	head int
+}
+
+func (q *fifo) push(j *Job) {
+	if q.head > 0 && q.head >= len(q.jobs)/2 {
+		n := copy(q.jobs, q.jobs[q.head:])
+		clear(q.jobs[n:])
+		q.jobs = q.jobs[:n]
+		q.head = 0
+	}
+	q.jobs = append(q.jobs, j)
+}
+
+func (q *fifo) pop() *Job {
+	if q.head == len(q.jobs) {
+		return nil
+	}
+	j := q.jobs[q.head]
+	q.jobs[q.head] = nil
+	q.head++
+	return j
+}
+
+// ready returns the queued jobs, oldest first.
+func (q *fifo) ready() []*Job { return q.jobs[q.head:] }
+
+func (q *fifo) len() int { return len(q.jobs) - q.head }
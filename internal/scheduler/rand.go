@@ -0,0 +1,28 @@
+package scheduler
+
+import "math/rand/v2"
+
+// Rand is the source of the random choices of randomized algorithms. A
+// *rand.Rand of math/rand/v2 is one; tests pass their own to choose the
+// draws.
+type Rand interface {
+	// Int64N returns a number in [0, n). n is always positive.
+	Int64N(n int64) int64
+}
+
+// NewRand returns the source randomized algorithms draw from under seed.
+// Each run needs a fresh one for its draws to depend only on the seed. Its
+// state can be saved in a Snapshot.
+func NewRand(seed int64) Rand {
+	src := rand.NewPCG(uint64(seed), 0)
+	return pcg{rand.New(src), src}
+}
+
+// pcg is a Rand whose state can be marshaled, being that of its PCG.
+type pcg struct {
+	*rand.Rand
+	src *rand.PCG
+}
+
+func (r pcg) MarshalBinary() ([]byte, error)    { return r.src.MarshalBinary() }
+func (r pcg) UnmarshalBinary(data []byte) error { return r.src.UnmarshalBinary(data) }
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// random runs a ready job chosen uniformly at random until it completes.
+type random struct {
+	ready []*Job // in the order they were made ready
+	rand  Rand
+}
+
+// NewRandom returns a non-preemptive policy choosing among the ready jobs
+// with draws from r.
+func NewRandom(r Rand) Policy {
+	return &random{rand: r}
+}
+
+func (p *random) Push(j *Job, _ int64) { p.ready = append(p.ready, j) }
+
+func (p *random) Pop(int64) *Job {
+	if len(p.ready) == 0 {
+		return nil
+	}
+	i := p.rand.Int64N(int64(len(p.ready)))
+	j := p.ready[i]
+	p.ready = slices.Delete(p.ready, int(i), int(i+1))
+	return j
+}
+
+func (p *random) Preempt(*Job, int64) bool { return false }
+func (p *random) Quantum(*Job) int64       { return 0 }
+
+func (p *random) SaveState() (json.RawMessage, error) {
+	r, err := saveRand(p.rand)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(readyState{Ready: indexes(p.ready), Rand: r})
+}
+
+func (p *random) RestoreState(state json.RawMessage, job func(int) *Job) error {
+	s, jobs, err := restoreReady(state, job)
+	if err != nil {
+		return err
+	}
+	p.ready = jobs
+	return restoreRand(p.rand, s.Rand)
+}
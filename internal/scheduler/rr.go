@@ -0,0 +1,38 @@
+package scheduler
+
+import "encoding/json"
+
+// rr runs ready jobs in turn, each for at most one quantum.
+type rr struct {
+	ready   fifo
+	quantum int64
+}
+
+// NewRR returns a round-robin policy. p must be valid, see RRParams.Validate.
+func NewRR(p RRParams) Policy {
+	return &rr{quantum: p.Quantum}
+}
+
+func (p *rr) Push(j *Job, _ int64)     { p.ready.push(j) }
+func (p *rr) Pop(int64) *Job           { return p.ready.pop() }
+func (p *rr) Preempt(*Job, int64) bool { return false }
+func (p *rr) Quantum(*Job) int64       { return p.quantum }
+
+func (p *rr) SaveState() (json.RawMessage, error) {
+	return json.Marshal(readyState{Ready: indexes(p.ready.ready())})
+}
+
+func (p *rr) RestoreState(state json.RawMessage, job func(int) *Job) error {
+	_, jobs, err := restoreReady(state, job)
+	for _, j := range jobs {
+		p.ready.push(j)
+	}
+	return err
+}
+
+func (p *rr) List(int64) []*Job { return append([]*Job(nil), p.ready.ready()...) }
+
+// RR schedules processes round-robin with the default time quantum.
+func RR(processes []Process) Result {
+	return Schedule(NewRR(DefaultRRParams()), processes)
+}
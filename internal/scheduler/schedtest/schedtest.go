@@ -0,0 +1,236 @@
+// Package schedtest checks implementations of scheduler.Policy, such as a
+// new algorithm added to scheduler.Algorithms, against what every policy
+// must guarantee: whatever it chooses, every process runs for exactly its
+// burst within its lifetime, on one CPU at a time, and the results follow
+// from the Gantt chart. A test of a new policy need only call
+//
+//	if err := schedtest.TestAlgorithm(a); err != nil {
+//		t.Fatal(err)
+//	}
+//
+// Which process runs when is up to the policy, so no schedule is checked
+// for being the right one, nor a CPU for never idling while processes wait.
+package schedtest
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"p1/internal/scheduler"
+)
+
+// ErrNonconforming is returned by TestAlgorithm for an algorithm breaking
+// an invariant.
+var ErrNonconforming = errors.New("algorithm breaks a scheduling invariant")
+
+// Timeout is the longest TestAlgorithm lets a single run take before
+// declaring that it never ends.
+var Timeout = 10 * time.Second
+
+// maxProblems is the most problems TestAlgorithm reports.
+const maxProblems = 10
+
+// Workload is a named workload scheduled by TestAlgorithm.
+type Workload struct {
+	Name      string
+	Processes []scheduler.Process
+}
+
+// Workloads returns the canonical workloads TestAlgorithm schedules, from
+// the empty one to those with idle gaps, processes without bursts and
+// processes sharing an ID, and some random ones besides.
+func Workloads() []Workload {
+	workloads := []Workload{
+		{"empty", nil},
+		{"single", []scheduler.Process{{ProcessID: 1, BurstDuration: 5}}},
+		{"example", []scheduler.Process{
+			{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 2},
+			{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Priority: 1},
+			{ProcessID: 3, BurstDuration: 6, ArrivalTime: 6, Priority: 3},
+		}},
+		{"together", []scheduler.Process{
+			{ProcessID: 1, BurstDuration: 4, Priority: 3},
+			{ProcessID: 2, BurstDuration: 2, Priority: 1},
+			{ProcessID: 3, BurstDuration: 7, Priority: 2},
+			{ProcessID: 4, BurstDuration: 2, Priority: 1},
+		}},
+		{"unsorted", []scheduler.Process{
+			{ProcessID: 1, BurstDuration: 3, ArrivalTime: 8, Priority: 1},
+			{ProcessID: 2, BurstDuration: 6, ArrivalTime: 0, Priority: 4},
+			{ProcessID: 3, BurstDuration: 2, ArrivalTime: 4, Priority: 2},
+		}},
+		{"idle", []scheduler.Process{
+			{ProcessID: 1, BurstDuration: 2, ArrivalTime: 3},
+			{ProcessID: 2, BurstDuration: 4, ArrivalTime: 20, Priority: 1},
+			{ProcessID: 3, BurstDuration: 1, ArrivalTime: 21},
+		}},
+		{"zero-burst", []scheduler.Process{
+			{ProcessID: 1, ArrivalTime: 0},
+			{ProcessID: 2, BurstDuration: 3, ArrivalTime: 0},
+			{ProcessID: 3, ArrivalTime: 2},
+			{ProcessID: 4, BurstDuration: 1, ArrivalTime: 9},
+		}},
+		{"duplicate-ids", []scheduler.Process{
+			{ProcessID: 1, BurstDuration: 4},
+			{ProcessID: 1, BurstDuration: 2, ArrivalTime: 1},
+			{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Priority: 1},
+		}},
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	for i := range 40 {
+		processes := make([]scheduler.Process, 1+r.IntN(30))
+		for j := range processes {
+			processes[j] = scheduler.Process{
+				ProcessID:     int64(j + 1),
+				BurstDuration: r.Int64N(12),
+				ArrivalTime:   r.Int64N(int64(3 * len(processes))),
+				Priority:      r.Int64N(5),
+			}
+		}
+		workloads = append(workloads, Workload{fmt.Sprintf("random-%d", i), processes})
+	}
+	return workloads
+}
+
+// variants are the options every workload is scheduled under.
+var variants = []struct {
+	name string
+	opts []scheduler.Option
+}{
+	{"default", nil},
+	{"cpus3", []scheduler.Option{scheduler.WithCPUs(3)}},
+	{"switch2", []scheduler.Option{scheduler.WithSwitchCost(2)}},
+	{"quantum2", []scheduler.Option{scheduler.WithQuantum(2), scheduler.WithMLFQ(scheduler.MLFQParams{Levels: 2, Quanta: []int64{1, 3}})}},
+	{"cpus2-switch1-seed7", []scheduler.Option{scheduler.WithCPUs(2), scheduler.WithSwitchCost(1), scheduler.WithSeed(7)}},
+	{"speeds2-1-switch1", []scheduler.Option{scheduler.WithSpeeds(2, 1), scheduler.WithSwitchCost(1)}},
+}
+
+// TestAlgorithm schedules every workload of Workloads with a, under a range
+// of options, and fails with ErrNonconforming if any result breaks an
+// invariant, if a run panics or takes longer than Timeout or if two runs of
+// it differ.
+func TestAlgorithm(a scheduler.Algorithm) error {
+	var problems []string
+	for _, w := range Workloads() {
+		for _, v := range variants {
+			sim, err := scheduler.NewSimulator(v.opts...)
+			if err != nil {
+				return err
+			}
+			for _, p := range check(sim, a, w.Processes) {
+				if len(problems) < maxProblems {
+					problems = append(problems, fmt.Sprintf("%s/%s: %s", w.Name, v.name, p))
+				}
+			}
+		}
+	}
+	if problems != nil {
+		return fmt.Errorf("%w: %s: %s", ErrNonconforming, a.Name, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// check returns what is wrong with scheduling processes with a under sim.
+func check(sim *scheduler.Simulator, a scheduler.Algorithm, processes []scheduler.Process) []string {
+	res, err := schedule(sim, a, processes)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	var problems []string
+	// The run ends once as many processes complete as there are, so one
+	// completing twice leaves another to never complete.
+	for i, p := range processes {
+		if i < len(res.Stats) && res.Stats[i].Process != p {
+			problems = append(problems, fmt.Sprintf("process %d, P%d, never completes", i, p.ProcessID))
+		}
+	}
+	if problems != nil {
+		return problems
+	}
+	if err := scheduler.VerifySpeeds(processes, res, sim.Options().Speeds); err != nil {
+		problems = append(problems, err.Error())
+	}
+	problems = append(problems, invariants(processes, sim.Options(), res)...)
+	if again, err := schedule(sim, a, processes); err != nil || !reflect.DeepEqual(again, res) {
+		problems = append(problems, "a second run gave a different result")
+	}
+	return problems
+}
+
+// schedule runs a over processes, failing if it panics or outlasts Timeout.
+func schedule(sim *scheduler.Simulator, a scheduler.Algorithm, processes []scheduler.Process) (res scheduler.Result, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panicked: %v", v)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	res = sim.Stream(ctx, a, processes, scheduler.Sink{})
+	if ctx.Err() != nil {
+		return res, fmt.Errorf("still running after %v", Timeout)
+	}
+	return res, nil
+}
+
+// invariants returns which of those Verify leaves out res breaks: slices
+// are on a CPU of opts and within the lifetime of their process, and no CPU
+// runs two slices at once, nor a process on two CPUs.
+func invariants(processes []scheduler.Process, opts scheduler.Options, res scheduler.Result) []string {
+	type lifetime struct {
+		n                  int
+		arrival, completes int64
+	}
+	lifetimes := make(map[int64]lifetime)
+	for i, p := range processes {
+		l, ok := lifetimes[p.ProcessID]
+		if !ok {
+			l.arrival = p.ArrivalTime
+		}
+		l.n++
+		l.arrival = min(l.arrival, p.ArrivalTime)
+		if i < len(res.Stats) {
+			l.completes = max(l.completes, res.Stats[i].Completion)
+		}
+		lifetimes[p.ProcessID] = l
+	}
+
+	var problems []string
+	for _, g := range res.Gantt {
+		l := lifetimes[g.PID]
+		switch {
+		case g.CPU < 0 || g.CPU >= opts.CPUs:
+			problems = append(problems, fmt.Sprintf("slice %+v is on no CPU", g))
+		case g.Start < l.arrival || g.Stop > l.completes:
+			problems = append(problems, fmt.Sprintf("slice %+v is outside P%d's lifetime %d-%d", g, g.PID, l.arrival, l.completes))
+		}
+	}
+
+	byCPU := slices.Clone(res.Gantt)
+	slices.SortFunc(byCPU, func(a, b scheduler.TimeSlice) int {
+		return cmp.Or(cmp.Compare(a.CPU, b.CPU), cmp.Compare(a.Start, b.Start))
+	})
+	for i := 1; i < len(byCPU); i++ {
+		if a, b := byCPU[i-1], byCPU[i]; a.CPU == b.CPU && b.Start < a.Stop {
+			problems = append(problems, fmt.Sprintf("slices %+v and %+v overlap", a, b))
+		}
+	}
+	// Processes sharing an ID may well run at once.
+	byPID := slices.Clone(res.Gantt)
+	slices.SortFunc(byPID, func(a, b scheduler.TimeSlice) int {
+		return cmp.Or(cmp.Compare(a.PID, b.PID), cmp.Compare(a.Start, b.Start))
+	})
+	for i := 1; i < len(byPID); i++ {
+		if a, b := byPID[i-1], byPID[i]; a.PID == b.PID && b.Start < a.Stop && lifetimes[a.PID].n == 1 {
+			problems = append(problems, fmt.Sprintf("P%d runs twice at once in %+v and %+v", a.PID, a, b))
+		}
+	}
+	return problems
+}
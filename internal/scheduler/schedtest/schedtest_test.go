@@ -0,0 +1,64 @@
+package schedtest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"p1/internal/scheduler"
+)
+
+func TestBuiltinsConform(t *testing.T) {
+	for _, a := range scheduler.Algorithms() {
+		if err := TestAlgorithm(a); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// hoard never dispatches anything.
+type hoard struct{}
+
+func (hoard) Push(*scheduler.Job, int64)         {}
+func (hoard) Pop(int64) *scheduler.Job           { return nil }
+func (hoard) Preempt(*scheduler.Job, int64) bool { return false }
+func (hoard) Quantum(*scheduler.Job) int64       { return 0 }
+
+// rerun dispatches its last job once more after it completes.
+type rerun struct {
+	ready []*scheduler.Job
+	last  *scheduler.Job
+}
+
+func (p *rerun) Push(j *scheduler.Job, _ int64) { p.ready = append(p.ready, j) }
+
+func (p *rerun) Pop(int64) *scheduler.Job {
+	if len(p.ready) == 0 {
+		if j := p.last; j != nil && j.Remaining == 0 {
+			p.last = nil
+			return j
+		}
+		return nil
+	}
+	p.last, p.ready = p.ready[0], p.ready[1:]
+	return p.last
+}
+
+func (p *rerun) Preempt(*scheduler.Job, int64) bool { return false }
+func (p *rerun) Quantum(*scheduler.Job) int64       { return 0 }
+
+func TestNonconforming(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		new  func(scheduler.Options) scheduler.Policy
+		want string
+	}{
+		{"hoard", func(scheduler.Options) scheduler.Policy { return hoard{} }, "panicked"},
+		{"rerun", func(scheduler.Options) scheduler.Policy { return &rerun{} }, "never completes"},
+	} {
+		err := TestAlgorithm(scheduler.Algorithm{Name: tt.name, New: tt.new})
+		if !errors.Is(err, ErrNonconforming) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %v mentioning %q", tt.name, err, ErrNonconforming, tt.want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package scheduler implements the CPU scheduling algorithms simulated by
+// schedsim. Each algorithm is a Policy deciding which ready process runs
+// next; a shared discrete-event engine drives the policy over a workload and
+// produces a Result holding the Gantt chart and per-process timings. Rendering
+// is left to the caller.
+//
+// Schedulers never modify the processes they are given and keep all of their
+// working state local to the call, so the same workload can be scheduled by
+// several algorithms in any order or concurrently.
+//
+// Simulator and Algorithm values are safe for concurrent use: every Schedule
+// or Simulate call builds a fresh Policy, and any number of calls may run in
+// parallel on the same Simulator and workload. A Policy itself holds the
+// ready queue of one run and must not be shared between runs.
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+)
+
+type (
+	Process struct {
+		ProcessID     int64 `json:"id"`
+		ArrivalTime   int64 `json:"arrival"`
+		BurstDuration int64 `json:"burst"`
+		Priority      int64 `json:"priority"`
+		// Weight, if set, is the share of the CPU the process gets under
+		// proportional-share scheduling, as lottery tickets. Otherwise the
+		// process holds the Tickets of its priority.
+		Weight int64 `json:"weight,omitempty"`
+		// Estimate, if set, is the burst schedulers expect the process to
+		// need. Those going by burst lengths decide by it, while the
+		// process still runs for BurstDuration.
+		Estimate int64 `json:"estimate,omitempty"`
+		// Group, if set, is the group the process is in under fair-share
+		// scheduling, which divides the CPU between groups by their
+		// shares. Processes without one make up a group of their own.
+		Group string `json:"group,omitempty"`
+		// Class, if set, is the scheduling class of the process, which
+		// scheduling by class runs it in. Processes without one are
+		// interactive.
+		Class Class `json:"class,omitempty"`
+		// Deadline, if set, is the time the process should complete by.
+		// Schedulers take no notice of it, but reports measure how late
+		// processes complete against it.
+		Deadline int64 `json:"deadline,omitempty"`
+	}
+	TimeSlice struct {
+		PID   int64 `json:"pid"`
+		Start int64 `json:"start"`
+		Stop  int64 `json:"stop"`
+		CPU   int   `json:"cpu"`
+	}
+	// Stat is the computed timing of a single process.
+	Stat struct {
+		Process
+		Wait       int64 `json:"wait"`
+		Turnaround int64 `json:"turnaround"`
+		Completion int64 `json:"completion"`
+	}
+	// Result is the outcome of running a scheduler over a set of processes.
+	// Stats are kept in the same order as the input processes, and of a run
+	// stopped early hold only those that completed.
+	Result struct {
+		Gantt         []TimeSlice `json:"gantt"`
+		Stats         []Stat      `json:"stats"`
+		AveWait       float64     `json:"averageWait"`
+		AveTurnaround float64     `json:"averageTurnaround"`
+		AveThroughput float64     `json:"throughput"`
+		// ContextSwitches counts dispatches of a process onto a CPU that
+		// last ran a different one.
+		ContextSwitches int `json:"contextSwitches"`
+	}
+)
+
+// ErrInvalidProcess is returned for processes with times or shares no
+// workload should have.
+var ErrInvalidProcess = errors.New("invalid process")
+
+// Validate fails with ErrInvalidProcess if p has a negative time, or a
+// weight past MaxWeight. Schedulers run a negative burst as none and start
+// the clock at the earliest arrival, but their results for such processes
+// mean little.
+func (p Process) Validate() error {
+	switch {
+	case p.BurstDuration < 0:
+		return fmt.Errorf("%w: burst must not be negative, got %d", ErrInvalidProcess, p.BurstDuration)
+	case p.ArrivalTime < 0:
+		return fmt.Errorf("%w: arrival must not be negative, got %d", ErrInvalidProcess, p.ArrivalTime)
+	case p.Weight < 0 || p.Weight > MaxWeight:
+		return fmt.Errorf("%w: weight must be from 0 to %d, got %d", ErrInvalidProcess, MaxWeight, p.Weight)
+	case p.Estimate < 0:
+		return fmt.Errorf("%w: estimate must not be negative, got %d", ErrInvalidProcess, p.Estimate)
+	case p.Deadline < 0:
+		return fmt.Errorf("%w: deadline must not be negative, got %d", ErrInvalidProcess, p.Deadline)
+	}
+	return nil
+}
+
+// ExpectedBurst returns the burst schedulers expect p to need: its estimate,
+// if it has one, or else its burst.
+func (p Process) ExpectedBurst() int64 {
+	if p.Estimate > 0 {
+		return p.Estimate
+	}
+	return p.BurstDuration
+}
+
+// Lateness returns how long after its deadline s completed, negative if it
+// completed before it, or 0 for a process without a deadline.
+func (s Stat) Lateness() int64 {
+	if s.Deadline == 0 {
+		return 0
+	}
+	return s.Completion - s.Deadline
+}
+
+// Tardiness returns how long after its deadline s completed, or 0 if it
+// met it.
+func (s Stat) Tardiness() int64 { return max(0, s.Lateness()) }
+
+// summarize fills in the averages of r from its per-process stats.
+func (r *Result) summarize() {
+	var totalWait, totalTurnaround, lastCompletion float64
+	for _, s := range r.Stats {
+		totalWait += float64(s.Wait)
+		totalTurnaround += float64(s.Turnaround)
+		lastCompletion = max(lastCompletion, float64(s.Completion))
+	}
+	count := float64(len(r.Stats))
+	if count == 0 || lastCompletion == 0 {
+		return
+	}
+	r.AveWait = totalWait / count
+	r.AveTurnaround = totalTurnaround / count
+	r.AveThroughput = count / lastCompletion
+}
+
+// Coalesce returns gantt with every slice that runs on where the one before
+// it on the same CPU stopped, for the same process, joined to that one. The
+// engine records slices this way; Coalesce brings charts written before it
+// did in line.
+func Coalesce(gantt []TimeSlice) []TimeSlice {
+	out := make([]TimeSlice, 0, len(gantt))
+	last := make(map[int]int)
+	for _, s := range gantt {
+		if i, ok := last[s.CPU]; ok && out[i].PID == s.PID && out[i].Stop == s.Start {
+			out[i].Stop = s.Stop
+			continue
+		}
+		last[s.CPU] = len(out)
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,398 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// example mirrors example_processes.csv.
+func example() []Process {
+	return []Process{
+		{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 2},
+		{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Priority: 1},
+		{ProcessID: 3, BurstDuration: 6, ArrivalTime: 6, Priority: 3},
+	}
+}
+
+type timing struct{ wait, turnaround, completion int64 }
+
+func timings(res Result) []timing {
+	t := make([]timing, len(res.Stats))
+	for i, s := range res.Stats {
+		t[i] = timing{s.Wait, s.Turnaround, s.Completion}
+	}
+	return t
+}
+
+func TestSchedulers(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule func([]Process) Result
+		gantt    []TimeSlice
+		timings  []timing
+		aveWait  float64
+	}{
+		{
+			name:     "FCFS",
+			schedule: FCFS,
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5},
+				{PID: 2, Start: 5, Stop: 14},
+				{PID: 3, Start: 14, Stop: 20},
+			},
+			timings: []timing{{0, 5, 5}, {2, 11, 14}, {8, 14, 20}},
+			aveWait: 10.0 / 3,
+		},
+		{
+			name:     "SJF",
+			schedule: SJF,
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5},
+				{PID: 2, Start: 5, Stop: 6},
+				{PID: 3, Start: 6, Stop: 12},
+				{PID: 2, Start: 12, Stop: 20},
+			},
+			timings: []timing{{0, 5, 5}, {8, 17, 20}, {0, 6, 12}},
+			aveWait: 8.0 / 3,
+		},
+		{
+			name:     "Priority",
+			schedule: SJFPriority,
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 3},
+				{PID: 2, Start: 3, Stop: 12},
+				{PID: 1, Start: 12, Stop: 14},
+				{PID: 3, Start: 14, Stop: 20},
+			},
+			timings: []timing{{9, 14, 14}, {0, 9, 12}, {8, 14, 20}},
+			aveWait: 17.0 / 3,
+		},
+		{
+			name:     "RR",
+			schedule: RR,
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5},
+				{PID: 2, Start: 5, Stop: 10},
+				{PID: 3, Start: 10, Stop: 15},
+				{PID: 2, Start: 15, Stop: 19},
+				{PID: 3, Start: 19, Stop: 20},
+			},
+			timings: []timing{{0, 5, 5}, {7, 16, 19}, {8, 14, 20}},
+			aveWait: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := tt.schedule(example())
+			if !reflect.DeepEqual(res.Gantt, tt.gantt) {
+				t.Errorf("gantt = %v, want %v", res.Gantt, tt.gantt)
+			}
+			if got := timings(res); !reflect.DeepEqual(got, tt.timings) {
+				t.Errorf("timings = %v, want %v", got, tt.timings)
+			}
+			if res.AveWait != tt.aveWait {
+				t.Errorf("average wait = %v, want %v", res.AveWait, tt.aveWait)
+			}
+			if res.AveThroughput != 3.0/20 {
+				t.Errorf("throughput = %v, want %v", res.AveThroughput, 3.0/20)
+			}
+		})
+	}
+}
+
+// largeWorkload returns n processes arriving three ticks apart with bursts
+// cycling through 1..7, leaving idle gaps every so often.
+func largeWorkload(n int) []Process {
+	processes := make([]Process, n)
+	for i := range processes {
+		processes[i] = Process{
+			ProcessID:     int64(i + 1),
+			ArrivalTime:   int64(i * 3),
+			BurstDuration: int64(i%7 + 1),
+			Priority:      int64(i % 5),
+		}
+	}
+	return processes
+}
+
+func TestPriorityTies(t *testing.T) {
+	// P1 comes first in the workload but arrives once P2 is running, alike
+	// in priority and burst, so it waits rather than preempting it.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, ArrivalTime: 2, Priority: 1},
+		{ProcessID: 2, BurstDuration: 5, Priority: 1},
+	}
+	want := []TimeSlice{{PID: 2, Start: 0, Stop: 5}, {PID: 1, Start: 5, Stop: 10}}
+	if res := SJFPriority(processes); !reflect.DeepEqual(res.Gantt, want) || res.ContextSwitches != 1 {
+		t.Errorf("gantt = %v with %d switches, want %v with 1", res.Gantt, res.ContextSwitches, want)
+	}
+}
+
+func TestRRLargeWorkload(t *testing.T) {
+	for _, n := range []int{101, 1000, 5000} {
+		res := RR(largeWorkload(n))
+		if len(res.Stats) != n {
+			t.Fatalf("n=%d: got %d stats", n, len(res.Stats))
+		}
+		for i, s := range res.Stats {
+			if s.ProcessID != int64(i+1) {
+				t.Fatalf("n=%d: stat %d has pid %d", n, i, s.ProcessID)
+			}
+			if s.Turnaround != s.Wait+s.BurstDuration {
+				t.Errorf("n=%d pid=%d: turnaround %d != wait %d + burst %d",
+					n, s.ProcessID, s.Turnaround, s.Wait, s.BurstDuration)
+			}
+			if s.Completion < s.ArrivalTime+s.BurstDuration {
+				t.Errorf("n=%d pid=%d: completion %d before arrival+burst", n, s.ProcessID, s.Completion)
+			}
+		}
+	}
+}
+
+func TestGanttCoalesced(t *testing.T) {
+	// P1 is alone for its first two quanta and again after P2 leaves, so
+	// it runs on with no new slice.
+	res := RR([]Process{
+		{ProcessID: 1, BurstDuration: 14},
+		{ProcessID: 2, BurstDuration: 2, ArrivalTime: 7},
+	})
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 10}, {PID: 2, Start: 10, Stop: 12}, {PID: 1, Start: 12, Stop: 16}}
+	if !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("gantt = %v, want %v", res.Gantt, want)
+	}
+
+	split := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 0, Stop: 3, CPU: 1}, {PID: 1, Start: 5, Stop: 10},
+		{PID: 2, Start: 4, Stop: 6, CPU: 1}, {PID: 1, Start: 10, Stop: 12, CPU: 1},
+	}
+	want = []TimeSlice{{PID: 1, Start: 0, Stop: 10}, {PID: 2, Start: 0, Stop: 3, CPU: 1}, {PID: 2, Start: 4, Stop: 6, CPU: 1}, {PID: 1, Start: 10, Stop: 12, CPU: 1}}
+	if got := Coalesce(split); !reflect.DeepEqual(got, want) {
+		t.Errorf("Coalesce = %v, want %v", got, want)
+	}
+}
+
+// draws is a Rand handing out its draws in turn and recording the n of
+// each.
+type draws struct {
+	next []int64
+	ns   []int64
+}
+
+func (d *draws) Int64N(n int64) int64 {
+	d.ns = append(d.ns, n)
+	v := d.next[0]
+	d.next = d.next[1:]
+	return v
+}
+
+func TestRandomized(t *testing.T) {
+	// P1, P2 and P3 hold 8, 9 and 7 tickets, 24 in all.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, BurstDuration: 6, Priority: 3},
+	}
+	d := &draws{next: []int64{23, 16, 0, 0, 0}}
+	res := Schedule(NewLottery(RRParams{Quantum: 5}, d), processes)
+	// The last ticket is P3's and ticket 16 the last of P2's. P2, ready
+	// last, takes the place of P1 once it completes, and so ticket 0.
+	want := []TimeSlice{{PID: 3, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 10}, {PID: 1, Start: 10, Stop: 15}, {PID: 2, Start: 15, Stop: 19}, {PID: 3, Start: 19, Stop: 20}}
+	if !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("lottery gantt = %v, want %v", res.Gantt, want)
+	}
+	if wantNs := []int64{24, 24, 24, 16, 7}; !reflect.DeepEqual(d.ns, wantNs) {
+		t.Errorf("lottery drew from %v, want %v", d.ns, wantNs)
+	}
+
+	d = &draws{next: []int64{2, 0, 0}}
+	res = Schedule(NewRandom(d), processes)
+	want = []TimeSlice{{PID: 3, Start: 0, Stop: 6}, {PID: 1, Start: 6, Stop: 11}, {PID: 2, Start: 11, Stop: 20}}
+	if !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("random gantt = %v, want %v", res.Gantt, want)
+	}
+	if wantNs := []int64{3, 2, 1}; !reflect.DeepEqual(d.ns, wantNs) {
+		t.Errorf("random drew from %v, want %v", d.ns, wantNs)
+	}
+}
+
+func TestProcessValidate(t *testing.T) {
+	if err := (Process{ProcessID: 1, BurstDuration: 5, Weight: MaxWeight}).Validate(); err != nil {
+		t.Error(err)
+	}
+	for _, p := range []Process{{BurstDuration: -1}, {ArrivalTime: -1}, {Weight: MaxWeight + 1}, {Estimate: -1}, {Deadline: -1}} {
+		if err := p.Validate(); !errors.Is(err, ErrInvalidProcess) {
+			t.Errorf("%+v: err = %v, want %v", p, err, ErrInvalidProcess)
+		}
+	}
+
+	// A negative burst runs as none rather than without end.
+	res := Schedule(NewFCFS(FCFSParams{}), []Process{{ProcessID: 1, BurstDuration: -3}, {ProcessID: 2, BurstDuration: 2}})
+	if want := []TimeSlice{{PID: 2, Start: 0, Stop: 2}}; !reflect.DeepEqual(res.Gantt, want) || res.Stats[0].Completion != 0 {
+		t.Errorf("gantt = %v, stats = %v, want %v with P1 complete at 0", res.Gantt, res.Stats, want)
+	}
+}
+
+func TestNice(t *testing.T) {
+	favoured, err := FromNice(Process{ProcessID: 1, BurstDuration: 4}, -5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, _ := FromNice(Process{ProcessID: 2, BurstDuration: 4}, 0)
+	if favoured.Priority != 15 || favoured.Share() != 3121 || plain.Priority != 20 || plain.Share() != 1024 {
+		t.Errorf("nice -5 = %+v, nice 0 = %+v", favoured, plain)
+	}
+	if _, err := FromNice(Process{}, 20); !errors.Is(err, ErrInvalidNice) {
+		t.Errorf("err = %v, want %v", err, ErrInvalidNice)
+	}
+
+	// The lottery holds every ticket of both, the favoured process's
+	// first.
+	d := &draws{next: []int64{3121, 0}}
+	res := Schedule(NewLottery(RRParams{Quantum: 4}, d), []Process{favoured, plain})
+	if want := []TimeSlice{{PID: 2, Start: 0, Stop: 4}, {PID: 1, Start: 4, Stop: 8}}; !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("lottery gantt = %v, want %v", res.Gantt, want)
+	}
+	if wantNs := []int64{4145, 3121}; !reflect.DeepEqual(d.ns, wantNs) {
+		t.Errorf("lottery drew from %v, want %v", d.ns, wantNs)
+	}
+}
+
+func TestEstimates(t *testing.T) {
+	// P1 is taken for the shortest, and runs on past its estimate; P2 is
+	// taken for longer than P3.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 8, Estimate: 2},
+		{ProcessID: 2, BurstDuration: 3, Estimate: 6},
+		{ProcessID: 3, BurstDuration: 4, ArrivalTime: 1},
+	}
+	res := SJF(processes)
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 8}, {PID: 3, Start: 8, Stop: 12}, {PID: 2, Start: 12, Stop: 15}}
+	if !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("sjf gantt = %v, want %v", res.Gantt, want)
+	}
+	if got := timings(res); !reflect.DeepEqual(got, []timing{{0, 8, 8}, {12, 15, 15}, {7, 11, 12}}) {
+		t.Errorf("timings = %v", got)
+	}
+
+	// Priority breaks the tie between P1 and P2 by their estimates.
+	res = SJFPriority(processes[:2])
+	if want := []TimeSlice{{PID: 1, Start: 0, Stop: 8}, {PID: 2, Start: 8, Stop: 11}}; !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("priority gantt = %v, want %v", res.Gantt, want)
+	}
+}
+
+func TestRREmpty(t *testing.T) {
+	if res := RR(nil); len(res.Stats) != 0 || len(res.Gantt) != 0 {
+		t.Errorf("got %+v", res)
+	}
+}
+
+func TestSchedulersDoNotShareState(t *testing.T) {
+	schedulers := []func([]Process) Result{FCFS, SJF, SJFPriority, RR}
+	processes := largeWorkload(200)
+	want := make([]Result, len(schedulers))
+	for i, schedule := range schedulers {
+		want[i] = schedule(largeWorkload(200))
+	}
+
+	// Run in reverse order and concurrently over one shared slice.
+	got := make([]Result, len(schedulers))
+	done := make(chan struct{})
+	for i := len(schedulers) - 1; i >= 0; i-- {
+		go func(i int) {
+			got[i] = schedulers[i](processes)
+			done <- struct{}{}
+		}(i)
+	}
+	for range schedulers {
+		<-done
+	}
+
+	if !reflect.DeepEqual(processes, largeWorkload(200)) {
+		t.Error("schedulers modified their input")
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("results depend on run order")
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	sim := Simulate(context.Background(), NewSJF(), example())
+	var got []Event
+	for ev := range sim.Events {
+		got = append(got, ev)
+	}
+	want := []Event{
+		{Kind: EventArrive, Time: 0, PID: 1},
+		{Kind: EventDispatch, Time: 0, PID: 1},
+		{Kind: EventArrive, Time: 3, PID: 2},
+		{Kind: EventComplete, Time: 5, PID: 1},
+		{Kind: EventDispatch, Time: 5, PID: 2},
+		{Kind: EventArrive, Time: 6, PID: 3},
+		{Kind: EventPreempt, Time: 6, PID: 2},
+		{Kind: EventDispatch, Time: 6, PID: 3},
+		{Kind: EventComplete, Time: 12, PID: 3},
+		{Kind: EventDispatch, Time: 12, PID: 2},
+		{Kind: EventComplete, Time: 20, PID: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+	if res := sim.Result(); !reflect.DeepEqual(res, SJF(example())) {
+		t.Errorf("result = %+v, want %+v", res, SJF(example()))
+	}
+}
+
+func TestSimulateIdle(t *testing.T) {
+	processes := []Process{{ProcessID: 1, BurstDuration: 2, ArrivalTime: 3}}
+	sim := Simulate(context.Background(), NewFCFS(FCFSParams{}), processes)
+	ev := <-sim.Events
+	if ev != (Event{Kind: EventIdle, Time: 0}) {
+		t.Errorf("first event = %v, want idle at 0", ev)
+	}
+	for range sim.Events {
+	}
+	if s := sim.Result().Stats[0]; s.Wait != 0 || s.Completion != 5 {
+		t.Errorf("stat = %+v, want wait 0 and completion 5", s)
+	}
+}
+
+func TestSimulateCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sim := Simulate(ctx, NewRR(RRParams{Quantum: 5}), largeWorkload(1000))
+	<-sim.Events
+	cancel()
+	for range sim.Events {
+	}
+	res := sim.Result()
+	if len(res.Stats) == 1000 {
+		t.Error("cancelled run completed every process")
+	}
+	for _, s := range res.Stats {
+		if s.Completion == 0 {
+			t.Fatalf("stat of a process yet to complete: %+v", s)
+		}
+	}
+}
+
+func TestEventJSON(t *testing.T) {
+	ev := Event{Kind: EventPreempt, Time: 6, PID: 2, CPU: 1}
+	b, err := json.Marshal(ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"kind":"preempt","time":6,"pid":2,"cpu":1}`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+	var got Event
+	if err := json.Unmarshal(b, &got); err != nil || got != ev {
+		t.Errorf("round trip = %+v, %v, want %+v", got, err, ev)
+	}
+	if err := json.Unmarshal([]byte(`{"kind":"explode"}`), &got); err == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+}
@@ -0,0 +1,198 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidOption = errors.New("invalid option")
+
+// Options configures a Simulator.
+type Options struct {
+	FCFS  FCFSParams  `json:"fcfs,omitzero"`
+	RR    RRParams    `json:"rr"`
+	MLFQ  MLFQParams  `json:"mlfq"`
+	Aging AgingParams `json:"aging"`
+	// FairShare configures fair-share scheduling, and the quantum it
+	// runs jobs for is that of RR.
+	FairShare FairShareParams `json:"fairShare,omitzero"`
+	// Classes names the algorithm scheduling each class of processes
+	// under scheduling by class.
+	Classes ClassParams `json:"classes,omitzero"`
+	// CPUs is the number of processors jobs are dispatched to.
+	CPUs int `json:"cpus"`
+	// Speeds, if set, are the speeds of the CPUs in turn, one for each, as
+	// whole multiples of the slowest a CPU can be: a CPU of speed 2 runs
+	// two ticks of burst in each tick, so that a burst of 5 takes it 3.
+	// Otherwise every CPU is of speed 1. Jobs are put on the fastest CPU
+	// free unless their policy is a Placer. Policies that keep track of how
+	// long jobs run, such as MLFQ and fair-share, count the burst they ran.
+	Speeds []int64 `json:"speeds,omitempty"`
+	// SwitchCost is the time a processor spends switching from one process
+	// to another before the new one makes progress. A process switched to
+	// runs for at least a tick before it can be preempted.
+	SwitchCost int64 `json:"switchCost"`
+	// Seed seeds the random choices of randomized algorithms.
+	Seed int64 `json:"seed"`
+	// NoGantt leaves the Gantt chart out of results, for runs where only
+	// the timings matter and the chart would dwarf them.
+	NoGantt bool `json:"noGantt,omitempty"`
+	// Assert checks the state of runs for consistency after every step,
+	// panicking with an *AssertionError at the first inconsistency. It
+	// slows runs down but changes no result.
+	Assert bool `json:"assert,omitempty"`
+	// Suspensions take processes out of contention for a while.
+	Suspensions []Suspension `json:"suspensions,omitempty"`
+	// ResumeBoost favours processes resuming from a suspension, as
+	// interactive schedulers favour those waking from I/O, see
+	// Job.Boosted.
+	ResumeBoost bool `json:"resumeBoost,omitempty"`
+}
+
+// DefaultOptions returns the options a Simulator uses unless told otherwise.
+func DefaultOptions() Options {
+	return Options{
+		RR:    DefaultRRParams(),
+		MLFQ:  DefaultMLFQParams(),
+		Aging: DefaultAgingParams(),
+		CPUs:  1,
+	}
+}
+
+// Option changes one setting of a Simulator.
+type Option func(*Options)
+
+// WithOptions replaces every setting with those in o.
+func WithOptions(o Options) Option { return func(dst *Options) { *dst = o } }
+
+// WithQuantum sets the round-robin time quantum.
+func WithQuantum(quantum int64) Option { return func(o *Options) { o.RR.Quantum = quantum } }
+
+func WithFCFS(p FCFSParams) Option           { return func(o *Options) { o.FCFS = p } }
+func WithRR(p RRParams) Option               { return func(o *Options) { o.RR = p } }
+func WithMLFQ(p MLFQParams) Option           { return func(o *Options) { o.MLFQ = p } }
+func WithAging(p AgingParams) Option         { return func(o *Options) { o.Aging = p } }
+func WithFairShare(p FairShareParams) Option { return func(o *Options) { o.FairShare = p } }
+func WithClasses(p ClassParams) Option       { return func(o *Options) { o.Classes = p } }
+func WithCPUs(n int) Option                  { return func(o *Options) { o.CPUs = n } }
+func WithSwitchCost(cost int64) Option       { return func(o *Options) { o.SwitchCost = cost } }
+func WithSeed(seed int64) Option             { return func(o *Options) { o.Seed = seed } }
+func WithoutGantt() Option                   { return func(o *Options) { o.NoGantt = true } }
+func WithAssertions() Option                 { return func(o *Options) { o.Assert = true } }
+func WithResumeBoost() Option                { return func(o *Options) { o.ResumeBoost = true } }
+
+// WithSpeeds sets the speeds of the CPUs, and their number to that of the
+// speeds.
+func WithSpeeds(speeds ...int64) Option {
+	return func(o *Options) { o.Speeds, o.CPUs = speeds, len(speeds) }
+}
+
+// WithSuspensions adds suspensions to those of the run.
+func WithSuspensions(s ...Suspension) Option {
+	return func(o *Options) { o.Suspensions = append(o.Suspensions, s...) }
+}
+
+// Simulator runs algorithms under a fixed set of options. A Simulator is
+// immutable once created and may be shared between goroutines.
+type Simulator struct {
+	opts Options
+}
+
+// NewSimulator returns a simulator with the default options changed by opts.
+func NewSimulator(opts ...Option) (*Simulator, error) {
+	o := DefaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.MLFQ.Quanta = append([]int64(nil), o.MLFQ.Quanta...)
+	o.Suspensions = append([]Suspension(nil), o.Suspensions...)
+	o.FairShare.Shares = append([]GroupShare(nil), o.FairShare.Shares...)
+	o.Speeds = append([]int64(nil), o.Speeds...)
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	return &Simulator{opts: o}, nil
+}
+
+// Validate reports the first setting of o that is out of range.
+func (o Options) Validate() error {
+	for _, p := range []interface{ Validate() error }{o.FCFS, o.RR, o.MLFQ, o.Aging, o.FairShare, o.Classes} {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	// fcfs lines processes up as given by their place in the whole
+	// workload, which it cannot do seeing one class of it.
+	for _, c := range classes {
+		if o.FCFS.Order == OrderGiven && o.Classes.Algorithm(c) == "fcfs" {
+			return fmt.Errorf("%w: fcfs cannot line up the %s class in given order", ErrInvalidOption, c)
+		}
+	}
+	switch {
+	case o.CPUs < 1:
+		return fmt.Errorf("%w: need at least one CPU, got %d", ErrInvalidOption, o.CPUs)
+	case o.SwitchCost < 0:
+		return fmt.Errorf("%w: switch cost must not be negative, got %d", ErrInvalidOption, o.SwitchCost)
+	case len(o.Speeds) > 0 && len(o.Speeds) != o.CPUs:
+		return fmt.Errorf("%w: need a speed for each of %d CPUs, got %d", ErrInvalidOption, o.CPUs, len(o.Speeds))
+	}
+	for c, v := range o.Speeds {
+		if v < 1 || v > MaxSpeed {
+			return fmt.Errorf("%w: speed of CPU %d must be from 1 to %d, got %d", ErrInvalidOption, c, MaxSpeed, v)
+		}
+	}
+	return validateSuspensions(o.Suspensions)
+}
+
+// MaxSpeed is the fastest a CPU can be, as a multiple of the slowest.
+const MaxSpeed = 1 << 16
+
+// Speed returns the speed of CPU c.
+func (o Options) Speed(c int) int64 {
+	if len(o.Speeds) == 0 {
+		return 1
+	}
+	return o.Speeds[c]
+}
+
+// Options returns the options s was created with.
+func (s *Simulator) Options() Options {
+	o := s.opts
+	o.MLFQ.Quanta = append([]int64(nil), o.MLFQ.Quanta...)
+	o.Suspensions = append([]Suspension(nil), o.Suspensions...)
+	o.FairShare.Shares = append([]GroupShare(nil), o.FairShare.Shares...)
+	o.Speeds = append([]int64(nil), o.Speeds...)
+	return o
+}
+
+// Schedule runs a over processes to completion.
+func (s *Simulator) Schedule(a Algorithm, processes []Process) Result {
+	return run(context.Background(), a.New(s.opts), processes, s.opts, nil)
+}
+
+// Sink receives the parts of a result as a run produces them, so they can be
+// written out before it ends.
+type Sink struct {
+	// Slice, if set, is called with each slice of the Gantt chart once it
+	// is final, which is in the order slices end rather than start.
+	Slice func(TimeSlice)
+	// Stat, if set, is called with the timing of each process as it
+	// completes.
+	Stat func(Stat)
+}
+
+// Stream runs a over processes like Schedule, passing each part of the
+// result to sink as it is settled. Once ctx is cancelled the run stops
+// early, and only processes completed by then are in its Stats.
+func (s *Simulator) Stream(ctx context.Context, a Algorithm, processes []Process, sink Sink) Result {
+	e := newEngine(a.New(s.opts), processes, s.opts)
+	e.sink = sink
+	return e.run(ctx)
+}
+
+// Simulate runs a over processes in the background, streaming its events as
+// they occur, see the package-level Simulate.
+func (s *Simulator) Simulate(ctx context.Context, a Algorithm, processes []Process) *Simulation {
+	return simulate(ctx, a.New(s.opts), processes, s.opts)
+}
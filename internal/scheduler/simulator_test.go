@@ -0,0 +1,336 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mustSimulator(t *testing.T, opts ...Option) *Simulator {
+	t.Helper()
+	sim, err := NewSimulator(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sim
+}
+
+func mustAlgorithm(t *testing.T, name string) Algorithm {
+	t.Helper()
+	a, err := LookupAlgorithm(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestNewSimulatorInvalid(t *testing.T) {
+	for _, opt := range []Option{
+		WithQuantum(0), WithCPUs(0), WithSwitchCost(-1),
+		WithSuspensions(Suspension{PID: 1, From: 5, To: 5}),
+		WithSuspensions(Suspension{PID: 1, From: 0, To: 5}, Suspension{PID: 1, From: 5, To: 9}),
+		WithFairShare(FairShareParams{Shares: []GroupShare{{Group: "web", Share: 0}}}),
+		WithFairShare(FairShareParams{Shares: []GroupShare{{Group: "web", Share: 2}, {Group: "web", Share: 3}}}),
+		WithClasses(ClassParams{Batch: "nope"}),
+		WithClasses(ClassParams{Realtime: "classes"}),
+		func(o *Options) { o.FCFS.Order, o.Classes.Interactive = OrderGiven, "fcfs" },
+		WithSpeeds(2, 0), WithSpeeds(MaxSpeed+1, 1), WithSpeeds(),
+		func(o *Options) { o.CPUs, o.Speeds = 2, []int64{2} },
+	} {
+		if _, err := NewSimulator(opt); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("err = %v, want %v", err, ErrInvalidOption)
+		}
+	}
+}
+
+func TestSimulatorDefaults(t *testing.T) {
+	sim := mustSimulator(t)
+	if got := sim.Options(); !reflect.DeepEqual(got, DefaultOptions()) {
+		t.Errorf("options = %+v, want %+v", got, DefaultOptions())
+	}
+	if got, want := sim.Schedule(mustAlgorithm(t, "rr"), example()), RR(example()); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestSimulatorQuantum(t *testing.T) {
+	res := mustSimulator(t, WithQuantum(20)).Schedule(mustAlgorithm(t, "rr"), example())
+	if !reflect.DeepEqual(res.Gantt, FCFS(example()).Gantt) {
+		t.Errorf("RR with a quantum longer than every burst should match FCFS, got %v", res.Gantt)
+	}
+}
+
+func TestSimulatorCPUs(t *testing.T) {
+	res := mustSimulator(t, WithCPUs(2)).Schedule(mustAlgorithm(t, "fcfs"), example())
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 5, CPU: 0},
+		{PID: 3, Start: 6, Stop: 12, CPU: 0},
+		{PID: 2, Start: 3, Stop: 12, CPU: 1},
+	}
+	if !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("gantt = %v, want %v", res.Gantt, want)
+	}
+	if res.AveWait != 0 {
+		t.Errorf("average wait = %v, want 0", res.AveWait)
+	}
+}
+
+// slowest is a policy putting jobs on the slowest CPU free.
+type slowest struct{ Policy }
+
+func (slowest) Place(_ *Job, free []int, _ int64) int { return len(free) - 1 }
+
+func TestSimulatorSpeeds(t *testing.T) {
+	sim := mustSimulator(t, WithSpeeds(1, 2))
+	res := sim.Schedule(mustAlgorithm(t, "fcfs"), example())
+	// P1 and P2 run on the faster CPU 1 in half the time, rounded up.
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 3, CPU: 1},
+		{PID: 2, Start: 3, Stop: 8, CPU: 1},
+		{PID: 3, Start: 6, Stop: 12, CPU: 0},
+	}
+	if !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("gantt = %v, want %v", res.Gantt, want)
+	}
+	if s := res.Stats[1]; s.Wait != 0 || s.Turnaround != 5 {
+		t.Errorf("P2 wait %d, turnaround %d, want 0, 5", s.Wait, s.Turnaround)
+	}
+	if err := VerifySpeeds(example(), res, sim.Options().Speeds); err != nil {
+		t.Error(err)
+	}
+	if err := Verify(example(), res); !errors.Is(err, ErrInconsistent) || !strings.Contains(err.Error(), "P1 runs for 3 in the chart, want its burst 5") {
+		t.Errorf("err = %v, want P1 to run short on CPUs of speed 1", err)
+	}
+
+	res = run(context.Background(), slowest{NewFCFS(FCFSParams{})}, example(), sim.Options(), nil)
+	want = []TimeSlice{
+		{PID: 1, Start: 0, Stop: 5, CPU: 0},
+		{PID: 2, Start: 3, Stop: 8, CPU: 1},
+		{PID: 3, Start: 6, Stop: 12, CPU: 0},
+	}
+	if !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("placed on the slowest CPU, gantt = %v, want %v", res.Gantt, want)
+	}
+
+	// The burst a fast CPU runs in its last tick saturates rather than
+	// wrapping past math.MaxInt64.
+	processes := []Process{{ProcessID: 1, BurstDuration: math.MaxInt64}}
+	res = mustSimulator(t, WithSpeeds(MaxSpeed, 1)).Schedule(mustAlgorithm(t, "fcfs"), processes)
+	if want := []TimeSlice{{PID: 1, Stop: math.MaxInt64/MaxSpeed + 1}}; !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("gantt = %v, want %v", res.Gantt, want)
+	}
+}
+
+func TestSimulatorSwitchCost(t *testing.T) {
+	res := mustSimulator(t, WithSwitchCost(1)).Schedule(mustAlgorithm(t, "fcfs"), example())
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 5},
+		{PID: 2, Start: 6, Stop: 15},
+		{PID: 3, Start: 16, Stop: 22},
+	}
+	if !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("gantt = %v, want %v", res.Gantt, want)
+	}
+	if res.ContextSwitches != 2 {
+		t.Errorf("context switches = %d, want 2", res.ContextSwitches)
+	}
+}
+
+func TestSimulatorSimulate(t *testing.T) {
+	sim := mustSimulator(t, WithCPUs(2))
+	run := sim.Simulate(context.Background(), mustAlgorithm(t, "sjf"), example())
+	cpus := map[int]bool{}
+	for ev := range run.Events {
+		if ev.Kind == EventDispatch {
+			cpus[ev.CPU] = true
+		}
+	}
+	if len(cpus) != 2 {
+		t.Errorf("dispatched onto CPUs %v, want both", cpus)
+	}
+	if res := run.Result(); !reflect.DeepEqual(res, sim.Schedule(mustAlgorithm(t, "sjf"), example())) {
+		t.Errorf("streamed result differs from Schedule: %+v", res)
+	}
+}
+
+func TestSimulatorStream(t *testing.T) {
+	sim := mustSimulator(t, WithCPUs(2), WithQuantum(2))
+	var slices []TimeSlice
+	var completed []int64
+	res := sim.Stream(context.Background(), mustAlgorithm(t, "rr"), example(), Sink{
+		Slice: func(s TimeSlice) { slices = append(slices, s) },
+		Stat:  func(s Stat) { completed = append(completed, s.ProcessID) },
+	})
+	if want := sim.Schedule(mustAlgorithm(t, "rr"), example()); !reflect.DeepEqual(res, want) {
+		t.Errorf("streamed result differs from Schedule: %+v", res)
+	}
+	if len(slices) != len(res.Gantt) || len(completed) != len(res.Stats) {
+		t.Fatalf("streamed %d slices and %d processes, want %d and %d", len(slices), len(completed), len(res.Gantt), len(res.Stats))
+	}
+	for _, s := range res.Gantt {
+		found := false
+		for _, got := range slices {
+			found = found || got == s
+		}
+		if !found {
+			t.Errorf("slice %v was not streamed", s)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if res := sim.Stream(ctx, mustAlgorithm(t, "rr"), example(), Sink{}); len(res.Gantt) != 0 {
+		t.Errorf("cancelled run has gantt %v", res.Gantt)
+	}
+}
+
+func TestSimulatorWithoutGantt(t *testing.T) {
+	rr := mustAlgorithm(t, "rr")
+	res := mustSimulator(t, WithoutGantt()).Schedule(rr, example())
+	want := mustSimulator(t).Schedule(rr, example())
+	if res.Gantt != nil {
+		t.Errorf("gantt = %v, want none", res.Gantt)
+	}
+	if !reflect.DeepEqual(res.Stats, want.Stats) || res.ContextSwitches != want.ContextSwitches {
+		t.Errorf("timings differ without the gantt chart: %+v", res)
+	}
+}
+
+// unremoving is a policy that cannot take ready jobs out of its queue.
+type unremoving struct{ Policy }
+
+func TestSimulatorSuspensions(t *testing.T) {
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1},
+		{ProcessID: 3, BurstDuration: 2, ArrivalTime: 2},
+	}
+
+	// P1 gives up its CPU, and the CPU idles until P1 is back.
+	sim := mustSimulator(t, WithSuspensions(Suspension{PID: 1, From: 2, To: 9}))
+	run := sim.Simulate(context.Background(), mustAlgorithm(t, "fcfs"), processes)
+	var kinds []EventKind
+	for ev := range run.Events {
+		if ev.PID == 1 {
+			kinds = append(kinds, ev.Kind)
+		}
+	}
+	res := run.Result()
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 5}, {PID: 3, Start: 5, Stop: 7}, {PID: 1, Start: 9, Stop: 12}}
+	if !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("gantt = %v, want %v", res.Gantt, want)
+	}
+	if s := res.Stats[0]; s.Wait != 7 || s.Completion != 12 {
+		t.Errorf("P1 waits %d and completes at %d, want 7 and 12", s.Wait, s.Completion)
+	}
+	if want := []EventKind{EventArrive, EventDispatch, EventSuspend, EventResume, EventDispatch, EventComplete}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("P1 events %v, want %v", kinds, want)
+	}
+
+	// P3 is suspended while ready, so P1 runs before it, whether or not the
+	// policy can take it out of its queue.
+	sim = mustSimulator(t, WithSuspensions(Suspension{PID: 3, From: 3, To: 6}), WithAssertions())
+	want = []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 4}, {PID: 1, Start: 4, Stop: 8}, {PID: 3, Start: 8, Stop: 10}}
+	for _, a := range []Algorithm{
+		mustAlgorithm(t, "sjf"),
+		{Name: "sjf", New: func(Options) Policy { return unremoving{NewSJF()} }},
+	} {
+		if res := sim.Schedule(a, processes); !reflect.DeepEqual(res.Gantt, want) {
+			t.Errorf("%T: gantt = %v, want %v", a.New(sim.Options()), res.Gantt, want)
+		}
+	}
+}
+
+func TestSimulatorResumeBoost(t *testing.T) {
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, Priority: 1},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Priority: 1},
+		{ProcessID: 3, BurstDuration: 2, ArrivalTime: 2, Priority: 3},
+	}
+	sim := mustSimulator(t, WithSuspensions(Suspension{PID: 3, From: 3, To: 6}), WithResumeBoost(), WithAssertions())
+	// Resumed P3 goes ahead of P1, however much less pressing it is.
+	for name, want := range map[string][]TimeSlice{
+		"sjf":      {{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 4}, {PID: 1, Start: 4, Stop: 6}, {PID: 3, Start: 6, Stop: 8}, {PID: 1, Start: 8, Stop: 10}},
+		"priority": {{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 4}, {PID: 1, Start: 4, Stop: 6}, {PID: 3, Start: 6, Stop: 8}, {PID: 1, Start: 8, Stop: 10}},
+		"aging":    {{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}, {PID: 1, Start: 4, Stop: 6}, {PID: 3, Start: 6, Stop: 8}, {PID: 2, Start: 8, Stop: 9}, {PID: 1, Start: 9, Stop: 10}},
+	} {
+		if res := sim.Schedule(mustAlgorithm(t, name), processes); !reflect.DeepEqual(res.Gantt, want) {
+			t.Errorf("%s: gantt = %v, want %v", name, res.Gantt, want)
+		}
+	}
+
+	// Resumed P1 is back on the top level it had dropped from.
+	sim = mustSimulator(t, WithMLFQ(MLFQParams{Levels: 2, Quanta: []int64{1, 10}}), WithSuspensions(Suspension{PID: 1, From: 4, To: 6}), WithResumeBoost())
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 2}, {PID: 3, Start: 2, Stop: 3}, {PID: 1, Start: 3, Stop: 4}, {PID: 2, Start: 4, Stop: 6}, {PID: 1, Start: 6, Stop: 7}}
+	if res := sim.Schedule(mustAlgorithm(t, "mlfq"), processes); !reflect.DeepEqual(res.Gantt[:len(want)], want) {
+		t.Errorf("mlfq: gantt = %v, want it to start %v", res.Gantt, want)
+	}
+}
+
+func TestSimulatorFairShare(t *testing.T) {
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 8, Group: "web"},
+		{ProcessID: 2, BurstDuration: 8, Group: "batch"},
+	}
+	// Web holds three times the share of batch, which holds DefaultShare,
+	// so it runs three ticks to every one of batch's while both are ready.
+	want := []TimeSlice{{PID: 2, Start: 0, Stop: 1}, {PID: 1, Start: 1, Stop: 4}, {PID: 2, Start: 4, Stop: 5}, {PID: 1, Start: 5, Stop: 8}, {PID: 2, Start: 8, Stop: 9}, {PID: 1, Start: 9, Stop: 11}, {PID: 2, Start: 11, Stop: 16}}
+	sim := mustSimulator(t, WithQuantum(1), WithFairShare(FairShareParams{Shares: []GroupShare{{Group: "web", Share: 3 * DefaultShare}}}), WithAssertions())
+	if res := sim.Schedule(mustAlgorithm(t, "fairshare"), processes); !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("gantt = %v, want %v", res.Gantt, want)
+	}
+
+	// Batch arriving once web has run for a while does not get to run
+	// alone until it has caught up.
+	processes[1].ArrivalTime, processes[1].BurstDuration = 6, 6
+	sim = mustSimulator(t, WithQuantum(2))
+	want = []TimeSlice{{PID: 1, Start: 0, Stop: 6}, {PID: 2, Start: 6, Stop: 10}, {PID: 1, Start: 10, Stop: 12}, {PID: 2, Start: 12, Stop: 14}}
+	if res := sim.Schedule(mustAlgorithm(t, "fairshare"), processes); !reflect.DeepEqual(res.Gantt[:len(want)], want) {
+		t.Errorf("gantt = %v, want it to start %v", res.Gantt, want)
+	}
+
+	// Times near the horizon still alternate between equal shares, rather
+	// than overflowing once multiplied by them.
+	processes = []Process{
+		{ProcessID: 1, BurstDuration: 4e18, Group: "web"},
+		{ProcessID: 2, BurstDuration: 4e18, Group: "batch"},
+	}
+	sim = mustSimulator(t, WithQuantum(1e18))
+	want = []TimeSlice{{PID: 2, Start: 0, Stop: 1e18}, {PID: 1, Start: 1e18, Stop: 2e18}, {PID: 2, Start: 2e18, Stop: 3e18}, {PID: 1, Start: 3e18, Stop: 4e18}}
+	if res := sim.Schedule(mustAlgorithm(t, "fairshare"), processes); !reflect.DeepEqual(res.Gantt[:len(want)], want) {
+		t.Errorf("gantt = %v, want it to start %v", res.Gantt, want)
+	}
+}
+
+func TestSimulatorClasses(t *testing.T) {
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 6, Class: ClassBatch},
+		{ProcessID: 2, BurstDuration: 4, ArrivalTime: 1},
+		{ProcessID: 3, BurstDuration: 2, ArrivalTime: 2, Class: ClassRealtime},
+	}
+	// Each class takes the CPU from those below it as soon as it arrives,
+	// and gives it back once it has nothing ready.
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 1}, {PID: 2, Start: 1, Stop: 2}, {PID: 3, Start: 2, Stop: 4}, {PID: 2, Start: 4, Stop: 7}, {PID: 1, Start: 7, Stop: 12}}
+	sim := mustSimulator(t, WithAssertions())
+	if res := sim.Schedule(mustAlgorithm(t, "classes"), processes); !reflect.DeepEqual(res.Gantt, want) {
+		t.Errorf("gantt = %v, want %v", res.Gantt, want)
+	}
+
+	// Batch round-robin shares the CPU, once the others are done, in
+	// quanta of 2.
+	processes = append(processes, Process{ProcessID: 4, BurstDuration: 3, ArrivalTime: 5, Class: ClassBatch})
+	sim = mustSimulator(t, WithQuantum(2), WithClasses(ClassParams{Batch: "rr"}))
+	want = []TimeSlice{{PID: 1, Start: 7, Stop: 9}, {PID: 4, Start: 9, Stop: 11}, {PID: 1, Start: 11, Stop: 13}, {PID: 4, Start: 13, Stop: 14}, {PID: 1, Start: 14, Stop: 15}}
+	if res := sim.Schedule(mustAlgorithm(t, "classes"), processes); !reflect.DeepEqual(res.Gantt[len(res.Gantt)-len(want):], want) {
+		t.Errorf("gantt = %v, want it to end %v", res.Gantt, want)
+	}
+
+	var p Process
+	if err := json.Unmarshal([]byte(`{"class":"idle"}`), &p); !errors.Is(err, ErrUnknownClass) {
+		t.Errorf("err = %v, want %v", err, ErrUnknownClass)
+	}
+}
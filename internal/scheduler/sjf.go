@@ -0,0 +1,52 @@
+package scheduler
+
+import "encoding/json"
+
+// sjf runs the job with the least time expected to remain, preempting the
+// running job when a shorter one arrives.
+type sjf struct {
+	ready jobHeap
+}
+
+// NewSJF returns a preemptive shortest-job-first policy.
+func NewSJF() Policy {
+	return &sjf{ready: jobHeap{less: boostedFirst(shorter)}}
+}
+
+func shorter(a, b *Job) bool {
+	if a, b := a.Expected(), b.Expected(); a != b {
+		return a < b
+	}
+	return a.Index < b.Index
+}
+
+func (p *sjf) Push(j *Job, _ int64) { p.ready.push(j) }
+func (p *sjf) Pop(int64) *Job       { return p.ready.pop() }
+
+func (p *sjf) Preempt(running *Job, _ int64) bool {
+	j := p.ready.peek()
+	if j == nil {
+		return false
+	}
+	if first, ok := favoured(j, running); ok {
+		return first
+	}
+	return j.Expected() < running.Expected()
+}
+
+func (p *sjf) Quantum(*Job) int64 { return 0 }
+
+func (p *sjf) SaveState() (json.RawMessage, error) { return p.ready.save() }
+
+func (p *sjf) RestoreState(state json.RawMessage, job func(int) *Job) error {
+	return p.ready.restore(state, job)
+}
+
+func (p *sjf) List(int64) []*Job { return sortedJobs(p.ready.jobs, p.ready.less) }
+
+// SJF schedules processes shortest-remaining-job-first, preempting the
+// running process whenever a shorter one has arrived. Processes with an
+// estimate are taken to be as long as it.
+func SJF(processes []Process) Result {
+	return Schedule(NewSJF(), processes)
+}
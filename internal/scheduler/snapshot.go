@@ -0,0 +1,339 @@
+package scheduler
+
+import (
+	"cmp"
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	// ErrNoSnapshot is returned for runs whose state cannot be saved, as
+	// their policy is not a Snapshotter.
+	ErrNoSnapshot = errors.New("run cannot be snapshotted")
+	// ErrBadSnapshot is returned by Replay for snapshots that do not
+	// describe a run, or a workload it cannot carry on with.
+	ErrBadSnapshot = errors.New("invalid snapshot")
+)
+
+type (
+	// Snapshot is the full state of a run at one time, from which Replay
+	// carries it on. Jobs are named by their index in Processes.
+	Snapshot struct {
+		Algorithm string    `json:"algorithm"`
+		Options   Options   `json:"options"`
+		Time      int64     `json:"time"`
+		Processes []Process `json:"processes"`
+		// Jobs holds the state of every process arrived by Time.
+		Jobs []JobSnapshot `json:"jobs"`
+		CPUs []CPUSnapshot `json:"cpus"`
+		// Requeue holds the jobs whose quantum ran out at Time, to be made
+		// ready once the arrivals due then have been.
+		Requeue         []int       `json:"requeue,omitempty"`
+		Gantt           []TimeSlice `json:"gantt,omitempty"`
+		ContextSwitches int         `json:"contextSwitches"`
+		// Policy is the state of the policy, as its Snapshotter saved it.
+		Policy json.RawMessage `json:"policy"`
+	}
+
+	// JobSnapshot is the state of one job.
+	JobSnapshot struct {
+		Index     int   `json:"index"`
+		Remaining int64 `json:"remaining"`
+		Ready     int64 `json:"ready"`
+		Waited    int64 `json:"waited"`
+		// Saved is the time CPUs faster than speed 1 saved the job.
+		Saved int64 `json:"saved,omitempty"`
+		// Completion is when the job completed, if Completed.
+		Completed  bool  `json:"completed,omitempty"`
+		Completion int64 `json:"completion,omitempty"`
+		// Suspended is set for a job set aside by a suspension.
+		Suspended bool `json:"suspended,omitempty"`
+		Boosted   bool `json:"boosted,omitempty"`
+	}
+
+	// CPUSnapshot is the state of one processor. Job and Last are -1 for
+	// no job, and Slice is as in the engine's cpu.
+	CPUSnapshot struct {
+		Job     int   `json:"job"`
+		Last    int   `json:"last"`
+		Resume  int64 `json:"resume"`
+		Expires int64 `json:"expires"`
+		Idle    bool  `json:"idle,omitempty"`
+		Slice   int   `json:"slice,omitempty"`
+	}
+
+	// Snapshotter is implemented by policies whose state can be kept in a
+	// Snapshot. Every built-in policy is one.
+	Snapshotter interface {
+		// SaveState returns the state of the policy, naming jobs by Index.
+		SaveState() (json.RawMessage, error)
+		// RestoreState loads a state returned by SaveState into a fresh
+		// policy, looking up jobs by Index with job, which returns nil for
+		// jobs that cannot be ready.
+		RestoreState(state json.RawMessage, job func(index int) *Job) error
+	}
+)
+
+// Snapshot runs a over processes until the first time at or after t at
+// which a decision is due, and returns the state of the run then, before
+// any decision due at that time is made. A run over before t is taken at
+// its end.
+func (s *Simulator) Snapshot(a Algorithm, processes []Process, t int64) (Snapshot, error) {
+	e := newEngine(a.New(s.opts), processes, s.opts)
+	for e.done < len(e.jobs) && e.t < t {
+		e.step()
+	}
+	return e.save(a.Name, processes)
+}
+
+// Replay carries on the run snap was taken of until every process
+// completes, giving the result the run would have had.
+func Replay(snap Snapshot) (Result, error) {
+	return ReplayWith(snap, snap.Processes)
+}
+
+// ReplayWith is Replay with processes in place of the snapshot's workload.
+// Processes arriving before the snapshot was taken cannot change and must
+// be given at the same positions, but those arriving since may be changed,
+// dropped or added to.
+func ReplayWith(snap Snapshot, processes []Process) (Result, error) {
+	a, err := LookupAlgorithm(snap.Algorithm)
+	if err != nil {
+		return Result{}, fmt.Errorf("%w: %w", ErrBadSnapshot, err)
+	}
+	if err := snap.Options.Validate(); err != nil {
+		return Result{}, fmt.Errorf("%w: %w", ErrBadSnapshot, err)
+	}
+	e := newEngine(a.New(snap.Options), processes, snap.Options)
+	if err := e.restore(snap); err != nil {
+		return Result{}, err
+	}
+	return e.run(context.Background()), nil
+}
+
+// save returns the state of e, a run of the named algorithm over
+// processes.
+func (e *engine) save(algorithm string, processes []Process) (Snapshot, error) {
+	sn, ok := e.policy.(Snapshotter)
+	if !ok {
+		return Snapshot{}, fmt.Errorf("%w: %s keeps no state it can save", ErrNoSnapshot, algorithm)
+	}
+	policy, err := sn.SaveState()
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("%w: %s: %w", ErrNoSnapshot, algorithm, err)
+	}
+	snap := Snapshot{
+		Algorithm:       algorithm,
+		Options:         e.opts,
+		Time:            e.t,
+		Processes:       slices.Clone(processes),
+		Jobs:            make([]JobSnapshot, e.next),
+		CPUs:            make([]CPUSnapshot, len(e.cpus)),
+		Requeue:         indexes(e.requeue),
+		Gantt:           slices.Clone(e.res.Gantt),
+		ContextSwitches: e.res.ContextSwitches,
+		Policy:          policy,
+	}
+	snap.Options.MLFQ.Quanta = slices.Clone(snap.Options.MLFQ.Quanta)
+	snap.Options.Suspensions = slices.Clone(snap.Options.Suspensions)
+	snap.Options.FairShare.Shares = slices.Clone(snap.Options.FairShare.Shares)
+	snap.Options.Speeds = slices.Clone(snap.Options.Speeds)
+	for i, j := range e.arrivals[:e.next] {
+		snap.Jobs[i] = JobSnapshot{Index: j.Index, Remaining: j.Remaining, Ready: j.Ready, Waited: j.Waited, Saved: j.saved, Completed: j.completed, Suspended: j.held, Boosted: j.Boosted}
+		if j.completed {
+			snap.Jobs[i].Completion = e.res.Stats[j.Index].Completion
+		}
+	}
+	slices.SortFunc(snap.Jobs, func(a, b JobSnapshot) int { return a.Index - b.Index })
+	for c, p := range e.cpus {
+		snap.CPUs[c] = CPUSnapshot{Job: index(p.job), Last: index(p.last), Resume: p.resume, Expires: p.expires, Idle: p.idle, Slice: p.slice}
+	}
+	return snap, nil
+}
+
+// restore puts e, a fresh run over a workload agreeing with that of snap,
+// in the state snap holds.
+func (e *engine) restore(snap Snapshot) error {
+	bad := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrBadSnapshot, fmt.Sprintf(format, args...))
+	}
+	arrived := make([]bool, len(e.jobs))
+	for _, s := range snap.Jobs {
+		if s.Index < 0 || s.Index >= len(snap.Processes) || s.Index >= len(e.jobs) || arrived[s.Index] {
+			return bad("job %d is not of a process", s.Index)
+		}
+		if p := snap.Processes[s.Index]; e.jobs[s.Index].Process != p {
+			return bad("process %d, P%d, arrived by %d and must stay as it was", s.Index, p.ProcessID, snap.Time)
+		}
+		arrived[s.Index] = true
+	}
+	for i, j := range e.jobs {
+		if !arrived[i] && j.ArrivalTime < snap.Time {
+			return bad("process %d, P%d, arrives before %d, when the snapshot was taken", i, j.ProcessID, snap.Time)
+		}
+	}
+	// Jobs line up by arrival time, so those arrived come first.
+	e.t, e.next = snap.Time, len(snap.Jobs)
+	for _, j := range e.arrivals[:e.next] {
+		if !arrived[j.Index] {
+			return bad("process %d, P%d, arrives at %d along with those arrived", j.Index, j.ProcessID, snap.Time)
+		}
+	}
+
+	// job returns the arrived job at index i, or nil if there is none.
+	job := func(i int) *Job {
+		if i < 0 || i >= len(e.jobs) || !arrived[i] {
+			return nil
+		}
+		return &e.jobs[i]
+	}
+	for _, s := range snap.Jobs {
+		j := job(s.Index)
+		if s.Remaining < 0 || s.Remaining > j.BurstDuration {
+			return bad("job %d has %d of its burst %d left", s.Index, s.Remaining, j.BurstDuration)
+		}
+		if s.Saved < 0 || s.Saved > j.BurstDuration-s.Remaining {
+			return bad("job %d was saved %d of the %d of its burst it ran", s.Index, s.Saved, j.BurstDuration-s.Remaining)
+		}
+		j.Remaining, j.Ready, j.Waited, j.saved, j.completed, j.held, j.Boosted = s.Remaining, s.Ready, s.Waited, s.Saved, s.Completed, s.Suspended, s.Boosted
+		if s.Suspended && (s.Completed || !suspended(j, snap.Options.Suspensions, snap.Time)) {
+			return bad("job %d is set aside at %d by no suspension", s.Index, snap.Time)
+		}
+		if s.Boosted && (s.Completed || s.Suspended || !snap.Options.ResumeBoost) {
+			return bad("job %d is boosted while it cannot be", s.Index)
+		}
+		if s.Completed {
+			e.done++
+			e.res.Stats[j.Index] = Stat{
+				Process:    j.Process,
+				Wait:       s.Completion - j.ArrivalTime - j.BurstDuration + j.saved,
+				Turnaround: s.Completion - j.ArrivalTime,
+				Completion: s.Completion,
+			}
+		}
+	}
+	// Changes before the snapshot was taken have been made.
+	e.changed, _ = slices.BinarySearchFunc(e.changes, snap.Time, func(c change, t int64) int { return cmp.Compare(c.t, t) })
+	for i := range e.jobs {
+		e.jobs[i].suspended = !e.jobs[i].completed && suspended(&e.jobs[i], snap.Options.Suspensions, snap.Time)
+	}
+	// pending returns the arrived job at index i if it has yet to complete
+	// and is not set aside.
+	pending := func(i int) *Job {
+		if j := job(i); j != nil && !j.completed && !j.held {
+			return j
+		}
+		return nil
+	}
+
+	if len(snap.CPUs) != len(e.cpus) {
+		return bad("%d CPUs, want %d", len(snap.CPUs), len(e.cpus))
+	}
+	if !e.opts.NoGantt {
+		e.res.Gantt = append(e.res.Gantt[:0], snap.Gantt...)
+	}
+	for c, s := range snap.CPUs {
+		p := cpu{job: pending(s.Job), last: job(s.Last), resume: s.Resume, expires: s.Expires, idle: s.Idle, slice: s.Slice}
+		switch {
+		case p.job == nil && s.Job != -1, p.last == nil && s.Last != -1:
+			return bad("CPU %d runs job %d after %d", c, s.Job, s.Last)
+		case s.Slice < 0 || s.Slice > len(e.res.Gantt):
+			return bad("CPU %d ran slice %d of %d", c, s.Slice, len(e.res.Gantt))
+		}
+		e.cpus[c] = p
+	}
+	for _, i := range snap.Requeue {
+		j := pending(i)
+		if j == nil {
+			return bad("requeued job %d is not pending", i)
+		}
+		e.requeue = append(e.requeue, j)
+	}
+	e.res.ContextSwitches = snap.ContextSwitches
+
+	sn, ok := e.policy.(Snapshotter)
+	if !ok {
+		return fmt.Errorf("%w: %s keeps no state it can restore", ErrBadSnapshot, snap.Algorithm)
+	}
+	if err := sn.RestoreState(snap.Policy, pending); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadSnapshot, err)
+	}
+	return nil
+}
+
+// index returns the index of j, or -1 for no job.
+func index(j *Job) int {
+	if j == nil {
+		return -1
+	}
+	return j.Index
+}
+
+func indexes(jobs []*Job) []int {
+	out := make([]int, len(jobs))
+	for i, j := range jobs {
+		out[i] = j.Index
+	}
+	return out
+}
+
+// lookup returns the jobs at indexes, as job finds them.
+func lookup(indexes []int, job func(int) *Job) ([]*Job, error) {
+	jobs := make([]*Job, len(indexes))
+	for i, x := range indexes {
+		if jobs[i] = job(x); jobs[i] == nil {
+			return nil, fmt.Errorf("job %d cannot be ready", x)
+		}
+	}
+	return jobs, nil
+}
+
+// readyState is the state of policies holding nothing but their ready jobs,
+// oldest first, and for some a count of jobs dispatched or a random source.
+type readyState struct {
+	Ready []int  `json:"ready"`
+	Next  int    `json:"next,omitempty"`
+	Rand  []byte `json:"rand,omitempty"`
+}
+
+func restoreReady(state json.RawMessage, job func(int) *Job) (readyState, []*Job, error) {
+	var s readyState
+	if err := json.Unmarshal(state, &s); err != nil {
+		return s, nil, err
+	}
+	jobs, err := lookup(s.Ready, job)
+	return s, jobs, err
+}
+
+func (h *jobHeap) save() (json.RawMessage, error) {
+	return json.Marshal(readyState{Ready: indexes(h.jobs)})
+}
+
+func (h *jobHeap) restore(state json.RawMessage, job func(int) *Job) error {
+	_, jobs, err := restoreReady(state, job)
+	for _, j := range jobs {
+		h.push(j)
+	}
+	return err
+}
+
+// saveRand returns the state of r, if it can be saved.
+func saveRand(r Rand) ([]byte, error) {
+	m, ok := r.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("random source %T cannot be saved", r)
+	}
+	return m.MarshalBinary()
+}
+
+func restoreRand(r Rand, state []byte) error {
+	u, ok := r.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return fmt.Errorf("random source %T cannot be restored", r)
+	}
+	return u.UnmarshalBinary(state)
+}
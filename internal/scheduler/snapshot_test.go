@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestSnapshotReplay(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+	for i := range 60 {
+		processes := randomWorkload(r)
+		for _, opts := range [][]Option{
+			nil,
+			{WithQuantum(2), WithMLFQ(MLFQParams{Levels: 2, Quanta: []int64{1, 3}}), WithSeed(3)},
+			{WithCPUs(3), WithFCFS(FCFSParams{Order: OrderGiven})},
+			{WithCPUs(2), WithSwitchCost(1), WithAging(AgingParams{Rate: 0.5})},
+			{WithQuantum(3), WithSuspensions(Suspension{PID: 1, From: 2, To: 10}, Suspension{PID: 2, From: 0, To: 25}, Suspension{PID: 3, From: 9, To: 30})},
+			{WithResumeBoost(), WithMLFQ(MLFQParams{Levels: 2, Quanta: []int64{1, 3}}), WithSuspensions(Suspension{PID: 1, From: 2, To: 10}, Suspension{PID: 3, From: 9, To: 12})},
+			{WithCPUs(2), WithFairShare(FairShareParams{Shares: []GroupShare{{Group: "a", Share: 3}, {Group: "c", Share: 1}}}), WithSuspensions(Suspension{PID: 1, From: 2, To: 10}, Suspension{PID: 3, From: 9, To: 12})},
+			{WithClasses(ClassParams{Realtime: "mlfq", Interactive: "fairshare", Batch: "lottery"}), WithSeed(5), WithSuspensions(Suspension{PID: 2, From: 3, To: 11})},
+		} {
+			sim := mustSimulator(t, opts...)
+			for _, a := range Algorithms() {
+				want := sim.Schedule(a, processes)
+				for _, at := range []int64{0, 3, 10, 25, 1000} {
+					name := fmt.Sprintf("workload %d, %s at %d, %+v", i, a.Name, at, sim.Options())
+					snap, err := sim.Snapshot(a, processes, at)
+					if err != nil {
+						t.Fatalf("%s: %v", name, err)
+					}
+					// Snapshots are kept as JSON.
+					data, err := json.Marshal(snap)
+					if err != nil {
+						t.Fatal(err)
+					}
+					var saved Snapshot
+					if err := json.Unmarshal(data, &saved); err != nil {
+						t.Fatal(err)
+					}
+					got, err := Replay(saved)
+					if err != nil {
+						t.Fatalf("%s: %v", name, err)
+					}
+					if !reflect.DeepEqual(got, want) {
+						t.Fatalf("%s: replayed\n%+v\nwant\n%+v", name, got, want)
+					}
+
+					// A workload changed only since the snapshot replays as
+					// it would run from the start.
+					changed := append([]Process(nil), processes...)
+					for k, p := range changed {
+						if !slices.ContainsFunc(snap.Jobs, func(j JobSnapshot) bool { return j.Index == k }) {
+							changed[k].BurstDuration = (p.BurstDuration + 3) % 7
+						}
+					}
+					changed = append(changed, Process{ProcessID: 99, BurstDuration: 4, ArrivalTime: snap.Time + 2, Priority: 1})
+					got, err = ReplayWith(saved, changed)
+					if err != nil {
+						t.Fatalf("%s, changed: %v", name, err)
+					}
+					if want := sim.Schedule(a, changed); !reflect.DeepEqual(got, want) {
+						t.Fatalf("%s, changed: replayed\n%+v\nwant\n%+v", name, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestReplayWithChangedPast(t *testing.T) {
+	sim := mustSimulator(t)
+	a, _ := LookupAlgorithm("rr")
+	snap, err := sim.Snapshot(a, example(), 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Time != 10 {
+		t.Errorf("snapshot at %d, want the quantum expiry at 10", snap.Time)
+	}
+	for _, processes := range [][]Process{
+		example()[:2],
+		{example()[1], example()[0], example()[2]},
+		append(example(), Process{ProcessID: 4, BurstDuration: 1, ArrivalTime: 9}),
+	} {
+		if _, err := ReplayWith(snap, processes); !errors.Is(err, ErrBadSnapshot) {
+			t.Errorf("%v: err = %v, want %v", processes, err, ErrBadSnapshot)
+		}
+	}
+}
+
+func TestSnapshotUnsavedRand(t *testing.T) {
+	sim := mustSimulator(t)
+	a := Algorithm{Name: "random", New: func(Options) Policy { return NewRandom(lowest{}) }}
+	if _, err := sim.Snapshot(a, example(), 4); !errors.Is(err, ErrNoSnapshot) {
+		t.Errorf("err = %v, want %v", err, ErrNoSnapshot)
+	}
+}
@@ -0,0 +1,182 @@
+package scheduler
+
+import (
+	"cmp"
+	"container/heap"
+	"fmt"
+	"slices"
+)
+
+type (
+	// Suspension takes every process with ID PID out of contention from
+	// From until To, as an operator suspending and later resuming it would.
+	// A process suspended while running gives up its CPU, and one suspended
+	// before it arrives arrives suspended. Time suspended counts towards
+	// wait and turnaround as any other time off the CPU does.
+	Suspension struct {
+		PID  int64 `json:"pid"`
+		From int64 `json:"from"`
+		To   int64 `json:"to"`
+	}
+
+	// Remover is implemented by policies that can take a ready job out of
+	// their queue. Every built-in policy is one. Under any other policy a
+	// job suspended while ready keeps its place until the policy picks it,
+	// when it is set aside instead of dispatched.
+	Remover interface {
+		// Remove takes ready job j out of the queue.
+		Remove(j *Job)
+	}
+)
+
+// validateSuspensions fails for a suspension that ends before it starts, or
+// that overlaps or abuts another of the same process.
+func validateSuspensions(suspensions []Suspension) error {
+	sorted := slices.Clone(suspensions)
+	slices.SortFunc(sorted, func(a, b Suspension) int {
+		return cmp.Or(cmp.Compare(a.PID, b.PID), cmp.Compare(a.From, b.From))
+	})
+	for i, s := range sorted {
+		switch {
+		case s.To <= s.From:
+			return fmt.Errorf("%w: P%d is resumed at %d, not after it is suspended at %d", ErrInvalidOption, s.PID, s.To, s.From)
+		case i > 0 && sorted[i-1].PID == s.PID && s.From <= sorted[i-1].To:
+			return fmt.Errorf("%w: P%d is suspended at %d while suspended from %d to %d", ErrInvalidOption, s.PID, s.From, sorted[i-1].From, sorted[i-1].To)
+		}
+	}
+	return nil
+}
+
+// change is a job being suspended or resumed at a time.
+type change struct {
+	t       int64
+	job     *Job
+	suspend bool
+}
+
+// changes returns the suspensions and resumptions of jobs in time order,
+// those due at the same time in the order of suspensions and then of jobs.
+func changes(jobs []Job, suspensions []Suspension) []change {
+	if len(suspensions) == 0 {
+		return nil
+	}
+	byPID := make(map[int64][]*Job)
+	for i := range jobs {
+		byPID[jobs[i].ProcessID] = append(byPID[jobs[i].ProcessID], &jobs[i])
+	}
+	var out []change
+	for _, s := range suspensions {
+		for _, j := range byPID[s.PID] {
+			out = append(out, change{s.From, j, true}, change{s.To, j, false})
+		}
+	}
+	slices.SortStableFunc(out, func(a, b change) int { return cmp.Compare(a.t, b.t) })
+	return out
+}
+
+// suspended reports whether a suspension of j is in force at time t, with
+// every change due before t made and none due at it.
+func suspended(j *Job, suspensions []Suspension, t int64) bool {
+	return slices.ContainsFunc(suspensions, func(s Suspension) bool {
+		return s.PID == j.ProcessID && s.From < t && t <= s.To
+	})
+}
+
+// suspend takes j out of contention. A job yet to arrive is only marked, to
+// be set aside when it does.
+func (e *engine) suspend(j *Job) {
+	if j.completed || j.suspended {
+		return
+	}
+	j.suspended = true
+	if j.ArrivalTime > e.t {
+		return
+	}
+	for c := range e.cpus {
+		if e.cpus[c].job == j {
+			e.stop(c)
+			e.hold(j, c)
+			return
+		}
+	}
+	if i := slices.Index(e.requeue, j); i >= 0 {
+		e.requeue = slices.Delete(e.requeue, i, i+1)
+	} else if r, ok := e.policy.(Remover); ok {
+		r.Remove(j)
+	} else {
+		// It is set aside once the policy picks it.
+		return
+	}
+	j.Waited += e.t - j.Ready
+	e.hold(j, 0)
+}
+
+// hold sets j aside until it is resumed, j having been running on CPU c if
+// it was running at all.
+func (e *engine) hold(j *Job, c int) {
+	j.held = true
+	e.emit(Event{Kind: EventSuspend, Time: e.t, PID: j.ProcessID, CPU: c})
+}
+
+// resume puts j back in contention, ready from now and boosted under
+// Options.ResumeBoost if it was set aside.
+func (e *engine) resume(j *Job) {
+	if !j.suspended {
+		return
+	}
+	j.suspended = false
+	if !j.held {
+		return
+	}
+	j.held = false
+	j.Ready = e.t
+	j.Boosted = e.opts.ResumeBoost
+	e.policy.Push(j, e.t)
+	e.emit(Event{Kind: EventResume, Time: e.t, PID: j.ProcessID})
+}
+
+// favoured orders a boosted job ahead of one that is not, reporting whether
+// a goes first and whether the boost told a and b apart at all.
+func favoured(a, b *Job) (first, ok bool) { return a.Boosted, a.Boosted != b.Boosted }
+
+// boostedFirst returns less with boosted jobs ordered ahead of the rest.
+func boostedFirst(less func(a, b *Job) bool) func(a, b *Job) bool {
+	return func(a, b *Job) bool {
+		if first, ok := favoured(a, b); ok {
+			return first
+		}
+		return less(a, b)
+	}
+}
+
+func (h *jobHeap) remove(j *Job) {
+	if i := slices.Index(h.jobs, j); i >= 0 {
+		heap.Remove(h, i)
+	}
+}
+
+func (q *fifo) remove(j *Job) {
+	if i := slices.Index(q.ready(), j); i >= 0 {
+		q.jobs = slices.Delete(q.jobs, q.head+i, q.head+i+1)
+	}
+}
+
+func (p *fcfs) Remove(j *Job)     { p.ready.remove(j) }
+func (p *sjf) Remove(j *Job)      { p.ready.remove(j) }
+func (p *priority) Remove(j *Job) { p.ready.remove(j) }
+func (p *aging) Remove(j *Job)    { p.ready.remove(j) }
+func (p *rr) Remove(j *Job)       { p.ready.remove(j) }
+
+func (p *mlfq) Remove(j *Job) { p.levels[p.state(j).level].remove(j) }
+
+func (p *lottery) Remove(j *Job) {
+	if i := slices.Index(p.ready, j); i >= 0 {
+		p.remove(i)
+	}
+}
+
+func (p *random) Remove(j *Job) {
+	if i := slices.Index(p.ready, j); i >= 0 {
+		p.ready = slices.Delete(p.ready, i, i+1)
+	}
+}
@@ -0,0 +1,199 @@
+package scheduler
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// ErrInconsistent is returned by Verify for a result whose timings do not
+// follow from its Gantt chart.
+var ErrInconsistent = errors.New("result inconsistent with its Gantt chart")
+
+// maxProblems is the most problems Verify reports.
+const maxProblems = 10
+
+// Verify recomputes the timings and averages of res, a result of scheduling
+// processes, from its Gantt chart and fails with ErrInconsistent if they
+// differ from those res holds. A process completes when its last slice
+// stops, except one without a burst, which never runs.
+//
+// Processes sharing an ID cannot be told apart in the chart, so they are
+// checked together: they must run for their bursts combined, and the last
+// of them completes when the last of their slices stops.
+func Verify(processes []Process, res Result) error {
+	return VerifySpeeds(processes, res, nil)
+}
+
+// VerifySpeeds is Verify for a result on CPUs of the given speeds, see
+// Options.Speeds, or of speed 1 if there are none. A process runs for its
+// burst when the burst the chart runs it for, each tick on a CPU of speed 2
+// counting 2, covers it with less than a tick to spare, and waits for its
+// turnaround less the time it ran. Processes sharing an ID may each have
+// some to spare, and their waits cannot be told apart.
+func VerifySpeeds(processes []Process, res Result, speeds []int64) error {
+	var problems []string
+	add := func(format string, args ...any) {
+		if len(problems) < maxProblems {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	// A process runs for ran ticks, getting through work of its burst, on
+	// CPUs of speeds up to fastest.
+	type run struct{ ran, work, fastest, stop int64 }
+	runs := make(map[int64]run)
+	for _, g := range res.Gantt {
+		if g.Stop <= g.Start {
+			add("slice %+v is empty", g)
+		}
+		speed := int64(1)
+		if len(speeds) > 0 {
+			if g.CPU < 0 || g.CPU >= len(speeds) {
+				add("slice %+v is on none of the %d CPUs", g, len(speeds))
+			} else {
+				speed = speeds[g.CPU]
+			}
+		}
+		r := runs[g.PID]
+		ran := g.Stop - g.Start
+		runs[g.PID] = run{ran: r.ran + ran, work: r.work + ran*speed, fastest: max(r.fastest, speed), stop: max(r.stop, g.Stop)}
+	}
+
+	if len(res.Stats) != len(processes) {
+		add("%d stats for %d processes", len(res.Stats), len(processes))
+		return fmt.Errorf("%w: %s", ErrInconsistent, strings.Join(problems, "; "))
+	}
+	type group struct {
+		n           int
+		burst, last int64
+	}
+	groups := make(map[int64]group)
+	for _, p := range processes {
+		g := groups[p.ProcessID]
+		g.n++
+		g.burst += p.BurstDuration
+		groups[p.ProcessID] = g
+	}
+
+	var wait, turnaround, last float64
+	for i, s := range res.Stats {
+		p := processes[i]
+		if s.Process != p {
+			add("stat %d is of %+v, want %+v", i, s.Process, p)
+		}
+		completion := s.Completion
+		if g := groups[p.ProcessID]; g.n == 1 && p.BurstDuration > 0 {
+			completion = runs[p.ProcessID].stop
+		} else if p.BurstDuration > 0 {
+			g.last = max(g.last, s.Completion)
+			groups[p.ProcessID] = g
+		}
+		if s.Completion != completion {
+			add("P%d completes at %d, but its last slice stops at %d", p.ProcessID, s.Completion, completion)
+		}
+		if t := completion - p.ArrivalTime; s.Turnaround != t {
+			add("P%d turnaround %d, want %d", p.ProcessID, s.Turnaround, t)
+		}
+		w := completion - p.ArrivalTime - p.BurstDuration
+		if len(speeds) > 0 && p.BurstDuration > 0 {
+			w = s.Wait
+			if groups[p.ProcessID].n == 1 {
+				w = completion - p.ArrivalTime - runs[p.ProcessID].ran
+			}
+		}
+		if s.Wait != w {
+			add("P%d wait %d, want %d", p.ProcessID, s.Wait, w)
+		}
+		wait += float64(w)
+		turnaround += float64(completion - p.ArrivalTime)
+		last = max(last, float64(completion))
+	}
+	for _, p := range processes {
+		g, ok := groups[p.ProcessID]
+		if !ok {
+			continue // checked already
+		}
+		delete(groups, p.ProcessID)
+		r := runs[p.ProcessID]
+		delete(runs, p.ProcessID)
+		switch spare := r.work - g.burst; {
+		case len(speeds) == 0 && spare != 0:
+			add("P%d runs for %d in the chart, want its burst %d", p.ProcessID, r.ran, g.burst)
+		case spare < 0 || spare > int64(g.n)*(max(r.fastest, 1)-1):
+			add("P%d runs %d of its burst in the chart, want %d", p.ProcessID, r.work, g.burst)
+		}
+		if g.n > 1 && g.burst > 0 && r.stop != g.last {
+			add("processes %d complete by %d, but their last slice stops at %d", p.ProcessID, g.last, r.stop)
+		}
+	}
+	for _, g := range res.Gantt {
+		if _, ok := runs[g.PID]; ok {
+			add("P%d is in the chart but not the workload", g.PID)
+			delete(runs, g.PID)
+		}
+	}
+
+	var want Result
+	if n := float64(len(processes)); n > 0 && last > 0 {
+		want.AveWait, want.AveTurnaround, want.AveThroughput = wait/n, turnaround/n, n/last
+	}
+	for _, m := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"average wait", res.AveWait, want.AveWait},
+		{"average turnaround", res.AveTurnaround, want.AveTurnaround},
+		{"throughput", res.AveThroughput, want.AveThroughput},
+	} {
+		if math.Abs(m.got-m.want) > 1e-9*max(1, math.Abs(m.want)) {
+			add("%s %v, want %v", m.name, m.got, m.want)
+		}
+	}
+
+	if problems != nil {
+		return fmt.Errorf("%w: %s", ErrInconsistent, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// FromGantt returns the result of scheduling processes as gantt charts,
+// such as a schedule another simulator produced, its timings following from
+// the chart as Verify checks them. A process completes when its last slice
+// stops, processes sharing an ID all when the last of theirs does, and one
+// without a burst, or missing from the chart, as it arrives. Verify tells
+// whether the chart runs the processes for their bursts.
+func FromGantt(processes []Process, gantt []TimeSlice) Result {
+	res := Result{Gantt: gantt, Stats: make([]Stat, len(processes))}
+	stops := make(map[int64]int64)
+	for _, g := range gantt {
+		stops[g.PID] = max(stops[g.PID], g.Stop)
+	}
+	for i, p := range processes {
+		completion := p.ArrivalTime
+		if stop, ok := stops[p.ProcessID]; ok && p.BurstDuration > 0 {
+			completion = stop
+		}
+		res.Stats[i] = Stat{
+			Process:    p,
+			Wait:       completion - p.ArrivalTime - p.BurstDuration,
+			Turnaround: completion - p.ArrivalTime,
+			Completion: completion,
+		}
+	}
+	// A switch is a slice of a process on a CPU the one before ran another.
+	byStart := slices.Clone(gantt)
+	slices.SortStableFunc(byStart, func(a, b TimeSlice) int { return cmp.Compare(a.Start, b.Start) })
+	last := make(map[int]int64)
+	for _, g := range byStart {
+		if pid, ok := last[g.CPU]; ok && pid != g.PID {
+			res.ContextSwitches++
+		}
+		last[g.CPU] = g.PID
+	}
+	res.summarize()
+	return res
+}
@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	processes := append(example(), Process{ProcessID: 4, ArrivalTime: 3}, Process{ProcessID: 1, BurstDuration: 2, ArrivalTime: 4})
+	for _, a := range Algorithms() {
+		for _, opts := range [][]Option{nil, {WithCPUs(2), WithSwitchCost(1)}} {
+			if err := Verify(processes, mustSimulator(t, opts...).Schedule(a, processes)); err != nil {
+				t.Errorf("%s: %v", a.Name, err)
+			}
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		change func(*Result)
+		want   string
+	}{
+		"completion": {func(r *Result) { r.Stats[0].Completion++ }, "P1 completes at 6, but its last slice stops at 5"},
+		"wait":       {func(r *Result) { r.Stats[1].Wait = 0 }, "P2 wait 0, want 2"},
+		"average":    {func(r *Result) { r.AveWait = 4 }, "average wait 4, want 3.3333333333333335"},
+		"slice":      {func(r *Result) { r.Gantt[2].Stop-- }, "P3 runs for 5 in the chart, want its burst 6"},
+		"stranger":   {func(r *Result) { r.Gantt = append(r.Gantt, TimeSlice{PID: 9, Start: 20, Stop: 21}) }, "P9 is in the chart but not the workload"},
+	} {
+		res := FCFS(example())
+		tc.change(&res)
+		err := Verify(example(), res)
+		if !errors.Is(err, ErrInconsistent) || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", name, err, tc.want)
+		}
+	}
+}
+
+func TestFromGantt(t *testing.T) {
+	processes := append(example(), Process{ProcessID: 4, BurstDuration: 3, ArrivalTime: 3})
+	for _, a := range Algorithms() {
+		for _, opts := range [][]Option{nil, {WithCPUs(2), WithSwitchCost(1)}} {
+			res := mustSimulator(t, opts...).Schedule(a, processes)
+			got := FromGantt(processes, res.Gantt)
+			if !reflect.DeepEqual(got, res) {
+				t.Errorf("%s: from the chart %+v, want %+v", a.Name, got, res)
+			}
+		}
+	}
+
+	// A chart short of a burst still gives timings, which Verify fails.
+	res := FromGantt(example(), FCFS(example()).Gantt[:2])
+	if res.Stats[2].Completion != example()[2].ArrivalTime {
+		t.Errorf("P3 missing from the chart completes at %d", res.Stats[2].Completion)
+	}
+	if err := Verify(example(), res); !errors.Is(err, ErrInconsistent) {
+		t.Errorf("err = %v, want %v", err, ErrInconsistent)
+	}
+}
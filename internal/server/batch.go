@@ -0,0 +1,270 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+
+	"p1/internal/config"
+	"p1/internal/input"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// MaxBatchBytes bounds the size of a batch upload.
+const MaxBatchBytes = 256 << 20
+
+// Batch states.
+const (
+	batchQueued  = "queued"
+	batchRunning = "running"
+	batchDone    = "done"
+)
+
+// batch is a set of workloads submitted together and scheduled in the
+// background with the same config.
+type batch struct {
+	id    string
+	cfg   config.Config
+	sim   *scheduler.Simulator
+	items []*batchItem
+	// left counts the items not yet processed.
+	left int
+}
+
+// batchItem is one workload file of a batch. Its fields after name are
+// guarded by Server.mu.
+type batchItem struct {
+	batch *batch
+	name  string
+	data  []byte
+
+	status string
+	err    string
+	doc    []byte
+}
+
+// batchStatus is the JSON form of a batch, for polling.
+type batchStatus struct {
+	ID        string            `json:"id"`
+	Status    string            `json:"status"`
+	Total     int               `json:"total"`
+	Completed int               `json:"completed"`
+	Failed    int               `json:"failed"`
+	Files     []batchFileStatus `json:"files"`
+}
+
+type batchFileStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// work processes batch items until the server is closed.
+func (s *Server) work() {
+	for {
+		select {
+		case it := <-s.queue:
+			s.process(it)
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *Server) process(it *batchItem) {
+	s.mu.Lock()
+	it.status = batchRunning
+	s.mu.Unlock()
+
+	var out []byte
+	processes, err := input.Load(bytes.NewReader(it.data))
+	if err == nil {
+		s.metrics.Workload(len(processes))
+		var doc render.Document
+		if doc, err = s.schedule(context.Background(), it.batch.cfg, it.batch.sim, processes); err == nil {
+			var buf bytes.Buffer
+			if err = render.JSON(&buf, doc.Results, render.Options{}); err == nil {
+				out = buf.Bytes()
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	it.status = batchDone
+	it.data = nil
+	it.doc = out
+	if err != nil {
+		it.err = err.Error()
+		s.metrics.Error("batch", "bad_workload")
+	}
+	it.batch.left--
+}
+
+// createBatch queues every file of a multipart upload's "workloads" field,
+// to be run with the config document in its "config" field, if any.
+func (s *Server) createBatch(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxBatchBytes)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		s.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	headers := r.MultipartForm.File["workloads"]
+	if len(headers) == 0 {
+		s.fail(w, http.StatusBadRequest, fmt.Errorf("no workloads field in the upload"))
+		return
+	}
+	cfg, sim, err := loadConfig([]byte(r.FormValue("config")))
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, err)
+		return
+	}
+
+	b := &batch{cfg: cfg, sim: sim, left: len(headers)}
+	for _, h := range headers {
+		data, err := readPart(h)
+		if err != nil {
+			s.fail(w, http.StatusBadRequest, err)
+			return
+		}
+		b.items = append(b.items, &batchItem{batch: b, name: h.Filename, data: data, status: batchQueued})
+	}
+
+	s.mu.Lock()
+	b.id = s.newID()
+	s.batches[b.id] = b
+	status := s.batchStatus(b)
+	s.mu.Unlock()
+
+	go func() {
+		for _, it := range b.items {
+			select {
+			case s.queue <- it:
+			case <-s.quit:
+				return
+			}
+		}
+	}()
+
+	w.Header().Set("Location", "/batches/"+b.id)
+	writeJSON(w, http.StatusAccepted, status)
+}
+
+func readPart(h *multipart.FileHeader) ([]byte, error) {
+	f, err := h.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+func (s *Server) batch(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	b, ok := s.batches[r.PathValue("id")]
+	var status batchStatus
+	if ok {
+		status = s.batchStatus(b)
+	}
+	s.mu.Unlock()
+	if !ok {
+		s.fail(w, http.StatusNotFound, fmt.Errorf("%w: batch %s", errNotFound, r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// batchStatus summarizes b. s.mu must be held.
+func (s *Server) batchStatus(b *batch) batchStatus {
+	st := batchStatus{ID: b.id, Status: batchQueued, Total: len(b.items)}
+	for _, it := range b.items {
+		st.Files = append(st.Files, batchFileStatus{Name: it.name, Status: it.status, Error: it.err})
+		switch {
+		case it.status == batchDone && it.err != "":
+			st.Failed++
+		case it.status == batchDone:
+			st.Completed++
+		case it.status == batchRunning:
+			st.Status = batchRunning
+		}
+	}
+	if st.Completed+st.Failed > 0 && st.Status == batchQueued {
+		st.Status = batchRunning
+	}
+	if b.left == 0 {
+		st.Status = batchDone
+	}
+	return st
+}
+
+// batchResults writes a zip archive holding the results document of every
+// workload of a finished batch, named after its file, and a status.json
+// listing any that failed.
+func (s *Server) batchResults(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	b, ok := s.batches[r.PathValue("id")]
+	var status batchStatus
+	if ok {
+		status = s.batchStatus(b)
+	}
+	s.mu.Unlock()
+	if !ok {
+		s.fail(w, http.StatusNotFound, fmt.Errorf("%w: batch %s", errNotFound, r.PathValue("id")))
+		return
+	}
+	if status.Status != batchDone {
+		s.fail(w, http.StatusConflict, fmt.Errorf("batch %s is still %s", b.id, status.Status))
+		return
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	used := make(map[string]bool)
+	for i, it := range b.items {
+		if it.doc == nil {
+			continue
+		}
+		name := strings.TrimSuffix(path.Base(strings.ReplaceAll(it.name, "\\", "/")), path.Ext(it.name)) + ".json"
+		if used[name] || name == "status.json" || name == ".json" {
+			name = fmt.Sprintf("%d-%s", i+1, name)
+		}
+		used[name] = true
+		if err := writeZipFile(zw, name, it.doc); err != nil {
+			s.fail(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	st, err := json.MarshalIndent(status, "", "  ")
+	if err == nil {
+		err = writeZipFile(zw, "status.json", append(st, '\n'))
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		s.fail(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=batch-%s.zip", b.id))
+	_, _ = w.Write(buf.Bytes())
+}
+
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
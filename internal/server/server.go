@@ -0,0 +1,319 @@
+// Package server exposes the schedulers over HTTP, as a backend for
+// web-based teaching tools, and serves a web UI built on them at /. All API
+// requests and responses are JSON unless noted:
+//
+//	GET  /algorithms                   the algorithms that can be run
+//	POST /workloads                    upload a workload as CSV rows or a JSON array
+//	GET  /workloads/{id}               the processes of a workload
+//	POST /workloads/{id}/results       run algorithms over a workload, with a
+//	                                   config file document as the body
+//	GET  /workloads/{id}/stream        a WebSocket streaming the events of runs
+//	                                   as they happen, optionally ?delay=50ms
+//	GET  /results/{id}                 the results document of a run
+//	GET  /results/{id}/chart           the text report of a run, optionally
+//	                                   ?algorithm=name&style=plain
+//	POST /batches                      queue many workloads at once, as the
+//	                                   files of a multipart "workloads" field,
+//	                                   with an optional "config" field
+//	GET  /batches/{id}                 the progress of a batch
+//	GET  /batches/{id}/results         a zip archive of the results of a
+//	                                   finished batch
+//	GET  /metrics                      Prometheus metrics, if enabled
+//
+// Batches are scheduled in the background by a pool of workers. Workloads,
+// results and batches are kept in memory for the life of the server. Runs
+// are refused that fail the checks of package limits, and fail with 503
+// Service Unavailable if they take longer than limits.Timeout.
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"p1/internal/config"
+	"p1/internal/input"
+	"p1/internal/limits"
+	"p1/internal/metrics"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+// MaxBodyBytes bounds the size of uploaded workloads and configs.
+const MaxBodyBytes = 10 << 20
+
+// scheduleTimeout bounds how long the algorithms of a run may take between
+// them.
+var scheduleTimeout = limits.Timeout
+
+var errNotFound = errors.New("not found")
+
+// Server is an http.Handler serving the scheduling API.
+type Server struct {
+	mux     *http.ServeMux
+	metrics *metrics.Metrics
+
+	queue chan *batchItem
+	quit  chan struct{}
+	close sync.Once
+
+	mu        sync.Mutex
+	nextID    int
+	workloads map[string][]scheduler.Process
+	results   map[string]render.Document
+	batches   map[string]*batch
+}
+
+// New returns a Server with no stored workloads, recording to m unless it is
+// nil. Its batch workers run until Close is called.
+func New(m *metrics.Metrics) *Server {
+	s := &Server{
+		mux:       http.NewServeMux(),
+		metrics:   m,
+		queue:     make(chan *batchItem),
+		quit:      make(chan struct{}),
+		workloads: make(map[string][]scheduler.Process),
+		results:   make(map[string]render.Document),
+		batches:   make(map[string]*batch),
+	}
+	for range runtime.GOMAXPROCS(0) {
+		go s.work()
+	}
+	s.mux.HandleFunc("GET /algorithms", s.algorithms)
+	s.mux.HandleFunc("POST /workloads", s.createWorkload)
+	s.mux.HandleFunc("GET /workloads/{id}", s.workload)
+	s.mux.HandleFunc("POST /workloads/{id}/results", s.createResults)
+	s.mux.HandleFunc("GET /workloads/{id}/stream", s.stream)
+	s.mux.HandleFunc("GET /results/{id}", s.result)
+	s.mux.HandleFunc("GET /results/{id}/chart", s.chart)
+	s.mux.HandleFunc("POST /batches", s.createBatch)
+	s.mux.HandleFunc("GET /batches/{id}", s.batch)
+	s.mux.HandleFunc("GET /batches/{id}/results", s.batchResults)
+	s.mux.Handle("GET /", http.FileServerFS(ui()))
+	if m != nil {
+		s.mux.Handle("GET /metrics", m.Handler())
+	}
+	return s
+}
+
+// Close stops the batch workers. Batches not yet finished are abandoned.
+func (s *Server) Close() {
+	s.close.Do(func() { close(s.quit) })
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) algorithms(w http.ResponseWriter, _ *http.Request) {
+	type algorithm struct {
+		Name  string `json:"name"`
+		Title string `json:"title"`
+	}
+	var list []algorithm
+	for _, a := range scheduler.Algorithms() {
+		list = append(list, algorithm{Name: a.Name, Title: a.Title})
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) createWorkload(w http.ResponseWriter, r *http.Request) {
+	processes, err := input.Load(http.MaxBytesReader(w, r.Body, MaxBodyBytes))
+	if err == nil {
+		err = limits.CheckSize(processes)
+	}
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.metrics.Workload(len(processes))
+	s.mu.Lock()
+	id := s.newID()
+	s.workloads[id] = processes
+	s.mu.Unlock()
+
+	w.Header().Set("Location", "/workloads/"+id)
+	writeJSON(w, http.StatusCreated, map[string]any{"id": id, "processes": processes})
+}
+
+func (s *Server) workload(w http.ResponseWriter, r *http.Request) {
+	processes, err := s.lookupWorkload(r.PathValue("id"))
+	if err != nil {
+		s.fail(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, processes)
+}
+
+func (s *Server) createResults(w http.ResponseWriter, r *http.Request) {
+	processes, err := s.lookupWorkload(r.PathValue("id"))
+	if err != nil {
+		s.fail(w, http.StatusNotFound, err)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodyBytes))
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	cfg, sim, err := loadConfig(body)
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	doc, err := s.schedule(r.Context(), cfg, sim, processes)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		s.fail(w, http.StatusServiceUnavailable, err)
+		return
+	case err != nil:
+		s.fail(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mu.Lock()
+	id := s.newID()
+	s.results[id] = doc
+	s.mu.Unlock()
+
+	w.Header().Set("Location", "/results/"+id)
+	writeJSON(w, http.StatusCreated, struct {
+		ID string `json:"id"`
+		render.Document
+	}{id, doc})
+}
+
+// loadConfig reads a config document, or the defaults if body is empty, and
+// builds the simulator it describes.
+func loadConfig(body []byte) (config.Config, *scheduler.Simulator, error) {
+	cfg := config.Default()
+	if len(bytes.TrimSpace(body)) > 0 {
+		var err error
+		if cfg, err = config.Load(bytes.NewReader(body)); err != nil {
+			return config.Config{}, nil, err
+		}
+	}
+	if err := limits.CheckOptions(cfg.Options); err != nil {
+		return config.Config{}, nil, err
+	}
+	sim, err := scheduler.NewSimulator(scheduler.WithOptions(cfg.Options))
+	if err != nil {
+		return config.Config{}, nil, err
+	}
+	return cfg, sim, nil
+}
+
+// schedule runs the algorithms of cfg over processes, failing if they take
+// longer than scheduleTimeout between them or ctx is cancelled first.
+func (s *Server) schedule(ctx context.Context, cfg config.Config, sim *scheduler.Simulator, processes []scheduler.Process) (render.Document, error) {
+	if err := limits.Check(cfg.Options, processes); err != nil {
+		return render.Document{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, scheduleTimeout)
+	defer cancel()
+	doc := render.Document{SchemaVersion: render.SchemaVersion}
+	for _, name := range cfg.Algorithms {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			return render.Document{}, err
+		}
+		start := time.Now()
+		res := sim.Stream(ctx, a, processes, scheduler.Sink{})
+		if err := ctx.Err(); err != nil {
+			return render.Document{}, fmt.Errorf("scheduling %s: %w", a.Name, err)
+		}
+		s.metrics.Simulation(a.Name, time.Since(start))
+		doc.Results = append(doc.Results, render.Named{Algorithm: a.Name, Title: a.Title, Result: res})
+	}
+	return doc, nil
+}
+
+func (s *Server) result(w http.ResponseWriter, r *http.Request) {
+	doc, err := s.lookupResults(r.PathValue("id"))
+	if err != nil {
+		s.fail(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+// chart writes the text report of a run, as printed by the CLI.
+func (s *Server) chart(w http.ResponseWriter, r *http.Request) {
+	doc, err := s.lookupResults(r.PathValue("id"))
+	if err != nil {
+		s.fail(w, http.StatusNotFound, err)
+		return
+	}
+	var opts render.Options
+	if style := r.URL.Query().Get("style"); style != "" {
+		if err := opts.Style.UnmarshalText([]byte(style)); err != nil {
+			s.fail(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	algorithm := r.URL.Query().Get("algorithm")
+
+	var buf bytes.Buffer
+	for _, n := range doc.Results {
+		if algorithm == "" || n.Algorithm == algorithm {
+			render.Report(&buf, n.Title, n.Result, opts)
+		}
+	}
+	if buf.Len() == 0 {
+		s.fail(w, http.StatusNotFound, fmt.Errorf("%w: algorithm %q in results", errNotFound, algorithm))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// newID returns an unused ID. s.mu must be held.
+func (s *Server) newID() string {
+	s.nextID++
+	return strconv.Itoa(s.nextID)
+}
+
+func (s *Server) lookupWorkload(id string) ([]scheduler.Process, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	processes, ok := s.workloads[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: workload %s", errNotFound, id)
+	}
+	return processes, nil
+}
+
+func (s *Server) lookupResults(id string) (render.Document, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	doc, ok := s.results[id]
+	if !ok {
+		return render.Document{}, fmt.Errorf("%w: results %s", errNotFound, id)
+	}
+	return doc, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// fail records and writes an error response.
+func (s *Server) fail(w http.ResponseWriter, status int, err error) {
+	s.metrics.Error("http", strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_")))
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
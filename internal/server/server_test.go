@@ -0,0 +1,288 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	"p1/internal/limits"
+	"p1/internal/metrics"
+	"p1/internal/render"
+	"p1/internal/scheduler"
+)
+
+func do(t *testing.T, h http.Handler, method, path, body string, wantStatus int) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	if rec.Code != wantStatus {
+		t.Fatalf("%s %s: status %d, want %d: %s", method, path, rec.Code, wantStatus, rec.Body.String())
+	}
+	return rec
+}
+
+func TestServer(t *testing.T) {
+	s := New(nil)
+
+	rec := do(t, s, "POST", "/workloads", "1,5,0,2\n2,9,3,1\n3,6,6,3", http.StatusCreated)
+	var workload struct{ ID string }
+	if err := json.Unmarshal(rec.Body.Bytes(), &workload); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.Header().Get("Location"); got != "/workloads/"+workload.ID {
+		t.Errorf("Location = %q", got)
+	}
+	do(t, s, "GET", "/workloads/"+workload.ID, "", http.StatusOK)
+
+	rec = do(t, s, "POST", "/workloads/"+workload.ID+"/results", `{"algorithms": ["fcfs", "rr"], "rr": {"quantum": 2}}`, http.StatusCreated)
+	var run struct {
+		ID string
+		render.Document
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &run); err != nil {
+		t.Fatal(err)
+	}
+	if run.SchemaVersion != render.SchemaVersion || len(run.Results) != 2 || run.Results[1].Algorithm != "rr" {
+		t.Fatalf("results = %+v", run.Document)
+	}
+
+	rec = do(t, s, "GET", "/results/"+run.ID, "", http.StatusOK)
+	doc, err := render.ReadJSON(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Results[0].Result.AveWait != 10.0/3 {
+		t.Errorf("fcfs average wait = %v", doc.Results[0].Result.AveWait)
+	}
+
+	rec = do(t, s, "GET", "/results/"+run.ID+"/chart?algorithm=rr&style=plain", "", http.StatusOK)
+	if out := rec.Body.String(); !strings.Contains(out, "Round-robin") || strings.Contains(out, "First-come") {
+		t.Errorf("chart:\n%s", out)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	s := New(nil)
+	do(t, s, "POST", "/workloads", "1,x,0", http.StatusBadRequest)
+	do(t, s, "GET", "/workloads/9", "", http.StatusNotFound)
+	do(t, s, "GET", "/results/9", "", http.StatusNotFound)
+	do(t, s, "DELETE", "/workloads", "", http.StatusMethodNotAllowed)
+
+	do(t, s, "POST", "/workloads", "1,5,0", http.StatusCreated)
+	do(t, s, "POST", "/workloads/1/results", `{"algorithms": ["nope"]}`, http.StatusBadRequest)
+	do(t, s, "POST", "/workloads/1/results", `{"cpus": 0}`, http.StatusBadRequest)
+	do(t, s, "POST", "/workloads/1/results", "", http.StatusCreated)
+	do(t, s, "GET", "/results/2/chart?algorithm=nope", "", http.StatusNotFound)
+	do(t, s, "GET", "/results/2/chart?style=fancy", "", http.StatusBadRequest)
+
+	// Runs too large, or that could overflow, are refused.
+	do(t, s, "POST", "/workloads/1/results", `{"cpus": 100000}`, http.StatusBadRequest)
+	do(t, s, "POST", "/workloads/1/results", `{"mlfq": {"levels": 65, "quanta": [`+strings.Repeat("1, ", 64)+`1]}}`, http.StatusBadRequest)
+	do(t, s, "POST", "/workloads", strings.Repeat("1,1,0\n", limits.MaxProcesses+1), http.StatusBadRequest)
+	rec := do(t, s, "POST", "/workloads", "1,9000000000000000000,0\n2,9000000000000000000,0", http.StatusCreated)
+	var workload struct{ ID string }
+	if err := json.Unmarshal(rec.Body.Bytes(), &workload); err != nil {
+		t.Fatal(err)
+	}
+	do(t, s, "POST", "/workloads/"+workload.ID+"/results", "", http.StatusBadRequest)
+
+	defer func(d time.Duration) { scheduleTimeout = d }(scheduleTimeout)
+	scheduleTimeout = 0
+	do(t, s, "POST", "/workloads/1/results", "", http.StatusServiceUnavailable)
+}
+
+func TestAlgorithms(t *testing.T) {
+	rec := do(t, New(nil), "GET", "/algorithms", "", http.StatusOK)
+	var list []struct{ Name string }
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != len(scheduler.Algorithms()) {
+		t.Errorf("got %d algorithms, want %d", len(list), len(scheduler.Algorithms()))
+	}
+}
+
+func TestUI(t *testing.T) {
+	s := New(nil)
+	rec := do(t, s, "GET", "/", "", http.StatusOK)
+	if !strings.Contains(rec.Body.String(), `<script src="app.js">`) {
+		t.Errorf("index page:\n%s", rec.Body.String())
+	}
+	do(t, s, "GET", "/app.js", "", http.StatusOK)
+	do(t, s, "GET", "/missing.js", "", http.StatusNotFound)
+}
+
+func TestMetrics(t *testing.T) {
+	s := New(metrics.New())
+	do(t, s, "POST", "/workloads", "1,5,0\n2,3,1", http.StatusCreated)
+	do(t, s, "POST", "/workloads/1/results", `{"algorithms": ["fcfs", "rr"]}`, http.StatusCreated)
+	do(t, s, "GET", "/results/9", "", http.StatusNotFound)
+
+	out := do(t, s, "GET", "/metrics", "", http.StatusOK).Body.String()
+	for _, want := range []string{
+		`schedsim_simulations_total{algorithm="fcfs"} 1`,
+		`schedsim_simulations_total{algorithm="rr"} 1`,
+		`schedsim_workload_processes_sum 2`,
+		`schedsim_errors_total{api="http",reason="not_found"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q", want)
+		}
+	}
+
+	do(t, New(nil), "GET", "/metrics", "", http.StatusNotFound)
+}
+
+func TestStream(t *testing.T) {
+	s := New(nil)
+	do(t, s, "POST", "/workloads", "1,5,0\n2,3,1", http.StatusCreated)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	ctx := context.Background()
+
+	conn, _, err := websocket.Dial(ctx, srv.URL+"/workloads/1/stream?delay=1ms", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+	if err := conn.Write(ctx, websocket.MessageText, []byte(`{"algorithms": ["fcfs", "rr"]}`)); err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	var results []string
+	for {
+		var msg streamMessage
+		err := wsjson.Read(ctx, conn, &msg)
+		if websocket.CloseStatus(err) == websocket.StatusNormalClosure {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.Result != nil {
+			results = append(results, msg.Algorithm)
+		} else if msg.Algorithm == "fcfs" {
+			kinds = append(kinds, msg.Event.Kind.String())
+		}
+	}
+	if want := []string{"arrive", "dispatch", "arrive", "complete", "dispatch", "complete"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("fcfs events = %v, want %v", kinds, want)
+	}
+	if want := []string{"fcfs", "rr"}; !reflect.DeepEqual(results, want) {
+		t.Errorf("results = %v, want %v", results, want)
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	s := New(nil)
+	do(t, s, "POST", "/workloads", "1,5,0", http.StatusCreated)
+	do(t, s, "GET", "/workloads/9/stream", "", http.StatusNotFound)
+	do(t, s, "GET", "/workloads/1/stream?delay=1h", "", http.StatusBadRequest)
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	ctx := context.Background()
+	conn, _, err := websocket.Dial(ctx, srv.URL+"/workloads/1/stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+	if err := conn.Write(ctx, websocket.MessageText, []byte(`{"algorithms": ["nope"]}`)); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = conn.Read(ctx)
+	if websocket.CloseStatus(err) != websocket.StatusPolicyViolation {
+		t.Errorf("err = %v, want a policy violation close", err)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	s := New(nil)
+	defer s.Close()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, data := range map[string]string{
+		"alice.csv": "1,5,0\n2,3,1",
+		"bob.csv":   "1,x,0",
+		"carol.csv": `[{"id": 1, "burst": 4}]`,
+	} {
+		fw, err := mw.CreateFormFile("workloads", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = fw.Write([]byte(data))
+	}
+	_ = mw.WriteField("config", `{"algorithms": ["fcfs", "rr"]}`)
+	_ = mw.Close()
+
+	req := httptest.NewRequest("POST", "/batches", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var status batchStatus
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		if err := json.Unmarshal(do(t, s, "GET", "/batches/1", "", http.StatusOK).Body.Bytes(), &status); err != nil {
+			t.Fatal(err)
+		}
+		if status.Status == batchDone {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("batch did not finish: %+v", status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if status.Total != 3 || status.Completed != 2 || status.Failed != 1 {
+		t.Errorf("status = %+v", status)
+	}
+
+	rec = do(t, s, "GET", "/batches/1/results", "", http.StatusOK)
+	zr, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	if want := []string{"alice.json", "carol.json", "status.json"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("archive holds %v, want %v", names, want)
+	}
+	f, err := zr.Open("alice.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := render.ReadJSON(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Results) != 2 || doc.Results[1].Algorithm != "rr" {
+		t.Errorf("alice.json = %+v", doc)
+	}
+}
+
+func TestBatchErrors(t *testing.T) {
+	s := New(nil)
+	defer s.Close()
+	do(t, s, "POST", "/batches", "", http.StatusBadRequest)
+	do(t, s, "GET", "/batches/9", "", http.StatusNotFound)
+	do(t, s, "GET", "/batches/9/results", "", http.StatusNotFound)
+}
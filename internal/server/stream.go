@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	"p1/internal/limits"
+	"p1/internal/scheduler"
+)
+
+// maxStreamDelay bounds the pacing a client may ask of a stream.
+const maxStreamDelay = time.Second
+
+// streamMessage is one message sent over a stream WebSocket: either an event
+// of the named algorithm's run or, once the run is over, its result.
+type streamMessage struct {
+	Algorithm string            `json:"algorithm"`
+	Event     *scheduler.Event  `json:"event,omitempty"`
+	Result    *scheduler.Result `json:"result,omitempty"`
+}
+
+// stream runs algorithms over a workload, sending their events over a
+// WebSocket as they are produced. The client sends a config document, or an
+// empty message for the defaults, and then reads one streamMessage per event
+// until the server closes the connection. With ?delay=50ms the server waits
+// that long per tick of simulated time between events, so the client can
+// animate the run as it arrives.
+func (s *Server) stream(w http.ResponseWriter, r *http.Request) {
+	processes, err := s.lookupWorkload(r.PathValue("id"))
+	if err != nil {
+		s.fail(w, http.StatusNotFound, err)
+		return
+	}
+	var delay time.Duration
+	if d := r.URL.Query().Get("delay"); d != "" {
+		if delay, err = time.ParseDuration(d); err != nil || delay < 0 || delay > maxStreamDelay {
+			s.fail(w, http.StatusBadRequest, fmt.Errorf("delay must be a duration up to %v", maxStreamDelay))
+			return
+		}
+	}
+
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow()
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	_, msg, err := conn.Read(ctx)
+	if err != nil {
+		return
+	}
+	cfg, sim, err := loadConfig(msg)
+	if err == nil {
+		err = limits.Check(cfg.Options, processes)
+	}
+	if err != nil {
+		s.closeWithError(conn, err)
+		return
+	}
+
+	for _, name := range cfg.Algorithms {
+		a, err := scheduler.LookupAlgorithm(name)
+		if err != nil {
+			s.closeWithError(conn, err)
+			return
+		}
+		start := time.Now()
+		run := sim.Simulate(ctx, a, processes)
+		var prev int64
+		for ev := range run.Events {
+			if ctx.Err() != nil {
+				continue
+			}
+			if delay > 0 && ev.Time > prev {
+				sleep(ctx, time.Duration(ev.Time-prev)*delay)
+			}
+			prev = ev.Time
+			if err := wsjson.Write(ctx, conn, streamMessage{
+				Algorithm: a.Name,
+				Event:     &ev,
+			}); err != nil {
+				cancel()
+			}
+		}
+		res := run.Result()
+		if ctx.Err() != nil {
+			return
+		}
+		s.metrics.Simulation(a.Name, time.Since(start))
+		if err := wsjson.Write(ctx, conn, streamMessage{Algorithm: a.Name, Result: &res}); err != nil {
+			return
+		}
+	}
+	_ = conn.Close(websocket.StatusNormalClosure, "")
+}
+
+// closeWithError records err and closes conn with it as the reason.
+func (s *Server) closeWithError(conn *websocket.Conn, err error) {
+	s.metrics.Error("http", "bad_request")
+	reason := err.Error()
+	if len(reason) > 123 { // the most a close frame can hold
+		reason = reason[:123]
+	}
+	_ = conn.Close(websocket.StatusPolicyViolation, reason)
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,19 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// uiFiles is the single-page web UI, which drives the API from the browser.
+//
+//go:embed ui
+var uiFiles embed.FS
+
+func ui() fs.FS {
+	sub, err := fs.Sub(uiFiles, "ui")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
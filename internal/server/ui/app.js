@@ -0,0 +1,225 @@
+"use strict";
+
+const $ = (id) => document.getElementById(id);
+
+async function api(method, path, body) {
+  const resp = await fetch(path, { method, body });
+  const data = await resp.json();
+  if (!resp.ok) {
+    throw new Error(data.error || resp.statusText);
+  }
+  return data;
+}
+
+const titles = new Map();
+
+async function loadAlgorithms() {
+  const list = await api("GET", "algorithms");
+  const defaults = new Set(["fcfs", "sjf", "priority", "rr"]);
+  for (const a of list) {
+    titles.set(a.name, a.title);
+    const label = document.createElement("label");
+    const box = document.createElement("input");
+    box.type = "checkbox";
+    box.value = a.name;
+    box.checked = defaults.has(a.name);
+    label.append(box, " " + a.title);
+    $("algorithms").append(label);
+  }
+}
+
+function options() {
+  const quanta = $("mlfq-quanta").value.split(",").map((q) => parseInt(q, 10)).filter((q) => !isNaN(q));
+  return {
+    algorithms: [...$("algorithms").querySelectorAll("input:checked")].map((b) => b.value),
+    rr: { quantum: parseInt($("quantum").value, 10) },
+    mlfq: { levels: quanta.length, quanta },
+    aging: { rate: parseFloat($("aging-rate").value) },
+    cpus: parseInt($("cpus").value, 10),
+    switchCost: parseInt($("switch-cost").value, 10),
+  };
+}
+
+async function run() {
+  $("error").textContent = "";
+  try {
+    const workload = await api("POST", "workloads", $("workload").value);
+    const doc = await api("POST", `workloads/${workload.id}/results`, JSON.stringify(options()));
+    show(doc.results);
+  } catch (err) {
+    $("error").textContent = err.message;
+  }
+}
+
+// color gives every process ID its own hue.
+function color(pid) {
+  return `hsl(${(pid * 137) % 360}, 55%, 45%)`;
+}
+
+// animate runs the simulation over a WebSocket, drawing each Gantt chart as
+// its events arrive, and shows the final results once every run is over.
+async function animate() {
+  $("error").textContent = "";
+  let workload;
+  try {
+    workload = await api("POST", "workloads", $("workload").value);
+  } catch (err) {
+    $("error").textContent = err.message;
+    return;
+  }
+  const url = new URL(`workloads/${workload.id}/stream?delay=${parseInt($("delay").value, 10) || 0}ms`, location.href);
+  url.protocol = url.protocol === "https:" ? "wss:" : "ws:";
+  const ws = new WebSocket(url);
+  const runs = new Map();
+  const results = [];
+
+  $("output").hidden = false;
+  $("comparison").tBodies[0].replaceChildren();
+  $("charts").replaceChildren();
+
+  ws.onopen = () => ws.send(JSON.stringify(options()));
+  ws.onmessage = (m) => {
+    const msg = JSON.parse(m.data);
+    const title = titles.get(msg.algorithm) || msg.algorithm;
+    if (msg.result) {
+      results.push({ algorithm: msg.algorithm, title, result: msg.result });
+      return;
+    }
+    let run = runs.get(msg.algorithm);
+    if (!run) {
+      run = { title, slices: [], open: new Map(), now: 0, div: document.createElement("div") };
+      runs.set(msg.algorithm, run);
+      $("charts").append(run.div);
+    }
+    const ev = msg.event;
+    run.now = ev.time;
+    if (ev.kind === "dispatch") {
+      run.open.set(ev.cpu, { pid: ev.pid, start: ev.time });
+    } else if (ev.kind === "preempt" || ev.kind === "complete") {
+      const o = run.open.get(ev.cpu);
+      if (o) {
+        run.slices.push({ pid: o.pid, start: o.start, stop: ev.time, cpu: ev.cpu });
+        run.open.delete(ev.cpu);
+      }
+    }
+    const open = [...run.open].map(([cpu, o]) => ({ pid: o.pid, start: o.start, stop: run.now, cpu }));
+    run.div.replaceWith((run.div = chart(title, run.slices.concat(open).filter((s) => s.stop > s.start))));
+  };
+  ws.onclose = (e) => {
+    if (e.code === 1000) {
+      show(results);
+    } else {
+      $("error").textContent = e.reason || "connection closed";
+    }
+  };
+}
+
+function show(results) {
+  $("output").hidden = false;
+  comparison(results);
+  $("charts").replaceChildren(...results.map((r) => chart(r.title, r.result.gantt)));
+}
+
+// chart draws one Gantt row per CPU used by slices.
+function chart(title, slices) {
+  const div = document.createElement("div");
+  div.className = "chart";
+  const h = document.createElement("h3");
+  h.textContent = title;
+  div.append(h);
+  const cpus = new Set(slices.map((s) => s.cpu));
+  for (const cpu of [...cpus].sort((a, b) => a - b)) {
+    div.append(gantt(slices.filter((s) => s.cpu === cpu), cpus.size > 1 ? cpu : null));
+  }
+  return div;
+}
+
+function comparison(results) {
+  const columns = [
+    ["averageWait", (v) => v.toFixed(2), Math.min],
+    ["averageTurnaround", (v) => v.toFixed(2), Math.min],
+    ["throughput", (v) => v.toFixed(3) + "/T", Math.max],
+    ["contextSwitches", String, Math.min],
+  ];
+  const best = columns.map(([key, , pick]) => pick(...results.map((r) => r.result[key])));
+  const body = $("comparison").tBodies[0];
+  body.replaceChildren();
+  for (const r of results) {
+    const tr = body.insertRow();
+    tr.insertCell().textContent = r.title;
+    columns.forEach(([key, format], i) => {
+      const td = tr.insertCell();
+      td.textContent = format(r.result[key]);
+      if (results.length > 1 && r.result[key] === best[i]) {
+        td.className = "best";
+      }
+    });
+  }
+}
+
+function gantt(slices, cpu) {
+  const div = document.createElement("div");
+  div.className = "gantt";
+  if (slices.length === 0) {
+    return div;
+  }
+  const end = slices.reduce((end, s) => Math.max(end, s.stop), 0);
+  const pct = (t) => `${(100 * t) / end}%`;
+  if (cpu !== null) {
+    const label = document.createElement("span");
+    label.className = "cpu";
+    label.textContent = `CPU ${cpu}`;
+    div.append(label);
+  }
+  const ticks = new Set([0]);
+  for (const s of slices) {
+    const el = document.createElement("div");
+    el.className = "slice";
+    el.style.left = pct(s.start);
+    el.style.width = pct(s.stop - s.start);
+    el.style.background = color(s.pid);
+    el.textContent = s.pid;
+    el.dataset.pid = s.pid;
+    el.addEventListener("mousemove", (e) => tooltip(e, `Process ${s.pid}: ${s.start} to ${s.stop}`));
+    el.addEventListener("mouseleave", () => ($("tooltip").hidden = true));
+    el.addEventListener("click", () => highlight(s.pid));
+    div.append(el);
+    ticks.add(s.start).add(s.stop);
+  }
+  for (const t of ticks) {
+    const el = document.createElement("span");
+    el.className = "tick";
+    el.style.left = pct(t);
+    el.textContent = t;
+    div.append(el);
+  }
+  return div;
+}
+
+function tooltip(e, text) {
+  const tip = $("tooltip");
+  tip.textContent = text;
+  tip.style.left = `${e.clientX + 12}px`;
+  tip.style.top = `${e.clientY + 12}px`;
+  tip.hidden = false;
+}
+
+// highlight dims every slice of other processes, or clears the highlight if
+// pid is already highlighted.
+let highlighted = null;
+function highlight(pid) {
+  highlighted = highlighted === pid ? null : pid;
+  for (const el of document.querySelectorAll(".slice")) {
+    el.classList.toggle("dim", highlighted !== null && Number(el.dataset.pid) !== highlighted);
+  }
+}
+
+$("file").addEventListener("change", async (e) => {
+  const file = e.target.files[0];
+  if (file) {
+    $("workload").value = await file.text();
+  }
+});
+$("run").addEventListener("click", run);
+$("animate").addEventListener("click", animate);
+loadAlgorithms().catch((err) => ($("error").textContent = err.message));
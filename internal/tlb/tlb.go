@@ -0,0 +1,203 @@
+// Package tlb simulates a translation lookaside buffer in front of the page
+// replacement of package paging: which references find their page's frame
+// in the TLB, and what that makes the effective memory access time.
+package tlb
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"p1/internal/paging"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid TLB configuration")
+	ErrUnknownPolicy = errors.New("unknown TLB replacement policy")
+)
+
+// Policy picks the entry of a full set to replace.
+type Policy string
+
+const (
+	// LRU replaces the entry least recently used.
+	LRU Policy = "lru"
+	// FIFO replaces the entry loaded longest ago.
+	FIFO Policy = "fifo"
+)
+
+// Config is the shape of a TLB.
+type Config struct {
+	// Entries is how many translations the TLB holds.
+	Entries int `json:"entries"`
+	// Ways is how many entries each set holds: 1 for a direct-mapped
+	// TLB, Entries for a fully associative one.
+	Ways   int    `json:"ways"`
+	Policy Policy `json:"policy"`
+}
+
+// Sets returns how many sets the entries are split into. Page p maps to set
+// p mod Sets.
+func (c Config) Sets() int { return c.Entries / c.Ways }
+
+// Check fails with ErrInvalidConfig unless the TLB has entries split evenly
+// into sets, or with ErrUnknownPolicy.
+func (c Config) Check() error {
+	if c.Entries < 1 || c.Ways < 1 || c.Entries%c.Ways != 0 {
+		return fmt.Errorf("%w: %d entries of %d ways, want ways dividing the entries", ErrInvalidConfig, c.Entries, c.Ways)
+	}
+	if c.Policy != LRU && c.Policy != FIFO {
+		return fmt.Errorf("%w: %q, want lru or fifo", ErrUnknownPolicy, c.Policy)
+	}
+	return nil
+}
+
+// String writes c as ParseConfig reads it.
+func (c Config) String() string {
+	return fmt.Sprintf("%dx%d:%s", c.Entries, c.Ways, c.Policy)
+}
+
+// ParseConfig reads a configuration of the form entries[xways][:policy],
+// such as 16x4:fifo. Leaving out the ways makes the TLB fully associative,
+// and leaving out the policy makes it LRU.
+func ParseConfig(s string) (Config, error) {
+	shape, policy, ok := strings.Cut(s, ":")
+	if !ok {
+		policy = string(LRU)
+	}
+	entries, ways, ok := strings.Cut(shape, "x")
+	c := Config{Policy: Policy(policy)}
+	var err error
+	if c.Entries, err = strconv.Atoi(entries); err != nil {
+		return Config{}, fmt.Errorf("%w %q: %w", ErrInvalidConfig, s, err)
+	}
+	c.Ways = c.Entries
+	if ok {
+		if c.Ways, err = strconv.Atoi(ways); err != nil {
+			return Config{}, fmt.Errorf("%w %q: %w", ErrInvalidConfig, s, err)
+		}
+	}
+	if err := c.Check(); err != nil {
+		return Config{}, err
+	}
+	return c, nil
+}
+
+// Timing is how long each part of a memory access takes.
+type Timing struct {
+	// TLB is the time to search the TLB, paid by every reference.
+	TLB time.Duration `json:"tlb"`
+	// Memory is the time of one memory access: every reference makes one,
+	// and a TLB miss makes another to read the page table.
+	Memory time.Duration `json:"memory"`
+	// Fault is the time to service a page fault.
+	Fault time.Duration `json:"fault"`
+}
+
+// DefaultTiming returns the usual textbook times: 20ns to search the TLB
+// and 100ns a memory access, leaving page faults out.
+func DefaultTiming() Timing {
+	return Timing{TLB: 20 * time.Nanosecond, Memory: 100 * time.Nanosecond}
+}
+
+// Step is the outcome of one reference.
+type Step struct {
+	Page int64 `json:"page"`
+	Hit  bool  `json:"hit"`
+	// Fault is set if the page was not in memory either.
+	Fault bool `json:"fault"`
+}
+
+// Result is the outcome of a reference string.
+type Result struct {
+	Config Config `json:"config"`
+	Steps  []Step `json:"steps"`
+	Hits   int    `json:"hits"`
+	Misses int    `json:"misses"`
+	Faults int    `json:"faults"`
+	// AccessTime is the effective access time: the average time of a
+	// reference.
+	AccessTime time.Duration `json:"access_time"`
+}
+
+// HitRate returns the share of references that hit the TLB, 0 if there were
+// none.
+func (r Result) HitRate() float64 {
+	if len(r.Steps) == 0 {
+		return 0
+	}
+	return float64(r.Hits) / float64(len(r.Steps))
+}
+
+// entry is a translation held by a set and when it was last used, or
+// loaded under FIFO.
+type entry struct {
+	page int64
+	used int
+}
+
+// Simulate replays the references of mem, a run of page replacement,
+// through a TLB of shape c. A page evicted from memory has its translation
+// dropped from the TLB, so a faulting reference always misses.
+func Simulate(c Config, mem paging.Result, t Timing) (Result, error) {
+	if err := c.Check(); err != nil {
+		return Result{}, err
+	}
+	res := Result{Config: c, Steps: make([]Step, len(mem.Steps)), Faults: mem.Faults}
+	sets := make([][]entry, c.Sets())
+	index := func(page int64) int {
+		n := int64(len(sets))
+		return int((page%n + n) % n)
+	}
+	find := func(page int64) int {
+		for j, e := range sets[index(page)] {
+			if e.page == page {
+				return j
+			}
+		}
+		return -1
+	}
+	var total time.Duration
+	for i, s := range mem.Steps {
+		if s.Evicted != paging.Empty {
+			if j := find(s.Evicted); j >= 0 {
+				k := index(s.Evicted)
+				sets[k] = append(sets[k][:j], sets[k][j+1:]...)
+			}
+		}
+		step := Step{Page: s.Page, Fault: s.Fault}
+		total += t.TLB + t.Memory
+		set := sets[index(s.Page)]
+		if j := find(s.Page); j >= 0 {
+			step.Hit = true
+			res.Hits++
+			if c.Policy == LRU {
+				set[j].used = i
+			}
+		} else {
+			res.Misses++
+			total += t.Memory
+			if len(set) < c.Ways {
+				sets[index(s.Page)] = append(set, entry{s.Page, i})
+			} else {
+				victim := 0
+				for j, e := range set {
+					if e.used < set[victim].used {
+						victim = j
+					}
+				}
+				set[victim] = entry{s.Page, i}
+			}
+		}
+		if s.Fault {
+			total += t.Fault
+		}
+		res.Steps[i] = step
+	}
+	if len(mem.Steps) > 0 {
+		res.AccessTime = total / time.Duration(len(mem.Steps))
+	}
+	return res, nil
+}
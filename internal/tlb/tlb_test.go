@@ -0,0 +1,93 @@
+package tlb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"p1/internal/paging"
+)
+
+func memory(t *testing.T, refs []int64, frames int) paging.Result {
+	t.Helper()
+	a, _ := paging.LookupAlgorithm("lru")
+	mem, err := paging.Simulate(a, refs, frames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mem
+}
+
+func TestSimulate(t *testing.T) {
+	mem := memory(t, []int64{1, 2, 1, 3, 1, 2}, 8)
+	for _, tc := range []struct {
+		config string
+		hits   int
+	}{
+		// 3 replaces 2, the least recently used.
+		{"2", 2},
+		// 3 replaces 1, loaded first, and 1 then replaces 2.
+		{"2x2:fifo", 1},
+		// 1 and 3 share a set; 2 has the other to itself.
+		{"2x1", 2},
+		{"4", 3},
+	} {
+		c, err := ParseConfig(tc.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := Simulate(c, mem, DefaultTiming())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Hits != tc.hits || res.Misses != 6-tc.hits || res.Faults != 3 {
+			t.Errorf("%s: %d hits, %d misses, %d faults, want %d hits", tc.config, res.Hits, res.Misses, res.Faults, tc.hits)
+		}
+	}
+
+	c, _ := ParseConfig("2")
+	res, _ := Simulate(c, mem, DefaultTiming())
+	// Every reference searches the TLB and reads memory; the 4 misses read
+	// the page table too.
+	if want := (6*120*time.Nanosecond + 4*100*time.Nanosecond) / 6; res.AccessTime != want {
+		t.Errorf("access time = %v, want %v", res.AccessTime, want)
+	}
+	res, _ = Simulate(c, mem, Timing{Fault: 6 * time.Millisecond})
+	if res.AccessTime != 3*time.Millisecond {
+		t.Errorf("access time with faults = %v, want 3ms", res.AccessTime)
+	}
+}
+
+func TestSimulateEviction(t *testing.T) {
+	// Each page is evicted from the single frame before it comes back, so
+	// its translation is gone too.
+	c, _ := ParseConfig("4")
+	res, err := Simulate(c, memory(t, []int64{1, 2, 1}, 1), DefaultTiming())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Hits != 0 || res.HitRate() != 0 {
+		t.Errorf("hits = %d, want none", res.Hits)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	c, err := ParseConfig("16x4:fifo")
+	if err != nil || c != (Config{16, 4, FIFO}) || c.Sets() != 4 || c.String() != "16x4:fifo" {
+		t.Errorf("config = %+v, %v", c, err)
+	}
+	if c, _ := ParseConfig("8"); c != (Config{8, 8, LRU}) {
+		t.Errorf("config = %+v, want fully associative LRU", c)
+	}
+	for in, want := range map[string]error{
+		"0":        ErrInvalidConfig,
+		"6x4":      ErrInvalidConfig,
+		"x2":       ErrInvalidConfig,
+		"4x":       ErrInvalidConfig,
+		"4:random": ErrUnknownPolicy,
+	} {
+		if _, err := ParseConfig(in); !errors.Is(err, want) {
+			t.Errorf("%q: err = %v, want %v", in, err, want)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+// Package tui animates a simulation in the terminal: a Gantt chart that grows
+// as processes run, the CPUs and ready queue after every event, and running
+// totals, with controls to play, pause, step and change speed.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"p1/internal/scheduler"
+)
+
+const (
+	minInterval     = 25 * time.Millisecond
+	maxInterval     = 2 * time.Second
+	defaultInterval = 400 * time.Millisecond
+)
+
+var (
+	titleStyle = lipgloss.NewStyle().Bold(true)
+	panelStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	helpStyle  = lipgloss.NewStyle().Faint(true)
+)
+
+type (
+	stateMsg struct {
+		state scheduler.State
+		ok    bool
+	}
+	resultMsg  struct{ res scheduler.Result }
+	advanceMsg struct{}
+)
+
+// openSlice is a process running on a CPU whose slice has not ended yet.
+type openSlice struct {
+	pid, start int64
+}
+
+// Model is the bubbletea model of one animated simulation.
+type Model struct {
+	title  string
+	total  int
+	states <-chan scheduler.State
+	result <-chan scheduler.Result
+	cancel context.CancelFunc
+
+	state   scheduler.State
+	seen    bool
+	slices  []scheduler.TimeSlice
+	open    map[int]openSlice
+	busy    int64
+	done    bool
+	res     scheduler.Result
+	pending bool
+
+	playing  bool
+	interval time.Duration
+	width    int
+}
+
+// New returns a paused Model animating a run of a over processes.
+func New(sim *scheduler.Simulator, a scheduler.Algorithm, processes []scheduler.Process) *Model {
+	obs := sim.Observe(a, processes)
+	states := obs.Subscribe(0)
+	result := make(chan scheduler.Result, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { result <- obs.Run(ctx) }()
+	return &Model{
+		title:    a.Title,
+		total:    len(processes),
+		states:   states,
+		result:   result,
+		cancel:   cancel,
+		open:     make(map[int]openSlice),
+		interval: defaultInterval,
+		width:    80,
+	}
+}
+
+// Run shows m on the terminal until the user quits.
+func Run(m *Model) error {
+	defer m.cancel()
+	_, err := tea.NewProgram(m).Run()
+	return err
+}
+
+func (m *Model) Init() tea.Cmd { return nil }
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			m.cancel()
+			return m, tea.Quit
+		case " ", "p":
+			m.playing = !m.playing
+			if m.playing {
+				return m, m.next()
+			}
+		case "n", "right", "enter":
+			if !m.playing {
+				return m, m.next()
+			}
+		case "+", "=", "up":
+			m.interval = max(m.interval/2, minInterval)
+		case "-", "down":
+			m.interval = min(m.interval*2, maxInterval)
+		}
+	case advanceMsg:
+		if m.playing {
+			return m, m.next()
+		}
+	case stateMsg:
+		m.pending = false
+		if !msg.ok {
+			r := m.result
+			return m, func() tea.Msg { return resultMsg{<-r} }
+		}
+		m.apply(msg.state)
+		if m.playing {
+			return m, tea.Tick(m.interval, func(time.Time) tea.Msg { return advanceMsg{} })
+		}
+	case resultMsg:
+		m.done = true
+		m.playing = false
+		m.res = msg.res
+	}
+	return m, nil
+}
+
+// next reads the next state of the run, unless one is already on its way.
+func (m *Model) next() tea.Cmd {
+	if m.pending || m.done {
+		return nil
+	}
+	m.pending = true
+	states := m.states
+	return func() tea.Msg {
+		s, ok := <-states
+		return stateMsg{s, ok}
+	}
+}
+
+// apply records the Gantt chart slices opened and closed by s.
+func (m *Model) apply(s scheduler.State) {
+	ev := s.Event
+	if m.seen {
+		for range m.open {
+			m.busy += ev.Time - m.state.Event.Time
+		}
+	}
+	switch ev.Kind {
+	case scheduler.EventDispatch:
+		m.open[ev.CPU] = openSlice{pid: ev.PID, start: ev.Time}
+	case scheduler.EventPreempt, scheduler.EventComplete, scheduler.EventSuspend:
+		// Only a process suspended while running was on the CPU.
+		if o, ok := m.open[ev.CPU]; ok && o.pid == ev.PID {
+			if ev.Time > o.start {
+				m.slices = append(m.slices, scheduler.TimeSlice{PID: o.pid, Start: o.start, Stop: ev.Time, CPU: ev.CPU})
+			}
+			delete(m.open, ev.CPU)
+		}
+	}
+	m.state = s
+	m.seen = true
+}
+
+func (m *Model) View() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(m.title))
+	b.WriteString("\n\n")
+	b.WriteString(m.gantt())
+	b.WriteString("\n")
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+		panelStyle.Render(m.cpus()),
+		panelStyle.Render(m.ready()),
+		panelStyle.Render(m.metrics()),
+	))
+	b.WriteString("\n")
+	status := "paused"
+	if m.playing {
+		status = "playing"
+	}
+	if m.done {
+		status = "finished"
+	}
+	b.WriteString(helpStyle.Render(fmt.Sprintf("%s, %v per event · space play/pause · n step · +/- speed · q quit", status, m.interval)))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// gantt draws the chart so far in the style of the text report, one row per
+// CPU, keeping the latest slices of a row wider than the terminal.
+func (m *Model) gantt() string {
+	var b strings.Builder
+	b.WriteString("Gantt schedule\n")
+	if len(m.slices) == 0 && len(m.open) == 0 {
+		b.WriteString("(nothing has run yet)\n")
+		return b.String()
+	}
+	for cpu := range m.state.CPUs {
+		var slices []scheduler.TimeSlice
+		for _, s := range m.slices {
+			if s.CPU == cpu {
+				slices = append(slices, s)
+			}
+		}
+		if o, ok := m.open[cpu]; ok && m.state.Event.Time > o.start {
+			slices = append(slices, scheduler.TimeSlice{PID: o.pid, Start: o.start, Stop: m.state.Event.Time, CPU: cpu})
+		}
+		if len(m.state.CPUs) > 1 {
+			fmt.Fprintf(&b, "CPU %d\n", cpu)
+		}
+		b.WriteString(ganttRow(slices, m.width))
+	}
+	return b.String()
+}
+
+func ganttRow(slices []scheduler.TimeSlice, width int) string {
+	if len(slices) == 0 {
+		return "(nothing has run on this CPU yet)\n"
+	}
+	var bars, times strings.Builder
+	for i, s := range slices {
+		fmt.Fprintf(&bars, "|%-8s", fmt.Sprintf("  %d", s.PID))
+		fmt.Fprintf(&times, "%-9d", s.Start)
+		if i == len(slices)-1 {
+			bars.WriteString("|")
+			times.WriteString(fmt.Sprint(s.Stop))
+		}
+	}
+	top, bottom := bars.String(), times.String()
+	// Terminals that do not report a size leave width at 0.
+	if over := len(bottom) - width; width > 1 && over > 0 {
+		top, bottom = "…"+top[min(over+1, len(top)):], "…"+bottom[over+1:]
+	}
+	return top + "\n" + bottom + "\n"
+}
+
+func (m *Model) cpus() string {
+	lines := []string{titleStyle.Render("CPUs")}
+	for i, c := range m.state.CPUs {
+		if c.Idle {
+			lines = append(lines, fmt.Sprintf("%d  idle", i))
+		} else {
+			lines = append(lines, fmt.Sprintf("%d  P%d, %d left", i, c.Job.PID, c.Job.Remaining))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) ready() string {
+	lines := []string{titleStyle.Render("Ready queue")}
+	for _, j := range m.state.Ready {
+		lines = append(lines, fmt.Sprintf("P%-4d %3d left, waited %d", j.PID, j.Remaining, j.Waited))
+	}
+	if len(m.state.Ready) == 0 {
+		lines = append(lines, "(empty)")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) metrics() string {
+	if !m.seen {
+		return titleStyle.Render("Metrics") + "\nnot started"
+	}
+	now := m.state.Event.Time
+	lines := []string{
+		titleStyle.Render("Metrics"),
+		fmt.Sprintf("time       %d", now),
+		fmt.Sprintf("last event %s", m.state.Event.Kind),
+		fmt.Sprintf("completed  %d/%d", m.state.Completed, m.total),
+	}
+	if now > 0 && len(m.state.CPUs) > 0 {
+		lines = append(lines, fmt.Sprintf("busy       %.0f%%", 100*float64(m.busy)/float64(now*int64(len(m.state.CPUs)))))
+	}
+	if m.done {
+		lines = append(lines,
+			fmt.Sprintf("avg wait   %.2f", m.res.AveWait),
+			fmt.Sprintf("avg turn.  %.2f", m.res.AveTurnaround),
+			fmt.Sprintf("throughput %.2f/T", m.res.AveThroughput))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"p1/internal/scheduler"
+)
+
+// drive steps m through its whole run as a paused user would, running each
+// command synchronously.
+func drive(t *testing.T, m *Model) {
+	t.Helper()
+	for i := 0; !m.done; i++ {
+		if i > 1000 {
+			t.Fatal("run did not finish")
+		}
+		cmd := func() tea.Cmd { _, c := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")}); return c }()
+		for cmd != nil {
+			_, cmd = m.Update(cmd())
+		}
+	}
+}
+
+func TestModel(t *testing.T) {
+	sim, err := scheduler.NewSimulator(scheduler.WithQuantum(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := scheduler.LookupAlgorithm("rr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	processes := []scheduler.Process{{ProcessID: 1, BurstDuration: 3}, {ProcessID: 2, BurstDuration: 2, ArrivalTime: 1}}
+	m := New(sim, a, processes)
+	defer m.cancel()
+
+	if !strings.Contains(m.View(), "nothing has run yet") {
+		t.Errorf("initial view:\n%s", m.View())
+	}
+	drive(t, m)
+
+	want := sim.Schedule(a, processes)
+	if !reflect.DeepEqual(m.slices, want.Gantt) {
+		t.Errorf("slices = %v, want %v", m.slices, want.Gantt)
+	}
+	if !reflect.DeepEqual(m.res, want) {
+		t.Errorf("result = %+v, want %+v", m.res, want)
+	}
+	view := m.View()
+	for _, s := range []string{"Round-robin", "finished", "completed  2/2", "busy       100%", "avg wait"} {
+		if !strings.Contains(view, s) {
+			t.Errorf("view missing %q:\n%s", s, view)
+		}
+	}
+}
+
+func TestSpeed(t *testing.T) {
+	m := &Model{interval: defaultInterval}
+	for range 10 {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+")})
+	}
+	if m.interval != minInterval {
+		t.Errorf("interval = %v, want %v", m.interval, minInterval)
+	}
+	for range 10 {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")})
+	}
+	if m.interval != maxInterval {
+		t.Errorf("interval = %v, want %v", m.interval, maxInterval)
+	}
+}
+
+func TestGanttRowWidth(t *testing.T) {
+	slices := []scheduler.TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 12}, {PID: 3, Start: 12, Stop: 20}}
+	if got := ganttRow(slices, 12); !strings.HasPrefix(got, "…") || !strings.Contains(got, "20") {
+		t.Errorf("narrow row = %q, want the latest slices", got)
+	}
+	for _, width := range []int{0, 1, 80} {
+		if got := ganttRow(slices, width); !strings.HasPrefix(got, "|  1") {
+			t.Errorf("width %d: row = %q, want the whole chart", width, got)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Package version reports which build of schedsim is running.
+package version
+
+import "runtime/debug"
+
+// String returns the module version and VCS revision schedsim was built
+// from, as far as the Go toolchain recorded them, such as
+// "v1.2.0 3f2a9c1 (modified)".
+func String() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	s := info.Main.Version
+	for _, setting := range info.Settings {
+		switch {
+		case setting.Key == "vcs.revision":
+			s += " " + setting.Value
+		case setting.Key == "vcs.modified" && setting.Value == "true":
+			s += " (modified)"
+		}
+	}
+	return s
+}
+
+// Clean reports whether String pins down the source schedsim was built
+// from: a VCS revision was recorded and there were no uncommitted changes.
+func Clean() bool {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return false
+	}
+	revision, modified := false, false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = true
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	return revision && !modified
+}
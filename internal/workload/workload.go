@@ -0,0 +1,167 @@
+// Package workload generates synthetic workloads, from uniform noise to
+// ones shaped like real systems: bursts drawn from heavy-tailed
+// distributions, arrivals coming in busy and quiet phases, and a mix of
+// CPU-bound processes with I/O-bound ones.
+package workload
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"p1/internal/scheduler"
+)
+
+var ErrInvalidSpec = errors.New("invalid workload spec")
+
+// Distribution is the distribution bursts are drawn from.
+type Distribution string
+
+const (
+	// Uniform bursts are equally likely to be of any length from 1 to
+	// twice the mean, also meant by the empty distribution.
+	Uniform Distribution = "uniform"
+	// Exponential bursts are mostly short, a few long.
+	Exponential Distribution = "exponential"
+	// Pareto bursts are heavy-tailed: most are short, but the few long ones
+	// make up much of the work, as on real systems. They are cut off at
+	// maxTail times the mean.
+	Pareto Distribution = "pareto"
+)
+
+var distributions = [...]Distribution{Uniform, Exponential, Pareto}
+
+func (d Distribution) MarshalText() ([]byte, error) { return []byte(d), nil }
+
+func (d *Distribution) UnmarshalText(text []byte) error {
+	for _, k := range distributions {
+		if string(text) == string(k) {
+			*d = k
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: distribution %q, want uniform, exponential or pareto", ErrInvalidSpec, text)
+}
+
+const (
+	// paretoShape is the shape of Pareto bursts, for which their mean is
+	// finite but their variance is not.
+	paretoShape = 1.5
+	// maxTail is how many times the mean the longest Pareto burst is.
+	maxTail = 100
+	// ioShare is the mean burst of I/O-bound processes as a share of that
+	// of CPU-bound ones.
+	ioShare = 0.1
+	// busyGaps is the mean gap between arrivals in a busy phase as a share
+	// of that in a steady stream, and that in a quiet phase 2 less it, so
+	// that busy and quiet phases together keep the pace of the stream.
+	busyGaps = 0.25
+)
+
+// Spec shapes a generated workload.
+type Spec struct {
+	// Processes is how many processes there are.
+	Processes int
+	// CPUs is how many processors the processes arrive about as fast as
+	// can run them, so that the ready queue neither drains nor grows
+	// without bound.
+	CPUs int
+	// MeanBurst is the mean burst of CPU-bound processes.
+	MeanBurst int64
+	// Bursts is the distribution they are drawn from.
+	Bursts Distribution
+	// IOBound is the fraction of processes that are I/O-bound, running for
+	// short exponential bursts of a tenth of MeanBurst on average. If any
+	// is, I/O-bound processes are of the interactive class and priorities
+	// 0 to 4, running first by priority, and CPU-bound ones of the batch
+	// class and priorities 5 to 9. Otherwise priorities are 0 to 9.
+	IOBound float64
+	// Phase is how many processes arrive in a phase on average, the phases
+	// being by turns busy, processes arriving four times as fast as in a
+	// steady stream, and quiet, arriving so much slower as to make up for
+	// it. If Phase is 0 processes arrive in a steady stream.
+	Phase int
+}
+
+// Validate reports whether s can shape a workload.
+func (s Spec) Validate() error {
+	var d Distribution
+	switch {
+	case s.Processes < 0:
+		return fmt.Errorf("%w: processes must not be negative, got %d", ErrInvalidSpec, s.Processes)
+	case s.CPUs < 1:
+		return fmt.Errorf("%w: need at least one CPU, got %d", ErrInvalidSpec, s.CPUs)
+	case s.MeanBurst < 1:
+		return fmt.Errorf("%w: mean burst must be at least 1, got %d", ErrInvalidSpec, s.MeanBurst)
+	case !(s.IOBound >= 0 && s.IOBound <= 1):
+		return fmt.Errorf("%w: I/O-bound fraction must be from 0 to 1, got %v", ErrInvalidSpec, s.IOBound)
+	case s.Phase < 0:
+		return fmt.Errorf("%w: phase must not be negative, got %d", ErrInvalidSpec, s.Phase)
+	case s.Bursts != "":
+		return d.UnmarshalText([]byte(s.Bursts))
+	}
+	return nil
+}
+
+// Generate returns the workload s shapes, numbering the processes from 1 in
+// order of arrival. The same seed always gives the same workload.
+func Generate(s Spec, seed int64) ([]scheduler.Process, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	r := rand.New(rand.NewPCG(uint64(seed), uint64(s.Processes)))
+	ioMean := max(1, ioShare*float64(s.MeanBurst))
+	mean := (1-s.IOBound)*float64(s.MeanBurst) + s.IOBound*ioMean
+	// Gaps are drawn from 0 to twice their mean, that of the bursts spread
+	// over the processors.
+	steady := int64(2 * mean / float64(s.CPUs))
+	busy := s.Phase > 0 && r.IntN(2) == 0
+
+	processes := make([]scheduler.Process, s.Processes)
+	var arrival int64
+	for i := range processes {
+		p := scheduler.Process{ProcessID: int64(i + 1), ArrivalTime: arrival}
+		io := s.IOBound > 0 && r.Float64() < s.IOBound
+		switch {
+		case io:
+			p.BurstDuration = exponential(r, ioMean)
+		case s.Bursts == Exponential:
+			p.BurstDuration = exponential(r, float64(s.MeanBurst))
+		case s.Bursts == Pareto:
+			// The least burst of a Pareto distribution of this shape is a
+			// third of its mean.
+			least := float64(s.MeanBurst) / 3
+			p.BurstDuration = max(1, int64(math.Round(min(least/math.Pow(1-r.Float64(), 1/paretoShape), maxTail*float64(s.MeanBurst)))))
+		default:
+			p.BurstDuration = 1 + r.Int64N(2*s.MeanBurst)
+		}
+		p.Priority = r.Int64N(10)
+		if s.IOBound > 0 {
+			p.Class, p.Priority = scheduler.ClassBatch, 5+p.Priority/2
+			if io {
+				p.Class, p.Priority = scheduler.ClassInteractive, p.Priority-5
+			}
+		}
+		processes[i] = p
+
+		gap := steady
+		if s.Phase > 0 {
+			if r.IntN(s.Phase) == 0 {
+				busy = !busy
+			}
+			gap = int64(float64(steady) * busyGaps)
+			if !busy {
+				gap = int64(float64(steady) * (2 - busyGaps))
+			}
+		}
+		arrival += r.Int64N(gap + 1)
+	}
+	return processes, nil
+}
+
+// exponential draws a burst from the exponential distribution of the
+// given mean, of at least 1.
+func exponential(r *rand.Rand, mean float64) int64 {
+	return max(1, int64(math.Round(r.ExpFloat64()*mean)))
+}
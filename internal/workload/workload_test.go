@@ -0,0 +1,135 @@
+package workload
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+
+	"p1/internal/scheduler"
+)
+
+func TestGenerate(t *testing.T) {
+	spec := Spec{Processes: 2000, CPUs: 2, MeanBurst: 20}
+	for _, d := range []Distribution{"", Exponential, Pareto} {
+		spec.Bursts = d
+		processes, err := Generate(spec, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(processes) != spec.Processes {
+			t.Fatalf("%s: %d processes", d, len(processes))
+		}
+		var work int64
+		for i, p := range processes {
+			if p.ProcessID != int64(i+1) || p.BurstDuration < 1 || p.Priority < 0 || p.Priority > 9 || p.Class != "" ||
+				(i > 0 && p.ArrivalTime < processes[i-1].ArrivalTime) {
+				t.Fatalf("%s: bad process %+v", d, p)
+			}
+			work += p.BurstDuration
+		}
+		if mean := float64(work) / float64(len(processes)); mean < 17 || mean > 23 {
+			t.Errorf("%s: mean burst %v, want about %d", d, mean, spec.MeanBurst)
+		}
+		got, err := Generate(spec, 1)
+		if err != nil || !reflect.DeepEqual(got, processes) {
+			t.Errorf("%s: same seed gave different workloads", d)
+		}
+	}
+
+	// The longest tenth of heavy-tailed bursts do more of the work than
+	// that of uniform ones.
+	top := func(d Distribution) float64 {
+		spec.Bursts = d
+		processes, _ := Generate(spec, 1)
+		bursts := make([]int64, len(processes))
+		var work, longest int64
+		for i, p := range processes {
+			bursts[i] = p.BurstDuration
+			work += p.BurstDuration
+		}
+		slices.Sort(bursts)
+		for _, b := range bursts[len(bursts)*9/10:] {
+			longest += b
+		}
+		return float64(longest) / float64(work)
+	}
+	if uniform, pareto := top(Uniform), top(Pareto); pareto < uniform+0.1 {
+		t.Errorf("longest tenth does %v of the work of pareto bursts, %v of uniform ones", pareto, uniform)
+	}
+}
+
+func TestGenerateMix(t *testing.T) {
+	processes, err := Generate(Spec{Processes: 2000, CPUs: 1, MeanBurst: 50, IOBound: 0.3}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io := 0
+	var ioWork int64
+	for _, p := range processes {
+		switch p.Class {
+		case scheduler.ClassInteractive:
+			io++
+			ioWork += p.BurstDuration
+			if p.Priority > 4 {
+				t.Fatalf("I/O-bound %+v", p)
+			}
+		case scheduler.ClassBatch:
+			if p.Priority < 5 || p.Priority > 9 {
+				t.Fatalf("CPU-bound %+v", p)
+			}
+		default:
+			t.Fatalf("unclassed %+v", p)
+		}
+	}
+	if io < 500 || io > 700 {
+		t.Errorf("%d of %d I/O-bound", io, len(processes))
+	}
+	if mean := float64(ioWork) / float64(io); mean < 4 || mean > 6 {
+		t.Errorf("I/O-bound mean burst %v, want about 5", mean)
+	}
+}
+
+func TestGeneratePhases(t *testing.T) {
+	// Arrivals in phases are as many over about as long as in a steady
+	// stream, but bunch up: more gaps are far from their mean.
+	spread := func(phase int) (int64, float64) {
+		processes, err := Generate(Spec{Processes: 4000, CPUs: 1, MeanBurst: 20, Phase: phase}, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		last := processes[len(processes)-1].ArrivalTime
+		mean := float64(last) / float64(len(processes)-1)
+		var variance float64
+		for i := 1; i < len(processes); i++ {
+			d := float64(processes[i].ArrivalTime-processes[i-1].ArrivalTime) - mean
+			variance += d * d
+		}
+		return last, variance / float64(len(processes)-1)
+	}
+	steadyLast, steady := spread(0)
+	phasedLast, phased := spread(20)
+	if phased < 1.5*steady {
+		t.Errorf("gaps vary by %v in phases, %v in a steady stream", phased, steady)
+	}
+	if r := float64(phasedLast) / float64(steadyLast); r < 0.8 || r > 1.2 {
+		t.Errorf("phases last until %d, a steady stream until %d", phasedLast, steadyLast)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, s := range []Spec{
+		{Processes: -1, CPUs: 1, MeanBurst: 1},
+		{CPUs: 0, MeanBurst: 1},
+		{CPUs: 1},
+		{CPUs: 1, MeanBurst: 1, Bursts: "normal"},
+		{CPUs: 1, MeanBurst: 1, IOBound: 1.5},
+		{CPUs: 1, MeanBurst: 1, IOBound: math.NaN()},
+		{CPUs: 1, MeanBurst: 1, Phase: -1},
+	} {
+		if _, err := Generate(s, 1); !errors.Is(err, ErrInvalidSpec) {
+			t.Errorf("%+v: err = %v, want %v", s, err, ErrInvalidSpec)
+		}
+	}
+}